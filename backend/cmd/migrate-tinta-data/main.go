@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"sirine-go/backend/config"
+	"sirine-go/backend/database"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+)
+
+/**
+ * Script untuk migrate kolom JSON tinta di khazwal_material_preparations
+ * ke line item khazwal_tinta_items yang mencakup:
+ * 1. Create tabel khazwal_tinta_items
+ * 2. Konversi tinta_requirements, tinta_actual, dan tinta_low_stock_flags per material prep
+ * 3. Ubah kolom tinta_requirements menjadi nullable agar insert material prep baru tidak gagal
+ * 4. Optional (-drop-legacy): hapus kolom JSON lama setelah semua data berhasil dikonversi
+ */
+
+const prepTable = "khazwal_material_preparations"
+
+var legacyColumns = []string{"tinta_requirements", "tinta_actual", "tinta_low_stock_flags"}
+
+// legacyTintaRow merupakan kolom JSON tinta lama dari satu material prep
+type legacyTintaRow struct {
+	ID                 uint64
+	TintaRequirements  []byte
+	TintaActual        []byte
+	TintaLowStockFlags []byte
+}
+
+func main() {
+	dropLegacy := flag.Bool("drop-legacy", false, "Hapus kolom JSON tinta lama setelah konversi berhasil")
+	flag.Parse()
+
+	// Load environment variables
+	if err := godotenv.Load("../.env"); err != nil {
+		log.Println("Warning: .env file tidak ditemukan, menggunakan default values")
+	}
+
+	// Load configuration
+	cfg := config.LoadConfig()
+
+	// Connect to database
+	if err := database.Connect(cfg); err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	db := database.GetDB()
+
+	log.Println("🔄 Starting tinta data migration...")
+
+	// Step 1: Pastikan tabel line item tinta sudah ada
+	if err := db.AutoMigrate(&models.KhazwalTintaItem{}); err != nil {
+		log.Fatal("Failed to migrate khazwal_tinta_items:", err)
+	}
+
+	// Step 2: Cek kolom JSON lama yang masih ada
+	selects := []string{"id"}
+	hasLegacy := false
+	for _, column := range legacyColumns {
+		if db.Migrator().HasColumn(prepTable, column) {
+			selects = append(selects, column)
+			hasLegacy = true
+		} else {
+			selects = append(selects, "NULL AS "+column)
+		}
+	}
+	if !hasLegacy {
+		log.Println("✅ Kolom JSON tinta lama sudah tidak ada. Tidak ada data untuk dimigrate.")
+		return
+	}
+
+	var rows []legacyTintaRow
+	if err := db.Table(prepTable).Select(selects).Order("id ASC").Scan(&rows).Error; err != nil {
+		log.Fatal("Failed to read material preparations:", err)
+	}
+	log.Printf("Found %d material preparations", len(rows))
+
+	// Step 3: Konversi per material prep dalam transaction masing-masing
+	migrated, skipped, failed := 0, 0, 0
+	for _, row := range rows {
+		var created int
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			created, err = services.MigrateLegacyTintaInTx(tx, row.ID, row.TintaRequirements, row.TintaActual, row.TintaLowStockFlags)
+			return err
+		})
+		switch {
+		case err != nil:
+			failed++
+			log.Printf("❌ Material prep %d gagal dikonversi: %v", row.ID, err)
+		case created == 0:
+			skipped++
+		default:
+			migrated++
+		}
+	}
+	log.Printf("✅ Converted %d material preparations (%d skipped, %d failed)", migrated, skipped, failed)
+
+	// Step 4: Kolom lama tidak lagi diisi oleh aplikasi sehingga harus nullable
+	if db.Migrator().HasColumn(prepTable, "tinta_requirements") {
+		if err := db.Exec("ALTER TABLE " + prepTable + " MODIFY tinta_requirements JSON NULL").Error; err != nil {
+			log.Fatal("Failed to make tinta_requirements nullable:", err)
+		}
+	}
+
+	// Step 5: Optional drop kolom lama
+	if *dropLegacy {
+		if failed > 0 {
+			log.Fatal("Kolom lama tidak dihapus karena masih ada material prep yang gagal dikonversi")
+		}
+		for _, column := range legacyColumns {
+			if !db.Migrator().HasColumn(prepTable, column) {
+				continue
+			}
+			if err := db.Migrator().DropColumn(prepTable, column); err != nil {
+				log.Fatalf("Failed to drop column %s: %v", column, err)
+			}
+			log.Printf("🗑️ Dropped column %s", column)
+		}
+	}
+
+	log.Println("✅ Tinta data migration completed!")
+	if !*dropLegacy {
+		log.Println("")
+		log.Println("📝 Next steps:")
+		log.Println("1. Verifikasi data di khazwal_tinta_items")
+		log.Println("2. Jalankan ulang dengan -drop-legacy untuk menghapus kolom JSON lama")
+	}
+}
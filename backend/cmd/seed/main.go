@@ -503,8 +503,13 @@ func seedProductionOrders() {
 			ProductionOrderID:    samplePOs[i].ID,
 			SAPPlatCode:          obcMaster.PlatNumber, // Use actual plat number from OBC Master
 			KertasBlankoQuantity: samplePOs[i].QuantityTargetLembarBesar,
-			TintaRequirements:    []byte(`{"cyan": 2, "magenta": 2, "yellow": 2, "black": 3}`),
 			Status:               models.MaterialPrepPending,
+			TintaItems: []models.KhazwalTintaItem{
+				{ColorCode: "black", RequiredKg: 3},
+				{ColorCode: "cyan", RequiredKg: 2},
+				{ColorCode: "magenta", RequiredKg: 2},
+				{ColorCode: "yellow", RequiredKg: 2},
+			},
 		}
 
 		if err := db.Create(&khazwalPrep).Error; err != nil {
@@ -1,15 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"sirine-go/backend/config"
 	"sirine-go/backend/database"
 	"sirine-go/backend/routes"
+	"sirine-go/backend/services"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
 
+// shutdownTimeout merupakan batas waktu menunggu request yang sedang berjalan saat graceful shutdown
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Load environment variables dari file .env
 	// Path relatif dari backend/ directory (working directory saat go run)
@@ -35,15 +46,43 @@ func main() {
 	}
 	log.Printf("Database tables synchronized (%d models)", registry.GetTableCount())
 
+	// Context dibatalkan saat menerima SIGINT/SIGTERM untuk menghentikan background worker
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Initialize Gin router
 	r := gin.Default()
 
-	// Setup routes
-	routes.SetupRoutes(r, cfg)
+	// Setup routes dan jalankan background worker
+	workers := routes.SetupRoutes(r, cfg)
+	workers.Start(ctx)
 
 	// Start server
-	log.Printf("Server berjalan di port %s", cfg.ServerPort)
-	if err := r.Run(":" + cfg.ServerPort); err != nil {
-		log.Fatal("Failed to start server:", err)
+	srv := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: r,
+	}
+	// Shutdown tidak membatalkan context request yang sedang berjalan,
+	// event stream SSE ditutup lewat event bus agar tidak menahan shutdown sampai timeout
+	srv.RegisterOnShutdown(services.DefaultEventBus().CloseAll)
+	go func() {
+		log.Printf("Server berjalan di port %s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Graceful shutdown: berhenti menerima request, lalu tunggu import job berhenti di batas chunk
+	<-ctx.Done()
+	stop()
+	log.Println("Shutdown server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Graceful shutdown server gagal: %v", err)
 	}
+	workers.Wait()
+
+	log.Println("Server berhenti")
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -39,6 +40,43 @@ type Config struct {
 	EmailUsername       string
 	EmailPassword       string
 	EmailFromAddress    string
+	
+	// Production Order numbering
+	PONumberFormat       string
+	PONumberDefaultPlant string
+	
+	// OBC import
+	OBCImportHeaderAliases string
+	
+	// SAP integration outbox
+	SAPAdapter            string
+	SAPFileDropDir        string
+	SAPSenderPartner      string
+	SAPHTTPBaseURL        string
+	SAPHTTPToken          string
+	SAPHTTPTimeout        time.Duration
+	SAPOutboxPollInterval time.Duration
+	
+	// Evidence attachments
+	EvidenceMaxUploadMB     int
+	EvidenceOrphanRetention time.Duration
+	EvidenceCleanupInterval time.Duration
+
+	// Notification delivery (email dan webhook)
+	NotificationDeliveryPollInterval time.Duration
+	NotificationDigestHour           int
+	NotificationWebhookSecret        string
+	NotificationWebhookTimeout       time.Duration
+	NotificationWebhookAllowedHosts  []string
+	NotificationWebhookAllowPrivate  bool
+	NotificationReadRetention        time.Duration
+	NotificationCleanupInterval      time.Duration
+
+	// Plat registry
+	PlatAllowUnregistered bool
+
+	// SLA antrian stage
+	SLAScanInterval time.Duration
 }
 
 // LoadConfig memuat configuration dari environment variables
@@ -75,6 +113,45 @@ func LoadConfig() *Config {
 		EmailUsername:    getEnv("EMAIL_USERNAME", ""),
 		EmailPassword:    getEnv("EMAIL_PASSWORD", ""),
 		EmailFromAddress: getEnv("EMAIL_FROM_ADDRESS", "noreply@sirine.local"),
+		
+		// Production Order numbering
+		PONumberFormat:       getEnv("PO_NUMBER_FORMAT", "{YYYY}{PLANT:4}{SEQ:6}"),
+		PONumberDefaultPlant: getEnv("PO_NUMBER_DEFAULT_PLANT", "0000"),
+		
+		// OBC import (format: "Alias=Header Kanonik;Alias2=Header2")
+		OBCImportHeaderAliases: getEnv("OBC_IMPORT_HEADER_ALIASES", ""),
+		
+		// SAP integration outbox (adapter: file, http, atau none)
+		SAPAdapter:            getEnv("SAP_ADAPTER", "file"),
+		SAPFileDropDir:        getEnv("SAP_FILE_DROP_DIR", "./storage/sap/outbound"),
+		SAPSenderPartner:      getEnv("SAP_SENDER_PARTNER", "SIRINE"),
+		SAPHTTPBaseURL:        getEnv("SAP_HTTP_BASE_URL", ""),
+		SAPHTTPToken:          getEnv("SAP_HTTP_TOKEN", ""),
+		SAPHTTPTimeout:        getDurationEnv("SAP_HTTP_TIMEOUT", 30*time.Second),
+		SAPOutboxPollInterval: getDurationEnv("SAP_OUTBOX_POLL_INTERVAL", 30*time.Second),
+		
+		// Evidence attachments (foto material dan foto waste)
+		EvidenceMaxUploadMB:     getIntEnv("EVIDENCE_MAX_UPLOAD_MB", 10),
+		EvidenceOrphanRetention: getDurationEnv("EVIDENCE_ORPHAN_RETENTION", 24*time.Hour),
+		EvidenceCleanupInterval: getDurationEnv("EVIDENCE_CLEANUP_INTERVAL", time.Hour),
+
+		// Notification delivery (alert kritis dan digest harian ke email, alert ke webhook)
+		NotificationDeliveryPollInterval: getDurationEnv("NOTIFICATION_DELIVERY_POLL_INTERVAL", 30*time.Second),
+		NotificationDigestHour:           getIntEnv("NOTIFICATION_DIGEST_HOUR", 7),
+		NotificationWebhookSecret:        getEnv("NOTIFICATION_WEBHOOK_SECRET", ""),
+		NotificationWebhookTimeout:       getDurationEnv("NOTIFICATION_WEBHOOK_TIMEOUT", 10*time.Second),
+		NotificationWebhookAllowedHosts:  getListEnv("NOTIFICATION_WEBHOOK_ALLOWED_HOSTS"),
+		NotificationWebhookAllowPrivate:  getEnv("NOTIFICATION_WEBHOOK_ALLOW_PRIVATE", "false") == "true",
+
+		// Notification cleanup (notifikasi kedaluwarsa dan notifikasi lama yang sudah dibaca)
+		NotificationReadRetention:   getDurationEnv("NOTIFICATION_READ_RETENTION", 30*24*time.Hour),
+		NotificationCleanupInterval: getDurationEnv("NOTIFICATION_CLEANUP_INTERVAL", time.Hour),
+
+		// Plat registry (fallback legacy untuk kode plat yang belum terdaftar)
+		PlatAllowUnregistered: getEnv("PLAT_ALLOW_UNREGISTERED", "false") == "true",
+
+		// SLA scheduler (scan antrian stage dan eskalasi PO yang hampir atau sudah overdue)
+		SLAScanInterval: getDurationEnv("SLA_SCAN_INTERVAL", 5*time.Minute),
 	}
 }
 
@@ -100,6 +177,16 @@ func getIntEnv(key string, defaultValue int) int {
 	return intValue
 }
 
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	value := os.Getenv(key)
 	if value == "" {
@@ -0,0 +1,21 @@
+-- Migration: Tambah stage dan status penghitungan/pemotongan pada production_orders dan po_stage_trackings
+-- Purpose: State machine PO memindahkan PO dari cetak ke KHAZWAL_COUNTING lalu KHAZWAL_CUTTING sebelum verifikasi
+
+ALTER TABLE production_orders
+    MODIFY current_stage ENUM('KHAZWAL_MATERIAL_PREP', 'CETAK', 'KHAZWAL_COUNTING', 'KHAZWAL_CUTTING', 'VERIFIKASI', 'KHAZKHIR', 'COMPLETED')
+        DEFAULT 'KHAZWAL_MATERIAL_PREP',
+    MODIFY current_status ENUM('WAITING_MATERIAL_PREP', 'MATERIAL_PREP_IN_PROGRESS', 'READY_FOR_CETAK', 'CETAK_IN_PROGRESS',
+        'WAITING_COUNTING', 'COUNTING_IN_PROGRESS', 'READY_FOR_CUTTING', 'CUTTING_IN_PROGRESS',
+        'READY_FOR_VERIFIKASI', 'VERIFIKASI_IN_PROGRESS', 'READY_FOR_KHAZKHIR', 'KHAZKHIR_IN_PROGRESS', 'COMPLETED')
+        DEFAULT 'WAITING_MATERIAL_PREP';
+
+ALTER TABLE po_stage_trackings
+    MODIFY stage ENUM('KHAZWAL_MATERIAL_PREP', 'CETAK', 'KHAZWAL_COUNTING', 'KHAZWAL_CUTTING', 'VERIFIKASI', 'KHAZKHIR', 'COMPLETED') NOT NULL;
+
+-- Rollback script (jika diperlukan, pastikan tidak ada PO di stage counting/cutting)
+-- ALTER TABLE po_stage_trackings
+--     MODIFY stage ENUM('KHAZWAL_MATERIAL_PREP', 'CETAK', 'VERIFIKASI', 'KHAZKHIR', 'COMPLETED') NOT NULL;
+-- ALTER TABLE production_orders
+--     MODIFY current_stage ENUM('KHAZWAL_MATERIAL_PREP', 'CETAK', 'VERIFIKASI', 'KHAZKHIR', 'COMPLETED') DEFAULT 'KHAZWAL_MATERIAL_PREP',
+--     MODIFY current_status ENUM('WAITING_MATERIAL_PREP', 'MATERIAL_PREP_IN_PROGRESS', 'READY_FOR_CETAK', 'CETAK_IN_PROGRESS',
+--         'READY_FOR_VERIFIKASI', 'VERIFIKASI_IN_PROGRESS', 'READY_FOR_KHAZKHIR', 'KHAZKHIR_IN_PROGRESS', 'COMPLETED') DEFAULT 'WAITING_MATERIAL_PREP';
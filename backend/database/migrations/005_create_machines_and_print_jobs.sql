@@ -0,0 +1,92 @@
+-- Migration: Create machines, print_jobs, print_job_logs, dan print_job_summaries
+-- Purpose: Operator cetak menjalankan print job per PO pada mesin yang terdaftar,
+-- progress/pause dicatat sebagai log dan ringkasan disimpan saat print job difinalisasi
+
+CREATE TABLE IF NOT EXISTS machines (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type ENUM('CETAK', 'CUTTING') NOT NULL,
+    capacity_sheets_per_hour BIGINT NOT NULL DEFAULT 0,
+    status ENUM('RUNNING', 'IDLE', 'DOWN', 'MAINTENANCE') DEFAULT 'IDLE' COMMENT 'RUNNING saat dipakai print job atau cutting',
+    location VARCHAR(100),
+    notes TEXT,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_machines_code (code),
+    INDEX idx_machines_type (type),
+    INDEX idx_machines_status (status),
+    INDEX idx_machines_deleted_at (deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS print_jobs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    machine_id BIGINT UNSIGNED NOT NULL,
+    operator_id BIGINT UNSIGNED NOT NULL,
+    status ENUM('IN_PROGRESS', 'PAUSED', 'COMPLETED') DEFAULT 'IN_PROGRESS',
+    good_sheets BIGINT DEFAULT 0,
+    rejected_sheets BIGINT DEFAULT 0,
+    started_at TIMESTAMP NULL,
+    paused_at TIMESTAMP NULL,
+    total_paused_minutes BIGINT DEFAULT 0,
+    completed_at TIMESTAMP NULL,
+    duration_minutes INT NULL COMMENT 'Durasi cetak di luar waktu pause',
+    notes TEXT,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_print_jobs_production_order_id (production_order_id),
+    INDEX idx_print_jobs_machine_id (machine_id),
+    INDEX idx_print_jobs_operator_id (operator_id),
+    INDEX idx_print_jobs_status (status),
+    INDEX idx_print_jobs_deleted_at (deleted_at),
+    CONSTRAINT fk_print_jobs_production_order FOREIGN KEY (production_order_id) REFERENCES production_orders(id),
+    CONSTRAINT fk_print_jobs_machine FOREIGN KEY (machine_id) REFERENCES machines(id),
+    CONSTRAINT fk_print_jobs_operator FOREIGN KEY (operator_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS print_job_logs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    print_job_id BIGINT UNSIGNED NOT NULL,
+    type ENUM('START', 'PAUSE', 'RESUME', 'PROGRESS', 'FINALIZE') NOT NULL,
+    good_sheets BIGINT DEFAULT 0,
+    rejected_sheets BIGINT DEFAULT 0,
+    notes TEXT,
+    logged_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NULL,
+    INDEX idx_print_job_logs_print_job_id (print_job_id),
+    CONSTRAINT fk_print_jobs_logs FOREIGN KEY (print_job_id) REFERENCES print_jobs(id),
+    CONSTRAINT fk_print_job_logs_logged_by_user FOREIGN KEY (logged_by) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS print_job_summaries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    print_job_id BIGINT UNSIGNED NOT NULL,
+    machine_id BIGINT UNSIGNED NOT NULL,
+    operator_id BIGINT UNSIGNED NOT NULL,
+    total_good_sheets BIGINT NOT NULL,
+    total_rejected_sheets BIGINT NOT NULL,
+    reject_percentage DECIMAL(5,2),
+    duration_minutes BIGINT NOT NULL,
+    total_paused_minutes BIGINT DEFAULT 0,
+    finalized_at TIMESTAMP NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_print_job_summaries_production_order_id (production_order_id),
+    UNIQUE INDEX idx_print_job_summaries_print_job_id (print_job_id),
+    INDEX idx_print_job_summaries_machine_id (machine_id),
+    INDEX idx_print_job_summaries_operator_id (operator_id),
+    INDEX idx_print_job_summaries_finalized_at (finalized_at),
+    CONSTRAINT fk_print_job_summaries_production_order FOREIGN KEY (production_order_id) REFERENCES production_orders(id),
+    CONSTRAINT fk_print_job_summaries_machine FOREIGN KEY (machine_id) REFERENCES machines(id),
+    CONSTRAINT fk_print_job_summaries_operator FOREIGN KEY (operator_id) REFERENCES users(id)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS print_job_summaries;
+-- DROP TABLE IF EXISTS print_job_logs;
+-- DROP TABLE IF EXISTS print_jobs;
+-- DROP TABLE IF EXISTS machines;
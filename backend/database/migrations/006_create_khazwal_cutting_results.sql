@@ -0,0 +1,31 @@
+-- Migration: Create khazwal_cutting_results
+-- Purpose: Hasil pemotongan lembar besar menjadi sisiran kiri/kanan per PO,
+-- cutting_machine berisi kode mesin potong dari machine registry
+
+CREATE TABLE IF NOT EXISTS khazwal_cutting_results (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    input_lembar_besar BIGINT NOT NULL DEFAULT 0 COMMENT 'Lembar besar baik dari hasil penghitungan',
+    expected_output BIGINT NOT NULL DEFAULT 0,
+    output_sisiran_kiri INT NULL,
+    output_sisiran_kanan INT NULL,
+    total_output BIGINT NOT NULL DEFAULT 0,
+    waste_quantity BIGINT NOT NULL DEFAULT 0,
+    waste_percentage DECIMAL(5,2),
+    waste_reason TEXT,
+    waste_photo_url VARCHAR(500),
+    cutting_machine VARCHAR(100) COMMENT 'Kode mesin potong (machines.code)',
+    cut_by BIGINT UNSIGNED NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'PENDING' COMMENT 'PENDING, IN_PROGRESS, COMPLETED',
+    started_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    duration_minutes INT NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_khazwal_cutting_results_production_order_id (production_order_id),
+    INDEX idx_khazwal_cutting_results_deleted_at (deleted_at)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS khazwal_cutting_results;
@@ -0,0 +1,51 @@
+-- Migration: Create verification_labels dan verifikasi_results
+-- Purpose: Verifikasi (QC) mengisi hasil HCS/HCTS per label 500 lembar kirim,
+-- rekap per PO dihitung dari semua label saat verifikasi difinalisasi
+
+CREATE TABLE IF NOT EXISTS verification_labels (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    label_number VARCHAR(50) NOT NULL,
+    sequence BIGINT NOT NULL,
+    total_labels BIGINT NOT NULL,
+    target_quantity BIGINT NOT NULL COMMENT 'Lembar kirim dalam label',
+    quantity_hcs BIGINT NOT NULL DEFAULT 0,
+    quantity_hcts BIGINT NOT NULL DEFAULT 0,
+    hcts_breakdown JSON COMMENT 'Breakdown jenis kerusakan HCTS',
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' COMMENT 'PENDING atau VERIFIED',
+    inspected_by BIGINT UNSIGNED NULL,
+    inspected_at TIMESTAMP NULL,
+    notes TEXT,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_verification_labels_production_order_id (production_order_id),
+    UNIQUE INDEX idx_label_po_sequence (production_order_id, sequence),
+    UNIQUE INDEX idx_verification_labels_label_number (label_number),
+    INDEX idx_verification_labels_status (status),
+    INDEX idx_verification_labels_deleted_at (deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS verifikasi_results (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    total_labels BIGINT NOT NULL DEFAULT 0,
+    total_input BIGINT NOT NULL DEFAULT 0,
+    total_hcs BIGINT NOT NULL DEFAULT 0,
+    total_hcts BIGINT NOT NULL DEFAULT 0,
+    percentage_hcs DECIMAL(5,2),
+    status VARCHAR(20) NOT NULL DEFAULT 'IN_PROGRESS' COMMENT 'IN_PROGRESS atau COMPLETED',
+    started_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    duration_minutes INT NULL,
+    verified_by BIGINT UNSIGNED NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_verifikasi_results_production_order_id (production_order_id),
+    INDEX idx_verifikasi_results_deleted_at (deleted_at)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS verifikasi_results;
+-- DROP TABLE IF EXISTS verification_labels;
@@ -0,0 +1,94 @@
+-- Migration: Create khazkhir_results, khazkhir_boxes, khazkhir_rims, dan khazkhir_shipments
+-- Purpose: Khazanah Akhir menghitung ulang HCS, mengemas per rim 500 lembar ke dalam box,
+-- lalu mencatat pengiriman dengan nomor surat jalan
+
+CREATE TABLE IF NOT EXISTS khazkhir_results (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    input_hcs BIGINT NOT NULL DEFAULT 0 COMMENT 'Total HCS dari verifikasi',
+    final_count INT NULL,
+    count_variance BIGINT NOT NULL DEFAULT 0,
+    variance_reason TEXT,
+    total_rims BIGINT NOT NULL DEFAULT 0,
+    total_boxes BIGINT NOT NULL DEFAULT 0,
+    rims_per_box BIGINT NOT NULL DEFAULT 0,
+    pallet_code VARCHAR(50),
+    status VARCHAR(20) NOT NULL DEFAULT 'COUNTING' COMMENT 'COUNTING, COUNTED, PACKED, DISPATCHED',
+    started_at TIMESTAMP NULL,
+    counted_at TIMESTAMP NULL,
+    packed_at TIMESTAMP NULL,
+    dispatched_at TIMESTAMP NULL,
+    duration_minutes INT NULL,
+    staff_id BIGINT UNSIGNED NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_khazkhir_results_production_order_id (production_order_id),
+    INDEX idx_khazkhir_results_status (status),
+    INDEX idx_khazkhir_results_deleted_at (deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS khazkhir_shipments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    delivery_document_number VARCHAR(50) NOT NULL COMMENT 'Nomor surat jalan',
+    recipient VARCHAR(255) NOT NULL,
+    destination VARCHAR(255),
+    vehicle_number VARCHAR(50),
+    total_boxes BIGINT NOT NULL,
+    total_rims BIGINT NOT NULL,
+    total_quantity BIGINT NOT NULL,
+    dispatched_by BIGINT UNSIGNED NOT NULL,
+    dispatched_at DATETIME(3) NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_khazkhir_shipments_production_order_id (production_order_id),
+    UNIQUE INDEX idx_khazkhir_shipments_delivery_document_number (delivery_document_number),
+    INDEX idx_khazkhir_shipments_deleted_at (deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS khazkhir_boxes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    box_number VARCHAR(50) NOT NULL,
+    sequence BIGINT NOT NULL,
+    total_boxes BIGINT NOT NULL,
+    rim_count BIGINT NOT NULL,
+    quantity BIGINT NOT NULL,
+    shipment_id BIGINT UNSIGNED NULL COMMENT 'Diisi saat box dikirim',
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_khazkhir_boxes_production_order_id (production_order_id),
+    UNIQUE INDEX idx_box_po_sequence (production_order_id, sequence),
+    UNIQUE INDEX idx_khazkhir_boxes_box_number (box_number),
+    INDEX idx_khazkhir_boxes_shipment_id (shipment_id),
+    INDEX idx_khazkhir_boxes_deleted_at (deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS khazkhir_rims (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    box_id BIGINT UNSIGNED NOT NULL,
+    rim_number VARCHAR(50) NOT NULL,
+    sequence BIGINT NOT NULL,
+    total_rims BIGINT NOT NULL,
+    quantity BIGINT NOT NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_khazkhir_rims_production_order_id (production_order_id),
+    UNIQUE INDEX idx_rim_po_sequence (production_order_id, sequence),
+    INDEX idx_khazkhir_rims_box_id (box_id),
+    UNIQUE INDEX idx_khazkhir_rims_rim_number (rim_number),
+    INDEX idx_khazkhir_rims_deleted_at (deleted_at),
+    CONSTRAINT fk_khazkhir_boxes_rims FOREIGN KEY (box_id) REFERENCES khazkhir_boxes(id)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS khazkhir_rims;
+-- DROP TABLE IF EXISTS khazkhir_boxes;
+-- DROP TABLE IF EXISTS khazkhir_shipments;
+-- DROP TABLE IF EXISTS khazkhir_results;
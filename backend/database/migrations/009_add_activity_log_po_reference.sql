@@ -0,0 +1,16 @@
+-- Migration: Tambah referensi PO dan deskripsi pada activity_logs
+-- Purpose: Timeline PO menggabungkan activity log workflow (START_COUNTING, FINALIZE_CUTTING, dll)
+-- sehingga action tidak lagi dibatasi enum CRUD/auth dan log dapat dicari per PO
+
+ALTER TABLE activity_logs
+    MODIFY action VARCHAR(50) NOT NULL COMMENT 'CRUD/auth action atau workflow action',
+    ADD COLUMN production_order_id BIGINT UNSIGNED NULL COMMENT 'PO terkait untuk timeline' AFTER entity_id,
+    ADD COLUMN description TEXT AFTER production_order_id,
+    ADD INDEX idx_activity_logs_production_order_id (production_order_id);
+
+-- Rollback script (jika diperlukan, pastikan tidak ada action di luar enum lama)
+-- ALTER TABLE activity_logs
+--     DROP INDEX idx_activity_logs_production_order_id,
+--     DROP COLUMN description,
+--     DROP COLUMN production_order_id,
+--     MODIFY action ENUM('CREATE', 'UPDATE', 'DELETE', 'LOGIN', 'LOGOUT', 'PASSWORD_CHANGE') NOT NULL;
@@ -0,0 +1,25 @@
+-- Migration: Tambah lineage split/merge dan pembatalan pada production_orders
+-- Purpose: PPIC dapat split/merge PO sebelum produksi dan membatalkan PO melalui state machine
+
+ALTER TABLE production_orders
+    MODIFY current_status ENUM('WAITING_MATERIAL_PREP', 'MATERIAL_PREP_IN_PROGRESS', 'READY_FOR_CETAK', 'CETAK_IN_PROGRESS',
+        'WAITING_COUNTING', 'COUNTING_IN_PROGRESS', 'READY_FOR_CUTTING', 'CUTTING_IN_PROGRESS',
+        'READY_FOR_VERIFIKASI', 'VERIFIKASI_IN_PROGRESS', 'READY_FOR_KHAZKHIR', 'KHAZKHIR_IN_PROGRESS', 'COMPLETED', 'CANCELLED')
+        DEFAULT 'WAITING_MATERIAL_PREP',
+    ADD COLUMN parent_po_id BIGINT UNSIGNED NULL COMMENT 'PO asal untuk PO hasil split' AFTER notes,
+    ADD COLUMN merged_into_po_id BIGINT UNSIGNED NULL COMMENT 'PO tujuan untuk PO yang di-merge' AFTER parent_po_id,
+    ADD COLUMN cancelled_at TIMESTAMP NULL AFTER merged_into_po_id,
+    ADD COLUMN cancelled_by BIGINT UNSIGNED NULL AFTER cancelled_at,
+    ADD COLUMN cancellation_reason TEXT AFTER cancelled_by,
+    ADD INDEX idx_production_orders_parent_po_id (parent_po_id),
+    ADD INDEX idx_production_orders_merged_into_po_id (merged_into_po_id);
+
+-- Rollback script (jika diperlukan, pastikan tidak ada PO CANCELLED)
+-- ALTER TABLE production_orders
+--     DROP INDEX idx_production_orders_merged_into_po_id,
+--     DROP INDEX idx_production_orders_parent_po_id,
+--     DROP COLUMN cancellation_reason, DROP COLUMN cancelled_by, DROP COLUMN cancelled_at,
+--     DROP COLUMN merged_into_po_id, DROP COLUMN parent_po_id,
+--     MODIFY current_status ENUM('WAITING_MATERIAL_PREP', 'MATERIAL_PREP_IN_PROGRESS', 'READY_FOR_CETAK', 'CETAK_IN_PROGRESS',
+--         'WAITING_COUNTING', 'COUNTING_IN_PROGRESS', 'READY_FOR_CUTTING', 'CUTTING_IN_PROGRESS',
+--         'READY_FOR_VERIFIKASI', 'VERIFIKASI_IN_PROGRESS', 'READY_FOR_KHAZKHIR', 'KHAZKHIR_IN_PROGRESS', 'COMPLETED') DEFAULT 'WAITING_MATERIAL_PREP';
@@ -0,0 +1,37 @@
+-- Migration: Create production_rules dan simpan rule yang dipakai pada production_orders
+-- Purpose: Buffer, maksimal quantity per PO, lembar per rim, dan multiplier lembar besar diatur per versi rule
+-- menggantikan konstanta hard-coded. Rule DEFAULT dibuat otomatis oleh aplikasi jika belum ada
+
+CREATE TABLE IF NOT EXISTS production_rules (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    version BIGINT NOT NULL COMMENT 'Perubahan rule membuat versi baru, versi lama dinonaktifkan',
+    name VARCHAR(100) NOT NULL,
+    product_type VARCHAR(50) COMMENT 'Kosong = semua product type',
+    personalization VARCHAR(20) COMMENT 'Kosong = semua personalisasi',
+    buffer_percentage DECIMAL(5,2) NOT NULL,
+    max_quantity_per_po BIGINT NOT NULL,
+    sheets_per_rim BIGINT NOT NULL,
+    lembar_besar_multiplier BIGINT NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    notes TEXT,
+    created_by BIGINT UNSIGNED NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_rule_code_version (code, version),
+    INDEX idx_production_rules_product_type (product_type),
+    INDEX idx_production_rules_personalization (personalization),
+    INDEX idx_production_rules_is_active (is_active)
+);
+
+ALTER TABLE production_orders
+    ADD COLUMN production_rule_id BIGINT UNSIGNED NULL COMMENT 'Versi rule yang dipakai saat generate PO' AFTER notes,
+    ADD COLUMN production_rule_version INT DEFAULT 0 AFTER production_rule_id,
+    ADD INDEX idx_production_orders_production_rule_id (production_rule_id);
+
+-- Rollback script (jika diperlukan)
+-- ALTER TABLE production_orders
+--     DROP INDEX idx_production_orders_production_rule_id,
+--     DROP COLUMN production_rule_version,
+--     DROP COLUMN production_rule_id;
+-- DROP TABLE IF EXISTS production_rules;
@@ -0,0 +1,15 @@
+-- Migration: Create po_number_sequences
+-- Purpose: Nomor PO dibuat dari counter per sequence key (tahun/plant sesuai format) yang di-increment
+-- dengan row lock, menggantikan nomor berbasis timestamp yang bisa bentrok
+
+CREATE TABLE IF NOT EXISTS po_number_sequences (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    sequence_key VARCHAR(50) NOT NULL COMMENT 'Prefix nomor PO sebelum {SEQ} (contoh 20261001) atau GLOBAL',
+    last_value BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_po_number_sequences_sequence_key (sequence_key)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS po_number_sequences;
@@ -0,0 +1,32 @@
+-- Migration: Create obc_import_previews
+-- Purpose: Import OBC dijalankan dry-run lebih dulu, hasil preview per row disimpan dengan token
+-- yang berlaku sampai expires_at dan di-commit tanpa upload ulang file
+
+CREATE TABLE IF NOT EXISTS obc_import_previews (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    token VARCHAR(64) NOT NULL,
+    file_name VARCHAR(255),
+    file_hash VARCHAR(64) COMMENT 'SHA-256 isi file, disalin ke import batch saat commit',
+    source_format VARCHAR(20),
+    auto_generate_po BOOLEAN DEFAULT FALSE,
+    total_rows BIGINT DEFAULT 0,
+    insert_count BIGINT DEFAULT 0,
+    update_count BIGINT DEFAULT 0,
+    unchanged_count BIGINT DEFAULT 0,
+    rejected_count BIGINT DEFAULT 0,
+    `rows` JSON COMMENT 'Hasil parsing dan diff per row',
+    status ENUM('PENDING', 'COMMITTED') DEFAULT 'PENDING',
+    expires_at DATETIME(3) NOT NULL,
+    created_by BIGINT UNSIGNED NOT NULL,
+    committed_by BIGINT UNSIGNED NULL,
+    committed_at DATETIME(3) NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_obc_import_previews_token (token),
+    INDEX idx_obc_import_previews_status (status),
+    INDEX idx_obc_import_previews_expires_at (expires_at),
+    INDEX idx_obc_import_previews_created_by (created_by)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS obc_import_previews;
@@ -0,0 +1,36 @@
+-- Migration: Create import_jobs
+-- Purpose: Import OBC berukuran besar diproses di background per chunk dengan progress yang bisa dipantau.
+-- Instance yang memproses job memperbarui heartbeat_at, job yang heartbeat-nya berhenti ditandai FAILED
+-- dan partially_applied menandai chunk yang sudah tersimpan sebelum job gagal
+
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    status ENUM('QUEUED', 'RUNNING', 'COMPLETED', 'FAILED') DEFAULT 'QUEUED',
+    file_name VARCHAR(255),
+    file_size BIGINT DEFAULT 0,
+    source_format VARCHAR(20),
+    auto_generate_po BOOLEAN DEFAULT FALSE,
+    total_rows BIGINT DEFAULT 0,
+    processed_rows BIGINT DEFAULT 0,
+    success_count BIGINT DEFAULT 0,
+    failed_count BIGINT DEFAULT 0,
+    pos_generated BIGINT DEFAULT 0,
+    failed_rows JSON,
+    result JSON,
+    error_message TEXT,
+    batch_id BIGINT UNSIGNED NULL COMMENT 'Import batch untuk rollback hasil job',
+    partially_applied BOOLEAN DEFAULT FALSE COMMENT 'Job gagal setelah sebagian chunk tersimpan',
+    heartbeat_at DATETIME(3) NULL,
+    duration_ms BIGINT DEFAULT 0,
+    started_at DATETIME(3) NULL,
+    finished_at DATETIME(3) NULL,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    INDEX idx_import_jobs_status (status),
+    INDEX idx_import_jobs_heartbeat_at (heartbeat_at),
+    INDEX idx_import_jobs_created_by (created_by)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS import_jobs;
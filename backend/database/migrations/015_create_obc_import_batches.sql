@@ -0,0 +1,50 @@
+-- Migration: Create obc_import_batches dan obc_import_batch_items
+-- Purpose: Setiap import OBC (upload, commit preview, atau background job) dicatat sebagai batch
+-- beserta data sebelum import per row sehingga batch dapat di-rollback
+
+CREATE TABLE IF NOT EXISTS obc_import_batches (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    source ENUM('UPLOAD', 'PREVIEW', 'JOB') NOT NULL,
+    file_name VARCHAR(255),
+    file_hash VARCHAR(64) COMMENT 'SHA-256 isi file untuk mencari batch per file',
+    source_format VARCHAR(20),
+    auto_generate_po BOOLEAN DEFAULT FALSE,
+    total_rows BIGINT DEFAULT 0,
+    success_count BIGINT DEFAULT 0,
+    failed_count BIGINT DEFAULT 0,
+    pos_generated BIGINT DEFAULT 0,
+    status ENUM('APPLIED', 'ROLLED_BACK') DEFAULT 'APPLIED',
+    preview_id BIGINT UNSIGNED NULL,
+    import_job_id BIGINT UNSIGNED NULL,
+    created_by BIGINT UNSIGNED NOT NULL,
+    rolled_back_by BIGINT UNSIGNED NULL,
+    rolled_back_at DATETIME(3) NULL,
+    rollback_reason TEXT,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    INDEX idx_obc_import_batches_file_hash (file_hash),
+    INDEX idx_obc_import_batches_status (status),
+    INDEX idx_obc_import_batches_created_by (created_by),
+    INDEX idx_obc_import_batches_created_at (created_at)
+);
+
+CREATE TABLE IF NOT EXISTS obc_import_batch_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    batch_id BIGINT UNSIGNED NOT NULL,
+    `row_number` BIGINT NOT NULL,
+    obc_number VARCHAR(20),
+    obc_master_id BIGINT UNSIGNED NULL,
+    outcome ENUM('INSERTED', 'UPDATED', 'UNCHANGED', 'FAILED') NOT NULL,
+    error TEXT,
+    changes JSON,
+    previous_data JSON COMMENT 'Snapshot OBC sebelum import untuk rollback',
+    generated_po_ids JSON,
+    created_at TIMESTAMP NULL,
+    INDEX idx_obc_import_batch_items_batch_id (batch_id),
+    INDEX idx_obc_import_batch_items_obc_master_id (obc_master_id),
+    CONSTRAINT fk_obc_import_batches_items FOREIGN KEY (batch_id) REFERENCES obc_import_batches(id)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS obc_import_batch_items;
+-- DROP TABLE IF EXISTS obc_import_batches;
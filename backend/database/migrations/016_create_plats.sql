@@ -0,0 +1,50 @@
+-- Migration: Create plats dan plat_checkouts
+-- Purpose: Registry plat cetak dengan lokasi penyimpanan dan status, setiap pengambilan untuk PO
+-- dicatat sebagai checkout beserta jumlah impression sampai plat dikembalikan
+
+CREATE TABLE IF NOT EXISTS plats (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL COMMENT 'Kode barcode plat',
+    description VARCHAR(255),
+    storage_location VARCHAR(100),
+    status ENUM('AVAILABLE', 'IN_USE', 'MAINTENANCE', 'RETIRED') DEFAULT 'AVAILABLE',
+    max_impressions BIGINT NOT NULL DEFAULT 0 COMMENT '0 berarti tidak ada batas',
+    total_impressions BIGINT NOT NULL DEFAULT 0,
+    usage_count BIGINT NOT NULL DEFAULT 0,
+    current_po_id BIGINT UNSIGNED NULL COMMENT 'PO yang sedang memakai plat',
+    checked_out_at DATETIME(3) NULL,
+    checked_out_by BIGINT UNSIGNED NULL,
+    notes TEXT,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_plats_code (code),
+    INDEX idx_plats_status (status),
+    INDEX idx_plats_current_po_id (current_po_id),
+    INDEX idx_plats_deleted_at (deleted_at),
+    CONSTRAINT fk_plats_current_po FOREIGN KEY (current_po_id) REFERENCES production_orders(id)
+);
+
+CREATE TABLE IF NOT EXISTS plat_checkouts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    plat_id BIGINT UNSIGNED NOT NULL,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    material_prep_id BIGINT UNSIGNED NULL,
+    checked_out_by BIGINT UNSIGNED NOT NULL,
+    checked_out_at DATETIME(3) NOT NULL,
+    returned_by BIGINT UNSIGNED NULL,
+    returned_at DATETIME(3) NULL COMMENT 'NULL selama plat belum dikembalikan',
+    return_condition VARCHAR(20) COMMENT 'GOOD atau DAMAGED',
+    impressions BIGINT NOT NULL DEFAULT 0,
+    notes TEXT,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    INDEX idx_plat_checkouts_plat_id (plat_id),
+    INDEX idx_plat_checkouts_production_order_id (production_order_id),
+    INDEX idx_plat_checkouts_returned_at (returned_at),
+    CONSTRAINT fk_plat_checkouts_production_order FOREIGN KEY (production_order_id) REFERENCES production_orders(id)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS plat_checkouts;
+-- DROP TABLE IF EXISTS plats;
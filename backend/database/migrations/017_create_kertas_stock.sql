@@ -0,0 +1,62 @@
+-- Migration: Create kertas_stocks, kertas_stock_movements, dan kertas_reservations
+-- Purpose: Saldo kertas blanko per jenis kertas dengan ledger mutasi, kertas direservasi saat material prep
+-- dimulai dan dikonsumsi saat material prep selesai
+
+CREATE TABLE IF NOT EXISTS kertas_stocks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    paper_type VARCHAR(50) NOT NULL COMMENT 'Sama dengan kode material pada OBC Master',
+    description VARCHAR(255),
+    unit VARCHAR(20) DEFAULT 'lembar',
+    on_hand BIGINT NOT NULL DEFAULT 0,
+    reserved BIGINT NOT NULL DEFAULT 0 COMMENT 'Total reservasi aktif',
+    low_stock_threshold BIGINT NOT NULL DEFAULT 0 COMMENT '0 berarti peringatan low stock tidak aktif',
+    low_stock_notified_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_kertas_stocks_paper_type (paper_type),
+    INDEX idx_kertas_stocks_deleted_at (deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS kertas_stock_movements (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    kertas_stock_id BIGINT UNSIGNED NOT NULL,
+    type ENUM('RECEIPT', 'RESERVE', 'CONSUME', 'RETURN') NOT NULL,
+    quantity BIGINT NOT NULL,
+    on_hand_after BIGINT NOT NULL,
+    reserved_after BIGINT NOT NULL,
+    production_order_id BIGINT UNSIGNED NULL,
+    material_prep_id BIGINT UNSIGNED NULL,
+    reference VARCHAR(100),
+    notes TEXT,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NULL,
+    INDEX idx_kertas_stock_movements_kertas_stock_id (kertas_stock_id),
+    INDEX idx_kertas_stock_movements_type (type),
+    INDEX idx_kertas_stock_movements_production_order_id (production_order_id),
+    INDEX idx_kertas_stock_movements_created_at (created_at),
+    CONSTRAINT fk_kertas_stock_movements_created_by_user FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS kertas_reservations (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    kertas_stock_id BIGINT UNSIGNED NOT NULL,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    material_prep_id BIGINT UNSIGNED NULL,
+    quantity BIGINT NOT NULL,
+    consumed_quantity BIGINT NOT NULL DEFAULT 0,
+    returned_quantity BIGINT NOT NULL DEFAULT 0,
+    status ENUM('RESERVED', 'CONSUMED') DEFAULT 'RESERVED',
+    consumed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    INDEX idx_kertas_reservations_kertas_stock_id (kertas_stock_id),
+    UNIQUE INDEX idx_kertas_reservations_production_order_id (production_order_id),
+    INDEX idx_kertas_reservations_status (status),
+    CONSTRAINT fk_kertas_reservations_kertas_stock FOREIGN KEY (kertas_stock_id) REFERENCES kertas_stocks(id)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS kertas_reservations;
+-- DROP TABLE IF EXISTS kertas_stock_movements;
+-- DROP TABLE IF EXISTS kertas_stocks;
@@ -0,0 +1,41 @@
+-- Migration: Create tinta_stocks dan tinta_stock_movements
+-- Purpose: Saldo tinta per warna dalam kg dengan ledger mutasi, peringatan low stock dihitung di server
+-- berdasarkan batas minimum per warna
+
+CREATE TABLE IF NOT EXISTS tinta_stocks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    color_code VARCHAR(30) NOT NULL COMMENT 'Nama warna lowercase',
+    name VARCHAR(100),
+    on_hand_kg DECIMAL(12,3) NOT NULL DEFAULT 0,
+    minimum_kg DECIMAL(12,3) NOT NULL DEFAULT 0 COMMENT '0 berarti peringatan low stock tidak aktif',
+    low_stock_notified_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_tinta_stocks_color_code (color_code),
+    INDEX idx_tinta_stocks_deleted_at (deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS tinta_stock_movements (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    tinta_stock_id BIGINT UNSIGNED NOT NULL,
+    type ENUM('RECEIPT', 'ISSUE', 'RETURN') NOT NULL,
+    quantity_kg DECIMAL(12,3) NOT NULL,
+    on_hand_after_kg DECIMAL(12,3) NOT NULL,
+    production_order_id BIGINT UNSIGNED NULL,
+    material_prep_id BIGINT UNSIGNED NULL,
+    reference VARCHAR(100),
+    notes TEXT,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NULL,
+    INDEX idx_tinta_stock_movements_tinta_stock_id (tinta_stock_id),
+    INDEX idx_tinta_stock_movements_type (type),
+    INDEX idx_tinta_stock_movements_production_order_id (production_order_id),
+    INDEX idx_tinta_stock_movements_material_prep_id (material_prep_id),
+    INDEX idx_tinta_stock_movements_created_at (created_at),
+    CONSTRAINT fk_tinta_stock_movements_created_by_user FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS tinta_stock_movements;
+-- DROP TABLE IF EXISTS tinta_stocks;
@@ -0,0 +1,31 @@
+-- Migration: Create sap_outbox_entries
+-- Purpose: Posting material ke SAP ditulis ke outbox dalam transaction yang sama dengan finalize,
+-- lalu dikirim worker (file drop atau HTTP) dengan retry exponential backoff
+
+CREATE TABLE IF NOT EXISTS sap_outbox_entries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL COMMENT 'MATERIAL_PREP_FINALIZED, COUNTING_FINALIZED, CUTTING_FINALIZED',
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id BIGINT UNSIGNED NOT NULL,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    idempotency_key VARCHAR(120) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' COMMENT 'PENDING, PROCESSING, DELIVERED, FAILED',
+    attempts BIGINT NOT NULL DEFAULT 0,
+    max_attempts BIGINT NOT NULL DEFAULT 8,
+    next_attempt_at DATETIME(3) NOT NULL,
+    last_attempt_at TIMESTAMP NULL,
+    last_error TEXT,
+    locked_at TIMESTAMP NULL,
+    delivered_at TIMESTAMP NULL,
+    external_reference VARCHAR(100) COMMENT 'Nomor dokumen IDoc (file drop) atau reference dari SAP (HTTP)',
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_sap_outbox_entries_idempotency_key (idempotency_key),
+    INDEX idx_sap_outbox_entries_event_type (event_type),
+    INDEX idx_sap_outbox_entries_production_order_id (production_order_id),
+    INDEX idx_sap_outbox_due (status, next_attempt_at)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS sap_outbox_entries;
@@ -0,0 +1,25 @@
+-- Migration: Create khazwal_tinta_items untuk line item tinta per warna
+-- Purpose: Mengganti kolom JSON tinta_requirements, tinta_actual, dan tinta_low_stock_flags
+-- Data lama dikonversi dengan: go run cmd/migrate-tinta-data/main.go
+
+CREATE TABLE IF NOT EXISTS khazwal_tinta_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    material_prep_id BIGINT UNSIGNED NOT NULL,
+    color_code VARCHAR(30) NOT NULL COMMENT 'Kode warna lowercase, sama dengan tinta_stocks.color_code',
+    required_kg DECIMAL(12,3) NOT NULL DEFAULT 0 COMMENT 'Kebutuhan tinta dari PO',
+    actual_kg DECIMAL(12,3) NULL COMMENT 'Jumlah actual yang disiapkan Staff Khazwal',
+    batch_number VARCHAR(50) COMMENT 'Nomor batch/lot tinta',
+    checked BOOLEAN NOT NULL DEFAULT FALSE,
+    low_stock BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Saldo inventory di bawah minimum saat actual diposting',
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    UNIQUE KEY idx_tinta_item_prep_color (material_prep_id, color_code),
+    CONSTRAINT fk_khazwal_material_preparations_tinta_items
+        FOREIGN KEY (material_prep_id) REFERENCES khazwal_material_preparations(id)
+);
+
+-- Kolom JSON lama tidak lagi diisi aplikasi
+ALTER TABLE khazwal_material_preparations MODIFY tinta_requirements JSON NULL;
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS khazwal_tinta_items;
@@ -0,0 +1,29 @@
+-- Migration: Create attachments untuk evidence foto material prep dan foto waste cutting
+-- Purpose: Menggantikan foto base64/URL bebas dengan file yang di-upload ke server
+-- File disimpan di ./public/uploads/evidence, lampiran tanpa pemilik dihapus oleh retention job
+
+CREATE TABLE IF NOT EXISTS attachments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    owner_type VARCHAR(30) NOT NULL COMMENT 'MATERIAL_PREP atau CUTTING_WASTE',
+    owner_id BIGINT UNSIGNED NULL COMMENT 'NULL selama lampiran belum dihubungkan ke entity',
+    file_name VARCHAR(255) COMMENT 'Nama file asli dari client',
+    content_type VARCHAR(50) NOT NULL COMMENT 'Hasil sniffing isi file',
+    size_bytes BIGINT NOT NULL,
+    width INT NOT NULL DEFAULT 0,
+    height INT NOT NULL DEFAULT 0,
+    checksum VARCHAR(64) NOT NULL COMMENT 'SHA-256 isi file',
+    storage_path VARCHAR(255) NOT NULL,
+    thumbnail_path VARCHAR(255) NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    thumbnail_url VARCHAR(500) NOT NULL,
+    uploaded_by BIGINT UNSIGNED NOT NULL,
+    linked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    INDEX idx_attachment_owner (owner_type, owner_id),
+    INDEX idx_attachments_uploaded_by (uploaded_by),
+    INDEX idx_attachments_updated_at (updated_at)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS attachments;
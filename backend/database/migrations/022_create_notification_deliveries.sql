@@ -0,0 +1,46 @@
+-- Migration: Create notification_preferences dan notification_deliveries
+-- Purpose: Notifikasi kritis dan digest harian dikirim ke email dan webhook selain in-app
+-- Pengiriman ditulis dalam transaction yang sama dengan notifikasi lalu dikirim worker dengan retry
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    email_enabled BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Alert WARNING/ERROR langsung ke email',
+    digest_enabled BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Ringkasan harian notifikasi belum dibaca',
+    webhook_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    webhook_url VARCHAR(500),
+    last_digest_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_notification_preferences_user_id (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    notification_id BIGINT UNSIGNED NULL COMMENT 'NULL untuk digest dan uji coba',
+    user_id BIGINT UNSIGNED NOT NULL,
+    channel VARCHAR(20) NOT NULL COMMENT 'EMAIL atau WEBHOOK',
+    kind VARCHAR(20) NOT NULL COMMENT 'ALERT, DIGEST, atau TEST',
+    recipient VARCHAR(500) NOT NULL COMMENT 'Alamat email atau webhook URL',
+    subject VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    idempotency_key VARCHAR(120) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' COMMENT 'PENDING, PROCESSING, SENT, FAILED',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 6,
+    next_attempt_at DATETIME(3) NOT NULL,
+    last_attempt_at TIMESTAMP NULL,
+    last_error TEXT,
+    locked_at TIMESTAMP NULL,
+    sent_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_notification_deliveries_idempotency_key (idempotency_key),
+    INDEX idx_notification_deliveries_notification_id (notification_id),
+    INDEX idx_notification_deliveries_user_id (user_id),
+    INDEX idx_notification_delivery_due (status, next_attempt_at)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS notification_deliveries;
+-- DROP TABLE IF EXISTS notification_preferences;
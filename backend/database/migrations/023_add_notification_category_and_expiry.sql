@@ -0,0 +1,25 @@
+-- Migration: Tambah kategori, link PO/stage, dan masa berlaku pada notifications
+-- Purpose: Notification center bisa difilter per kategori, user bisa me-mute kategori,
+-- dan notifikasi kedaluwarsa serta notifikasi lama yang sudah dibaca dihapus oleh cleanup job
+
+ALTER TABLE notifications
+    ADD COLUMN category VARCHAR(20) NOT NULL DEFAULT 'SYSTEM' COMMENT 'HANDOVER, ALERT, ACHIEVEMENT, atau SYSTEM' AFTER type,
+    ADD COLUMN po_id BIGINT UNSIGNED NULL COMMENT 'PO terkait, NULL untuk notifikasi umum' AFTER category,
+    ADD COLUMN stage VARCHAR(50) NULL COMMENT 'Stage PO terkait' AFTER po_id,
+    ADD COLUMN expires_at TIMESTAMP NULL COMMENT 'NULL berarti tidak pernah kedaluwarsa' AFTER read_at,
+    ADD INDEX idx_notifications_category (category),
+    ADD INDEX idx_notifications_po_id (po_id),
+    ADD INDEX idx_notifications_expires_at (expires_at);
+
+-- Notifikasi lama: achievement tetap tanpa masa berlaku, WARNING/ERROR dianggap alert
+UPDATE notifications SET category = 'ACHIEVEMENT' WHERE type = 'ACHIEVEMENT';
+UPDATE notifications SET category = 'ALERT', expires_at = DATE_ADD(created_at, INTERVAL 30 DAY) WHERE type IN ('WARNING', 'ERROR');
+UPDATE notifications SET expires_at = DATE_ADD(created_at, INTERVAL 30 DAY) WHERE category = 'SYSTEM';
+
+ALTER TABLE notification_preferences
+    ADD COLUMN muted_categories JSON NULL COMMENT 'Kategori yang di-mute user, ALERT tidak dapat di-mute' AFTER webhook_url;
+
+-- Rollback script (jika diperlukan)
+-- ALTER TABLE notification_preferences DROP COLUMN muted_categories;
+-- ALTER TABLE notifications DROP INDEX idx_notifications_expires_at, DROP INDEX idx_notifications_po_id,
+--     DROP INDEX idx_notifications_category, DROP COLUMN expires_at, DROP COLUMN stage, DROP COLUMN po_id, DROP COLUMN category;
@@ -0,0 +1,39 @@
+-- Migration: Create sla_policies dan sla_breaches
+-- Purpose: SLA antrian setiap stage dapat dikonfigurasi per priority menggantikan batas overdue yang hard-coded,
+-- scheduler mencatat PO yang hampir atau sudah melewati SLA sekali per kali masuk antrian dan mengeskalasinya
+
+CREATE TABLE IF NOT EXISTS sla_policies (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    stage VARCHAR(50) NOT NULL,
+    priority VARCHAR(20) NOT NULL DEFAULT '' COMMENT 'Kosong = semua priority di stage',
+    warning_minutes INT NOT NULL COMMENT 'Lewat batas ini PO AT_RISK',
+    breach_minutes INT NOT NULL COMMENT 'Lewat batas ini PO BREACHED (overdue)',
+    updated_by BIGINT UNSIGNED NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_sla_policy_stage_priority (stage, priority)
+);
+
+CREATE TABLE IF NOT EXISTS sla_breaches (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    production_order_id BIGINT UNSIGNED NOT NULL,
+    stage VARCHAR(50) NOT NULL,
+    level VARCHAR(20) NOT NULL COMMENT 'AT_RISK atau BREACHED',
+    waiting_since DATETIME(3) NOT NULL COMMENT 'Waktu PO masuk status antrian',
+    queue_status VARCHAR(50) NOT NULL,
+    priority VARCHAR(20) NOT NULL,
+    threshold_minutes INT NOT NULL,
+    waiting_minutes INT NOT NULL COMMENT 'Lama menunggu saat terdeteksi',
+    detected_at DATETIME(3) NOT NULL,
+    resolved_at TIMESTAMP NULL COMMENT 'Diisi saat PO keluar dari antrian',
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_sla_breach_dedup (production_order_id, stage, level, waiting_since),
+    INDEX idx_sla_breaches_stage (stage),
+    INDEX idx_sla_breaches_detected_at (detected_at),
+    INDEX idx_sla_breaches_resolved_at (resolved_at)
+);
+
+-- Rollback script (jika diperlukan)
+-- DROP TABLE IF EXISTS sla_breaches;
+-- DROP TABLE IF EXISTS sla_policies;
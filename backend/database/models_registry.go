@@ -3,6 +3,8 @@ package database
 import (
 	"sirine-go/backend/internal/counting"
 	"sirine-go/backend/internal/cutting"
+	"sirine-go/backend/internal/khazkhir"
+	"sirine-go/backend/internal/verifikasi"
 	"sirine-go/backend/models"
 )
 
@@ -28,17 +30,65 @@ func NewModelsRegistry() *ModelsRegistry {
 	registry.Register(&models.PasswordResetToken{}, "password_reset_tokens")
 	registry.Register(&models.ActivityLog{}, "activity_logs")
 	registry.Register(&models.Notification{}, "notifications")
+	registry.Register(&models.NotificationPreference{}, "notification_preferences")
+	registry.Register(&models.NotificationDelivery{}, "notification_deliveries")
 
 	// OBC Master & Production Order models (OBCMaster HARUS sebelum ProductionOrder untuk foreign key)
 	registry.Register(&models.OBCMaster{}, "obc_masters")
+	registry.Register(&models.OBCImportPreview{}, "obc_import_previews")
+	registry.Register(&models.ImportJob{}, "import_jobs")
+	registry.Register(&models.OBCImportBatch{}, "obc_import_batches")
+	registry.Register(&models.OBCImportBatchItem{}, "obc_import_batch_items")
+	registry.Register(&models.ProductionRule{}, "production_rules")
 	registry.Register(&models.ProductionOrder{}, "production_orders")
+	registry.Register(&models.PONumberSequence{}, "po_number_sequences")
 	registry.Register(&models.POStageTracking{}, "po_stage_trackings")
 	registry.Register(&models.KhazwalMaterialPreparation{}, "khazwal_material_preparations")
+	registry.Register(&models.KhazwalTintaItem{}, "khazwal_tinta_items")
+
+	// Machine & Cetak Print Job models (Machine HARUS sebelum PrintJob untuk foreign key)
+	registry.Register(&models.Machine{}, "machines")
+	registry.Register(&models.PrintJob{}, "print_jobs")
+	registry.Register(&models.PrintJobLog{}, "print_job_logs")
+	registry.Register(&models.PrintJobSummary{}, "print_job_summaries")
+
+	// Plat registry (setelah ProductionOrder untuk foreign key current_po_id)
+	registry.Register(&models.Plat{}, "plats")
+	registry.Register(&models.PlatCheckout{}, "plat_checkouts")
+
+	// Kertas blanko stock ledger
+	registry.Register(&models.KertasStock{}, "kertas_stocks")
+	registry.Register(&models.KertasStockMovement{}, "kertas_stock_movements")
+	registry.Register(&models.KertasReservation{}, "kertas_reservations")
+
+	// Tinta inventory
+	registry.Register(&models.TintaStock{}, "tinta_stocks")
+	registry.Register(&models.TintaStockMovement{}, "tinta_stock_movements")
+
+	// SAP integration outbox
+	registry.Register(&models.SAPOutboxEntry{}, "sap_outbox_entries")
+
+	// Evidence attachments (foto material dan foto waste)
+	registry.Register(&models.Attachment{}, "attachments")
+
+	// SLA antrian stage dan history breach
+	registry.Register(&models.SLAPolicy{}, "sla_policies")
+	registry.Register(&models.SLABreach{}, "sla_breaches")
 
 	// Khazwal Counting & Cutting models (Epic 2 & 3)
 	registry.Register(&counting.KhazwalCountingResult{}, "khazwal_counting_results")
 	registry.Register(&cutting.KhazwalCuttingResult{}, "khazwal_cutting_results")
 
+	// Verifikasi (QC) models
+	registry.Register(&verifikasi.VerificationLabel{}, "verification_labels")
+	registry.Register(&verifikasi.VerifikasiResult{}, "verifikasi_results")
+
+	// Khazanah Akhir models
+	registry.Register(&khazkhir.KhazkhirResult{}, "khazkhir_results")
+	registry.Register(&khazkhir.KhazkhirBox{}, "khazkhir_boxes")
+	registry.Register(&khazkhir.KhazkhirRim{}, "khazkhir_rims")
+	registry.Register(&khazkhir.Shipment{}, "khazkhir_shipments")
+
 	return registry
 }
 
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentHandler merupakan handler untuk evidence attachment (foto material dan foto waste)
+// yang mencakup upload multipart, detail, list per entity pemilik, dan hapus lampiran yang belum terhubung
+type AttachmentHandler struct {
+	attachmentService *services.AttachmentService
+}
+
+// NewAttachmentHandler membuat instance baru dari AttachmentHandler
+func NewAttachmentHandler(attachmentService *services.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// Upload meng-upload satu foto bukti yang kemudian dihubungkan saat finalize material prep
+// atau update hasil cutting
+// @route POST /api/attachments
+// @access STAFF_KHAZWAL, ADMIN, MANAGER
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	user, ok := h.getUser(c)
+	if !ok {
+		return
+	}
+
+	ownerType := models.AttachmentOwnerType(strings.ToUpper(c.PostForm("owner_type")))
+	if !models.IsValidAttachmentOwnerType(ownerType) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "owner_type harus MATERIAL_PREP atau CUTTING_WASTE",
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "File foto tidak ditemukan",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	attachment, err := h.attachmentService.Upload(ownerType, fileHeader, user.ID)
+	if err != nil {
+		h.handleError(c, err, "Gagal meng-upload foto bukti")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Foto bukti berhasil di-upload",
+		"data":    attachment,
+	})
+}
+
+// List mengambil foto bukti yang terhubung ke satu entity pemilik
+// @route GET /api/attachments?owner_type=MATERIAL_PREP&owner_id=1
+// @access STAFF_KHAZWAL, ADMIN, MANAGER
+func (h *AttachmentHandler) List(c *gin.Context) {
+	ownerType := models.AttachmentOwnerType(strings.ToUpper(c.Query("owner_type")))
+	ownerID, err := strconv.ParseUint(c.Query("owner_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "owner_id tidak valid",
+		})
+		return
+	}
+
+	attachments, err := h.attachmentService.ListByOwner(ownerType, ownerID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil foto bukti")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Foto bukti berhasil diambil",
+		"data":    attachments,
+	})
+}
+
+// Detail mengambil metadata satu foto bukti
+// @route GET /api/attachments/:id
+// @access STAFF_KHAZWAL, ADMIN, MANAGER
+func (h *AttachmentHandler) Detail(c *gin.Context) {
+	id, ok := h.parseAttachmentID(c)
+	if !ok {
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil foto bukti")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Foto bukti berhasil diambil",
+		"data":    attachment,
+	})
+}
+
+// Delete menghapus foto bukti yang belum terhubung ke material prep atau cutting
+// @route DELETE /api/attachments/:id
+// @access STAFF_KHAZWAL (pengunggah), ADMIN, MANAGER
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	user, ok := h.getUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := h.parseAttachmentID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(id, user.ID, user.IsAdmin()); err != nil {
+		h.handleError(c, err, "Gagal menghapus foto bukti")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Foto bukti berhasil dihapus",
+	})
+}
+
+// parseAttachmentID mengambil attachment ID dari path parameter
+func (h *AttachmentHandler) parseAttachmentID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID foto bukti tidak valid",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// getUser mengambil user yang sedang login dari context (dari auth middleware)
+func (h *AttachmentHandler) getUser(c *gin.Context) (*models.User, bool) {
+	userInterface, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User tidak terautentikasi",
+		})
+		return nil, false
+	}
+
+	user, ok := userInterface.(*models.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Error mengambil data user",
+		})
+		return nil, false
+	}
+	return user, true
+}
+
+// handleError memetakan error dari AttachmentService ke HTTP status code yang sesuai
+func (h *AttachmentHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrAttachmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrAttachmentUnsupportedType),
+		errors.Is(err, services.ErrAttachmentInvalidImage),
+		errors.Is(err, services.ErrAttachmentInvalidOwner):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrAttachmentForbidden):
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrAttachmentLocked):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
@@ -200,8 +200,7 @@ func (h *CetakHandler) GetDetail(c *gin.Context) {
 			KertasBlankoQuantity: prep.KertasBlankoQuantity,
 			KertasBlankoActual:   prep.KertasBlankoActual,
 			KertasBlankoVariance: prep.KertasBlankoVariance,
-			TintaRequirements:    prep.TintaRequirements,
-			TintaActual:          prep.TintaActual,
+			TintaItems:           prep.TintaItems,
 			MaterialPhotos:       prep.MaterialPhotos,
 			Notes:                prep.Notes,
 		}
@@ -300,8 +299,7 @@ type MaterialPrepDTO struct {
 	KertasBlankoQuantity int         `json:"kertas_blanko_quantity"`
 	KertasBlankoActual   *int        `json:"kertas_blanko_actual,omitempty"`
 	KertasBlankoVariance int         `json:"kertas_blanko_variance"`
-	TintaRequirements    interface{} `json:"tinta_requirements,omitempty"`
-	TintaActual          interface{} `json:"tinta_actual,omitempty"`
+	TintaItems           []services.TintaVariance `json:"tinta_items,omitempty"`
 	MaterialPhotos       []string    `json:"material_photos,omitempty"`
 	Notes                string      `json:"notes,omitempty"`
 }
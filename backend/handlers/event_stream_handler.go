@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventStreamHeartbeat merupakan interval komentar ping agar proxy tidak menutup koneksi idle
+const eventStreamHeartbeat = 25 * time.Second
+
+// EventStreamHandler merupakan handler untuk Server-Sent Events yang meneruskan domain event
+// (perubahan status PO, notifikasi baru, finalize) ke client sesuai topic yang diizinkan untuk role-nya
+type EventStreamHandler struct {
+	eventBus *services.EventBus
+}
+
+// NewEventStreamHandler membuat instance baru dari EventStreamHandler
+func NewEventStreamHandler(eventBus *services.EventBus) *EventStreamHandler {
+	return &EventStreamHandler{
+		eventBus: eventBus,
+	}
+}
+
+// Stream membuka koneksi SSE yang mengirim domain event hingga client disconnect, token expired, atau server shutdown
+// @route GET /api/events?topics=notifications,queue.cetak
+// @access Authenticated
+func (h *EventStreamHandler) Stream(c *gin.Context) {
+	userVal, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User tidak ditemukan",
+		})
+		return
+	}
+	user := userVal.(*models.User)
+
+	allowed := services.TopicsForRole(user.Role)
+	topics, err := parseEventTopics(c.Query("topics"), allowed)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+			"data":    gin.H{"allowed_topics": allowed},
+		})
+		return
+	}
+	if len(topics) == 0 {
+		topics = allowed
+	}
+
+	// Stream ditutup saat access token expired agar client refresh token lalu reconnect
+	var expired <-chan time.Time
+	if claimsVal, ok := c.Get("claims"); ok {
+		if claims, ok := claimsVal.(*services.JWTClaims); ok && claims.ExpiresAt != nil {
+			timer := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+			defer timer.Stop()
+			expired = timer.C
+		}
+	}
+
+	sub := h.eventBus.Subscribe(services.NewUserEventFilter(user.ID, user.Role, topics))
+	defer h.eventBus.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	writeSSE(c, "", "ready", gin.H{"topics": topics})
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-expired:
+			writeSSE(c, "", "token_expired", gin.H{"message": "Token sudah expired, silakan reconnect"})
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Subscriber diputus karena terlalu lambat atau server shutdown, client perlu memuat ulang data lalu reconnect
+				writeSSE(c, "", "resync", gin.H{"message": "Event terlewat, muat ulang data"})
+				return
+			}
+			writeSSE(c, fmt.Sprint(event.ID), string(event.Type), event)
+		}
+	}
+}
+
+// parseEventTopics memvalidasi daftar topic dari query string terhadap topic yang diizinkan untuk role
+func parseEventTopics(raw string, allowed []string) ([]string, error) {
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, topic := range allowed {
+		allowedSet[topic] = true
+	}
+
+	var topics []string
+	seen := make(map[string]bool)
+	for _, topic := range strings.Split(raw, ",") {
+		topic = strings.TrimSpace(topic)
+		if topic == "" || seen[topic] {
+			continue
+		}
+		if !allowedSet[topic] {
+			return nil, fmt.Errorf("Topic %s tidak diizinkan untuk role ini", topic)
+		}
+		seen[topic] = true
+		topics = append(topics, topic)
+	}
+	return topics, nil
+}
+
+// writeSSE menulis satu frame SSE lalu flush ke client
+func writeSSE(c *gin.Context, id, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	if id != "" {
+		fmt.Fprintf(c.Writer, "id: %s\n", id)
+	}
+	fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, payload)
+	c.Writer.Flush()
+}
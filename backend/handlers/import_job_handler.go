@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importJobStreamInterval merupakan interval polling progress untuk SSE stream import job
+const importJobStreamInterval = time.Second
+
+// ImportJobHandler merupakan handler untuk import OBC yang berjalan di background
+// yang mencakup upload file, polling/streaming progress, dan download hasil import
+type ImportJobHandler struct {
+	jobService *services.ImportJobService
+}
+
+// NewImportJobHandler membuat instance baru dari ImportJobHandler
+func NewImportJobHandler(jobService *services.ImportJobService) *ImportJobHandler {
+	return &ImportJobHandler{
+		jobService: jobService,
+	}
+}
+
+// Create mengupload file import OBC dan menjalankannya sebagai background job,
+// response langsung dikembalikan dengan job ID untuk polling progress
+// @route POST /api/obc/import-jobs
+// @access ADMIN, PPIC
+func (h *ImportJobHandler) Create(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "File tidak ditemukan",
+			"error":   "Parameter 'file' diperlukan untuk upload file import",
+		})
+		return
+	}
+	defer file.Close()
+
+	if !isSupportedImportFile(header.Filename) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Format file tidak valid",
+			"error":   "Hanya file Excel (.xlsx), CSV (.csv), TSV (.tsv), atau text export SAP (.txt) yang diperbolehkan",
+		})
+		return
+	}
+
+	// File dibaca penuh ke memory karena request multipart sudah selesai saat job diproses
+	content, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Gagal membaca file",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	autoGeneratePO := c.DefaultQuery("auto_generate_po", "false") == "true"
+
+	job, err := h.jobService.StartImportJob(content, header.Filename, autoGeneratePO, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal membuat import job")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": "Import job berhasil dibuat dan sedang diproses",
+		"data":    job,
+	})
+}
+
+// List mengambil list import job dengan filter status dan pagination
+// @route GET /api/obc/import-jobs
+// @access ADMIN, PPIC
+func (h *ImportJobHandler) List(c *gin.Context) {
+	var filters services.ImportJobFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.jobService.ListJobs(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil list import job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "List import job berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Detail mengambil status dan progress import job untuk polling
+// @route GET /api/obc/import-jobs/:id
+// @access ADMIN, PPIC
+func (h *ImportJobHandler) Detail(c *gin.Context) {
+	id, ok := h.parseJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobService.GetJob(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil detail import job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Detail import job berhasil diambil",
+		"data":    job,
+	})
+}
+
+// Stream mengirim progress import job via Server-Sent Events
+// sampai job selesai atau client menutup koneksi
+// @route GET /api/obc/import-jobs/:id/stream
+// @access ADMIN, PPIC
+func (h *ImportJobHandler) Stream(c *gin.Context) {
+	id, ok := h.parseJobID(c)
+	if !ok {
+		return
+	}
+
+	// Pastikan job ada sebelum membuka stream agar client mendapat 404 yang jelas
+	if _, err := h.jobService.GetJob(id); err != nil {
+		h.handleError(c, err, "Gagal mengambil detail import job")
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(importJobStreamInterval)
+	defer ticker.Stop()
+
+	first := true
+	c.Stream(func(w io.Writer) bool {
+		if !first {
+			select {
+			case <-c.Request.Context().Done():
+				return false
+			case <-ticker.C:
+			}
+		}
+		first = false
+
+		job, err := h.jobService.GetJob(id)
+		if err != nil {
+			c.SSEvent("error", gin.H{"message": err.Error()})
+			return false
+		}
+
+		if job.IsFinished() {
+			c.SSEvent("done", job)
+			return false
+		}
+		c.SSEvent("progress", job)
+		return true
+	})
+}
+
+// Result mengunduh ImportResult final dari job yang sudah selesai sebagai file JSON
+// @route GET /api/obc/import-jobs/:id/result
+// @access ADMIN, PPIC
+func (h *ImportJobHandler) Result(c *gin.Context) {
+	id, ok := h.parseJobID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.jobService.GetJobResult(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil hasil import job")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=import-job-%d-result.json", id))
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Hasil import job berhasil diambil",
+		"data":    result,
+	})
+}
+
+// parseJobID mengambil import job ID dari path parameter
+func (h *ImportJobHandler) parseJobID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID import job tidak valid",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleError memetakan error import job ke HTTP status code
+func (h *ImportJobHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrImportJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrImportJobNotFinished),
+		errors.Is(err, services.ErrImportJobFailed):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrImportJobUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
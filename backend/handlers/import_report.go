@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseImportReportQuery membaca query report (xlsx/csv) dan report_scope (failed/all),
+// requested bernilai false jika client tidak meminta file report
+func parseImportReportQuery(c *gin.Context) (opts services.ImportReportOptions, requested bool, ok bool) {
+	format := c.Query("report")
+	if format == "" {
+		return opts, false, true
+	}
+
+	opts, err := services.ParseImportReportOptions(format, c.Query("report_scope"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter report tidak valid",
+			"error":   err.Error(),
+		})
+		return opts, true, false
+	}
+	return opts, true, true
+}
+
+// sendImportReport mengirim file report hasil import sebagai attachment,
+// ringkasan hasil import dikirim lewat header X-Import-*
+func sendImportReport(c *gin.Context, report *services.ImportReport, total, success, failed int) {
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", report.FileName))
+	c.Header("X-Import-Total-Rows", strconv.Itoa(total))
+	c.Header("X-Import-Success-Count", strconv.Itoa(success))
+	c.Header("X-Import-Failed-Count", strconv.Itoa(failed))
+	c.Data(http.StatusOK, report.ContentType, report.Content)
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KertasStockHandler merupakan handler untuk stock ledger kertas blanko
+// yang mencakup saldo stok, penerimaan, retur, dan riwayat mutasi
+type KertasStockHandler struct {
+	kertasStockService *services.KertasStockService
+}
+
+// NewKertasStockHandler membuat instance baru dari KertasStockHandler
+func NewKertasStockHandler(kertasStockService *services.KertasStockService) *KertasStockHandler {
+	return &KertasStockHandler{
+		kertasStockService: kertasStockService,
+	}
+}
+
+// List mengambil saldo stok semua jenis kertas dengan filter search dan low stock
+// @route GET /api/kertas-stocks
+// @access STAFF_KHAZWAL, PPIC, ADMIN, MANAGER
+func (h *KertasStockHandler) List(c *gin.Context) {
+	var filters services.KertasStockFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.kertasStockService.ListStocks(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil stok kertas")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Stok kertas berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Detail mengambil saldo stok satu jenis kertas
+// @route GET /api/kertas-stocks/:id
+// @access STAFF_KHAZWAL, PPIC, ADMIN, MANAGER
+func (h *KertasStockHandler) Detail(c *gin.Context) {
+	id, ok := h.parseStockID(c)
+	if !ok {
+		return
+	}
+
+	stock, err := h.kertasStockService.GetStock(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil detail stok kertas")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Detail stok kertas berhasil diambil",
+		"data":    stock,
+	})
+}
+
+// Movements mengambil riwayat mutasi stok satu jenis kertas
+// @route GET /api/kertas-stocks/:id/movements
+// @access STAFF_KHAZWAL, PPIC, ADMIN, MANAGER
+func (h *KertasStockHandler) Movements(c *gin.Context) {
+	id, ok := h.parseStockID(c)
+	if !ok {
+		return
+	}
+
+	var filters services.KertasMovementFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.kertasStockService.ListMovements(id, filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil riwayat mutasi stok kertas")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Riwayat mutasi stok kertas berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Create mendaftarkan jenis kertas baru ke stock ledger
+// @route POST /api/kertas-stocks
+// @access ADMIN
+func (h *KertasStockHandler) Create(c *gin.Context) {
+	var req services.CreateKertasStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	stock, err := h.kertasStockService.CreateStock(req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mendaftarkan jenis kertas")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Jenis kertas berhasil didaftarkan",
+		"data":    stock,
+	})
+}
+
+// Update mengupdate deskripsi, satuan, dan threshold low stock jenis kertas
+// @route PUT /api/kertas-stocks/:id
+// @access ADMIN, MANAGER
+func (h *KertasStockHandler) Update(c *gin.Context) {
+	id, ok := h.parseStockID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateKertasStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	stock, err := h.kertasStockService.UpdateStock(id, req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengupdate jenis kertas")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Jenis kertas berhasil diupdate",
+		"data":    stock,
+	})
+}
+
+// Receive mencatat penerimaan kertas ke gudang
+// @route POST /api/kertas-stocks/:id/receipts
+// @access STAFF_KHAZWAL, ADMIN, MANAGER
+func (h *KertasStockHandler) Receive(c *gin.Context) {
+	id, ok := h.parseStockID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.ReceiveKertasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	stock, err := h.kertasStockService.ReceiveStock(id, req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mencatat penerimaan kertas")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Penerimaan kertas berhasil dicatat",
+		"data":    stock,
+	})
+}
+
+// Return mengembalikan sisa kertas yang tidak terpakai dari PO ke stok
+// @route POST /api/kertas-stocks/returns
+// @access STAFF_KHAZWAL, ADMIN, MANAGER
+func (h *KertasStockHandler) Return(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.ReturnKertasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	stock, err := h.kertasStockService.ReturnStock(req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mencatat retur kertas")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Retur kertas berhasil dicatat",
+		"data":    stock,
+	})
+}
+
+// parseStockID mengambil stok kertas ID dari path parameter
+func (h *KertasStockHandler) parseStockID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID stok kertas tidak valid",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleError memetakan error dari KertasStockService ke HTTP status code yang sesuai
+func (h *KertasStockHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrKertasStockNotFound),
+		errors.Is(err, services.ErrKertasReservationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrKertasPaperTypeExists),
+		errors.Is(err, services.ErrKertasReturnExceeded),
+		errors.Is(err, services.ErrKertasStockInsufficient):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidKertasQuantity),
+		errors.Is(err, services.ErrInvalidKertasThreshold):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
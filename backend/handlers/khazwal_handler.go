@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"sirine-go/backend/models"
 	"sirine-go/backend/services"
 	"strconv"
 
@@ -201,7 +203,17 @@ func (h *KhazwalHandler) StartPrep(c *gin.Context) {
 			return
 		}
 
-		if err == gorm.ErrInvalidData {
+		// Stok kertas blanko tidak cukup untuk direservasi atau jenis kertas belum terdaftar
+		if errors.Is(err, services.ErrKertasStockInsufficient) || errors.Is(err, services.ErrKertasPaperTypeNotFound) {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+
+		var transitionErr *models.InvalidPOTransitionError
+		if err == gorm.ErrInvalidData || errors.As(err, &transitionErr) {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"success": false,
 				"message": "PO tidak dalam status yang valid untuk dimulai",
@@ -298,6 +310,26 @@ func (h *KhazwalHandler) ConfirmPlat(c *gin.Context) {
 			return
 		}
 
+		// Kode plat belum terdaftar di registry plat
+		if errors.Is(err, services.ErrPlatNotRegistered) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+
+		// Plat tidak bisa diambil menurut registry plat
+		if errors.Is(err, services.ErrPlatCheckedOut) ||
+			errors.Is(err, services.ErrPlatRetired) ||
+			errors.Is(err, services.ErrPlatInMaintenance) {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Gagal mengkonfirmasi plat",
@@ -438,7 +470,7 @@ func (h *KhazwalHandler) UpdateTinta(c *gin.Context) {
 	}
 
 	// Call service untuk update tinta
-	err = h.khazwalService.UpdateTinta(prepID, req.TintaActual, userID)
+	result, err := h.khazwalService.UpdateTinta(prepID, req.TintaActual, userID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -456,6 +488,23 @@ func (h *KhazwalHandler) UpdateTinta(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, services.ErrInvalidTintaUsage) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+
+		// Saldo tinta di inventory tidak cukup untuk pemakaian
+		if errors.Is(err, services.ErrTintaStockInsufficient) {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Gagal mengupdate tinta",
@@ -467,10 +516,11 @@ func (h *KhazwalHandler) UpdateTinta(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Tinta berhasil diupdate",
+		"data":    result,
 	})
 }
 
-// Finalize menyelesaikan material preparation dengan upload photos dan notes
+// Finalize menyelesaikan material preparation dengan foto bukti dan notes
 // @route POST /api/khazwal/material-prep/:id/finalize
 // @access STAFF_KHAZWAL, ADMIN, MANAGER
 func (h *KhazwalHandler) Finalize(c *gin.Context) {
@@ -517,8 +567,20 @@ func (h *KhazwalHandler) Finalize(c *gin.Context) {
 	}
 
 	// Call service untuk finalize material prep
-	result, err := h.khazwalService.FinalizeMaterialPrep(prepID, req.Photos, req.Notes, userID)
+	result, err := h.khazwalService.FinalizeMaterialPrep(prepID, req.AttachmentIDs, req.Notes, userID)
 	if err != nil {
+		// Foto bukti tidak ditemukan, bukan foto material prep, atau sudah terhubung ke data lain
+		if errors.Is(err, services.ErrAttachmentNotFound) ||
+			errors.Is(err, services.ErrAttachmentInvalidOwner) ||
+			errors.Is(err, services.ErrAttachmentAlreadyLinked) ||
+			errors.Is(err, services.ErrAttachmentTooMany) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
@@ -527,6 +589,25 @@ func (h *KhazwalHandler) Finalize(c *gin.Context) {
 			return
 		}
 
+		// Saldo kertas blanko di stock ledger tidak cukup untuk pemakaian actual atau jenis kertas belum terdaftar
+		if errors.Is(err, services.ErrKertasStockInsufficient) || errors.Is(err, services.ErrKertasPaperTypeNotFound) {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+
+		var transitionErr *models.InvalidPOTransitionError
+		if errors.As(err, &transitionErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": "Status PO tidak mengizinkan material preparation diselesaikan",
+				"error":   err.Error(),
+			})
+			return
+		}
+
 		if err == gorm.ErrInvalidData {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"success": false,
@@ -618,22 +699,16 @@ type UpdateKertasRequest struct {
 }
 
 // UpdateTintaRequest merupakan DTO untuk update tinta request
+// yang berisi jumlah actual per warna beserta satuan dan nomor batch
 type UpdateTintaRequest struct {
-	TintaActual []TintaItem `json:"tinta_actual" binding:"required"`
-}
-
-// TintaItem merupakan struct untuk individual tinta item
-type TintaItem struct {
-	Color    string  `json:"color" binding:"required"`
-	Quantity float64 `json:"quantity" binding:"required,min=0"`
-	Checked  bool    `json:"checked"`
+	TintaActual []services.TintaActualInput `json:"tinta_actual" binding:"required,min=1,dive"`
 }
 
 // FinalizeRequest merupakan DTO untuk finalize material preparation request
-// yang mencakup optional photos (base64) dan notes
+// yang mencakup optional ID foto bukti hasil upload ke /api/attachments dan notes
 type FinalizeRequest struct {
-	Photos []string `json:"photos"`
-	Notes  string   `json:"notes"`
+	AttachmentIDs []uint64 `json:"attachment_ids"`
+	Notes         string   `json:"notes"`
 }
 
 // FinalizeResponse merupakan DTO untuk finalize material preparation response
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MachineHandler merupakan handler untuk registry mesin produksi
+// yang mencakup CRUD mesin cetak/potong dan manajemen status mesin
+type MachineHandler struct {
+	machineService *services.MachineService
+}
+
+// NewMachineHandler membuat instance baru dari MachineHandler
+func NewMachineHandler(machineService *services.MachineService) *MachineHandler {
+	return &MachineHandler{
+		machineService: machineService,
+	}
+}
+
+// List mengambil list mesin dengan filter tipe, status, dan search
+// @route GET /api/admin/machines
+// @access ADMIN, MANAGER
+func (h *MachineHandler) List(c *gin.Context) {
+	var filters services.MachineFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.machineService.ListMachines(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Gagal mengambil list mesin",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "List mesin berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Detail mengambil detail mesin berdasarkan ID
+// @route GET /api/admin/machines/:id
+// @access ADMIN, MANAGER
+func (h *MachineHandler) Detail(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID mesin tidak valid",
+		})
+		return
+	}
+
+	machine, err := h.machineService.GetMachine(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil detail mesin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Detail mesin berhasil diambil",
+		"data":    machine,
+	})
+}
+
+// Create mendaftarkan mesin baru ke registry
+// @route POST /api/admin/machines
+// @access ADMIN
+func (h *MachineHandler) Create(c *gin.Context) {
+	var req services.CreateMachineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	machine, err := h.machineService.CreateMachine(req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mendaftarkan mesin")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Mesin berhasil didaftarkan",
+		"data":    machine,
+	})
+}
+
+// Update mengupdate data mesin
+// @route PUT /api/admin/machines/:id
+// @access ADMIN
+func (h *MachineHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID mesin tidak valid",
+		})
+		return
+	}
+
+	var req services.UpdateMachineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	machine, err := h.machineService.UpdateMachine(id, req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengupdate mesin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Mesin berhasil diupdate",
+		"data":    machine,
+	})
+}
+
+// UpdateStatus mengubah status operasional mesin
+// @route PATCH /api/admin/machines/:id/status
+// @access ADMIN, MANAGER
+func (h *MachineHandler) UpdateStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID mesin tidak valid",
+		})
+		return
+	}
+
+	var req services.UpdateMachineStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	machine, err := h.machineService.UpdateMachineStatus(id, req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengubah status mesin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Status mesin berhasil diubah",
+		"data":    machine,
+	})
+}
+
+// Delete menghapus mesin dari registry
+// @route DELETE /api/admin/machines/:id
+// @access ADMIN
+func (h *MachineHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID mesin tidak valid",
+		})
+		return
+	}
+
+	if err := h.machineService.DeleteMachine(id); err != nil {
+		h.handleError(c, err, "Gagal menghapus mesin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Mesin berhasil dihapus",
+	})
+}
+
+// handleError memetakan error dari MachineService ke HTTP status code yang sesuai
+func (h *MachineHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrMachineNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrMachineCodeExists), errors.Is(err, services.ErrMachineBusy):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidMachineType),
+		errors.Is(err, services.ErrInvalidMachineStatus),
+		errors.Is(err, services.ErrInvalidCapacity):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
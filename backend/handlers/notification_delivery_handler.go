@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationDeliveryHandler merupakan handler untuk preferensi channel notifikasi user
+// dan monitoring delivery queue email dan webhook
+type NotificationDeliveryHandler struct {
+	deliveryService *services.NotificationDeliveryService
+}
+
+// NewNotificationDeliveryHandler membuat instance baru dari NotificationDeliveryHandler
+func NewNotificationDeliveryHandler(deliveryService *services.NotificationDeliveryService) *NotificationDeliveryHandler {
+	return &NotificationDeliveryHandler{
+		deliveryService: deliveryService,
+	}
+}
+
+// GetPreferences mengambil preferensi channel notifikasi milik user yang login
+// @route GET /api/notifications/preferences
+// @access Authenticated
+func (h *NotificationDeliveryHandler) GetPreferences(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	preference, err := h.deliveryService.GetPreference(userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil preferensi notifikasi")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Preferensi notifikasi berhasil diambil",
+		"data":    preference,
+	})
+}
+
+// UpdatePreferences menyimpan preferensi channel notifikasi milik user yang login
+// @route PUT /api/notifications/preferences
+// @access Authenticated
+func (h *NotificationDeliveryHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateNotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Data tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	preference, err := h.deliveryService.UpdatePreference(userID, req)
+	if err != nil {
+		h.handleError(c, err, "Gagal menyimpan preferensi notifikasi")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Preferensi notifikasi berhasil disimpan",
+		"data":    preference,
+	})
+}
+
+// SendTest mengirim notifikasi uji coba ke email dan webhook yang aktif
+// @route POST /api/notifications/preferences/test
+// @access Authenticated
+func (h *NotificationDeliveryHandler) SendTest(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.deliveryService.SendTest(userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengirim notifikasi uji coba")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": "Notifikasi uji coba dijadwalkan untuk dikirim",
+		"data":    deliveries,
+	})
+}
+
+// List mengambil list pengiriman dengan filter status, channel, dan user
+// @route GET /api/admin/notification-deliveries
+// @access ADMIN, MANAGER
+func (h *NotificationDeliveryHandler) List(c *gin.Context) {
+	var filters services.NotificationDeliveryFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.deliveryService.ListDeliveries(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil pengiriman notifikasi")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Pengiriman notifikasi berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Retry menjadwalkan ulang pengiriman yang gagal untuk segera dikirim
+// @route POST /api/admin/notification-deliveries/:id/retry
+// @access ADMIN
+func (h *NotificationDeliveryHandler) Retry(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID pengiriman notifikasi tidak valid",
+		})
+		return
+	}
+
+	delivery, err := h.deliveryService.RetryDelivery(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal menjadwalkan ulang pengiriman notifikasi")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Pengiriman notifikasi dijadwalkan ulang",
+		"data":    delivery,
+	})
+}
+
+// handleError memetakan error dari NotificationDeliveryService ke HTTP status code yang sesuai
+func (h *NotificationDeliveryHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrNotificationDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrNotificationDeliveryNotRetryable):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrWebhookURLInvalid),
+		errors.Is(err, services.ErrWebhookHostNotAllowed),
+		errors.Is(err, services.ErrWebhookAddressBlocked),
+		errors.Is(err, services.ErrNotificationChannelNotEnabled),
+		errors.Is(err, services.ErrNotificationEmailMissing),
+		errors.Is(err, services.ErrNotificationCategoryNotMutable):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
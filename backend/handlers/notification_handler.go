@@ -5,6 +5,7 @@ import (
 	"sirine-go/backend/models"
 	"sirine-go/backend/services"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,7 +22,7 @@ func NewNotificationHandler(service *services.NotificationService) *Notification
 }
 
 // GetUserNotifications mengambil semua notifikasi user
-// GET /api/notifications?unread_only=true
+// GET /api/notifications?unread_only=true&category=HANDOVER
 func (h *NotificationHandler) GetUserNotifications(c *gin.Context) {
 	// Get user dari context (set oleh auth middleware)
 	userID, exists := c.Get("user_id")
@@ -33,10 +34,18 @@ func (h *NotificationHandler) GetUserNotifications(c *gin.Context) {
 		return
 	}
 
-	// Parse query param untuk filter unread only
+	// Parse query param untuk filter unread only dan kategori
 	unreadOnly := c.Query("unread_only") == "true"
+	category := models.NotificationCategory(strings.ToUpper(c.Query("category")))
+	if category != "" && !category.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Kategori notifikasi tidak valid",
+		})
+		return
+	}
 
-	notifications, err := h.service.GetUserNotifications(userID.(uint64), unreadOnly)
+	notifications, err := h.service.GetUserNotifications(userID.(uint64), unreadOnly, category)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -221,6 +230,65 @@ func (h *NotificationHandler) CreateNotification(c *gin.Context) {
 	})
 }
 
+// BroadcastNotification mengirim notifikasi ke semua user aktif dengan role tertentu,
+// opsional dibatasi ke satu departemen
+// POST /api/notifications/broadcast
+func (h *NotificationHandler) BroadcastNotification(c *gin.Context) {
+	var req struct {
+		Roles      []models.UserRole           `json:"roles" binding:"required,min=1"`
+		Department models.Department           `json:"department"`
+		Title      string                      `json:"title" binding:"required,max=255"`
+		Message    string                      `json:"message" binding:"required"`
+		Type       models.NotificationType     `json:"type"`
+		Category   models.NotificationCategory `json:"category"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Data tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Type == "" {
+		req.Type = models.NotificationInfo
+	}
+	if req.Category != "" && !req.Category.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Kategori notifikasi tidak valid",
+		})
+		return
+	}
+
+	count, err := h.service.NotifyAudiences(services.NotificationContent{
+		Title:    req.Title,
+		Message:  req.Message,
+		Type:     req.Type,
+		Category: req.Category,
+	}, services.NotificationAudience{
+		Roles:      req.Roles,
+		Department: req.Department,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Gagal mengirim notifikasi",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Notifikasi berhasil dikirim",
+		"data": gin.H{
+			"recipients": count,
+		},
+	})
+}
+
 // DeleteNotification menghapus notifikasi
 // DELETE /api/notifications/:id
 func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
@@ -1,15 +1,20 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"net/http"
+	"path/filepath"
 	"sirine-go/backend/services"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 // OBCHandler merupakan handler untuk OBC Master endpoints
-// yang mencakup import Excel, list, detail, dan generate PO
+// yang mencakup import Excel/CSV, list, detail, dan generate PO
 type OBCHandler struct {
 	obcService *services.OBCImportService
 }
@@ -21,8 +26,9 @@ func NewOBCHandler(obcService *services.OBCImportService) *OBCHandler {
 	}
 }
 
-// Import melakukan upload dan import Excel file OBC Master
-// dengan parsing, validation, dan optional auto PO generation
+// Import melakukan upload dan import file OBC Master (Excel, CSV, TSV, atau text export SAP)
+// dengan parsing, validation, dan optional auto PO generation,
+// gunakan query dry_run=true untuk preview tanpa menyimpan data
 // @route POST /api/obc/import
 // @access ADMIN, PPIC
 func (h *OBCHandler) Import(c *gin.Context) {
@@ -32,18 +38,18 @@ func (h *OBCHandler) Import(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"message": "File tidak ditemukan",
-			"error":   "Parameter 'file' diperlukan untuk upload Excel",
+			"error":   "Parameter 'file' diperlukan untuk upload file import",
 		})
 		return
 	}
 	defer file.Close()
 
 	// Validate file extension
-	if header.Header.Get("Content-Type") != "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" {
+	if !isSupportedImportFile(header.Filename) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"message": "Format file tidak valid",
-			"error":   "Hanya file Excel (.xlsx) yang diperbolehkan",
+			"error":   "Hanya file Excel (.xlsx), CSV (.csv), TSV (.tsv), atau text export SAP (.txt) yang diperbolehkan",
 		})
 		return
 	}
@@ -51,17 +57,74 @@ func (h *OBCHandler) Import(c *gin.Context) {
 	// Parse auto_generate_po flag (default: false)
 	autoGeneratePO := c.DefaultQuery("auto_generate_po", "false") == "true"
 
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	// Dry-run: parse dan bandingkan dengan data existing tanpa menyimpan ke obc_masters
+	if c.DefaultQuery("dry_run", "false") == "true" {
+		preview, err := h.obcService.PreviewImport(file, header.Filename, autoGeneratePO, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Gagal preview file import",
+				"error":   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Preview import berhasil, gunakan token untuk commit",
+			"data":    preview,
+		})
+		return
+	}
+
+	// Optional: kembalikan file asli beserta kolom error (query report=xlsx|csv)
+	reportOpts, wantReport, ok := parseImportReportQuery(c)
+	if !ok {
+		return
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Gagal membaca file",
+			"error":   err.Error(),
+		})
+		return
+	}
+
 	// Call service untuk import
-	result, err := h.obcService.ImportFromExcel(file, autoGeneratePO)
+	result, err := h.obcService.ImportFromFile(bytes.NewReader(content), header.Filename, autoGeneratePO, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"message": "Gagal import Excel file",
+			"message": "Gagal import file",
 			"error":   err.Error(),
 		})
 		return
 	}
 
+	if wantReport {
+		report, err := h.obcService.BuildFailedRowsReport(content, header.Filename, result.FailedRows, reportOpts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Import selesai tetapi gagal membuat report",
+				"error":   err.Error(),
+				"data":    result,
+			})
+			return
+		}
+		c.Header("X-Import-Batch-ID", strconv.FormatUint(result.BatchID, 10))
+		sendImportReport(c, report, result.TotalRows, result.SuccessCount, result.FailedCount)
+		return
+	}
+
 	// Return result dengan detail success/failed rows
 	statusCode := http.StatusOK
 	message := "Import berhasil"
@@ -74,6 +137,8 @@ func (h *OBCHandler) Import(c *gin.Context) {
 		"success": result.FailedCount == 0,
 		"message": message,
 		"data": gin.H{
+			"source_format":  result.SourceFormat,
+			"batch_id":       result.BatchID,
 			"total_rows":     result.TotalRows,
 			"success_count":  result.SuccessCount,
 			"failed_count":   result.FailedCount,
@@ -86,6 +151,69 @@ func (h *OBCHandler) Import(c *gin.Context) {
 	})
 }
 
+// CommitImport meng-commit batch import yang sudah di-preview berdasarkan token
+// @route POST /api/obc/import/commit
+// @access ADMIN, PPIC
+func (h *OBCHandler) CommitImport(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Token preview wajib diisi",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.obcService.CommitPreview(req.Token, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImportPreviewNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+		case errors.Is(err, services.ErrImportPreviewCommitted):
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+		case errors.Is(err, services.ErrImportPreviewExpired):
+			c.JSON(http.StatusGone, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Gagal commit import",
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	statusCode := http.StatusOK
+	message := "Commit import berhasil"
+	if result.FailedCount > 0 {
+		statusCode = http.StatusPartialContent
+		message = "Commit import selesai dengan beberapa error"
+	}
+
+	c.JSON(statusCode, gin.H{
+		"success": result.FailedCount == 0,
+		"message": message,
+		"data":    result,
+	})
+}
+
 // List mengambil list OBC Masters dengan pagination dan filter
 // untuk display table atau dropdown selection
 // @route GET /api/obc
@@ -197,6 +325,17 @@ func (h *OBCHandler) Detail(c *gin.Context) {
 		return
 	}
 
+	// Resolve production rule OBC untuk total dengan buffer
+	rule, err := h.obcService.ResolveProductionRule(obc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Gagal mengambil production rule OBC",
+			"error":   err.Error(),
+		})
+		return
+	}
+
 	// Transform ke DTO dengan full details
 	var obcDate, dueDate, createdOn string
 	if obc.OBCDate != nil {
@@ -267,7 +406,7 @@ func (h *OBCHandler) Detail(c *gin.Context) {
 		ProductionOrders:     poItems,
 		TotalPOs:             len(poItems),
 		TotalPOQuantity:      obc.GetTotalPOQuantity(),
-		TotalWithBuffer:      obc.CalculateTotalWithBuffer(),
+		TotalWithBuffer:      obc.CalculateTotalWithBuffer(rule),
 		IsPersonalized:       obc.IsPersonalized(),
 	}
 
@@ -409,3 +548,12 @@ type POSummaryDTO struct {
 	DueDate         string `json:"due_date"`
 	CreatedAt       string `json:"created_at"`
 }
+
+// isSupportedImportFile memeriksa ekstensi file import OBC yang didukung
+func isSupportedImportFile(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".xlsx", ".csv", ".tsv", ".txt":
+		return true
+	}
+	return false
+}
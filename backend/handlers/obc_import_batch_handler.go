@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OBCImportBatchHandler merupakan handler untuk riwayat import OBC
+// yang mencakup list batch, detail hasil per row, dan rollback batch
+type OBCImportBatchHandler struct {
+	batchService *services.OBCImportBatchService
+}
+
+// NewOBCImportBatchHandler membuat instance baru dari OBCImportBatchHandler
+func NewOBCImportBatchHandler(batchService *services.OBCImportBatchService) *OBCImportBatchHandler {
+	return &OBCImportBatchHandler{
+		batchService: batchService,
+	}
+}
+
+// List mengambil riwayat import batch dengan filter status, sumber, hash file, dan uploader
+// @route GET /api/obc/import-batches
+// @access ADMIN, PPIC
+func (h *OBCImportBatchHandler) List(c *gin.Context) {
+	var filters services.ImportBatchFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.batchService.ListBatches(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil riwayat import")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Riwayat import berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Detail mengambil detail import batch beserta hasil per row
+// @route GET /api/obc/import-batches/:id
+// @access ADMIN, PPIC
+func (h *OBCImportBatchHandler) Detail(c *gin.Context) {
+	id, ok := h.parseBatchID(c)
+	if !ok {
+		return
+	}
+
+	batch, err := h.batchService.GetBatch(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil detail import batch")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Detail import batch berhasil diambil",
+		"data":    batch,
+	})
+}
+
+// Rollback me-revert OBC Master yang diubah batch dan menghapus PO yang di-generate,
+// hanya selama belum ada PO yang mulai material prep
+// @route POST /api/obc/import-batches/:id/rollback
+// @access ADMIN, PPIC
+func (h *OBCImportBatchHandler) Rollback(c *gin.Context) {
+	id, ok := h.parseBatchID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Alasan rollback wajib diisi",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.batchService.RollbackBatch(id, userID, req.Reason)
+	if err != nil {
+		h.handleError(c, err, "Gagal rollback import batch")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Rollback import batch berhasil",
+		"data":    result,
+	})
+}
+
+// parseBatchID mengambil import batch ID dari path parameter
+func (h *OBCImportBatchHandler) parseBatchID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID import batch tidak valid",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleError memetakan error import batch ke HTTP status code
+func (h *OBCImportBatchHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrImportBatchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrImportBatchRolledBack),
+		errors.Is(err, services.ErrImportBatchSuperseded),
+		errors.Is(err, services.ErrImportBatchMaterialPrepStarted):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrRollbackReasonRequired):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
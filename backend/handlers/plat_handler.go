@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlatHandler merupakan handler untuk registry plat cetak
+// yang mencakup CRUD plat, status siklus hidup, pengembalian plat, dan riwayat pemakaian
+type PlatHandler struct {
+	platService *services.PlatService
+}
+
+// NewPlatHandler membuat instance baru dari PlatHandler
+func NewPlatHandler(platService *services.PlatService) *PlatHandler {
+	return &PlatHandler{
+		platService: platService,
+	}
+}
+
+// List mengambil list plat dengan filter status, lokasi penyimpanan, dan search
+// @route GET /api/plats
+// @access STAFF_KHAZWAL, OPERATOR_CETAK, ADMIN, MANAGER
+func (h *PlatHandler) List(c *gin.Context) {
+	var filters services.PlatFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.platService.ListPlats(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil list plat")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "List plat berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Detail mengambil detail plat beserta PO yang sedang memakai
+// @route GET /api/plats/:id
+// @access STAFF_KHAZWAL, OPERATOR_CETAK, ADMIN, MANAGER
+func (h *PlatHandler) Detail(c *gin.Context) {
+	id, ok := h.parsePlatID(c)
+	if !ok {
+		return
+	}
+
+	plat, err := h.platService.GetPlat(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil detail plat")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Detail plat berhasil diambil",
+		"data":    plat,
+	})
+}
+
+// History mengambil riwayat pengambilan dan pengembalian plat per PO
+// @route GET /api/plats/:id/history
+// @access STAFF_KHAZWAL, OPERATOR_CETAK, ADMIN, MANAGER
+func (h *PlatHandler) History(c *gin.Context) {
+	id, ok := h.parsePlatID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	result, err := h.platService.ListCheckouts(id, page, perPage)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil riwayat plat")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Riwayat plat berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Return mengembalikan plat ke penyimpanan setelah selesai dipakai
+// @route POST /api/plats/:id/return
+// @access STAFF_KHAZWAL, OPERATOR_CETAK, ADMIN, MANAGER
+func (h *PlatHandler) Return(c *gin.Context) {
+	id, ok := h.parsePlatID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.ReturnPlatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	plat, err := h.platService.ReturnPlat(id, req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengembalikan plat")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Plat berhasil dikembalikan",
+		"data":    plat,
+	})
+}
+
+// Create mendaftarkan plat baru ke registry
+// @route POST /api/admin/plats
+// @access ADMIN
+func (h *PlatHandler) Create(c *gin.Context) {
+	var req services.CreatePlatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	plat, err := h.platService.CreatePlat(req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mendaftarkan plat")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Plat berhasil didaftarkan",
+		"data":    plat,
+	})
+}
+
+// Update mengupdate data plat
+// @route PUT /api/admin/plats/:id
+// @access ADMIN
+func (h *PlatHandler) Update(c *gin.Context) {
+	id, ok := h.parsePlatID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdatePlatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	plat, err := h.platService.UpdatePlat(id, req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengupdate plat")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Plat berhasil diupdate",
+		"data":    plat,
+	})
+}
+
+// UpdateStatus mengubah status plat (AVAILABLE, MAINTENANCE, RETIRED)
+// @route PATCH /api/admin/plats/:id/status
+// @access ADMIN, MANAGER
+func (h *PlatHandler) UpdateStatus(c *gin.Context) {
+	id, ok := h.parsePlatID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdatePlatStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	plat, err := h.platService.UpdatePlatStatus(id, req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengubah status plat")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Status plat berhasil diubah",
+		"data":    plat,
+	})
+}
+
+// parsePlatID mengambil plat ID dari path parameter
+func (h *PlatHandler) parsePlatID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID plat tidak valid",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleError memetakan error dari PlatService ke HTTP status code yang sesuai
+func (h *PlatHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrPlatNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrPlatCodeExists),
+		errors.Is(err, services.ErrPlatInUse),
+		errors.Is(err, services.ErrPlatCheckedOut),
+		errors.Is(err, services.ErrPlatRetired),
+		errors.Is(err, services.ErrPlatInMaintenance),
+		errors.Is(err, services.ErrPlatNotCheckedOut):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidPlatStatus),
+		errors.Is(err, services.ErrInvalidPlatCondition),
+		errors.Is(err, services.ErrInvalidImpressions):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PrintJobHandler merupakan handler untuk workflow print job di Unit Cetak
+// yang mencakup start, pause, resume, progress logging, dan finalize
+type PrintJobHandler struct {
+	printJobService *services.PrintJobService
+}
+
+// NewPrintJobHandler membuat instance baru dari PrintJobHandler
+func NewPrintJobHandler(printJobService *services.PrintJobService) *PrintJobHandler {
+	return &PrintJobHandler{
+		printJobService: printJobService,
+	}
+}
+
+// PauseResumeRequest merupakan request body untuk pause dan resume print job
+type PauseResumeRequest struct {
+	Notes string `json:"notes"`
+}
+
+// Start memulai print job untuk PO dengan assignment mesin dan operator
+// @route POST /api/cetak/po/:id/start
+// @access OPERATOR_CETAK, SUPERVISOR_CETAK, ADMIN, MANAGER
+func (h *PrintJobHandler) Start(c *gin.Context) {
+	poID, ok := h.parseID(c, "ID PO tidak valid")
+	if !ok {
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.StartPrintJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	job, err := h.printJobService.StartPrintJob(poID, req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal memulai print job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Print job berhasil dimulai",
+		"data":    job,
+	})
+}
+
+// Pause menghentikan sementara print job yang sedang berjalan
+// @route POST /api/cetak/jobs/:id/pause
+// @access OPERATOR_CETAK, SUPERVISOR_CETAK, ADMIN, MANAGER
+func (h *PrintJobHandler) Pause(c *gin.Context) {
+	jobID, ok := h.parseID(c, "ID print job tidak valid")
+	if !ok {
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req PauseResumeRequest
+	_ = c.ShouldBindJSON(&req)
+
+	job, err := h.printJobService.PausePrintJob(jobID, req.Notes, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal pause print job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Print job berhasil di-pause",
+		"data":    job,
+	})
+}
+
+// Resume melanjutkan print job yang sedang di-pause
+// @route POST /api/cetak/jobs/:id/resume
+// @access OPERATOR_CETAK, SUPERVISOR_CETAK, ADMIN, MANAGER
+func (h *PrintJobHandler) Resume(c *gin.Context) {
+	jobID, ok := h.parseID(c, "ID print job tidak valid")
+	if !ok {
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req PauseResumeRequest
+	_ = c.ShouldBindJSON(&req)
+
+	job, err := h.printJobService.ResumePrintJob(jobID, req.Notes, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal resume print job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Print job berhasil dilanjutkan",
+		"data":    job,
+	})
+}
+
+// LogProgress mencatat progress lembar baik dan rusak untuk print job
+// @route POST /api/cetak/jobs/:id/progress
+// @access OPERATOR_CETAK, SUPERVISOR_CETAK, ADMIN, MANAGER
+func (h *PrintJobHandler) LogProgress(c *gin.Context) {
+	jobID, ok := h.parseID(c, "ID print job tidak valid")
+	if !ok {
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.PrintProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	job, err := h.printJobService.LogProgress(jobID, req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mencatat progress cetak")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Progress cetak berhasil dicatat",
+		"data":    job,
+	})
+}
+
+// Finalize menyelesaikan print job dan menyerahkan PO ke queue penghitungan
+// @route POST /api/cetak/jobs/:id/finalize
+// @access OPERATOR_CETAK, SUPERVISOR_CETAK, ADMIN, MANAGER
+func (h *PrintJobHandler) Finalize(c *gin.Context) {
+	jobID, ok := h.parseID(c, "ID print job tidak valid")
+	if !ok {
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.PrintProgressRequest
+	_ = c.ShouldBindJSON(&req)
+
+	summary, err := h.printJobService.FinalizePrintJob(jobID, req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal menyelesaikan print job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Print job berhasil diselesaikan. PO siap untuk penghitungan.",
+		"data":    summary,
+	})
+}
+
+// GetJob mengambil detail print job termasuk progress logs
+// @route GET /api/cetak/jobs/:id
+// @access OPERATOR_CETAK, SUPERVISOR_CETAK, ADMIN, MANAGER
+func (h *PrintJobHandler) GetJob(c *gin.Context) {
+	jobID, ok := h.parseID(c, "ID print job tidak valid")
+	if !ok {
+		return
+	}
+
+	job, err := h.printJobService.GetPrintJob(jobID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil print job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Print job berhasil diambil",
+		"data":    job,
+	})
+}
+
+// GetJobByPO mengambil print job berdasarkan Production Order
+// @route GET /api/cetak/po/:id/job
+// @access OPERATOR_CETAK, SUPERVISOR_CETAK, ADMIN, MANAGER
+func (h *PrintJobHandler) GetJobByPO(c *gin.Context) {
+	poID, ok := h.parseID(c, "ID PO tidak valid")
+	if !ok {
+		return
+	}
+
+	job, err := h.printJobService.GetPrintJobByPO(poID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil print job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Print job berhasil diambil",
+		"data":    job,
+	})
+}
+
+// GetActiveJobs mengambil list print job yang sedang berjalan atau di-pause
+// @route GET /api/cetak/jobs/active
+// @access OPERATOR_CETAK, SUPERVISOR_CETAK, ADMIN, MANAGER
+func (h *PrintJobHandler) GetActiveJobs(c *gin.Context) {
+	jobs, err := h.printJobService.GetActivePrintJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Gagal mengambil print job aktif",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Print job aktif berhasil diambil",
+		"data":    jobs,
+	})
+}
+
+// parseID mengambil ID dari URL param dan mengirim response 400 jika tidak valid
+func (h *PrintJobHandler) parseID(c *gin.Context, message string) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": message,
+			"error":   err.Error(),
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleError memetakan error dari PrintJobService ke HTTP status code yang sesuai
+func (h *PrintJobHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	var transitionErr *models.InvalidPOTransitionError
+	statusCode := http.StatusInternalServerError
+	message := fallbackMessage
+
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		statusCode = http.StatusNotFound
+		message = "Production Order tidak ditemukan"
+	case errors.Is(err, services.ErrPrintJobNotFound), errors.Is(err, services.ErrMachineNotFound):
+		statusCode = http.StatusNotFound
+		message = err.Error()
+	case errors.As(err, &transitionErr):
+		statusCode = http.StatusBadRequest
+		message = "PO tidak dalam status yang valid untuk proses cetak"
+	case errors.Is(err, services.ErrPrintJobAlreadyStarted),
+		errors.Is(err, services.ErrPrintJobCompleted),
+		errors.Is(err, services.ErrMachineBusy):
+		statusCode = http.StatusConflict
+		message = err.Error()
+	case errors.Is(err, services.ErrPrintJobNotInProgress),
+		errors.Is(err, services.ErrPrintJobNotPaused),
+		errors.Is(err, services.ErrMachineNotAvailable),
+		errors.Is(err, services.ErrInvalidPrintOperator),
+		errors.Is(err, services.ErrInvalidSheetCount),
+		errors.Is(err, services.ErrNoGoodSheets):
+		statusCode = http.StatusBadRequest
+		message = err.Error()
+	}
+
+	response := gin.H{
+		"success": false,
+		"message": message,
+	}
+	if statusCode == http.StatusInternalServerError {
+		response["error"] = err.Error()
+	}
+	c.JSON(statusCode, response)
+}
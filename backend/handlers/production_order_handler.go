@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProductionOrderHandler merupakan handler untuk endpoint Production Order lintas stage
+// yang mencakup manajemen PO oleh PPIC dan timeline end-to-end PO
+type ProductionOrderHandler struct {
+	poService       *services.ProductionOrderService
+	timelineService *services.POTimelineService
+}
+
+// NewProductionOrderHandler membuat instance baru dari ProductionOrderHandler
+func NewProductionOrderHandler(poService *services.ProductionOrderService, timelineService *services.POTimelineService) *ProductionOrderHandler {
+	return &ProductionOrderHandler{
+		poService:       poService,
+		timelineService: timelineService,
+	}
+}
+
+// List mengambil list PO dengan filter stage, status, OBC, priority, dan due window
+// @route GET /api/production-orders
+// @access PPIC, ADMIN, MANAGER
+func (h *ProductionOrderHandler) List(c *gin.Context) {
+	var filters services.ProductionOrderFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.poService.ListProductionOrders(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil list PO")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "List PO berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Detail mengambil detail PO beserta stage tracking
+// @route GET /api/production-orders/:id
+// @access PPIC, ADMIN, MANAGER
+func (h *ProductionOrderHandler) Detail(c *gin.Context) {
+	id, ok := h.parsePOID(c)
+	if !ok {
+		return
+	}
+
+	po, err := h.poService.GetProductionOrder(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil detail PO")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Detail PO berhasil diambil",
+		"data":    po,
+	})
+}
+
+// Update mengubah priority, due date, dan catatan PO
+// @route PATCH /api/production-orders/:id
+// @access PPIC, ADMIN
+func (h *ProductionOrderHandler) Update(c *gin.Context) {
+	id, ok := h.parsePOID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateProductionOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Data tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	po, err := h.poService.UpdateProductionOrder(id, req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengupdate PO")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "PO berhasil diupdate",
+		"data":    po,
+	})
+}
+
+// Split memecah satu PO menjadi beberapa PO sesuai list quantity
+// @route POST /api/production-orders/:id/split
+// @access PPIC, ADMIN
+func (h *ProductionOrderHandler) Split(c *gin.Context) {
+	id, ok := h.parsePOID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.SplitProductionOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Data tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	orders, err := h.poService.SplitProductionOrder(id, req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal split PO")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "PO berhasil di-split",
+		"data":    orders,
+	})
+}
+
+// Merge menggabungkan beberapa PO sisa dari OBC yang sama menjadi satu PO
+// @route POST /api/production-orders/merge
+// @access PPIC, ADMIN
+func (h *ProductionOrderHandler) Merge(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.MergeProductionOrdersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Data tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	po, err := h.poService.MergeProductionOrders(req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal merge PO")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "PO berhasil di-merge",
+		"data":    po,
+	})
+}
+
+// Cancel membatalkan PO dengan alasan
+// @route POST /api/production-orders/:id/cancel
+// @access PPIC, ADMIN
+func (h *ProductionOrderHandler) Cancel(c *gin.Context) {
+	id, ok := h.parsePOID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.CancelProductionOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Alasan pembatalan wajib diisi",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	po, err := h.poService.CancelProductionOrder(id, req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal membatalkan PO")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "PO berhasil dibatalkan",
+		"data":    po,
+	})
+}
+
+// GetTimeline mengambil timeline PO yang menggabungkan record setiap stage,
+// stage tracking, dan activity logs dalam satu list event terurut
+// @route GET /api/production-orders/:id/timeline
+// @access All authenticated users
+func (h *ProductionOrderHandler) GetTimeline(c *gin.Context) {
+	id, ok := h.parsePOID(c)
+	if !ok {
+		return
+	}
+
+	timeline, err := h.timelineService.GetTimeline(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil timeline PO")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Timeline PO berhasil diambil",
+		"data":    timeline,
+	})
+}
+
+// parsePOID mengambil PO ID dari path parameter
+func (h *ProductionOrderHandler) parsePOID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID PO tidak valid",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleError memetakan error Production Order ke HTTP status code
+func (h *ProductionOrderHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrPONotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrPONotEditable),
+		errors.Is(err, services.ErrPONotCancellable),
+		errors.Is(err, services.ErrPONotSplittable):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidPriority),
+		errors.Is(err, services.ErrInvalidDueDate),
+		errors.Is(err, services.ErrInvalidSplitQuantities),
+		errors.Is(err, services.ErrMergeRequiresMultiple),
+		errors.Is(err, services.ErrMergeDifferentOBC),
+		errors.Is(err, services.ErrMergeExceedsMaximum),
+		errors.Is(err, services.ErrCancelReasonRequired):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProductionRuleHandler merupakan handler untuk aturan pembagian OBC → PO
+// yang mencakup list, detail, create, versioning update, dan deactivate rule
+type ProductionRuleHandler struct {
+	ruleService *services.ProductionRuleService
+}
+
+// NewProductionRuleHandler membuat instance baru dari ProductionRuleHandler
+func NewProductionRuleHandler(ruleService *services.ProductionRuleService) *ProductionRuleHandler {
+	return &ProductionRuleHandler{
+		ruleService: ruleService,
+	}
+}
+
+// List mengambil list production rule dengan filter kode, tipe produk, dan personalisasi
+// @route GET /api/admin/production-rules
+// @access ADMIN, PPIC, MANAGER
+func (h *ProductionRuleHandler) List(c *gin.Context) {
+	var filters services.ProductionRuleFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.ruleService.ListRules(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil list production rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "List production rule berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Detail mengambil detail production rule berdasarkan ID
+// @route GET /api/admin/production-rules/:id
+// @access ADMIN, PPIC, MANAGER
+func (h *ProductionRuleHandler) Detail(c *gin.Context) {
+	id, ok := h.parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.ruleService.GetRule(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil detail production rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Detail production rule berhasil diambil",
+		"data":    rule,
+	})
+}
+
+// Create membuat production rule baru
+// @route POST /api/admin/production-rules
+// @access ADMIN, PPIC
+func (h *ProductionRuleHandler) Create(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.CreateProductionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.ruleService.CreateRule(req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal membuat production rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Production rule berhasil dibuat",
+		"data":    rule,
+	})
+}
+
+// Update membuat versi baru dari production rule
+// @route PUT /api/admin/production-rules/:id
+// @access ADMIN, PPIC
+func (h *ProductionRuleHandler) Update(c *gin.Context) {
+	id, ok := h.parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateProductionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.ruleService.UpdateRule(id, req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengupdate production rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Production rule berhasil diupdate ke versi baru",
+		"data":    rule,
+	})
+}
+
+// Deactivate menonaktifkan production rule
+// @route DELETE /api/admin/production-rules/:id
+// @access ADMIN, PPIC
+func (h *ProductionRuleHandler) Deactivate(c *gin.Context) {
+	id, ok := h.parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.ruleService.DeactivateRule(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal menonaktifkan production rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Production rule berhasil dinonaktifkan",
+		"data":    rule,
+	})
+}
+
+// parseRuleID mengambil rule ID dari path parameter
+func (h *ProductionRuleHandler) parseRuleID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID production rule tidak valid",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleError memetakan error dari ProductionRuleService ke HTTP status code yang sesuai
+func (h *ProductionRuleHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrProductionRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrProductionRuleCodeExists),
+		errors.Is(err, services.ErrProductionRuleInactive),
+		errors.Is(err, services.ErrDefaultRuleRequired):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, models.ErrInvalidBufferPercentage),
+		errors.Is(err, models.ErrInvalidMaxQuantityPerPO),
+		errors.Is(err, models.ErrInvalidSheetsPerRim),
+		errors.Is(err, models.ErrInvalidLembarBesarMultiplier):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SAPOutboxHandler merupakan handler untuk monitoring integration outbox posting material ke SAP
+// yang mencakup list entry, ringkasan status, dan retry entry yang gagal
+type SAPOutboxHandler struct {
+	sapOutboxService *services.SAPOutboxService
+}
+
+// NewSAPOutboxHandler membuat instance baru dari SAPOutboxHandler
+func NewSAPOutboxHandler(sapOutboxService *services.SAPOutboxService) *SAPOutboxHandler {
+	return &SAPOutboxHandler{
+		sapOutboxService: sapOutboxService,
+	}
+}
+
+// List mengambil list entry outbox dengan filter status, event type, dan PO
+// @route GET /api/admin/sap-outbox
+// @access ADMIN, MANAGER
+func (h *SAPOutboxHandler) List(c *gin.Context) {
+	var filters services.SAPOutboxFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.sapOutboxService.ListEntries(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil SAP outbox")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SAP outbox berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Summary mengambil jumlah entry outbox per status dan adapter yang aktif
+// @route GET /api/admin/sap-outbox/summary
+// @access ADMIN, MANAGER
+func (h *SAPOutboxHandler) Summary(c *gin.Context) {
+	summary, err := h.sapOutboxService.GetSummary()
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil ringkasan SAP outbox")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Ringkasan SAP outbox berhasil diambil",
+		"data":    summary,
+	})
+}
+
+// Detail mengambil detail entry outbox beserta payload dan error terakhir
+// @route GET /api/admin/sap-outbox/:id
+// @access ADMIN, MANAGER
+func (h *SAPOutboxHandler) Detail(c *gin.Context) {
+	id, ok := h.parseEntryID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.sapOutboxService.GetEntry(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil detail SAP outbox")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Detail SAP outbox berhasil diambil",
+		"data":    entry,
+	})
+}
+
+// Retry menjadwalkan ulang entry outbox untuk segera dikirim ke SAP
+// @route POST /api/admin/sap-outbox/:id/retry
+// @access ADMIN
+func (h *SAPOutboxHandler) Retry(c *gin.Context) {
+	id, ok := h.parseEntryID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.sapOutboxService.RetryEntry(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal menjadwalkan ulang SAP outbox")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Entry SAP outbox dijadwalkan ulang",
+		"data":    entry,
+	})
+}
+
+// parseEntryID mengambil entry outbox ID dari path parameter
+func (h *SAPOutboxHandler) parseEntryID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID entry SAP outbox tidak valid",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleError memetakan error dari SAPOutboxService ke HTTP status code yang sesuai
+func (h *SAPOutboxHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrSAPOutboxNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrSAPOutboxNotRetryable):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SLAHandler merupakan handler untuk konfigurasi SLA antrian stage dan laporan history breach
+type SLAHandler struct {
+	slaService *services.SLAService
+}
+
+// NewSLAHandler membuat instance baru dari SLAHandler
+func NewSLAHandler(slaService *services.SLAService) *SLAHandler {
+	return &SLAHandler{
+		slaService: slaService,
+	}
+}
+
+// ListPolicies mengambil SLA efektif untuk setiap stage dan priority
+// @route GET /api/admin/sla/policies
+// @access ADMIN, PPIC, MANAGER
+func (h *SLAHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.slaService.ListPolicies()
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil SLA policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SLA policy berhasil diambil",
+		"data":    policies,
+	})
+}
+
+// UpsertPolicy menyimpan SLA untuk stage dan priority
+// @route PUT /api/admin/sla/policies
+// @access ADMIN, MANAGER
+func (h *SLAHandler) UpsertPolicy(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User tidak terautentikasi",
+		})
+		return
+	}
+
+	var req services.UpsertSLAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Data tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.slaService.UpsertPolicy(req, userID.(uint64))
+	if err != nil {
+		h.handleError(c, err, "Gagal menyimpan SLA policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SLA policy berhasil disimpan",
+		"data":    policy,
+	})
+}
+
+// DeletePolicy menghapus SLA policy sehingga kembali ke SLA bawaan
+// @route DELETE /api/admin/sla/policies/:id
+// @access ADMIN, MANAGER
+func (h *SLAHandler) DeletePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID SLA policy tidak valid",
+		})
+		return
+	}
+
+	if err := h.slaService.DeletePolicy(id); err != nil {
+		h.handleError(c, err, "Gagal menghapus SLA policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SLA policy dihapus, stage kembali memakai SLA bawaan",
+	})
+}
+
+// ListBreaches mengambil history breach SLA dengan filter stage, level, priority, status, dan tanggal
+// @route GET /api/admin/sla/breaches
+// @access ADMIN, PPIC, MANAGER
+func (h *SLAHandler) ListBreaches(c *gin.Context) {
+	var filters services.SLABreachFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.slaService.ListBreaches(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil history breach SLA")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "History breach SLA berhasil diambil",
+		"data":    result,
+	})
+}
+
+// GetBreachSummary merekap breach SLA per stage untuk laporan
+// @route GET /api/admin/sla/breaches/summary
+// @access ADMIN, PPIC, MANAGER
+func (h *SLAHandler) GetBreachSummary(c *gin.Context) {
+	var filters services.SLABreachFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	summary, err := h.slaService.GetBreachSummary(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil rekap breach SLA")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Rekap breach SLA berhasil diambil",
+		"data":    summary,
+	})
+}
+
+// handleError memetakan error dari SLAService ke HTTP status code yang sesuai
+func (h *SLAHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrSLAPolicyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrSLAStageInvalid),
+		errors.Is(err, services.ErrSLAThresholdInvalid),
+		errors.Is(err, services.ErrSLADateInvalid),
+		errors.Is(err, services.ErrInvalidPriority):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
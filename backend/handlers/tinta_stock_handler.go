@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sirine-go/backend/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TintaStockHandler merupakan handler untuk inventory tinta per warna
+// yang mencakup saldo stok, batas minimum, penerimaan, dan riwayat mutasi
+type TintaStockHandler struct {
+	tintaStockService *services.TintaStockService
+}
+
+// NewTintaStockHandler membuat instance baru dari TintaStockHandler
+func NewTintaStockHandler(tintaStockService *services.TintaStockService) *TintaStockHandler {
+	return &TintaStockHandler{
+		tintaStockService: tintaStockService,
+	}
+}
+
+// List mengambil saldo stok semua warna tinta dengan filter search dan low stock
+// @route GET /api/tinta-stocks
+// @access STAFF_KHAZWAL, PPIC, ADMIN, MANAGER
+func (h *TintaStockHandler) List(c *gin.Context) {
+	var filters services.TintaStockFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.tintaStockService.ListStocks(filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil stok tinta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Stok tinta berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Detail mengambil saldo stok satu warna tinta
+// @route GET /api/tinta-stocks/:id
+// @access STAFF_KHAZWAL, PPIC, ADMIN, MANAGER
+func (h *TintaStockHandler) Detail(c *gin.Context) {
+	id, ok := h.parseStockID(c)
+	if !ok {
+		return
+	}
+
+	stock, err := h.tintaStockService.GetStock(id)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil detail stok tinta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Detail stok tinta berhasil diambil",
+		"data":    stock,
+	})
+}
+
+// Movements mengambil riwayat mutasi stok satu warna tinta
+// @route GET /api/tinta-stocks/:id/movements
+// @access STAFF_KHAZWAL, PPIC, ADMIN, MANAGER
+func (h *TintaStockHandler) Movements(c *gin.Context) {
+	id, ok := h.parseStockID(c)
+	if !ok {
+		return
+	}
+
+	var filters services.TintaMovementFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Parameter query tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.tintaStockService.ListMovements(id, filters)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil riwayat mutasi stok tinta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Riwayat mutasi stok tinta berhasil diambil",
+		"data":    result,
+	})
+}
+
+// Create mendaftarkan warna tinta baru ke inventory
+// @route POST /api/tinta-stocks
+// @access ADMIN
+func (h *TintaStockHandler) Create(c *gin.Context) {
+	var req services.CreateTintaStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	stock, err := h.tintaStockService.CreateStock(req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mendaftarkan warna tinta")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Warna tinta berhasil didaftarkan",
+		"data":    stock,
+	})
+}
+
+// Update mengupdate nama dan batas minimum warna tinta
+// @route PUT /api/tinta-stocks/:id
+// @access ADMIN, MANAGER
+func (h *TintaStockHandler) Update(c *gin.Context) {
+	id, ok := h.parseStockID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateTintaStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	stock, err := h.tintaStockService.UpdateStock(id, req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengupdate warna tinta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Warna tinta berhasil diupdate",
+		"data":    stock,
+	})
+}
+
+// Receive mencatat penerimaan tinta ke gudang
+// @route POST /api/tinta-stocks/:id/receipts
+// @access STAFF_KHAZWAL, ADMIN, MANAGER
+func (h *TintaStockHandler) Receive(c *gin.Context) {
+	id, ok := h.parseStockID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req services.ReceiveTintaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	stock, err := h.tintaStockService.ReceiveStock(id, req, userID)
+	if err != nil {
+		h.handleError(c, err, "Gagal mencatat penerimaan tinta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Penerimaan tinta berhasil dicatat",
+		"data":    stock,
+	})
+}
+
+// parseStockID mengambil stok tinta ID dari path parameter
+func (h *TintaStockHandler) parseStockID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "ID stok tinta tidak valid",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleError memetakan error dari TintaStockService ke HTTP status code yang sesuai
+func (h *TintaStockHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, services.ErrTintaStockNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrTintaColorExists),
+		errors.Is(err, services.ErrTintaStockInsufficient):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidTintaQuantity),
+		errors.Is(err, services.ErrInvalidTintaMinimum):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fallbackMessage,
+			"error":   err.Error(),
+		})
+	}
+}
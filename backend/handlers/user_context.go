@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getUserID mengambil user ID dari context (dari auth middleware),
+// response error langsung dikirim jika user tidak terautentikasi
+func getUserID(c *gin.Context) (uint64, bool) {
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User tidak terautentikasi",
+		})
+		return 0, false
+	}
+
+	userID, ok := userIDInterface.(uint64)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Format user ID tidak valid",
+		})
+		return 0, false
+	}
+	return userID, true
+}
@@ -390,6 +390,12 @@ func (h *UserHandler) ImportUsersFromCSV(c *gin.Context) {
 		return
 	}
 
+	// Optional: kembalikan file asli beserta kolom error (query report=xlsx|csv)
+	reportOpts, wantReport, ok := parseImportReportQuery(c)
+	if !ok {
+		return
+	}
+
 	// Import users
 	result, err := h.userService.BulkImportUsersFromCSV(csvData)
 	if err != nil {
@@ -401,6 +407,21 @@ func (h *UserHandler) ImportUsersFromCSV(c *gin.Context) {
 		return
 	}
 
+	if wantReport {
+		report, err := h.userService.BuildImportReport(csvData, fileHeader.Filename, result, reportOpts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Import selesai tetapi gagal membuat report",
+				"error":   err.Error(),
+				"data":    result,
+			})
+			return
+		}
+		sendImportReport(c, report, result.Imported+result.Failed, result.Imported, result.Failed)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": fmt.Sprintf("Import selesai: %d berhasil, %d gagal", result.Imported, result.Failed),
@@ -1,10 +1,13 @@
 package counting
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"sirine-go/backend/models"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -248,6 +251,8 @@ func (h *CountingHandler) FinalizeCounting(c *gin.Context) {
 			statusCode = http.StatusBadRequest
 		} else if err == ErrDefectBreakdownSumMismatch {
 			statusCode = http.StatusUnprocessableEntity
+		} else if errors.As(err, new(*models.InvalidPOTransitionError)) {
+			statusCode = http.StatusConflict
 		}
 
 		c.JSON(statusCode, gin.H{
@@ -146,9 +146,11 @@ type QueueItemResponse struct {
 	POID              uint64    `json:"po_id"`
 	PONumber          int64     `json:"po_number"`
 	OBCNumber         string    `json:"obc_number"`
+	Priority          string    `json:"priority"`
 	TargetQuantity    int       `json:"target_quantity"`
 	PrintCompletedAt  time.Time `json:"print_completed_at"`
 	WaitingMinutes    int       `json:"waiting_minutes"`
+	SLALevel          string    `json:"sla_level"`
 	IsOverdue         bool      `json:"is_overdue"`
 	Machine           *MachineInfo `json:"machine,omitempty"`
 	Operator          *OperatorInfo `json:"operator,omitempty"`
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"time"
 
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+
 	"gorm.io/gorm"
 )
 
@@ -34,30 +37,36 @@ func NewCountingRepository(db *gorm.DB) CountingRepository {
 }
 
 // GetCountingQueue mengambil list PO yang menunggu penghitungan (FIFO)
-// dengan join ke print_job_summaries untuk mendapatkan info mesin dan operator
+// dengan join ke print_job_summaries untuk mendapatkan info mesin dan operator,
+// status overdue mengikuti SLA penghitungan sesuai priority PO
 func (r *countingRepositoryImpl) GetCountingQueue(machineID *uint64, dateFrom, dateTo *time.Time) ([]QueueItemResponse, error) {
 	var results []QueueItemResponse
 
+	thresholds, err := services.LoadSLAThresholds(r.db)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat SLA penghitungan: %w", err)
+	}
+
 	query := r.db.Table("production_orders po").
 		Select(`
 			po.id as po_id,
 			po.po_number,
 			po.obc_number,
+			po.priority,
 			po.quantity_target_lembar_besar as target_quantity,
 			pjs.finalized_at as print_completed_at,
-			EXTRACT(EPOCH FROM (NOW() - pjs.finalized_at)) / 60 as waiting_minutes,
-			CASE WHEN EXTRACT(EPOCH FROM (NOW() - pjs.finalized_at)) / 60 > 120 THEN true ELSE false END as is_overdue,
+			TIMESTAMPDIFF(MINUTE, pjs.finalized_at, NOW()) as waiting_minutes,
 			m.id as machine_id,
 			m.name as machine_name,
 			m.code as machine_code,
 			u.id as operator_id,
-			u.name as operator_name,
+			u.full_name as operator_name,
 			u.nip as operator_nip
 		`).
 		Joins("INNER JOIN print_job_summaries pjs ON pjs.production_order_id = po.id").
 		Joins("LEFT JOIN machines m ON m.id = pjs.machine_id").
 		Joins("LEFT JOIN users u ON u.id = pjs.operator_id").
-		Where("po.current_status = ?", "WAITING_COUNTING").
+		Where("po.current_status = ?", models.StatusWaitingCounting).
 		Where("pjs.finalized_at IS NOT NULL").
 		Order("pjs.finalized_at ASC") // FIFO
 
@@ -89,10 +98,10 @@ func (r *countingRepositoryImpl) GetCountingQueue(machineID *uint64, dateFrom, d
 			&item.POID,
 			&item.PONumber,
 			&item.OBCNumber,
+			&item.Priority,
 			&item.TargetQuantity,
 			&item.PrintCompletedAt,
 			&waitingMinutes,
-			&item.IsOverdue,
 			&machineID,
 			&machineName,
 			&machineCode,
@@ -105,6 +114,8 @@ func (r *countingRepositoryImpl) GetCountingQueue(machineID *uint64, dateFrom, d
 		}
 
 		item.WaitingMinutes = int(waitingMinutes)
+		item.SLALevel = string(thresholds.Evaluate(models.StageKhazwalCounting, models.POPriority(item.Priority), item.WaitingMinutes))
+		item.IsOverdue = item.SLALevel == string(models.SLABreached)
 
 		// Populate machine info
 		if machineID != nil && machineName != nil && machineCode != nil {
@@ -216,12 +227,12 @@ func (r *countingRepositoryImpl) GetCountingDetailWithRelations(id uint64) (*Cou
 			po.obc_number,
 			po.quantity_target_lembar_besar as target_quantity,
 			u.id as counted_by_id,
-			u.name as counted_by_name,
+			u.full_name as counted_by_name,
 			u.nip as counted_by_nip,
 			pjs.machine_id,
 			m.name as machine_name,
 			pjs.operator_id,
-			op.name as operator_name,
+			op.full_name as operator_name,
 			pjs.finalized_at
 		`).
 		Joins("INNER JOIN production_orders po ON po.id = kcr.production_order_id").
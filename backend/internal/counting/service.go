@@ -2,9 +2,13 @@ package counting
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -26,15 +30,17 @@ type CountingService interface {
 
 // countingServiceImpl merupakan implementasi CountingService
 type countingServiceImpl struct {
-	db   *gorm.DB
-	repo CountingRepository
+	db          *gorm.DB
+	repo        CountingRepository
+	transitions *services.POTransitionService
 }
 
 // NewCountingService membuat instance baru CountingService
 func NewCountingService(db *gorm.DB, repo CountingRepository) CountingService {
 	return &countingServiceImpl{
-		db:   db,
-		repo: repo,
+		db:          db,
+		repo:        repo,
+		transitions: services.NewPOTransitionService(db),
 	}
 }
 
@@ -87,33 +93,31 @@ func (s *countingServiceImpl) GetCountingDetailByPOID(poID uint64) (*CountingDet
 // dengan validasi PO status dan create counting record
 func (s *countingServiceImpl) StartCounting(poID uint64, userID uint64) (*StartCountingResponse, error) {
 	// Start transaction untuk atomicity
-	tx := s.db.Begin()
+	eventDB, events := services.WithDomainEvents(s.db)
+	tx := eventDB.Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
 		}
 	}()
 
-	// 1. Validate PO exists dan status correct
-	var po struct {
-		ID            uint64
-		CurrentStatus string
-	}
-	if err := tx.Table("production_orders").
-		Select("id, current_status").
-		Where("id = ?", poID).
-		First(&po).Error; err != nil {
+	// 1. Pindahkan PO ke COUNTING_IN_PROGRESS via state machine
+	// yang sekaligus memvalidasi PO dalam status WAITING_COUNTING
+	if _, err := s.transitions.TransitionInTx(tx, services.POTransitionRequest{
+		POID:      poID,
+		To:        models.StatusCountingInProgress,
+		HandledBy: &userID,
+		Notes:     "Penghitungan dimulai",
+	}); err != nil {
 		tx.Rollback()
+		var transitionErr *models.InvalidPOTransitionError
+		if errors.As(err, &transitionErr) {
+			return nil, ErrPONotReadyForCounting
+		}
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("PO tidak ditemukan")
 		}
-		return nil, fmt.Errorf("gagal validasi PO: %w", err)
-	}
-
-	// Check PO status
-	if po.CurrentStatus != "WAITING_COUNTING" {
-		tx.Rollback()
-		return nil, ErrPONotReadyForCounting
+		return nil, fmt.Errorf("gagal update PO status: %w", err)
 	}
 
 	// 2. Check tidak ada counting IN_PROGRESS lain untuk PO ini
@@ -141,27 +145,7 @@ func (s *countingServiceImpl) StartCounting(poID uint64, userID uint64) (*StartC
 		return nil, fmt.Errorf("gagal membuat counting record: %w", err)
 	}
 
-	// 4. Update PO status ke SEDANG_DIHITUNG
-	if err := tx.Table("production_orders").
-		Where("id = ?", poID).
-		Updates(map[string]interface{}{
-			"current_status": "SEDANG_DIHITUNG",
-			"updated_at":     time.Now(),
-		}).Error; err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("gagal update PO status: %w", err)
-	}
-
-	// 5. Update po_stage_tracking set started_at untuk stage KHAZWAL_COUNTING
-	if err := tx.Table("po_stage_tracking").
-		Where("production_order_id = ?", poID).
-		Where("stage = ?", "KHAZWAL_COUNTING").
-		Update("started_at", now).Error; err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("gagal update stage tracking: %w", err)
-	}
-
-	// 6. Log activity
+	// 4. Log activity
 	activityLog := map[string]interface{}{
 		"user_id":             userID,
 		"action":              "START_COUNTING",
@@ -180,6 +164,7 @@ func (s *countingServiceImpl) StartCounting(poID uint64, userID uint64) (*StartC
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("gagal commit transaction: %w", err)
 	}
+	events.Publish()
 
 	// Return response
 	response := &StartCountingResponse{
@@ -253,7 +238,8 @@ func (s *countingServiceImpl) UpdateResult(id uint64, req UpdateResultRequest, t
 // FinalizeCounting menyelesaikan penghitungan dengan lock data dan advance PO ke next stage
 func (s *countingServiceImpl) FinalizeCounting(id uint64) (*FinalizeCountingResponse, error) {
 	// Start transaction
-	tx := s.db.Begin()
+	eventDB, events := services.WithDomainEvents(s.db)
+	tx := eventDB.Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -271,13 +257,15 @@ func (s *countingServiceImpl) FinalizeCounting(id uint64) (*FinalizeCountingResp
 		return nil, fmt.Errorf("gagal mengambil counting record: %w", err)
 	}
 
-	// 2. Get PO untuk target quantity
+	// 2. Get PO untuk target quantity dan notifikasi
 	var po struct {
 		ID                       uint64
+		PONumber                 int64
+		OBCNumber                string
 		QuantityTargetLembarBesar int
 	}
 	if err := tx.Table("production_orders").
-		Select("id, quantity_target_lembar_besar").
+		Select("id, po_number, obc_number, quantity_target_lembar_besar").
 		Where("id = ?", counting.ProductionOrderID).
 		First(&po).Error; err != nil {
 		tx.Rollback()
@@ -301,28 +289,46 @@ func (s *countingServiceImpl) FinalizeCounting(id uint64) (*FinalizeCountingResp
 		return nil, fmt.Errorf("gagal finalize counting: %w", err)
 	}
 
-	// 5. Update PO status dan stage
-	if err := tx.Table("production_orders").
-		Where("id = ?", counting.ProductionOrderID).
-		Updates(map[string]interface{}{
-			"current_stage":  "KHAZWAL_CUTTING",
-			"current_status": "SIAP_POTONG",
-			"updated_at":     time.Now(),
-		}).Error; err != nil {
+	// 5. Pindahkan PO ke READY_FOR_CUTTING via state machine
+	// yang sekaligus mencatat penyelesaian stage KHAZWAL_COUNTING di po_stage_trackings
+	if _, err := s.transitions.TransitionInTx(tx, services.POTransitionRequest{
+		POID:      counting.ProductionOrderID,
+		To:        models.StatusReadyForCutting,
+		HandledBy: counting.CountedBy,
+		Notes:     "Penghitungan selesai",
+		StartedAt: counting.StartedAt,
+	}); err != nil {
 		tx.Rollback()
-		return nil, fmt.Errorf("gagal update PO status: %w", err)
+		return nil, err
 	}
+	services.RecordDomainEventInTx(tx, services.NewFinalizedEvent(services.EventCountingFinalized, models.StageKhazwalCounting, counting.ProductionOrderID, counting.ID, counting.CountedBy))
 
-	// 6. Update po_stage_tracking set completed_at untuk stage KHAZWAL_COUNTING
-	if err := tx.Table("po_stage_tracking").
-		Where("production_order_id = ?", counting.ProductionOrderID).
-		Where("stage = ?", "KHAZWAL_COUNTING").
-		Update("completed_at", now).Error; err != nil {
+	// 6. Tulis posting hasil penghitungan ke SAP outbox, dikirim worker setelah commit
+	if err := services.EnqueueSAPPostingInTx(tx, buildCountingSAPPosting(&counting)); err != nil {
 		tx.Rollback()
-		return nil, fmt.Errorf("gagal update stage tracking: %w", err)
+		return nil, fmt.Errorf("gagal menulis SAP outbox: %w", err)
+	}
+
+	// 7. Notifikasi serah terima ke staff Khazwal untuk pemotongan,
+	// dengan eskalasi ke manager Khazwal dan PPIC jika persentase rusak melebihi threshold
+	if err := services.NotifyHandoverInTx(tx, po.ID, models.StageKhazwalCutting,
+		"Siap Dipotong - PO #"+po.OBCNumber,
+		fmt.Sprintf("Penghitungan PO #%d (OBC %s) selesai dengan %d lembar baik. Silakan lakukan pemotongan.", po.PONumber, po.OBCNumber, counting.QuantityGood),
+	); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if counting.DefectPercentageExceedsThreshold(models.DefectThresholdPercentage) {
+		if err := services.NotifyEscalationInTx(tx, po.ID, models.StageKhazwalCounting,
+			"Lembar Rusak Tinggi - PO #"+po.OBCNumber,
+			fmt.Sprintf("Penghitungan PO #%d (OBC %s) selesai dengan %.2f%% lembar rusak (%d lembar).", po.PONumber, po.OBCNumber, *counting.PercentageDefect, counting.QuantityDefect),
+		); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 
-	// 7. Log activity (immutable)
+	// 8. Log activity (immutable)
 	activityLog := map[string]interface{}{
 		"user_id":             counting.CountedBy,
 		"action":              "FINALIZE_COUNTING",
@@ -338,12 +344,13 @@ func (s *countingServiceImpl) FinalizeCounting(id uint64) (*FinalizeCountingResp
 		fmt.Printf("Warning: gagal log activity: %v\n", err)
 	}
 
-	// 8. Commit transaction
+	// 9. Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("gagal commit transaction: %w", err)
 	}
+	events.Publish()
 
-	// 9. Return response
+	// 10. Return response
 	response := &FinalizeCountingResponse{
 		ID:              counting.ID,
 		Status:          string(CountingCompleted),
@@ -354,6 +361,35 @@ func (s *countingServiceImpl) FinalizeCounting(id uint64) (*FinalizeCountingResp
 	return response, nil
 }
 
+// buildCountingSAPPosting menyusun posting goods receipt lembar besar baik dan scrap lembar rusak,
+// material dikosongkan agar diisi kode produk SAP milik PO
+func buildCountingSAPPosting(counting *KhazwalCountingResult) services.SAPPosting {
+	items := []services.SAPPostingItem{
+		{
+			Description:  "Lembar besar baik",
+			Quantity:     float64(counting.QuantityGood),
+			Unit:         "lembar",
+			MovementType: services.SAPMovementGoodsReceipt,
+		},
+	}
+	if counting.QuantityDefect > 0 {
+		items = append(items, services.SAPPostingItem{
+			Description:  "Lembar besar rusak",
+			Quantity:     float64(counting.QuantityDefect),
+			Unit:         "lembar",
+			MovementType: services.SAPMovementScrap,
+		})
+	}
+
+	return services.SAPPosting{
+		EventType:         models.SAPEventCountingFinalized,
+		AggregateType:     "khazwal_counting_result",
+		AggregateID:       counting.ID,
+		ProductionOrderID: counting.ProductionOrderID,
+		Items:             items,
+	}
+}
+
 // buildFinalizeMetadata membuat metadata untuk activity log finalize
 func buildFinalizeMetadata(counting *KhazwalCountingResult) string {
 	metadata := map[string]interface{}{
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sirine-go/backend/models"
 	"time"
 )
 
@@ -22,9 +23,7 @@ var (
 
 // Constants untuk validation thresholds
 const (
-	DefectBreakdownThreshold = 5.0  // 5% - jika rusak > 5%, wajib breakdown
-	ToleranceThreshold       = 2.0  // 2% - untuk warning threshold
-	MaxWaitingMinutesWarning = 120  // 2 jam - untuk overdue warning
+	ToleranceThreshold = 2.0 // 2% - untuk warning threshold
 )
 
 // ValidateUpdateResultRequest memvalidasi request untuk update counting result
@@ -44,7 +43,7 @@ func ValidateUpdateResultRequest(req UpdateResultRequest, targetQuantity int) er
 	percentageDefect := float64(req.QuantityDefect) / float64(totalCounted) * 100
 	
 	// Validate defect breakdown requirement (> 5%)
-	if percentageDefect > DefectBreakdownThreshold {
+	if percentageDefect > models.DefectThresholdPercentage {
 		if len(req.DefectBreakdown) == 0 {
 			return ErrDefectBreakdownRequired
 		}
@@ -91,7 +90,7 @@ func ValidateFinalizeRequirements(counting *KhazwalCountingResult, targetQuantit
 	}
 
 	// Check defect breakdown requirement
-	if counting.PercentageDefect != nil && *counting.PercentageDefect > DefectBreakdownThreshold {
+	if counting.PercentageDefect != nil && *counting.PercentageDefect > models.DefectThresholdPercentage {
 		if !counting.HasDefectBreakdown() {
 			return ErrDefectBreakdownRequired
 		}
@@ -136,11 +135,6 @@ func CalculateWaitingMinutes(printCompletedAt *time.Time) int {
 	return int(duration.Minutes())
 }
 
-// IsOverdue memeriksa apakah PO sudah overdue (> 2 jam menunggu)
-func IsOverdue(waitingMinutes int) bool {
-	return waitingMinutes > MaxWaitingMinutesWarning
-}
-
 // ParseDefectBreakdown mem-parse JSON defect breakdown ke slice of DefectBreakdownItem
 func ParseDefectBreakdown(jsonData []byte) ([]DefectBreakdownItem, error) {
 	if jsonData == nil || len(jsonData) == 0 {
@@ -1,9 +1,13 @@
 package cutting
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -174,6 +178,18 @@ func (h *Handler) StartCutting(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error": "Counting result not completed yet",
 			})
+		case ErrMachineNotFound:
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Cutting machine is not registered",
+			})
+		case ErrMachineNotAvailable:
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Cutting machine is not available",
+			})
+		case ErrMachineBusy:
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Cutting machine is in use by another cutting",
+			})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "Failed to start cutting",
@@ -224,6 +240,15 @@ func (h *Handler) UpdateCuttingResult(c *gin.Context) {
 	// Update result via service
 	response, err := h.service.UpdateCuttingResult(id, req)
 	if err != nil {
+		if errors.Is(err, services.ErrAttachmentNotFound) ||
+			errors.Is(err, services.ErrAttachmentInvalidOwner) ||
+			errors.Is(err, services.ErrAttachmentAlreadyLinked) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid waste photo attachment",
+				"details": err.Error(),
+			})
+			return
+		}
 		switch err {
 		case ErrCuttingNotFound:
 			c.JSON(http.StatusNotFound, gin.H{
@@ -294,6 +319,13 @@ func (h *Handler) FinalizeCutting(c *gin.Context) {
 				"error": "Waste exceeds 2%, reason and photo are required",
 			})
 		default:
+			if errors.As(err, new(*models.InvalidPOTransitionError)) {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":   "PO status does not allow finalizing cutting",
+					"details": err.Error(),
+				})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "Failed to finalize cutting",
 				"details": err.Error(),
@@ -1,6 +1,7 @@
 package cutting
 
 import (
+	"sirine-go/backend/models"
 	"time"
 
 	"gorm.io/gorm"
@@ -141,7 +142,7 @@ func (kcr *KhazwalCuttingResult) WasteExceedsThreshold(threshold float64) bool {
 
 // RequiresWasteDocumentation memeriksa apakah waste memerlukan dokumentasi (reason & photo)
 func (kcr *KhazwalCuttingResult) RequiresWasteDocumentation() bool {
-	return kcr.WasteExceedsThreshold(2.0)
+	return kcr.WasteExceedsThreshold(models.WasteThresholdPercentage)
 }
 
 // HasWasteDocumentation memeriksa apakah waste documentation sudah lengkap
@@ -4,7 +4,12 @@ import (
 	"fmt"
 	"time"
 
+	"sirine-go/backend/internal/verifikasi"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repository merupakan interface untuk database operations cutting
@@ -21,20 +26,42 @@ type Repository interface {
 	
 	// Related data operations
 	GetCountingResultByPOID(poID uint64) (*CountingResult, error)
-	UpdatePOStatus(poID uint64, stage, status string) error
-	UpdatePOStageTracking(poID uint64, field string, value time.Time) error
+	TransitionPO(poID uint64, to models.POStatus, userID *uint64, notes string, startedAt *time.Time) error
 	GetPOInfo(poID uint64) (*POInfo, error)
 	GetOperatorInfo(userID uint64) (*OperatorInfo, error)
+	LockMachineByCode(code string) (*models.Machine, error)
+	SetMachineStatus(code string, status models.MachineStatus) error
+	CountActiveCuttingByMachine(code string) (int64, error)
+	CreateVerificationLabels(poID uint64, poNumber int64, totalOutput int) (int, error)
+	NotifyHandover(poID uint64, toStage models.POStage, title, message string) error
+	NotifyEscalation(poID uint64, stage models.POStage, title, message string) error
+	EnqueueSAPPosting(posting services.SAPPosting) error
+	RecordEvent(event services.DomainEvent)
+	LinkWasteAttachment(cuttingID, attachmentID uint64) (*models.Attachment, error)
+
+	// Transaction operations
+	WithTransaction(fn func(txRepo Repository) error) error
 }
 
 // repository merupakan implementasi konkret dari Repository interface
 type repository struct {
-	db *gorm.DB
+	db          *gorm.DB
+	transitions *services.POTransitionService
 }
 
 // NewRepository membuat instance baru dari repository
 func NewRepository(db *gorm.DB) Repository {
-	return &repository{db: db}
+	return &repository{
+		db:          db,
+		transitions: services.NewPOTransitionService(db),
+	}
+}
+
+// WithTransaction menjalankan fn dengan repository yang terikat ke satu database transaction
+func (r *repository) WithTransaction(fn func(txRepo Repository) error) error {
+	return services.TransactionWithEvents(r.db, func(tx *gorm.DB) error {
+		return fn(&repository{db: tx, transitions: r.transitions})
+	})
 }
 
 // CountingResult merupakan struct simplified untuk query counting result
@@ -59,12 +86,11 @@ func (r *repository) GetCuttingQueue(filters QueueFilters) ([]QueueItemResponse,
 			kcr.quantity_good as input_lembar_besar,
 			kcr.quantity_good * 2 as estimated_output,
 			kcr.completed_at as counting_completed_at,
-			TIMESTAMPDIFF(MINUTE, kcr.completed_at, NOW()) as waiting_minutes,
-			CASE WHEN TIMESTAMPDIFF(MINUTE, kcr.completed_at, NOW()) > 60 THEN 1 ELSE 0 END as is_overdue
+			TIMESTAMPDIFF(MINUTE, kcr.completed_at, NOW()) as waiting_minutes
 		`).
 		Joins("INNER JOIN khazwal_counting_results kcr ON kcr.production_order_id = po.id").
-		Where("po.current_stage = ?", "KHAZWAL_CUTTING").
-		Where("po.current_status = ?", "SIAP_POTONG").
+		Where("po.current_stage = ?", models.StageKhazwalCutting).
+		Where("po.current_status = ?", models.StatusReadyForCutting).
 		Where("po.deleted_at IS NULL").
 		Where("kcr.status = ?", "COMPLETED")
 	
@@ -105,8 +131,21 @@ func (r *repository) GetCuttingQueue(filters QueueFilters) ([]QueueItemResponse,
 		`)
 	}
 	
-	err := query.Find(&results).Error
-	return results, err
+	if err := query.Find(&results).Error; err != nil {
+		return nil, err
+	}
+
+	// Status overdue mengikuti SLA pemotongan sesuai priority PO
+	thresholds, err := services.LoadSLAThresholds(r.db)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		level := thresholds.Evaluate(models.StageKhazwalCutting, models.POPriority(results[i].Priority), results[i].WaitingMinutes)
+		results[i].SLALevel = string(level)
+		results[i].IsOverdue = level == models.SLABreached
+	}
+	return results, nil
 }
 
 // GetQueueMetadata mengambil metadata queue (total, counts by priority)
@@ -115,8 +154,8 @@ func (r *repository) GetQueueMetadata(filters QueueFilters) (QueueMetadata, erro
 	
 	query := r.db.Table("production_orders as po").
 		Joins("INNER JOIN khazwal_counting_results kcr ON kcr.production_order_id = po.id").
-		Where("po.current_stage = ?", "KHAZWAL_CUTTING").
-		Where("po.current_status = ?", "SIAP_POTONG").
+		Where("po.current_stage = ?", models.StageKhazwalCutting).
+		Where("po.current_status = ?", models.StatusReadyForCutting).
 		Where("po.deleted_at IS NULL").
 		Where("kcr.status = ?", "COMPLETED")
 	
@@ -142,8 +181,8 @@ func (r *repository) GetQueueMetadata(filters QueueFilters) (QueueMetadata, erro
 	var urgentCount int64
 	r.db.Table("production_orders as po").
 		Joins("INNER JOIN khazwal_counting_results kcr ON kcr.production_order_id = po.id").
-		Where("po.current_stage = ?", "KHAZWAL_CUTTING").
-		Where("po.current_status = ?", "SIAP_POTONG").
+		Where("po.current_stage = ?", models.StageKhazwalCutting).
+		Where("po.current_status = ?", models.StatusReadyForCutting).
 		Where("po.deleted_at IS NULL").
 		Where("kcr.status = ?", "COMPLETED").
 		Where("po.priority = ?", "URGENT").
@@ -153,8 +192,8 @@ func (r *repository) GetQueueMetadata(filters QueueFilters) (QueueMetadata, erro
 	var normalCount int64
 	r.db.Table("production_orders as po").
 		Joins("INNER JOIN khazwal_counting_results kcr ON kcr.production_order_id = po.id").
-		Where("po.current_stage = ?", "KHAZWAL_CUTTING").
-		Where("po.current_status = ?", "SIAP_POTONG").
+		Where("po.current_stage = ?", models.StageKhazwalCutting).
+		Where("po.current_status = ?", models.StatusReadyForCutting).
 		Where("po.deleted_at IS NULL").
 		Where("kcr.status = ?", "COMPLETED").
 		Where("po.priority = ?", "NORMAL").
@@ -217,52 +256,24 @@ func (r *repository) GetCountingResultByPOID(poID uint64) (*CountingResult, erro
 	return &result, nil
 }
 
-// UpdatePOStatus mengupdate status production order
-func (r *repository) UpdatePOStatus(poID uint64, stage, status string) error {
-	return r.db.Table("production_orders").
-		Where("id = ?", poID).
-		Updates(map[string]interface{}{
-			"current_stage":  stage,
-			"current_status": status,
-			"updated_at":     time.Now(),
-		}).Error
-}
-
-// UpdatePOStageTracking mengupdate timestamp di po_stage_tracking
-func (r *repository) UpdatePOStageTracking(poID uint64, field string, value time.Time) error {
-	// Check if record exists
-	var count int64
-	r.db.Table("po_stage_tracking").
-		Where("production_order_id = ?", poID).
-		Where("stage = ?", "KHAZWAL_CUTTING").
-		Count(&count)
-	
-	if count == 0 {
-		// Create new record
-		return r.db.Table("po_stage_tracking").Create(map[string]interface{}{
-			"production_order_id": poID,
-			"stage":               "KHAZWAL_CUTTING",
-			field:                 value,
-			"created_at":          time.Now(),
-			"updated_at":          time.Now(),
-		}).Error
-	}
-	
-	// Update existing record
-	return r.db.Table("po_stage_tracking").
-		Where("production_order_id = ?", poID).
-		Where("stage = ?", "KHAZWAL_CUTTING").
-		Updates(map[string]interface{}{
-			field:        value,
-			"updated_at": time.Now(),
-		}).Error
+// TransitionPO memindahkan status production order melalui central state machine
+// yang juga mencatat POStageTracking untuk audit trail
+func (r *repository) TransitionPO(poID uint64, to models.POStatus, userID *uint64, notes string, startedAt *time.Time) error {
+	_, err := r.transitions.TransitionInTx(r.db, services.POTransitionRequest{
+		POID:      poID,
+		To:        to,
+		HandledBy: userID,
+		Notes:     notes,
+		StartedAt: startedAt,
+	})
+	return err
 }
 
 // GetPOInfo mengambil info production order
 func (r *repository) GetPOInfo(poID uint64) (*POInfo, error) {
 	var info POInfo
 	err := r.db.Table("production_orders").
-		Select("po_number, obc_number, priority, target_quantity").
+		Select("po_number, obc_number, priority, quantity_target_lembar_besar as target_quantity").
 		Where("id = ?", poID).
 		First(&info).Error
 	
@@ -290,3 +301,77 @@ func (r *repository) GetOperatorInfo(userID uint64) (*OperatorInfo, error) {
 	
 	return &info, nil
 }
+
+// LockMachineByCode mengambil mesin dari machine registry berdasarkan kode dengan row lock,
+// sehingga pengecekan mesin sibuk dan start cutting lain pada mesin yang sama berjalan berurutan
+func (r *repository) LockMachineByCode(code string) (*models.Machine, error) {
+	var machine models.Machine
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&machine).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrMachineNotFound
+		}
+		return nil, err
+	}
+	return &machine, nil
+}
+
+// SetMachineStatus mengubah status mesin potong (RUNNING saat cutting dimulai, IDLE saat selesai)
+func (r *repository) SetMachineStatus(code string, status models.MachineStatus) error {
+	return r.db.Model(&models.Machine{}).
+		Where("code = ?", code).
+		Update("status", status).Error
+}
+
+// CountActiveCuttingByMachine menghitung cutting IN_PROGRESS yang memakai mesin tertentu
+func (r *repository) CountActiveCuttingByMachine(code string) (int64, error) {
+	var count int64
+	err := r.db.Model(&KhazwalCuttingResult{}).
+		Where("cutting_machine = ?", code).
+		Where("status = ?", CuttingInProgress).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateVerificationLabels membuat label verifikasi per 500 lembar kirim untuk PO
+// dan mengembalikan jumlah label yang dibuat
+func (r *repository) CreateVerificationLabels(poID uint64, poNumber int64, totalOutput int) (int, error) {
+	labels := verifikasi.BuildLabels(poID, poNumber, totalOutput)
+	if len(labels) == 0 {
+		return 0, nil
+	}
+	if err := r.db.Create(&labels).Error; err != nil {
+		return 0, err
+	}
+	return len(labels), nil
+}
+
+// NotifyHandover mengirim notifikasi serah terima ke unit yang mengerjakan stage tujuan PO
+func (r *repository) NotifyHandover(poID uint64, toStage models.POStage, title, message string) error {
+	return services.NotifyHandoverInTx(r.db, poID, toStage, title, message)
+}
+
+// NotifyEscalation mengirim notifikasi eskalasi ke manager departemen stage dan PPIC
+func (r *repository) NotifyEscalation(poID uint64, stage models.POStage, title, message string) error {
+	return services.NotifyEscalationInTx(r.db, poID, stage, title, message)
+}
+
+// EnqueueSAPPosting menulis posting material ke SAP outbox dalam transaction repository
+func (r *repository) EnqueueSAPPosting(posting services.SAPPosting) error {
+	return services.EnqueueSAPPostingInTx(r.db, posting)
+}
+
+// RecordEvent mencatat domain event yang dipublish ke event stream setelah transaction commit
+func (r *repository) RecordEvent(event services.DomainEvent) {
+	services.RecordDomainEventInTx(r.db, event)
+}
+
+// LinkWasteAttachment menghubungkan foto bukti waste ke cutting record dalam transaction repository,
+// foto waste sebelumnya dilepas dan akan dihapus oleh retention job
+func (r *repository) LinkWasteAttachment(cuttingID, attachmentID uint64) (*models.Attachment, error) {
+	attachments, err := services.LinkAttachmentsInTx(r.db, models.AttachmentOwnerCuttingWaste, cuttingID, []uint64{attachmentID})
+	if err != nil {
+		return nil, err
+	}
+	return &attachments[0], nil
+}
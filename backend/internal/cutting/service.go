@@ -1,8 +1,13 @@
 package cutting
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
 )
 
 // Service merupakan interface untuk business logic cutting
@@ -76,7 +81,8 @@ func (s *service) StartCutting(poID uint64, req StartCuttingRequest, userID uint
 		return nil, ErrCountingNotCompleted
 	}
 	
-	// 4. Create cutting record
+	// 4. Lock mesin potong, validasi terhadap machine registry, create cutting record,
+	// pindahkan PO ke CUTTING_IN_PROGRESS, dan tandai mesin RUNNING dalam satu transaction
 	now := time.Now()
 	inputLembarBesar := countingResult.QuantityGood
 	expectedOutput := inputLembarBesar * 2
@@ -85,30 +91,48 @@ func (s *service) StartCutting(poID uint64, req StartCuttingRequest, userID uint
 		ProductionOrderID: poID,
 		InputLembarBesar:  inputLembarBesar,
 		ExpectedOutput:    expectedOutput,
-		CuttingMachine:    req.CuttingMachine,
+		CuttingMachine:    strings.ToUpper(strings.TrimSpace(req.CuttingMachine)),
 		CutBy:             &userID,
 		Status:            CuttingInProgress,
 		StartedAt:         &now,
 	}
 	
-	err = s.repo.Create(cutting)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create cutting record: %w", err)
-	}
-	
-	// 5. Update PO status to SEDANG_DIPOTONG
-	err = s.repo.UpdatePOStatus(poID, "KHAZWAL_CUTTING", "SEDANG_DIPOTONG")
-	if err != nil {
-		return nil, fmt.Errorf("failed to update PO status: %w", err)
-	}
-	
-	// 6. Update po_stage_tracking with started_at
-	err = s.repo.UpdatePOStageTracking(poID, "started_at", now)
+	err = s.repo.WithTransaction(func(txRepo Repository) error {
+		machine, err := txRepo.LockMachineByCode(cutting.CuttingMachine)
+		if err != nil {
+			return err
+		}
+		if machine.Type != models.MachineTypeCutting || !machine.IsOperational() {
+			return ErrMachineNotAvailable
+		}
+		activeCount, err := txRepo.CountActiveCuttingByMachine(machine.Code)
+		if err != nil {
+			return fmt.Errorf("failed to check machine usage: %w", err)
+		}
+		if activeCount > 0 {
+			return ErrMachineBusy
+		}
+		
+		if err := txRepo.TransitionPO(poID, models.StatusCuttingInProgress, &userID, "Pemotongan dimulai", nil); err != nil {
+			var transitionErr *models.InvalidPOTransitionError
+			if errors.As(err, &transitionErr) {
+				return ErrPONotReadyForCutting
+			}
+			return fmt.Errorf("failed to update PO status: %w", err)
+		}
+		if err := txRepo.Create(cutting); err != nil {
+			return fmt.Errorf("failed to create cutting record: %w", err)
+		}
+		if err := txRepo.SetMachineStatus(machine.Code, models.MachineRunning); err != nil {
+			return fmt.Errorf("failed to update machine status: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update stage tracking: %w", err)
+		return nil, err
 	}
 	
-	// 7. Build response
+	// 5. Build response
 	return &StartCuttingResponse{
 		ID:                cutting.ID,
 		ProductionOrderID: cutting.ProductionOrderID,
@@ -147,12 +171,18 @@ func (s *service) UpdateCuttingResult(id uint64, req UpdateResultRequest) (*Upda
 	if req.WasteReason != "" {
 		cutting.WasteReason = req.WasteReason
 	}
-	if req.WastePhotoURL != "" {
-		cutting.WastePhotoURL = req.WastePhotoURL
-	}
 	
-	// 6. Save to database
-	err = s.repo.Update(cutting)
+	// 6. Link waste photo evidence and save to database in one transaction
+	err = s.repo.WithTransaction(func(txRepo Repository) error {
+		if req.WasteAttachmentID != nil {
+			attachment, err := txRepo.LinkWasteAttachment(cutting.ID, *req.WasteAttachmentID)
+			if err != nil {
+				return err
+			}
+			cutting.WastePhotoURL = attachment.URL
+		}
+		return txRepo.Update(cutting)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to update cutting result: %w", err)
 	}
@@ -200,35 +230,58 @@ func (s *service) FinalizeCutting(id uint64) (*FinalizeCuttingResponse, error) {
 	cutting.CompletedAt = &now
 	cutting.UpdateDuration()
 	
-	// 5. Save cutting record
-	err = s.repo.Update(cutting)
-	if err != nil {
-		return nil, fmt.Errorf("failed to finalize cutting: %w", err)
-	}
-	
-	// 6. Update PO status to SIAP_VERIFIKASI
-	err = s.repo.UpdatePOStatus(cutting.ProductionOrderID, "VERIFIKASI", "SIAP_VERIFIKASI")
+	// 5. Get PO info untuk nomor label verifikasi dan notifikasi
+	poInfo, err := s.repo.GetPOInfo(cutting.ProductionOrderID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update PO status: %w", err)
+		return nil, err
 	}
 	
-	// 7. Update po_stage_tracking with completed_at
-	err = s.repo.UpdatePOStageTracking(cutting.ProductionOrderID, "completed_at", now)
+	// 6. Save cutting record, pindahkan PO ke READY_FOR_VERIFIKASI, kembalikan mesin ke IDLE,
+	// generate verification labels (1 label per 500 lembar kirim), posting SAP outbox, dan notifikasi Tim Verifikasi
+	// dalam satu transaction
+	labelsGenerated := 0
+	err = s.repo.WithTransaction(func(txRepo Repository) error {
+		if err := txRepo.Update(cutting); err != nil {
+			return fmt.Errorf("failed to finalize cutting: %w", err)
+		}
+		if err := txRepo.TransitionPO(cutting.ProductionOrderID, models.StatusReadyForVerifikasi, cutting.CutBy, "Pemotongan selesai", cutting.StartedAt); err != nil {
+			return fmt.Errorf("failed to update PO status: %w", err)
+		}
+		if err := txRepo.SetMachineStatus(cutting.CuttingMachine, models.MachineIdle); err != nil {
+			return fmt.Errorf("failed to update machine status: %w", err)
+		}
+		txRepo.RecordEvent(services.NewFinalizedEvent(services.EventCuttingFinalized, models.StageKhazwalCutting, cutting.ProductionOrderID, cutting.ID, cutting.CutBy))
+		
+		count, err := txRepo.CreateVerificationLabels(cutting.ProductionOrderID, poInfo.PONumber, cutting.TotalOutput)
+		if err != nil {
+			return fmt.Errorf("failed to generate verification labels: %w", err)
+		}
+		labelsGenerated = count
+		
+		if err := txRepo.EnqueueSAPPosting(buildCuttingSAPPosting(cutting)); err != nil {
+			return fmt.Errorf("failed to write SAP outbox: %w", err)
+		}
+		
+		title := fmt.Sprintf("Siap Verifikasi - PO #%s", poInfo.OBCNumber)
+		message := fmt.Sprintf("Pemotongan PO #%d (OBC %s) selesai dengan %d label verifikasi. Silakan lakukan verifikasi.", poInfo.PONumber, poInfo.OBCNumber, labelsGenerated)
+		if err := txRepo.NotifyHandover(cutting.ProductionOrderID, models.StageVerifikasi, title, message); err != nil {
+			return fmt.Errorf("failed to notify verifikasi team: %w", err)
+		}
+		
+		if cutting.WasteExceedsThreshold(models.WasteThresholdPercentage) {
+			title := fmt.Sprintf("Waste Tinggi - PO #%s", poInfo.OBCNumber)
+			message := fmt.Sprintf("Pemotongan PO #%d (OBC %s) selesai dengan waste %.2f%%. Alasan: %s", poInfo.PONumber, poInfo.OBCNumber, *cutting.WastePercentage, cutting.WasteReason)
+			if err := txRepo.NotifyEscalation(cutting.ProductionOrderID, models.StageKhazwalCutting, title, message); err != nil {
+				return fmt.Errorf("failed to notify waste escalation: %w", err)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update stage tracking: %w", err)
+		return nil, err
 	}
 	
-	// 8. Generate verification labels (Sprint 3 feature)
-	// Total labels = CEIL(total_output / 500)
-	labelsGenerated := (cutting.TotalOutput + 499) / 500 // Integer ceiling division
-	
-	// TODO: Insert ke verification_labels table (Sprint 3)
-	// For now, just return the count
-	
-	// 9. Create notification to Tim Verifikasi (Sprint 3 feature)
-	// TODO: Implement notification creation
-	
-	// 10. Build response
+	// 7. Build response
 	return &FinalizeCuttingResponse{
 		ID:              cutting.ID,
 		Status:          string(cutting.Status),
@@ -279,3 +332,32 @@ func (s *service) GetCuttingDetail(id uint64) (*CuttingDetailResponse, error) {
 		PO:                  poInfo,
 	}, nil
 }
+
+// buildCuttingSAPPosting menyusun posting goods receipt lembar kirim hasil potong dan scrap waste,
+// material dikosongkan agar diisi kode produk SAP milik PO
+func buildCuttingSAPPosting(cutting *KhazwalCuttingResult) services.SAPPosting {
+	items := []services.SAPPostingItem{
+		{
+			Description:  "Lembar kirim hasil potong",
+			Quantity:     float64(cutting.TotalOutput),
+			Unit:         "lembar",
+			MovementType: services.SAPMovementGoodsReceipt,
+		},
+	}
+	if cutting.WasteQuantity > 0 {
+		items = append(items, services.SAPPostingItem{
+			Description:  "Waste pemotongan",
+			Quantity:     float64(cutting.WasteQuantity),
+			Unit:         "lembar",
+			MovementType: services.SAPMovementScrap,
+		})
+	}
+
+	return services.SAPPosting{
+		EventType:         models.SAPEventCuttingFinalized,
+		AggregateType:     "khazwal_cutting_result",
+		AggregateID:       cutting.ID,
+		ProductionOrderID: cutting.ProductionOrderID,
+		Items:             items,
+	}
+}
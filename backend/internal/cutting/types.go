@@ -9,7 +9,7 @@ import (
 var (
 	ErrCuttingNotFound            = errors.New("cutting record not found")
 	ErrPONotFound                 = errors.New("production order not found")
-	ErrPONotReadyForCutting       = errors.New("PO not in SIAP_POTONG status")
+	ErrPONotReadyForCutting       = errors.New("PO not in READY_FOR_CUTTING status")
 	ErrCuttingAlreadyStarted      = errors.New("cutting already started for this PO")
 	ErrCuttingNotInProgress       = errors.New("cutting not in progress")
 	ErrMissingOutputData          = errors.New("output sisiran kiri & kanan must be filled")
@@ -17,6 +17,9 @@ var (
 	ErrInvalidWasteData           = errors.New("invalid waste data")
 	ErrCuttingAlreadyCompleted    = errors.New("cutting already completed")
 	ErrCountingNotCompleted       = errors.New("counting result not completed yet")
+	ErrMachineNotFound            = errors.New("cutting machine not registered")
+	ErrMachineNotAvailable        = errors.New("cutting machine is down, under maintenance, or not a cutting machine")
+	ErrMachineBusy                = errors.New("cutting machine is in use by another cutting")
 )
 
 // Request & Response DTOs
@@ -31,6 +34,7 @@ type QueueItemResponse struct {
 	EstimatedOutput      int       `json:"estimated_output"`
 	CountingCompletedAt  time.Time `json:"counting_completed_at"`
 	WaitingMinutes       int       `json:"waiting_minutes"`
+	SLALevel             string    `json:"sla_level"`
 	IsOverdue            bool      `json:"is_overdue"`
 }
 
@@ -58,6 +62,7 @@ type QueueMetadata struct {
 
 // StartCuttingRequest merupakan request DTO untuk start cutting
 type StartCuttingRequest struct {
+	// CuttingMachine merupakan kode mesin potong yang terdaftar di machine registry
 	CuttingMachine string `json:"cutting_machine" binding:"required"`
 	// CutBy diambil dari auth user
 }
@@ -76,10 +81,10 @@ type StartCuttingResponse struct {
 
 // UpdateResultRequest merupakan request DTO untuk update cutting results
 type UpdateResultRequest struct {
-	OutputSisiranKiri  int    `json:"output_sisiran_kiri" binding:"required,min=0"`
-	OutputSisiranKanan int    `json:"output_sisiran_kanan" binding:"required,min=0"`
-	WasteReason        string `json:"waste_reason"`
-	WastePhotoURL      string `json:"waste_photo_url"`
+	OutputSisiranKiri  int     `json:"output_sisiran_kiri" binding:"required,min=0"`
+	OutputSisiranKanan int     `json:"output_sisiran_kanan" binding:"required,min=0"`
+	WasteReason        string  `json:"waste_reason"`
+	WasteAttachmentID  *uint64 `json:"waste_attachment_id"`
 }
 
 // UpdateResultResponse merupakan response DTO untuk update result
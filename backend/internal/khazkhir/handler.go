@@ -0,0 +1,258 @@
+package khazkhir
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"sirine-go/backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// KhazkhirHandler merupakan HTTP handler untuk Khazanah Akhir endpoints
+type KhazkhirHandler struct {
+	service KhazkhirService
+	db      *gorm.DB
+}
+
+// NewKhazkhirHandler membuat instance baru KhazkhirHandler
+func NewKhazkhirHandler(db *gorm.DB) *KhazkhirHandler {
+	repo := NewKhazkhirRepository(db)
+	service := NewKhazkhirService(db, repo)
+
+	return &KhazkhirHandler{
+		service: service,
+		db:      db,
+	}
+}
+
+// GetKhazkhirQueue menghandle GET /api/khazkhir/queue
+// untuk mengambil list PO yang siap atau sedang diproses Khazanah Akhir (FIFO sorted)
+func (h *KhazkhirHandler) GetKhazkhirQueue(c *gin.Context) {
+	response, err := h.service.GetKhazkhirQueue()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Gagal mengambil khazkhir queue",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response.Data,
+		"meta":    response.Meta,
+	})
+}
+
+// GetKhazkhirDetail menghandle GET /api/khazkhir/po/:po_id
+// untuk mengambil detail Khazanah Akhir PO beserta kemasan dan pengiriman
+func (h *KhazkhirHandler) GetKhazkhirDetail(c *gin.Context) {
+	poID, ok := parsePOID(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.GetKhazkhirDetail(poID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    detail,
+	})
+}
+
+// StartKhazkhir menghandle POST /api/khazkhir/po/:po_id/start
+// untuk memulai proses Khazanah Akhir dan update PO status
+func (h *KhazkhirHandler) StartKhazkhir(c *gin.Context) {
+	poID, ok := parsePOID(c)
+	if !ok {
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.StartKhazkhir(poID, userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Khazanah Akhir berhasil dimulai",
+		"data":    response,
+	})
+}
+
+// SubmitFinalCount menghandle PATCH /api/khazkhir/po/:po_id/count
+// untuk input penghitungan akhir HCS (bisa dipanggil multiple times sebelum pengemasan)
+func (h *KhazkhirHandler) SubmitFinalCount(c *gin.Context) {
+	poID, ok := parsePOID(c)
+	if !ok {
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req FinalCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.SubmitFinalCount(poID, req, userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Penghitungan akhir berhasil disimpan",
+		"data":    result,
+	})
+}
+
+// PackHCS menghandle POST /api/khazkhir/po/:po_id/pack
+// untuk mengemas HCS ke dalam rim dan box
+func (h *KhazkhirHandler) PackHCS(c *gin.Context) {
+	poID, ok := parsePOID(c)
+	if !ok {
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req PackRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Request body tidak valid",
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	response, err := h.service.PackHCS(poID, req, userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Pengemasan berhasil. PO siap dikirim.",
+		"data":    response,
+	})
+}
+
+// Dispatch menghandle POST /api/khazkhir/po/:po_id/dispatch
+// untuk mencatat pengiriman dan menyelesaikan PO
+func (h *KhazkhirHandler) Dispatch(c *gin.Context) {
+	poID, ok := parsePOID(c)
+	if !ok {
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req DispatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.Dispatch(poID, req, userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Pengiriman berhasil dicatat. PO selesai.",
+		"data":    response,
+	})
+}
+
+// handleError memetakan error Khazanah Akhir ke HTTP status code
+func (h *KhazkhirHandler) handleError(c *gin.Context, err error) {
+	statusCode := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, ErrPONotFound), errors.Is(err, ErrKhazkhirNotStarted):
+		statusCode = http.StatusNotFound
+	case errors.Is(err, ErrKhazkhirAlreadyExists),
+		errors.Is(err, ErrAlreadyDispatched),
+		errors.Is(err, ErrDeliveryDocumentExists),
+		errors.As(err, new(*models.InvalidPOTransitionError)):
+		statusCode = http.StatusConflict
+	case errors.Is(err, ErrPONotReadyForKhazkhir),
+		errors.Is(err, ErrVerifikasiNotCompleted),
+		errors.Is(err, ErrAlreadyCounted),
+		errors.Is(err, ErrNotCounted),
+		errors.Is(err, ErrNotPacked),
+		errors.Is(err, ErrInvalidRimsPerBox),
+		errors.Is(err, ErrRecipientRequired),
+		errors.Is(err, ErrDeliveryDocumentInvalid):
+		statusCode = http.StatusBadRequest
+	case errors.Is(err, ErrInvalidFinalCount), errors.Is(err, ErrVarianceReasonRequired):
+		statusCode = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(statusCode, gin.H{
+		"success": false,
+		"message": err.Error(),
+	})
+}
+
+// parsePOID mengambil PO ID dari path parameter
+func parsePOID(c *gin.Context) (uint64, bool) {
+	poID, err := strconv.ParseUint(c.Param("po_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "PO ID tidak valid",
+		})
+		return 0, false
+	}
+	return poID, true
+}
+
+// getUserID mengambil user ID dari context yang di-set oleh auth middleware
+func getUserID(c *gin.Context) (uint64, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User tidak terautentikasi",
+		})
+		return 0, false
+	}
+	return userID.(uint64), true
+}
@@ -0,0 +1,252 @@
+package khazkhir
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// KhazkhirStatus merupakan enum untuk status proses Khazanah Akhir per PO
+type KhazkhirStatus string
+
+const (
+	KhazkhirCounting   KhazkhirStatus = "COUNTING"
+	KhazkhirCounted    KhazkhirStatus = "COUNTED"
+	KhazkhirPacked     KhazkhirStatus = "PACKED"
+	KhazkhirDispatched KhazkhirStatus = "DISPATCHED"
+)
+
+// KhazkhirResult merupakan model untuk rekap proses Khazanah Akhir per PO
+// yang mencakup penghitungan akhir HCS, pengemasan dan pengiriman
+type KhazkhirResult struct {
+	ID                uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductionOrderID uint64 `gorm:"uniqueIndex;not null" json:"production_order_id"`
+
+	// Penghitungan Akhir (Lembar Kirim HCS)
+	InputHCS       int    `gorm:"not null;default:0" json:"input_hcs"`
+	FinalCount     *int   `gorm:"type:int null" json:"final_count"`
+	CountVariance  int    `gorm:"not null;default:0" json:"count_variance"`
+	VarianceReason string `gorm:"type:text" json:"variance_reason"`
+
+	// Pengemasan
+	TotalRims  int    `gorm:"not null;default:0" json:"total_rims"`
+	TotalBoxes int    `gorm:"not null;default:0" json:"total_boxes"`
+	RimsPerBox int    `gorm:"not null;default:0" json:"rims_per_box"`
+	PalletCode string `gorm:"type:varchar(50)" json:"pallet_code"`
+
+	// Status & Timing
+	Status          KhazkhirStatus `gorm:"type:varchar(20);not null;default:'COUNTING';index" json:"status"`
+	StartedAt       *time.Time     `gorm:"type:timestamp null" json:"started_at"`
+	CountedAt       *time.Time     `gorm:"type:timestamp null" json:"counted_at"`
+	PackedAt        *time.Time     `gorm:"type:timestamp null" json:"packed_at"`
+	DispatchedAt    *time.Time     `gorm:"type:timestamp null" json:"dispatched_at"`
+	DurationMinutes *int           `gorm:"type:int null" json:"duration_minutes"`
+
+	// Staff
+	StaffID *uint64 `gorm:"type:bigint unsigned null" json:"staff_id"`
+
+	// Timestamps
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName menentukan nama tabel di database
+func (KhazkhirResult) TableName() string {
+	return "khazkhir_results"
+}
+
+// IsCounted memeriksa apakah penghitungan akhir sudah dilakukan dan belum dikemas
+func (kr *KhazkhirResult) IsCounted() bool {
+	return kr.Status == KhazkhirCounted
+}
+
+// IsPacked memeriksa apakah pengemasan sudah selesai dan siap dikirim
+func (kr *KhazkhirResult) IsPacked() bool {
+	return kr.Status == KhazkhirPacked
+}
+
+// IsDispatched memeriksa apakah PO sudah dikirim
+func (kr *KhazkhirResult) IsDispatched() bool {
+	return kr.Status == KhazkhirDispatched
+}
+
+// UpdateDuration mengupdate DurationMinutes dari started_at ke dispatched_at
+func (kr *KhazkhirResult) UpdateDuration() {
+	if kr.StartedAt == nil || kr.DispatchedAt == nil {
+		return
+	}
+	duration := int(kr.DispatchedAt.Sub(*kr.StartedAt).Minutes())
+	kr.DurationMinutes = &duration
+}
+
+// KhazkhirBox merupakan model untuk kemasan box yang berisi beberapa rim HCS
+type KhazkhirBox struct {
+	ID                uint64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductionOrderID uint64  `gorm:"not null;index;uniqueIndex:idx_box_po_sequence" json:"production_order_id"`
+	BoxNumber         string  `gorm:"type:varchar(50);uniqueIndex;not null" json:"box_number"`
+	Sequence          int     `gorm:"not null;uniqueIndex:idx_box_po_sequence" json:"sequence"`
+	TotalBoxes        int     `gorm:"not null" json:"total_boxes"`
+	RimCount          int     `gorm:"not null" json:"rim_count"`
+	Quantity          int     `gorm:"not null" json:"quantity"`
+	ShipmentID        *uint64 `gorm:"index" json:"shipment_id"`
+
+	// Relations
+	Rims []KhazkhirRim `gorm:"foreignKey:BoxID" json:"rims,omitempty"`
+
+	// Timestamps
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName menentukan nama tabel di database
+func (KhazkhirBox) TableName() string {
+	return "khazkhir_boxes"
+}
+
+// KhazkhirRim merupakan model untuk kemasan per 1 rim (500 lembar kirim HCS)
+// dengan label nomor rim berformat urutan/total
+type KhazkhirRim struct {
+	ID                uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductionOrderID uint64 `gorm:"not null;index;uniqueIndex:idx_rim_po_sequence" json:"production_order_id"`
+	BoxID             uint64 `gorm:"not null;index" json:"box_id"`
+	RimNumber         string `gorm:"type:varchar(50);uniqueIndex;not null" json:"rim_number"`
+	Sequence          int    `gorm:"not null;uniqueIndex:idx_rim_po_sequence" json:"sequence"`
+	TotalRims         int    `gorm:"not null" json:"total_rims"`
+	Quantity          int    `gorm:"not null" json:"quantity"`
+
+	// Timestamps
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName menentukan nama tabel di database
+func (KhazkhirRim) TableName() string {
+	return "khazkhir_rims"
+}
+
+// Shipment merupakan model untuk record pengiriman hasil produksi per PO
+// beserta penerima dan nomor surat jalan
+type Shipment struct {
+	ID                     uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductionOrderID      uint64 `gorm:"uniqueIndex;not null" json:"production_order_id"`
+	DeliveryDocumentNumber string `gorm:"type:varchar(50);uniqueIndex;not null" json:"delivery_document_number"`
+	Recipient              string `gorm:"type:varchar(255);not null" json:"recipient"`
+	Destination            string `gorm:"type:varchar(255)" json:"destination"`
+	VehicleNumber          string `gorm:"type:varchar(50)" json:"vehicle_number"`
+
+	// Muatan
+	TotalBoxes    int `gorm:"not null" json:"total_boxes"`
+	TotalRims     int `gorm:"not null" json:"total_rims"`
+	TotalQuantity int `gorm:"not null" json:"total_quantity"`
+
+	// Staff & Timing
+	DispatchedBy uint64    `gorm:"not null" json:"dispatched_by"`
+	DispatchedAt time.Time `gorm:"not null" json:"dispatched_at"`
+	Notes        string    `gorm:"type:text" json:"notes"`
+
+	// Timestamps
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName menentukan nama tabel di database
+func (Shipment) TableName() string {
+	return "khazkhir_shipments"
+}
+
+// Request & Response DTOs
+
+// QueueItemResponse merupakan response DTO untuk item di khazkhir queue
+type QueueItemResponse struct {
+	POID                  uint64     `json:"po_id"`
+	PONumber              int64      `json:"po_number"`
+	OBCNumber             string     `json:"obc_number"`
+	Priority              string     `json:"priority"`
+	CurrentStatus         string     `json:"current_status"`
+	KhazkhirStatus        *string    `json:"khazkhir_status"`
+	TotalHCS              int        `json:"total_hcs"`
+	VerifikasiCompletedAt *time.Time `json:"verifikasi_completed_at"`
+	WaitingMinutes        int        `json:"waiting_minutes"`
+	SLALevel              string     `json:"sla_level"`
+	IsOverdue             bool       `json:"is_overdue"`
+}
+
+// QueueResponse merupakan response DTO untuk queue endpoint
+type QueueResponse struct {
+	Data []QueueItemResponse `json:"data"`
+	Meta QueueMetadata       `json:"meta"`
+}
+
+// QueueMetadata merupakan metadata untuk queue response
+type QueueMetadata struct {
+	Total        int `json:"total"`
+	InProgress   int `json:"in_progress"`
+	OverdueCount int `json:"overdue_count"`
+}
+
+// KhazkhirDetailResponse merupakan response DTO untuk detail Khazanah Akhir per PO
+type KhazkhirDetailResponse struct {
+	POID      uint64          `json:"po_id"`
+	PONumber  int64           `json:"po_number"`
+	OBCNumber string          `json:"obc_number"`
+	Status    string          `json:"status"`
+	Result    *KhazkhirResult `json:"result,omitempty"`
+	Boxes     []KhazkhirBox   `json:"boxes"`
+	Shipment  *Shipment       `json:"shipment,omitempty"`
+}
+
+// StartKhazkhirResponse merupakan response DTO untuk start Khazanah Akhir
+type StartKhazkhirResponse struct {
+	ID                uint64    `json:"id"`
+	ProductionOrderID uint64    `json:"production_order_id"`
+	Status            string    `json:"status"`
+	InputHCS          int       `json:"input_hcs"`
+	StartedAt         time.Time `json:"started_at"`
+	StaffID           uint64    `json:"staff_id"`
+}
+
+// FinalCountRequest merupakan request DTO untuk input penghitungan akhir HCS
+type FinalCountRequest struct {
+	FinalCount     int    `json:"final_count" binding:"min=0"`
+	VarianceReason string `json:"variance_reason"`
+}
+
+// PackRequest merupakan request DTO untuk pengemasan HCS ke rim dan box
+type PackRequest struct {
+	RimsPerBox int    `json:"rims_per_box" binding:"omitempty,min=1"`
+	PalletCode string `json:"pallet_code"`
+}
+
+// PackResponse merupakan response DTO untuk hasil pengemasan
+type PackResponse struct {
+	ID         uint64        `json:"id"`
+	Status     string        `json:"status"`
+	FinalCount int           `json:"final_count"`
+	TotalRims  int           `json:"total_rims"`
+	TotalBoxes int           `json:"total_boxes"`
+	RimsPerBox int           `json:"rims_per_box"`
+	PalletCode string        `json:"pallet_code"`
+	Boxes      []KhazkhirBox `json:"boxes"`
+}
+
+// DispatchRequest merupakan request DTO untuk pengiriman hasil produksi
+type DispatchRequest struct {
+	Recipient              string `json:"recipient" binding:"required"`
+	DeliveryDocumentNumber string `json:"delivery_document_number" binding:"required"`
+	Destination            string `json:"destination"`
+	VehicleNumber          string `json:"vehicle_number"`
+	Notes                  string `json:"notes"`
+}
+
+// DispatchResponse merupakan response DTO untuk hasil pengiriman
+type DispatchResponse struct {
+	ID              uint64    `json:"id"`
+	Status          string    `json:"status"`
+	Shipment        Shipment  `json:"shipment"`
+	DispatchedAt    time.Time `json:"dispatched_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+}
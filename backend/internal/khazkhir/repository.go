@@ -0,0 +1,170 @@
+package khazkhir
+
+import (
+	"fmt"
+	"time"
+
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+
+	"gorm.io/gorm"
+)
+
+// KhazkhirRepository merupakan interface untuk database operations Khazanah Akhir
+type KhazkhirRepository interface {
+	// Queue operations
+	GetKhazkhirQueue() ([]QueueItemResponse, error)
+
+	// Result operations
+	GetResultByPOID(poID uint64) (*KhazkhirResult, error)
+
+	// Packaging & shipment operations
+	GetBoxesByPOID(poID uint64) ([]KhazkhirBox, error)
+	GetShipmentByPOID(poID uint64) (*Shipment, error)
+
+	// Related data operations
+	GetPOInfo(poID uint64) (*POInfo, error)
+}
+
+// POInfo merupakan info Production Order untuk Khazanah Akhir
+type POInfo struct {
+	ID            uint64 `gorm:"column:id"`
+	PONumber      int64  `gorm:"column:po_number"`
+	OBCNumber     string `gorm:"column:obc_number"`
+	CurrentStatus string `gorm:"column:current_status"`
+}
+
+// khazkhirRepositoryImpl merupakan implementasi KhazkhirRepository
+type khazkhirRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKhazkhirRepository membuat instance baru KhazkhirRepository
+func NewKhazkhirRepository(db *gorm.DB) KhazkhirRepository {
+	return &khazkhirRepositoryImpl{db: db}
+}
+
+// GetKhazkhirQueue mengambil list PO yang siap atau sedang diproses Khazanah Akhir (FIFO)
+// dengan total HCS dan waktu tunggu sejak verifikasi selesai
+func (r *khazkhirRepositoryImpl) GetKhazkhirQueue() ([]QueueItemResponse, error) {
+	var rows []struct {
+		POID                  uint64
+		PONumber              int64
+		OBCNumber             string
+		Priority              string
+		CurrentStatus         string
+		KhazkhirStatus        *string
+		TotalHCS              int
+		VerifikasiCompletedAt *time.Time
+	}
+
+	thresholds, err := services.LoadSLAThresholds(r.db)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat SLA khazkhir: %w", err)
+	}
+
+	err = r.db.Table("production_orders po").
+		Select(`
+			po.id as po_id,
+			po.po_number,
+			po.obc_number,
+			po.priority,
+			po.current_status,
+			kr.status as khazkhir_status,
+			COALESCE(vr.total_hcs, 0) as total_hcs,
+			vr.completed_at as verifikasi_completed_at
+		`).
+		Joins("LEFT JOIN verifikasi_results vr ON vr.production_order_id = po.id AND vr.deleted_at IS NULL").
+		Joins("LEFT JOIN khazkhir_results kr ON kr.production_order_id = po.id AND kr.deleted_at IS NULL").
+		Where("po.current_status IN ?", []models.POStatus{models.StatusReadyForKhazkhir, models.StatusKhazkhirInProgress}).
+		Where("po.deleted_at IS NULL").
+		Order("vr.completed_at ASC"). // FIFO
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("gagal query khazkhir queue: %w", err)
+	}
+
+	results := make([]QueueItemResponse, 0, len(rows))
+	for _, row := range rows {
+		item := QueueItemResponse{
+			POID:                  row.POID,
+			PONumber:              row.PONumber,
+			OBCNumber:             row.OBCNumber,
+			Priority:              row.Priority,
+			CurrentStatus:         row.CurrentStatus,
+			KhazkhirStatus:        row.KhazkhirStatus,
+			TotalHCS:              row.TotalHCS,
+			VerifikasiCompletedAt: row.VerifikasiCompletedAt,
+		}
+		if row.VerifikasiCompletedAt != nil {
+			sla := thresholds.Status(models.StageKhazkhir, models.POPriority(row.Priority), *row.VerifikasiCompletedAt, time.Now())
+			item.WaitingMinutes = sla.WaitingMinutes
+			item.SLALevel = string(sla.Level)
+			item.IsOverdue = sla.IsOverdue
+		}
+		results = append(results, item)
+	}
+
+	return results, nil
+}
+
+// GetResultByPOID mengambil rekap Khazanah Akhir berdasarkan Production Order ID
+func (r *khazkhirRepositoryImpl) GetResultByPOID(poID uint64) (*KhazkhirResult, error) {
+	var result KhazkhirResult
+
+	if err := r.db.Where("production_order_id = ?", poID).First(&result).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil rekap khazkhir: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetBoxesByPOID mengambil semua box beserta rim untuk PO urut berdasarkan sequence
+func (r *khazkhirRepositoryImpl) GetBoxesByPOID(poID uint64) ([]KhazkhirBox, error) {
+	var boxes []KhazkhirBox
+
+	if err := r.db.Where("production_order_id = ?", poID).
+		Preload("Rims", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence ASC")
+		}).
+		Order("sequence ASC").
+		Find(&boxes).Error; err != nil {
+		return nil, fmt.Errorf("gagal mengambil kemasan: %w", err)
+	}
+
+	return boxes, nil
+}
+
+// GetShipmentByPOID mengambil record pengiriman berdasarkan Production Order ID
+func (r *khazkhirRepositoryImpl) GetShipmentByPOID(poID uint64) (*Shipment, error) {
+	var shipment Shipment
+
+	if err := r.db.Where("production_order_id = ?", poID).First(&shipment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil pengiriman: %w", err)
+	}
+
+	return &shipment, nil
+}
+
+// GetPOInfo mengambil info Production Order
+func (r *khazkhirRepositoryImpl) GetPOInfo(poID uint64) (*POInfo, error) {
+	var info POInfo
+
+	if err := r.db.Table("production_orders").
+		Select("id, po_number, obc_number, current_status").
+		Where("id = ? AND deleted_at IS NULL", poID).
+		First(&info).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPONotFound
+		}
+		return nil, fmt.Errorf("gagal mengambil PO: %w", err)
+	}
+
+	return &info, nil
+}
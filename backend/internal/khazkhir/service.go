@@ -0,0 +1,500 @@
+package khazkhir
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sirine-go/backend/internal/verifikasi"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// KhazkhirService merupakan interface untuk business logic Khazanah Akhir operations
+type KhazkhirService interface {
+	// Queue operations
+	GetKhazkhirQueue() (*QueueResponse, error)
+
+	// Detail operations
+	GetKhazkhirDetail(poID uint64) (*KhazkhirDetailResponse, error)
+
+	// Action operations
+	StartKhazkhir(poID uint64, userID uint64) (*StartKhazkhirResponse, error)
+	SubmitFinalCount(poID uint64, req FinalCountRequest, userID uint64) (*KhazkhirResult, error)
+	PackHCS(poID uint64, req PackRequest, userID uint64) (*PackResponse, error)
+	Dispatch(poID uint64, req DispatchRequest, userID uint64) (*DispatchResponse, error)
+}
+
+// khazkhirServiceImpl merupakan implementasi KhazkhirService
+type khazkhirServiceImpl struct {
+	db          *gorm.DB
+	repo        KhazkhirRepository
+	transitions *services.POTransitionService
+}
+
+// NewKhazkhirService membuat instance baru KhazkhirService
+func NewKhazkhirService(db *gorm.DB, repo KhazkhirRepository) KhazkhirService {
+	return &khazkhirServiceImpl{
+		db:          db,
+		repo:        repo,
+		transitions: services.NewPOTransitionService(db),
+	}
+}
+
+// GetKhazkhirQueue mengambil list PO yang menunggu atau sedang diproses Khazanah Akhir
+func (s *khazkhirServiceImpl) GetKhazkhirQueue() (*QueueResponse, error) {
+	items, err := s.repo.GetKhazkhirQueue()
+	if err != nil {
+		return nil, err
+	}
+
+	meta := QueueMetadata{Total: len(items)}
+	for _, item := range items {
+		if item.CurrentStatus == string(models.StatusKhazkhirInProgress) {
+			meta.InProgress++
+		}
+		if item.IsOverdue {
+			meta.OverdueCount++
+		}
+	}
+
+	return &QueueResponse{
+		Data: items,
+		Meta: meta,
+	}, nil
+}
+
+// GetKhazkhirDetail mengambil detail Khazanah Akhir PO beserta kemasan dan pengiriman
+func (s *khazkhirServiceImpl) GetKhazkhirDetail(poID uint64) (*KhazkhirDetailResponse, error) {
+	po, err := s.repo.GetPOInfo(poID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.GetResultByPOID(poID)
+	if err != nil {
+		return nil, err
+	}
+
+	boxes, err := s.repo.GetBoxesByPOID(poID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment, err := s.repo.GetShipmentByPOID(poID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KhazkhirDetailResponse{
+		POID:      po.ID,
+		PONumber:  po.PONumber,
+		OBCNumber: po.OBCNumber,
+		Status:    po.CurrentStatus,
+		Result:    result,
+		Boxes:     boxes,
+		Shipment:  shipment,
+	}, nil
+}
+
+// StartKhazkhir memulai proses Khazanah Akhir untuk PO dengan status READY_FOR_KHAZKHIR
+// dengan input HCS diambil dari rekap verifikasi
+func (s *khazkhirServiceImpl) StartKhazkhir(poID uint64, userID uint64) (*StartKhazkhirResponse, error) {
+	eventDB, events := services.WithDomainEvents(s.db)
+	tx := eventDB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Pindahkan PO ke KHAZKHIR_IN_PROGRESS via state machine
+	// yang sekaligus memvalidasi PO dalam status READY_FOR_KHAZKHIR
+	if _, err := s.transitions.TransitionInTx(tx, services.POTransitionRequest{
+		POID:      poID,
+		To:        models.StatusKhazkhirInProgress,
+		HandledBy: &userID,
+		Notes:     "Khazanah Akhir dimulai",
+	}); err != nil {
+		tx.Rollback()
+		var transitionErr *models.InvalidPOTransitionError
+		if errors.As(err, &transitionErr) {
+			return nil, ErrPONotReadyForKhazkhir
+		}
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPONotFound
+		}
+		return nil, fmt.Errorf("gagal update PO status: %w", err)
+	}
+
+	// 2. Get rekap verifikasi sebagai input HCS
+	var verifikasiResult verifikasi.VerifikasiResult
+	if err := tx.Where("production_order_id = ?", poID).First(&verifikasiResult).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrVerifikasiNotCompleted
+		}
+		return nil, fmt.Errorf("gagal mengambil rekap verifikasi: %w", err)
+	}
+	if !verifikasiResult.IsCompleted() {
+		tx.Rollback()
+		return nil, ErrVerifikasiNotCompleted
+	}
+
+	// 3. Check belum ada rekap Khazanah Akhir untuk PO ini
+	var existing int64
+	if err := tx.Model(&KhazkhirResult{}).
+		Where("production_order_id = ?", poID).
+		Count(&existing).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal check existing khazkhir: %w", err)
+	}
+	if existing > 0 {
+		tx.Rollback()
+		return nil, ErrKhazkhirAlreadyExists
+	}
+
+	// 4. Create rekap Khazanah Akhir
+	now := time.Now()
+	result := &KhazkhirResult{
+		ProductionOrderID: poID,
+		InputHCS:          verifikasiResult.TotalHCS,
+		Status:            KhazkhirCounting,
+		StartedAt:         &now,
+		StaffID:           &userID,
+	}
+	if err := tx.Create(result).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membuat rekap khazkhir: %w", err)
+	}
+
+	// 5. Log activity
+	s.logActivity(tx, userID, "START_KHAZKHIR", result.ID, poID,
+		fmt.Sprintf("Memulai Khazanah Akhir untuk PO %d", poID))
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaction: %w", err)
+	}
+	events.Publish()
+
+	return &StartKhazkhirResponse{
+		ID:                result.ID,
+		ProductionOrderID: poID,
+		Status:            string(KhazkhirCounting),
+		InputHCS:          result.InputHCS,
+		StartedAt:         now,
+		StaffID:           userID,
+	}, nil
+}
+
+// SubmitFinalCount menyimpan hasil penghitungan akhir HCS
+// (dapat dipanggil ulang untuk koreksi sebelum pengemasan).
+// Rekap di-lock dengan lock yang sama dengan PackHCS dan status dicek ulang,
+// sehingga final count tidak bisa berubah setelah HCS dikemas
+func (s *khazkhirServiceImpl) SubmitFinalCount(poID uint64, req FinalCountRequest, userID uint64) (*KhazkhirResult, error) {
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Get rekap Khazanah Akhir dengan lock
+	result, err := s.lockResult(tx, poID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if result.Status != KhazkhirCounting && result.Status != KhazkhirCounted {
+		tx.Rollback()
+		return nil, ErrAlreadyCounted
+	}
+
+	// 2. Validate request dengan business rules
+	if err := ValidateFinalCount(req, result.InputHCS); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 3. Update rekap
+	now := time.Now()
+	finalCount := req.FinalCount
+	result.FinalCount = &finalCount
+	result.CountVariance = req.FinalCount - result.InputHCS
+	result.VarianceReason = req.VarianceReason
+	result.Status = KhazkhirCounted
+	result.CountedAt = &now
+	result.StaffID = &userID
+
+	if err := tx.Save(result).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal update rekap khazkhir: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// PackHCS mengemas HCS hasil penghitungan akhir per 1 rim (500 lembar kirim)
+// yang dikelompokkan ke dalam box per PO
+func (s *khazkhirServiceImpl) PackHCS(poID uint64, req PackRequest, userID uint64) (*PackResponse, error) {
+	rimsPerBox := req.RimsPerBox
+	if rimsPerBox == 0 {
+		rimsPerBox = DefaultRimsPerBox
+	}
+	if err := ValidateRimsPerBox(rimsPerBox); err != nil {
+		return nil, err
+	}
+
+	eventDB, events := services.WithDomainEvents(s.db)
+	tx := eventDB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Get rekap Khazanah Akhir dengan lock
+	result, err := s.lockResult(tx, poID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if !result.IsCounted() || result.FinalCount == nil {
+		tx.Rollback()
+		return nil, ErrNotCounted
+	}
+
+	po, err := s.getPOInfo(tx, poID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 2. Generate kemasan rim dan box
+	boxes := BuildPackages(poID, po.PONumber, *result.FinalCount, rimsPerBox)
+	if err := tx.Create(&boxes).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membuat kemasan: %w", err)
+	}
+
+	// 3. Update rekap
+	now := time.Now()
+	result.TotalRims = CalculateRimCount(*result.FinalCount)
+	result.TotalBoxes = len(boxes)
+	result.RimsPerBox = rimsPerBox
+	result.PalletCode = strings.TrimSpace(req.PalletCode)
+	result.Status = KhazkhirPacked
+	result.PackedAt = &now
+
+	if err := tx.Save(result).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal update rekap khazkhir: %w", err)
+	}
+
+	// 4. Log activity
+	s.logActivity(tx, userID, "PACK_KHAZKHIR", result.ID, poID,
+		fmt.Sprintf("Mengemas PO %d: %d rim dalam %d box", poID, result.TotalRims, result.TotalBoxes))
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaction: %w", err)
+	}
+	events.Publish()
+
+	return &PackResponse{
+		ID:         result.ID,
+		Status:     string(result.Status),
+		FinalCount: *result.FinalCount,
+		TotalRims:  result.TotalRims,
+		TotalBoxes: result.TotalBoxes,
+		RimsPerBox: result.RimsPerBox,
+		PalletCode: result.PalletCode,
+		Boxes:      boxes,
+	}, nil
+}
+
+// Dispatch mencatat pengiriman hasil produksi dengan penerima dan nomor surat jalan,
+// lalu menyelesaikan PO (stage COMPLETED) dan mengirim notifikasi ke PPIC
+func (s *khazkhirServiceImpl) Dispatch(poID uint64, req DispatchRequest, userID uint64) (*DispatchResponse, error) {
+	if err := ValidateDispatchRequest(req); err != nil {
+		return nil, err
+	}
+	documentNumber := strings.ToUpper(strings.TrimSpace(req.DeliveryDocumentNumber))
+
+	eventDB, events := services.WithDomainEvents(s.db)
+	tx := eventDB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Get rekap Khazanah Akhir dengan lock
+	result, err := s.lockResult(tx, poID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if result.IsDispatched() {
+		tx.Rollback()
+		return nil, ErrAlreadyDispatched
+	}
+	if !result.IsPacked() {
+		tx.Rollback()
+		return nil, ErrNotPacked
+	}
+
+	// 2. Check nomor surat jalan belum digunakan
+	var documentCount int64
+	if err := tx.Model(&Shipment{}).
+		Where("delivery_document_number = ?", documentNumber).
+		Count(&documentCount).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal check surat jalan: %w", err)
+	}
+	if documentCount > 0 {
+		tx.Rollback()
+		return nil, ErrDeliveryDocumentExists
+	}
+
+	// 3. Create record pengiriman
+	now := time.Now()
+	shipment := Shipment{
+		ProductionOrderID:      poID,
+		DeliveryDocumentNumber: documentNumber,
+		Recipient:              strings.TrimSpace(req.Recipient),
+		Destination:            strings.TrimSpace(req.Destination),
+		VehicleNumber:          strings.TrimSpace(req.VehicleNumber),
+		TotalBoxes:             result.TotalBoxes,
+		TotalRims:              result.TotalRims,
+		TotalQuantity:          *result.FinalCount,
+		DispatchedBy:           userID,
+		DispatchedAt:           now,
+		Notes:                  req.Notes,
+	}
+	if err := tx.Create(&shipment).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membuat pengiriman: %w", err)
+	}
+
+	if err := tx.Model(&KhazkhirBox{}).
+		Where("production_order_id = ?", poID).
+		Update("shipment_id", shipment.ID).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal update kemasan: %w", err)
+	}
+
+	// 4. Update rekap
+	result.Status = KhazkhirDispatched
+	result.DispatchedAt = &now
+	result.UpdateDuration()
+
+	if err := tx.Save(result).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal update rekap khazkhir: %w", err)
+	}
+
+	// 5. Selesaikan PO via state machine (stage COMPLETED)
+	if _, err := s.transitions.TransitionInTx(tx, services.POTransitionRequest{
+		POID:      poID,
+		To:        models.StatusPOCompleted,
+		HandledBy: &userID,
+		Notes:     fmt.Sprintf("Dikirim ke %s dengan surat jalan %s", shipment.Recipient, shipment.DeliveryDocumentNumber),
+		StartedAt: result.StartedAt,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	services.RecordDomainEventInTx(tx, services.NewFinalizedEvent(services.EventKhazkhirFinalized, models.StageKhazkhir, poID, result.ID, &userID))
+
+	// 6. Notifikasi PO selesai ke PPIC
+	po, err := s.getPOInfo(tx, poID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := services.NotifyHandoverInTx(tx, poID, models.StageCompleted,
+		"PO Selesai - PO #"+po.OBCNumber,
+		fmt.Sprintf("PO #%d (OBC %s) telah dikirim ke %s: %d rim (%d lembar), surat jalan %s.", po.PONumber, po.OBCNumber, shipment.Recipient, shipment.TotalRims, shipment.TotalQuantity, shipment.DeliveryDocumentNumber),
+	); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 7. Log activity (immutable)
+	s.logActivity(tx, userID, "DISPATCH_KHAZKHIR", shipment.ID, poID,
+		fmt.Sprintf("Mengirim PO %d dengan surat jalan %s", poID, shipment.DeliveryDocumentNumber))
+
+	// 8. Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaction: %w", err)
+	}
+	events.Publish()
+
+	durationMinutes := 0
+	if result.DurationMinutes != nil {
+		durationMinutes = *result.DurationMinutes
+	}
+
+	return &DispatchResponse{
+		ID:              result.ID,
+		Status:          string(KhazkhirDispatched),
+		Shipment:        shipment,
+		DispatchedAt:    now,
+		DurationMinutes: durationMinutes,
+	}, nil
+}
+
+// lockResult mengambil rekap Khazanah Akhir dengan row lock di dalam transaction
+func (s *khazkhirServiceImpl) lockResult(tx *gorm.DB, poID uint64) (*KhazkhirResult, error) {
+	var result KhazkhirResult
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("production_order_id = ?", poID).
+		First(&result).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrKhazkhirNotStarted
+		}
+		return nil, fmt.Errorf("gagal mengambil rekap khazkhir: %w", err)
+	}
+	return &result, nil
+}
+
+// getPOInfo mengambil info PO di dalam transaction
+func (s *khazkhirServiceImpl) getPOInfo(tx *gorm.DB, poID uint64) (*POInfo, error) {
+	var po POInfo
+	if err := tx.Table("production_orders").
+		Select("id, po_number, obc_number, current_status").
+		Where("id = ?", poID).
+		First(&po).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPONotFound
+		}
+		return nil, fmt.Errorf("gagal mengambil PO: %w", err)
+	}
+	return &po, nil
+}
+
+// logActivity mencatat activity log Khazanah Akhir (non-critical)
+func (s *khazkhirServiceImpl) logActivity(tx *gorm.DB, userID uint64, action string, entityID uint64, poID uint64, description string) {
+	activityLog := map[string]interface{}{
+		"user_id":             userID,
+		"action":              action,
+		"entity_type":         "KHAZKHIR",
+		"entity_id":           entityID,
+		"production_order_id": poID,
+		"description":         description,
+		"created_at":          time.Now(),
+	}
+	if err := tx.Table("activity_logs").Create(activityLog).Error; err != nil {
+		// Log error tapi tidak rollback (non-critical)
+		fmt.Printf("Warning: gagal log activity: %v\n", err)
+	}
+}
@@ -0,0 +1,120 @@
+package khazkhir
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationErrors merupakan collection of validation errors
+var (
+	ErrPONotFound              = errors.New("PO tidak ditemukan")
+	ErrPONotReadyForKhazkhir   = errors.New("PO belum siap untuk Khazanah Akhir")
+	ErrKhazkhirAlreadyExists   = errors.New("proses Khazanah Akhir untuk PO ini sudah dimulai")
+	ErrKhazkhirNotStarted      = errors.New("proses Khazanah Akhir untuk PO ini belum dimulai")
+	ErrVerifikasiNotCompleted  = errors.New("rekap verifikasi untuk PO ini belum selesai")
+	ErrInvalidFinalCount       = errors.New("final_count harus > 0")
+	ErrVarianceReasonRequired  = errors.New("variance_reason wajib diisi karena ada selisih dari HCS verifikasi")
+	ErrAlreadyCounted          = errors.New("penghitungan akhir sudah dilakukan dan PO sudah dikemas")
+	ErrNotCounted              = errors.New("penghitungan akhir belum dilakukan")
+	ErrNotPacked               = errors.New("PO belum dikemas")
+	ErrAlreadyDispatched       = errors.New("PO sudah dikirim")
+	ErrInvalidRimsPerBox       = errors.New("rims_per_box harus antara 1 dan 100")
+	ErrRecipientRequired       = errors.New("recipient wajib diisi")
+	ErrDeliveryDocumentInvalid = errors.New("delivery_document_number wajib diisi")
+	ErrDeliveryDocumentExists  = errors.New("nomor surat jalan sudah digunakan")
+)
+
+// Constants untuk Khazanah Akhir
+const (
+	SheetsPerRim      = 500 // 1 rim = 500 lembar kirim
+	DefaultRimsPerBox = 10  // jumlah rim per box jika tidak ditentukan
+	MaxRimsPerBox     = 100
+)
+
+// CalculateRimCount menghitung jumlah rim dari total HCS (ceiling division)
+func CalculateRimCount(quantity int) int {
+	if quantity <= 0 {
+		return 0
+	}
+	return (quantity + SheetsPerRim - 1) / SheetsPerRim
+}
+
+// ValidateFinalCount memvalidasi input penghitungan akhir terhadap total HCS verifikasi
+// dengan business rules: final count > 0 dan variance reason wajib jika ada selisih
+func ValidateFinalCount(req FinalCountRequest, inputHCS int) error {
+	if req.FinalCount <= 0 {
+		return ErrInvalidFinalCount
+	}
+
+	if req.FinalCount != inputHCS && strings.TrimSpace(req.VarianceReason) == "" {
+		return ErrVarianceReasonRequired
+	}
+
+	return nil
+}
+
+// ValidateRimsPerBox memvalidasi jumlah rim per box
+func ValidateRimsPerBox(rimsPerBox int) error {
+	if rimsPerBox < 1 || rimsPerBox > MaxRimsPerBox {
+		return ErrInvalidRimsPerBox
+	}
+	return nil
+}
+
+// ValidateDispatchRequest memvalidasi data pengiriman
+func ValidateDispatchRequest(req DispatchRequest) error {
+	if strings.TrimSpace(req.Recipient) == "" {
+		return ErrRecipientRequired
+	}
+	if strings.TrimSpace(req.DeliveryDocumentNumber) == "" {
+		return ErrDeliveryDocumentInvalid
+	}
+	return nil
+}
+
+// BuildPackages membuat kemasan rim per 500 lembar kirim yang dikelompokkan ke dalam box
+// dengan rim terakhir berisi sisa lembar dan box terakhir berisi sisa rim
+func BuildPackages(poID uint64, poNumber int64, quantity int, rimsPerBox int) []KhazkhirBox {
+	totalRims := CalculateRimCount(quantity)
+	if totalRims == 0 || rimsPerBox <= 0 {
+		return []KhazkhirBox{}
+	}
+	totalBoxes := (totalRims + rimsPerBox - 1) / rimsPerBox
+
+	boxes := make([]KhazkhirBox, 0, totalBoxes)
+	remaining := quantity
+	rimSeq := 0
+
+	for b := 1; b <= totalBoxes; b++ {
+		box := KhazkhirBox{
+			ProductionOrderID: poID,
+			BoxNumber:         fmt.Sprintf("BOX-%d-%03d", poNumber, b),
+			Sequence:          b,
+			TotalBoxes:        totalBoxes,
+		}
+
+		for r := 0; r < rimsPerBox && rimSeq < totalRims; r++ {
+			rimSeq++
+			rimQty := SheetsPerRim
+			if remaining < SheetsPerRim {
+				rimQty = remaining
+			}
+			remaining -= rimQty
+
+			box.Rims = append(box.Rims, KhazkhirRim{
+				ProductionOrderID: poID,
+				RimNumber:         fmt.Sprintf("RIM-%d-%03d", poNumber, rimSeq),
+				Sequence:          rimSeq,
+				TotalRims:         totalRims,
+				Quantity:          rimQty,
+			})
+			box.RimCount++
+			box.Quantity += rimQty
+		}
+
+		boxes = append(boxes, box)
+	}
+
+	return boxes
+}
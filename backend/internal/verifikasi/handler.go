@@ -0,0 +1,229 @@
+package verifikasi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"sirine-go/backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// VerifikasiHandler merupakan HTTP handler untuk verifikasi (QC) endpoints
+type VerifikasiHandler struct {
+	service VerifikasiService
+	db      *gorm.DB
+}
+
+// NewVerifikasiHandler membuat instance baru VerifikasiHandler
+func NewVerifikasiHandler(db *gorm.DB) *VerifikasiHandler {
+	repo := NewVerifikasiRepository(db)
+	service := NewVerifikasiService(db, repo)
+
+	return &VerifikasiHandler{
+		service: service,
+		db:      db,
+	}
+}
+
+// GetVerifikasiQueue menghandle GET /api/verifikasi/queue
+// untuk mengambil list PO yang siap atau sedang diverifikasi (FIFO sorted)
+func (h *VerifikasiHandler) GetVerifikasiQueue(c *gin.Context) {
+	response, err := h.service.GetVerifikasiQueue()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Gagal mengambil verifikasi queue",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response.Data,
+		"meta":    response.Meta,
+	})
+}
+
+// GetVerifikasiDetail menghandle GET /api/verifikasi/po/:po_id
+// untuk mengambil detail verifikasi PO beserta semua label
+func (h *VerifikasiHandler) GetVerifikasiDetail(c *gin.Context) {
+	poID, err := strconv.ParseUint(c.Param("po_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "PO ID tidak valid",
+		})
+		return
+	}
+
+	detail, err := h.service.GetVerifikasiDetail(poID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == ErrPONotFound {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    detail,
+	})
+}
+
+// StartVerifikasi menghandle POST /api/verifikasi/po/:po_id/start
+// untuk memulai proses verifikasi dan update PO status
+func (h *VerifikasiHandler) StartVerifikasi(c *gin.Context) {
+	poID, err := strconv.ParseUint(c.Param("po_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "PO ID tidak valid",
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User tidak terautentikasi",
+		})
+		return
+	}
+
+	response, err := h.service.StartVerifikasi(poID, userID.(uint64))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == ErrPONotFound {
+			statusCode = http.StatusNotFound
+		} else if err == ErrPONotReadyForVerifikasi || err == ErrLabelsNotGenerated {
+			statusCode = http.StatusBadRequest
+		} else if err == ErrVerifikasiAlreadyExists {
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Verifikasi berhasil dimulai",
+		"data":    response,
+	})
+}
+
+// UpdateLabelResult menghandle PATCH /api/verifikasi/labels/:id/result
+// untuk input hasil HCS/HCTS per label (bisa dipanggil multiple times sebelum finalize)
+func (h *VerifikasiHandler) UpdateLabelResult(c *gin.Context) {
+	labelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Label ID tidak valid",
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User tidak terautentikasi",
+		})
+		return
+	}
+
+	var req LabelResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.UpdateLabelResult(labelID, req, userID.(uint64))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == ErrLabelNotFound {
+			statusCode = http.StatusNotFound
+		} else if err == ErrInvalidQuantity ||
+			err == ErrHCTSBreakdownRequired ||
+			err == ErrVerifikasiNotInProgress {
+			statusCode = http.StatusBadRequest
+		} else if errors.Is(err, ErrLabelQuantityMismatch) || errors.Is(err, ErrHCTSBreakdownSumMismatch) {
+			statusCode = http.StatusUnprocessableEntity
+		}
+
+		c.JSON(statusCode, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Hasil verifikasi label berhasil disimpan",
+		"data":    response,
+	})
+}
+
+// FinalizeVerifikasi menghandle POST /api/verifikasi/po/:po_id/finalize
+// untuk menyelesaikan verifikasi dan menyerahkan PO ke Khazkhir
+func (h *VerifikasiHandler) FinalizeVerifikasi(c *gin.Context) {
+	poID, err := strconv.ParseUint(c.Param("po_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "PO ID tidak valid",
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User tidak terautentikasi",
+		})
+		return
+	}
+
+	response, err := h.service.FinalizeVerifikasi(poID, userID.(uint64))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == ErrVerifikasiNotInProgress || err == ErrLabelsNotGenerated || errors.Is(err, ErrUnverifiedLabels) {
+			statusCode = http.StatusBadRequest
+		} else if errors.As(err, new(*models.InvalidPOTransitionError)) {
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Verifikasi berhasil diselesaikan. PO siap untuk Khazanah Akhir.",
+		"data":    response,
+	})
+}
@@ -0,0 +1,230 @@
+package verifikasi
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// LabelStatus merupakan enum untuk status verifikasi per label
+type LabelStatus string
+
+const (
+	LabelPending  LabelStatus = "PENDING"
+	LabelVerified LabelStatus = "VERIFIED"
+)
+
+// VerifikasiStatus merupakan enum untuk status verifikasi per PO
+type VerifikasiStatus string
+
+const (
+	VerifikasiInProgress VerifikasiStatus = "IN_PROGRESS"
+	VerifikasiCompleted  VerifikasiStatus = "COMPLETED"
+)
+
+// DefectBreakdownItem merupakan struct untuk item breakdown HCTS per jenis kerusakan
+type DefectBreakdownItem struct {
+	Type     string `json:"type" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,min=1"`
+}
+
+// VerificationLabel merupakan model untuk label verifikasi per bundle 500 lembar kirim
+// yang di-generate saat pemotongan selesai dan diisi hasil HCS/HCTS oleh inspector
+type VerificationLabel struct {
+	ID                uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductionOrderID uint64 `gorm:"not null;index;uniqueIndex:idx_label_po_sequence" json:"production_order_id"`
+	LabelNumber       string `gorm:"type:varchar(50);uniqueIndex;not null" json:"label_number"`
+	Sequence          int    `gorm:"not null;uniqueIndex:idx_label_po_sequence" json:"sequence"`
+	TotalLabels       int    `gorm:"not null" json:"total_labels"`
+	TargetQuantity    int    `gorm:"not null" json:"target_quantity"`
+
+	// Hasil Verifikasi (Lembar Kirim)
+	QuantityHCS   int            `gorm:"not null;default:0" json:"quantity_hcs"`
+	QuantityHCTS  int            `gorm:"not null;default:0" json:"quantity_hcts"`
+	HCTSBreakdown datatypes.JSON `gorm:"type:json" json:"hcts_breakdown"`
+
+	// Status & Staff
+	Status      LabelStatus `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"`
+	InspectedBy *uint64     `gorm:"type:bigint unsigned null" json:"inspected_by"`
+	InspectedAt *time.Time  `gorm:"type:timestamp null" json:"inspected_at"`
+	Notes       string      `gorm:"type:text" json:"notes"`
+
+	// Timestamps
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName menentukan nama tabel di database
+func (VerificationLabel) TableName() string {
+	return "verification_labels"
+}
+
+// IsVerified memeriksa apakah label sudah diisi hasil verifikasi
+func (vl *VerificationLabel) IsVerified() bool {
+	return vl.Status == LabelVerified
+}
+
+// TotalInspected menghitung total lembar yang sudah diverifikasi (HCS + HCTS)
+func (vl *VerificationLabel) TotalInspected() int {
+	return vl.QuantityHCS + vl.QuantityHCTS
+}
+
+// VerifikasiResult merupakan model untuk rekap hasil verifikasi per PO
+// yang mencakup total HCS/HCTS dari semua label dan tracking durasi
+type VerifikasiResult struct {
+	ID                uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductionOrderID uint64 `gorm:"uniqueIndex;not null" json:"production_order_id"`
+
+	// Rekap Hasil (Lembar Kirim)
+	TotalLabels   int      `gorm:"not null;default:0" json:"total_labels"`
+	TotalInput    int      `gorm:"not null;default:0" json:"total_input"`
+	TotalHCS      int      `gorm:"not null;default:0" json:"total_hcs"`
+	TotalHCTS     int      `gorm:"not null;default:0" json:"total_hcts"`
+	PercentageHCS *float64 `gorm:"type:decimal(5,2)" json:"percentage_hcs"`
+
+	// Status & Timing
+	Status          VerifikasiStatus `gorm:"type:varchar(20);not null;default:'IN_PROGRESS'" json:"status"`
+	StartedAt       *time.Time       `gorm:"type:timestamp null" json:"started_at"`
+	CompletedAt     *time.Time       `gorm:"type:timestamp null" json:"completed_at"`
+	DurationMinutes *int             `gorm:"type:int null" json:"duration_minutes"`
+
+	// Staff
+	VerifiedBy *uint64 `gorm:"type:bigint unsigned null" json:"verified_by"`
+
+	// Timestamps
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName menentukan nama tabel di database
+func (VerifikasiResult) TableName() string {
+	return "verifikasi_results"
+}
+
+// IsInProgress memeriksa apakah verifikasi sedang dalam progress
+func (vr *VerifikasiResult) IsInProgress() bool {
+	return vr.Status == VerifikasiInProgress
+}
+
+// IsCompleted memeriksa apakah verifikasi sudah selesai
+func (vr *VerifikasiResult) IsCompleted() bool {
+	return vr.Status == VerifikasiCompleted
+}
+
+// ApplyLabelTotals merekap total HCS/HCTS dari semua label verifikasi
+func (vr *VerifikasiResult) ApplyLabelTotals(labels []VerificationLabel) {
+	vr.TotalLabels = len(labels)
+	vr.TotalInput = 0
+	vr.TotalHCS = 0
+	vr.TotalHCTS = 0
+	for _, label := range labels {
+		vr.TotalInput += label.TargetQuantity
+		vr.TotalHCS += label.QuantityHCS
+		vr.TotalHCTS += label.QuantityHCTS
+	}
+
+	inspected := vr.TotalHCS + vr.TotalHCTS
+	if inspected > 0 {
+		percentage := float64(vr.TotalHCS) / float64(inspected) * 100
+		vr.PercentageHCS = &percentage
+	}
+}
+
+// UpdateDuration mengupdate DurationMinutes dari started_at ke completed_at
+func (vr *VerifikasiResult) UpdateDuration() {
+	if vr.StartedAt == nil || vr.CompletedAt == nil {
+		return
+	}
+	duration := int(vr.CompletedAt.Sub(*vr.StartedAt).Minutes())
+	vr.DurationMinutes = &duration
+}
+
+// Request & Response DTOs
+
+// QueueItemResponse merupakan response DTO untuk item di verifikasi queue
+type QueueItemResponse struct {
+	POID               uint64     `json:"po_id"`
+	PONumber           int64      `json:"po_number"`
+	OBCNumber          string     `json:"obc_number"`
+	Priority           string     `json:"priority"`
+	CurrentStatus      string     `json:"current_status"`
+	TotalLabels        int        `json:"total_labels"`
+	VerifiedLabels     int        `json:"verified_labels"`
+	TotalInput         int        `json:"total_input"`
+	CuttingCompletedAt *time.Time `json:"cutting_completed_at"`
+	WaitingMinutes     int        `json:"waiting_minutes"`
+	SLALevel           string     `json:"sla_level"`
+	IsOverdue          bool       `json:"is_overdue"`
+}
+
+// QueueResponse merupakan response DTO untuk queue endpoint
+type QueueResponse struct {
+	Data []QueueItemResponse `json:"data"`
+	Meta QueueMetadata       `json:"meta"`
+}
+
+// QueueMetadata merupakan metadata untuk queue response
+type QueueMetadata struct {
+	Total        int `json:"total"`
+	InProgress   int `json:"in_progress"`
+	OverdueCount int `json:"overdue_count"`
+}
+
+// LabelResponse merupakan response DTO untuk label verifikasi
+type LabelResponse struct {
+	ID             uint64                `json:"id"`
+	LabelNumber    string                `json:"label_number"`
+	Sequence       int                   `json:"sequence"`
+	TotalLabels    int                   `json:"total_labels"`
+	TargetQuantity int                   `json:"target_quantity"`
+	QuantityHCS    int                   `json:"quantity_hcs"`
+	QuantityHCTS   int                   `json:"quantity_hcts"`
+	HCTSBreakdown  []DefectBreakdownItem `json:"hcts_breakdown"`
+	Status         LabelStatus           `json:"status"`
+	InspectedBy    *uint64               `json:"inspected_by"`
+	InspectedAt    *time.Time            `json:"inspected_at"`
+	Notes          string                `json:"notes"`
+}
+
+// VerifikasiDetailResponse merupakan response DTO untuk detail verifikasi per PO
+type VerifikasiDetailResponse struct {
+	POID      uint64            `json:"po_id"`
+	PONumber  int64             `json:"po_number"`
+	OBCNumber string            `json:"obc_number"`
+	Status    string            `json:"status"`
+	Result    *VerifikasiResult `json:"result,omitempty"`
+	Labels    []LabelResponse   `json:"labels"`
+}
+
+// StartVerifikasiResponse merupakan response DTO untuk start verifikasi
+type StartVerifikasiResponse struct {
+	ID                uint64    `json:"id"`
+	ProductionOrderID uint64    `json:"production_order_id"`
+	Status            string    `json:"status"`
+	TotalLabels       int       `json:"total_labels"`
+	StartedAt         time.Time `json:"started_at"`
+	VerifiedBy        uint64    `json:"verified_by"`
+}
+
+// LabelResultRequest merupakan request DTO untuk input hasil HCS/HCTS per label
+type LabelResultRequest struct {
+	QuantityHCS   int                   `json:"quantity_hcs" binding:"min=0"`
+	QuantityHCTS  int                   `json:"quantity_hcts" binding:"min=0"`
+	HCTSBreakdown []DefectBreakdownItem `json:"hcts_breakdown"`
+	Notes         string                `json:"notes"`
+}
+
+// FinalizeVerifikasiResponse merupakan response DTO untuk finalize verifikasi
+type FinalizeVerifikasiResponse struct {
+	ID              uint64    `json:"id"`
+	Status          string    `json:"status"`
+	TotalLabels     int       `json:"total_labels"`
+	TotalHCS        int       `json:"total_hcs"`
+	TotalHCTS       int       `json:"total_hcts"`
+	PercentageHCS   *float64  `json:"percentage_hcs"`
+	CompletedAt     time.Time `json:"completed_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+}
@@ -0,0 +1,156 @@
+package verifikasi
+
+import (
+	"fmt"
+	"time"
+
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+
+	"gorm.io/gorm"
+)
+
+// VerifikasiRepository merupakan interface untuk database operations verifikasi
+type VerifikasiRepository interface {
+	// Queue operations
+	GetVerifikasiQueue() ([]QueueItemResponse, error)
+
+	// Label operations
+	GetLabelsByPOID(poID uint64) ([]VerificationLabel, error)
+
+	// Result operations
+	GetResultByPOID(poID uint64) (*VerifikasiResult, error)
+
+	// Related data operations
+	GetPOInfo(poID uint64) (*POInfo, error)
+}
+
+// POInfo merupakan info Production Order untuk verifikasi
+type POInfo struct {
+	ID            uint64 `gorm:"column:id"`
+	PONumber      int64  `gorm:"column:po_number"`
+	OBCNumber     string `gorm:"column:obc_number"`
+	CurrentStatus string `gorm:"column:current_status"`
+}
+
+// verifikasiRepositoryImpl merupakan implementasi VerifikasiRepository
+type verifikasiRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewVerifikasiRepository membuat instance baru VerifikasiRepository
+func NewVerifikasiRepository(db *gorm.DB) VerifikasiRepository {
+	return &verifikasiRepositoryImpl{db: db}
+}
+
+// GetVerifikasiQueue mengambil list PO yang siap atau sedang diverifikasi (FIFO)
+// dengan rekap jumlah label dan waktu tunggu sejak pemotongan selesai
+func (r *verifikasiRepositoryImpl) GetVerifikasiQueue() ([]QueueItemResponse, error) {
+	var rows []struct {
+		POID               uint64
+		PONumber           int64
+		OBCNumber          string
+		Priority           string
+		CurrentStatus      string
+		TotalLabels        int
+		VerifiedLabels     int
+		TotalInput         int
+		CuttingCompletedAt *time.Time
+	}
+
+	thresholds, err := services.LoadSLAThresholds(r.db)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat SLA verifikasi: %w", err)
+	}
+
+	err = r.db.Table("production_orders po").
+		Select(`
+			po.id as po_id,
+			po.po_number,
+			po.obc_number,
+			po.priority,
+			po.current_status,
+			COUNT(vl.id) as total_labels,
+			COALESCE(SUM(CASE WHEN vl.status = ? THEN 1 ELSE 0 END), 0) as verified_labels,
+			COALESCE(SUM(vl.target_quantity), 0) as total_input,
+			kcr.completed_at as cutting_completed_at
+		`, LabelVerified).
+		Joins("LEFT JOIN verification_labels vl ON vl.production_order_id = po.id AND vl.deleted_at IS NULL").
+		Joins("LEFT JOIN khazwal_cutting_results kcr ON kcr.production_order_id = po.id").
+		Where("po.current_status IN ?", []models.POStatus{models.StatusReadyForVerifikasi, models.StatusVerifikasiInProgress}).
+		Where("po.deleted_at IS NULL").
+		Group("po.id, po.po_number, po.obc_number, po.priority, po.current_status, kcr.completed_at").
+		Order("kcr.completed_at ASC"). // FIFO
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("gagal query verifikasi queue: %w", err)
+	}
+
+	results := make([]QueueItemResponse, 0, len(rows))
+	for _, row := range rows {
+		item := QueueItemResponse{
+			POID:               row.POID,
+			PONumber:           row.PONumber,
+			OBCNumber:          row.OBCNumber,
+			Priority:           row.Priority,
+			CurrentStatus:      row.CurrentStatus,
+			TotalLabels:        row.TotalLabels,
+			VerifiedLabels:     row.VerifiedLabels,
+			TotalInput:         row.TotalInput,
+			CuttingCompletedAt: row.CuttingCompletedAt,
+		}
+		if row.CuttingCompletedAt != nil {
+			sla := thresholds.Status(models.StageVerifikasi, models.POPriority(row.Priority), *row.CuttingCompletedAt, time.Now())
+			item.WaitingMinutes = sla.WaitingMinutes
+			item.SLALevel = string(sla.Level)
+			item.IsOverdue = sla.IsOverdue
+		}
+		results = append(results, item)
+	}
+
+	return results, nil
+}
+
+// GetLabelsByPOID mengambil semua label verifikasi untuk PO urut berdasarkan sequence
+func (r *verifikasiRepositoryImpl) GetLabelsByPOID(poID uint64) ([]VerificationLabel, error) {
+	var labels []VerificationLabel
+
+	if err := r.db.Where("production_order_id = ?", poID).
+		Order("sequence ASC").
+		Find(&labels).Error; err != nil {
+		return nil, fmt.Errorf("gagal mengambil label verifikasi: %w", err)
+	}
+
+	return labels, nil
+}
+
+// GetResultByPOID mengambil rekap verifikasi berdasarkan Production Order ID
+func (r *verifikasiRepositoryImpl) GetResultByPOID(poID uint64) (*VerifikasiResult, error) {
+	var result VerifikasiResult
+
+	if err := r.db.Where("production_order_id = ?", poID).First(&result).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("gagal mengambil rekap verifikasi: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetPOInfo mengambil info Production Order
+func (r *verifikasiRepositoryImpl) GetPOInfo(poID uint64) (*POInfo, error) {
+	var info POInfo
+
+	if err := r.db.Table("production_orders").
+		Select("id, po_number, obc_number, current_status").
+		Where("id = ? AND deleted_at IS NULL", poID).
+		First(&info).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPONotFound
+		}
+		return nil, fmt.Errorf("gagal mengambil PO: %w", err)
+	}
+
+	return &info, nil
+}
@@ -0,0 +1,426 @@
+package verifikasi
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VerifikasiService merupakan interface untuk business logic verifikasi (QC) operations
+type VerifikasiService interface {
+	// Queue operations
+	GetVerifikasiQueue() (*QueueResponse, error)
+
+	// Detail operations
+	GetVerifikasiDetail(poID uint64) (*VerifikasiDetailResponse, error)
+
+	// Action operations
+	StartVerifikasi(poID uint64, userID uint64) (*StartVerifikasiResponse, error)
+	UpdateLabelResult(labelID uint64, req LabelResultRequest, userID uint64) (*LabelResponse, error)
+	FinalizeVerifikasi(poID uint64, userID uint64) (*FinalizeVerifikasiResponse, error)
+}
+
+// verifikasiServiceImpl merupakan implementasi VerifikasiService
+type verifikasiServiceImpl struct {
+	db          *gorm.DB
+	repo        VerifikasiRepository
+	transitions *services.POTransitionService
+}
+
+// NewVerifikasiService membuat instance baru VerifikasiService
+func NewVerifikasiService(db *gorm.DB, repo VerifikasiRepository) VerifikasiService {
+	return &verifikasiServiceImpl{
+		db:          db,
+		repo:        repo,
+		transitions: services.NewPOTransitionService(db),
+	}
+}
+
+// GetVerifikasiQueue mengambil list PO yang menunggu atau sedang diverifikasi
+func (s *verifikasiServiceImpl) GetVerifikasiQueue() (*QueueResponse, error) {
+	items, err := s.repo.GetVerifikasiQueue()
+	if err != nil {
+		return nil, err
+	}
+
+	meta := QueueMetadata{Total: len(items)}
+	for _, item := range items {
+		if item.CurrentStatus == string(models.StatusVerifikasiInProgress) {
+			meta.InProgress++
+		}
+		if item.IsOverdue {
+			meta.OverdueCount++
+		}
+	}
+
+	return &QueueResponse{
+		Data: items,
+		Meta: meta,
+	}, nil
+}
+
+// GetVerifikasiDetail mengambil detail verifikasi PO beserta semua label
+func (s *verifikasiServiceImpl) GetVerifikasiDetail(poID uint64) (*VerifikasiDetailResponse, error) {
+	po, err := s.repo.GetPOInfo(poID)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := s.repo.GetLabelsByPOID(poID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.GetResultByPOID(poID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &VerifikasiDetailResponse{
+		POID:      po.ID,
+		PONumber:  po.PONumber,
+		OBCNumber: po.OBCNumber,
+		Status:    po.CurrentStatus,
+		Result:    result,
+		Labels:    make([]LabelResponse, 0, len(labels)),
+	}
+	for i := range labels {
+		response.Labels = append(response.Labels, buildLabelResponse(&labels[i]))
+	}
+
+	return response, nil
+}
+
+// StartVerifikasi memulai proses verifikasi untuk PO dengan status READY_FOR_VERIFIKASI
+func (s *verifikasiServiceImpl) StartVerifikasi(poID uint64, userID uint64) (*StartVerifikasiResponse, error) {
+	eventDB, events := services.WithDomainEvents(s.db)
+	tx := eventDB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Pindahkan PO ke VERIFIKASI_IN_PROGRESS via state machine
+	// yang sekaligus memvalidasi PO dalam status READY_FOR_VERIFIKASI
+	if _, err := s.transitions.TransitionInTx(tx, services.POTransitionRequest{
+		POID:      poID,
+		To:        models.StatusVerifikasiInProgress,
+		HandledBy: &userID,
+		Notes:     "Verifikasi dimulai",
+	}); err != nil {
+		tx.Rollback()
+		var transitionErr *models.InvalidPOTransitionError
+		if errors.As(err, &transitionErr) {
+			return nil, ErrPONotReadyForVerifikasi
+		}
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPONotFound
+		}
+		return nil, fmt.Errorf("gagal update PO status: %w", err)
+	}
+
+	// 2. Check label verifikasi sudah di-generate saat pemotongan selesai
+	var labelCount int64
+	if err := tx.Model(&VerificationLabel{}).
+		Where("production_order_id = ?", poID).
+		Count(&labelCount).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal check label verifikasi: %w", err)
+	}
+	if labelCount == 0 {
+		tx.Rollback()
+		return nil, ErrLabelsNotGenerated
+	}
+
+	// 3. Check belum ada rekap verifikasi untuk PO ini
+	var existing int64
+	if err := tx.Model(&VerifikasiResult{}).
+		Where("production_order_id = ?", poID).
+		Count(&existing).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal check existing verifikasi: %w", err)
+	}
+	if existing > 0 {
+		tx.Rollback()
+		return nil, ErrVerifikasiAlreadyExists
+	}
+
+	// 4. Create rekap verifikasi
+	now := time.Now()
+	result := &VerifikasiResult{
+		ProductionOrderID: poID,
+		TotalLabels:       int(labelCount),
+		Status:            VerifikasiInProgress,
+		StartedAt:         &now,
+		VerifiedBy:        &userID,
+	}
+	if err := tx.Create(result).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membuat rekap verifikasi: %w", err)
+	}
+
+	// 5. Log activity
+	activityLog := map[string]interface{}{
+		"user_id":             userID,
+		"action":              "START_VERIFIKASI",
+		"entity_type":         "VERIFIKASI",
+		"entity_id":           result.ID,
+		"production_order_id": poID,
+		"description":         fmt.Sprintf("Memulai verifikasi untuk PO %d", poID),
+		"created_at":          time.Now(),
+	}
+	if err := tx.Table("activity_logs").Create(activityLog).Error; err != nil {
+		// Log error tapi tidak rollback (non-critical)
+		fmt.Printf("Warning: gagal log activity: %v\n", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaction: %w", err)
+	}
+	events.Publish()
+
+	return &StartVerifikasiResponse{
+		ID:                result.ID,
+		ProductionOrderID: poID,
+		Status:            string(VerifikasiInProgress),
+		TotalLabels:       result.TotalLabels,
+		StartedAt:         now,
+		VerifiedBy:        userID,
+	}, nil
+}
+
+// UpdateLabelResult mengisi hasil HCS/HCTS untuk satu label
+// (dapat dipanggil ulang untuk koreksi sebelum finalize).
+// Rekap verifikasi di-lock dengan lock yang sama dengan FinalizeVerifikasi dan status dicek ulang,
+// sehingga label tidak bisa berubah setelah rekap HCS/HCTS dihitung
+func (s *verifikasiServiceImpl) UpdateLabelResult(labelID uint64, req LabelResultRequest, userID uint64) (*LabelResponse, error) {
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Get label untuk mengetahui PO-nya
+	var label VerificationLabel
+	if err := tx.First(&label, labelID).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrLabelNotFound
+		}
+		return nil, fmt.Errorf("gagal mengambil label verifikasi: %w", err)
+	}
+
+	// 2. Lock rekap verifikasi lebih dulu (urutan lock sama dengan finalize),
+	// validate PO masih dalam proses verifikasi, lalu lock label
+	var result VerifikasiResult
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("production_order_id = ?", label.ProductionOrderID).
+		First(&result).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrVerifikasiNotInProgress
+		}
+		return nil, fmt.Errorf("gagal mengambil rekap verifikasi: %w", err)
+	}
+	if !result.IsInProgress() {
+		tx.Rollback()
+		return nil, ErrVerifikasiNotInProgress
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&label, labelID).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mengambil label verifikasi: %w", err)
+	}
+
+	// 3. Validate request dengan business rules
+	if err := ValidateLabelResult(req, label.TargetQuantity); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	breakdownJSON, err := SerializeHCTSBreakdown(req.HCTSBreakdown)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 4. Update label
+	now := time.Now()
+	label.QuantityHCS = req.QuantityHCS
+	label.QuantityHCTS = req.QuantityHCTS
+	label.HCTSBreakdown = breakdownJSON
+	label.Status = LabelVerified
+	label.InspectedBy = &userID
+	label.InspectedAt = &now
+	label.Notes = req.Notes
+
+	if err := tx.Save(&label).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal update label verifikasi: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaction: %w", err)
+	}
+
+	response := buildLabelResponse(&label)
+	return &response, nil
+}
+
+// FinalizeVerifikasi menyelesaikan verifikasi PO, merekap HCS/HCTS,
+// memindahkan PO ke Khazkhir, dan mengirim notifikasi ke Staff Khazkhir
+func (s *verifikasiServiceImpl) FinalizeVerifikasi(poID uint64, userID uint64) (*FinalizeVerifikasiResponse, error) {
+	eventDB, events := services.WithDomainEvents(s.db)
+	tx := eventDB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Get rekap verifikasi dengan lock
+	var result VerifikasiResult
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("production_order_id = ?", poID).
+		First(&result).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrVerifikasiNotInProgress
+		}
+		return nil, fmt.Errorf("gagal mengambil rekap verifikasi: %w", err)
+	}
+
+	// 2. Get semua label dan validate
+	var labels []VerificationLabel
+	if err := tx.Where("production_order_id = ?", poID).
+		Order("sequence ASC").
+		Find(&labels).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mengambil label verifikasi: %w", err)
+	}
+	if err := ValidateFinalizeRequirements(&result, labels); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 3. Update rekap verifikasi
+	now := time.Now()
+	result.ApplyLabelTotals(labels)
+	result.Status = VerifikasiCompleted
+	result.CompletedAt = &now
+	result.UpdateDuration()
+
+	if err := tx.Save(&result).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal update rekap verifikasi: %w", err)
+	}
+
+	// 4. Pindahkan PO ke READY_FOR_KHAZKHIR via state machine
+	if _, err := s.transitions.TransitionInTx(tx, services.POTransitionRequest{
+		POID:      poID,
+		To:        models.StatusReadyForKhazkhir,
+		HandledBy: &userID,
+		Notes:     fmt.Sprintf("Verifikasi selesai. HCS: %d, HCTS: %d", result.TotalHCS, result.TotalHCTS),
+		StartedAt: result.StartedAt,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	services.RecordDomainEventInTx(tx, services.NewFinalizedEvent(services.EventVerifikasiFinalized, models.StageVerifikasi, poID, result.ID, &userID))
+
+	// 5. Notifikasi serah terima ke staff Khazkhir
+	var po POInfo
+	if err := tx.Table("production_orders").
+		Select("id, po_number, obc_number, current_status").
+		Where("id = ?", poID).
+		First(&po).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mengambil PO: %w", err)
+	}
+
+	if err := services.NotifyHandoverInTx(tx, po.ID, models.StageKhazkhir,
+		"Siap Khazkhir - PO #"+po.OBCNumber,
+		fmt.Sprintf("Verifikasi PO #%d (OBC %s) selesai dengan %d lembar HCS. Silakan proses pengemasan.", po.PONumber, po.OBCNumber, result.TotalHCS),
+	); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Eskalasi ke manager Verifikasi dan PPIC jika persentase HCTS (tidak layak) melebihi threshold
+	if result.PercentageHCS != nil && 100-*result.PercentageHCS > models.DefectThresholdPercentage {
+		if err := services.NotifyEscalationInTx(tx, po.ID, models.StageVerifikasi,
+			"HCTS Tinggi - PO #"+po.OBCNumber,
+			fmt.Sprintf("Verifikasi PO #%d (OBC %s) selesai dengan %.2f%% HCTS (%d lembar).", po.PONumber, po.OBCNumber, 100-*result.PercentageHCS, result.TotalHCTS),
+		); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// 6. Log activity (immutable)
+	activityLog := map[string]interface{}{
+		"user_id":             userID,
+		"action":              "FINALIZE_VERIFIKASI",
+		"entity_type":         "VERIFIKASI",
+		"entity_id":           result.ID,
+		"production_order_id": poID,
+		"description":         fmt.Sprintf("Menyelesaikan verifikasi untuk PO %d", poID),
+		"created_at":          time.Now(),
+	}
+	if err := tx.Table("activity_logs").Create(activityLog).Error; err != nil {
+		// Log error tapi tidak rollback
+		fmt.Printf("Warning: gagal log activity: %v\n", err)
+	}
+
+	// 7. Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaction: %w", err)
+	}
+	events.Publish()
+
+	durationMinutes := 0
+	if result.DurationMinutes != nil {
+		durationMinutes = *result.DurationMinutes
+	}
+
+	return &FinalizeVerifikasiResponse{
+		ID:              result.ID,
+		Status:          string(VerifikasiCompleted),
+		TotalLabels:     result.TotalLabels,
+		TotalHCS:        result.TotalHCS,
+		TotalHCTS:       result.TotalHCTS,
+		PercentageHCS:   result.PercentageHCS,
+		CompletedAt:     now,
+		DurationMinutes: durationMinutes,
+	}, nil
+}
+
+// buildLabelResponse mengubah VerificationLabel ke LabelResponse
+func buildLabelResponse(label *VerificationLabel) LabelResponse {
+	breakdown, err := ParseHCTSBreakdown(label.HCTSBreakdown)
+	if err != nil {
+		breakdown = []DefectBreakdownItem{}
+	}
+
+	return LabelResponse{
+		ID:             label.ID,
+		LabelNumber:    label.LabelNumber,
+		Sequence:       label.Sequence,
+		TotalLabels:    label.TotalLabels,
+		TargetQuantity: label.TargetQuantity,
+		QuantityHCS:    label.QuantityHCS,
+		QuantityHCTS:   label.QuantityHCTS,
+		HCTSBreakdown:  breakdown,
+		Status:         label.Status,
+		InspectedBy:    label.InspectedBy,
+		InspectedAt:    label.InspectedAt,
+		Notes:          label.Notes,
+	}
+}
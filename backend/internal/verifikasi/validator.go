@@ -0,0 +1,137 @@
+package verifikasi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ValidationErrors merupakan collection of validation errors
+var (
+	ErrPONotFound               = errors.New("PO tidak ditemukan")
+	ErrPONotReadyForVerifikasi  = errors.New("PO belum siap untuk verifikasi")
+	ErrVerifikasiNotInProgress  = errors.New("verifikasi tidak dalam status IN_PROGRESS")
+	ErrVerifikasiAlreadyExists  = errors.New("verifikasi untuk PO ini sudah dimulai")
+	ErrLabelsNotGenerated       = errors.New("label verifikasi untuk PO ini belum di-generate")
+	ErrLabelNotFound            = errors.New("label verifikasi tidak ditemukan")
+	ErrInvalidQuantity          = errors.New("quantity_hcs dan quantity_hcts harus >= 0")
+	ErrLabelQuantityMismatch    = errors.New("total HCS + HCTS harus sama dengan target quantity label")
+	ErrHCTSBreakdownRequired    = errors.New("hcts_breakdown wajib diisi jika ada HCTS")
+	ErrHCTSBreakdownSumMismatch = errors.New("total hcts_breakdown harus sama dengan quantity_hcts")
+	ErrUnverifiedLabels         = errors.New("masih ada label yang belum diverifikasi")
+)
+
+// Constants untuk verifikasi
+const (
+	SheetsPerLabel = 500 // 1 label = 1 bundle 500 lembar kirim
+)
+
+// CalculateLabelCount menghitung jumlah label dari total output (ceiling division)
+func CalculateLabelCount(totalOutput int) int {
+	if totalOutput <= 0 {
+		return 0
+	}
+	return (totalOutput + SheetsPerLabel - 1) / SheetsPerLabel
+}
+
+// BuildLabels membuat label verifikasi per 500 lembar kirim untuk PO
+// dengan label terakhir berisi sisa lembar
+func BuildLabels(poID uint64, poNumber int64, totalOutput int) []VerificationLabel {
+	count := CalculateLabelCount(totalOutput)
+	labels := make([]VerificationLabel, 0, count)
+
+	remaining := totalOutput
+	for i := 1; i <= count; i++ {
+		target := SheetsPerLabel
+		if remaining < SheetsPerLabel {
+			target = remaining
+		}
+		remaining -= target
+
+		labels = append(labels, VerificationLabel{
+			ProductionOrderID: poID,
+			LabelNumber:       fmt.Sprintf("VRF-%d-%03d", poNumber, i),
+			Sequence:          i,
+			TotalLabels:       count,
+			TargetQuantity:    target,
+			Status:            LabelPending,
+		})
+	}
+
+	return labels
+}
+
+// ValidateLabelResult memvalidasi input HCS/HCTS terhadap target quantity label
+// dengan business rules: HCS + HCTS = target dan breakdown wajib jika ada HCTS
+func ValidateLabelResult(req LabelResultRequest, targetQuantity int) error {
+	if req.QuantityHCS < 0 || req.QuantityHCTS < 0 {
+		return ErrInvalidQuantity
+	}
+
+	if req.QuantityHCS+req.QuantityHCTS != targetQuantity {
+		return fmt.Errorf("%w: expected %d, got %d", ErrLabelQuantityMismatch, targetQuantity, req.QuantityHCS+req.QuantityHCTS)
+	}
+
+	if req.QuantityHCTS > 0 {
+		if len(req.HCTSBreakdown) == 0 {
+			return ErrHCTSBreakdownRequired
+		}
+
+		sum := 0
+		for _, item := range req.HCTSBreakdown {
+			sum += item.Quantity
+		}
+		if sum != req.QuantityHCTS {
+			return fmt.Errorf("%w: expected %d, got %d", ErrHCTSBreakdownSumMismatch, req.QuantityHCTS, sum)
+		}
+	}
+
+	return nil
+}
+
+// ValidateFinalizeRequirements memvalidasi bahwa semua label sudah diverifikasi
+func ValidateFinalizeRequirements(result *VerifikasiResult, labels []VerificationLabel) error {
+	if !result.IsInProgress() {
+		return ErrVerifikasiNotInProgress
+	}
+
+	if len(labels) == 0 {
+		return ErrLabelsNotGenerated
+	}
+
+	for _, label := range labels {
+		if !label.IsVerified() {
+			return fmt.Errorf("%w: %s", ErrUnverifiedLabels, label.LabelNumber)
+		}
+	}
+
+	return nil
+}
+
+// ParseHCTSBreakdown mem-parse JSON HCTS breakdown ke slice of DefectBreakdownItem
+func ParseHCTSBreakdown(jsonData []byte) ([]DefectBreakdownItem, error) {
+	if len(jsonData) == 0 {
+		return []DefectBreakdownItem{}, nil
+	}
+
+	var breakdown []DefectBreakdownItem
+	if err := json.Unmarshal(jsonData, &breakdown); err != nil {
+		return nil, fmt.Errorf("gagal parse hcts_breakdown: %w", err)
+	}
+
+	return breakdown, nil
+}
+
+// SerializeHCTSBreakdown men-serialize slice of DefectBreakdownItem ke JSON
+func SerializeHCTSBreakdown(breakdown []DefectBreakdownItem) ([]byte, error) {
+	if len(breakdown) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(breakdown)
+	if err != nil {
+		return nil, fmt.Errorf("gagal serialize hcts_breakdown: %w", err)
+	}
+
+	return data, nil
+}
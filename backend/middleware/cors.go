@@ -30,7 +30,7 @@ func CORS() gin.HandlerFunc {
 			AllowAllOrigins:  true,
 			AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 			AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-			ExposeHeaders:    []string{"Content-Length"},
+			ExposeHeaders:    []string{"Content-Length", "Content-Disposition", "X-Import-Total-Rows", "X-Import-Success-Count", "X-Import-Failed-Count", "X-Import-Batch-ID"},
 			AllowCredentials: false, // Tidak bisa true jika AllowAllOrigins true
 			MaxAge:           12 * time.Hour,
 		})
@@ -40,7 +40,7 @@ func CORS() gin.HandlerFunc {
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", "X-Import-Total-Rows", "X-Import-Success-Count", "X-Import-Failed-Count", "X-Import-Batch-ID"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	})
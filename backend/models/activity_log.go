@@ -15,6 +15,15 @@ const (
 	ActionLogin          ActivityAction = "LOGIN"
 	ActionLogout         ActivityAction = "LOGOUT"
 	ActionPasswordChange ActivityAction = "PASSWORD_CHANGE"
+
+	// Production Order management actions (PPIC)
+	ActionPOUpdate ActivityAction = "PO_UPDATE"
+	ActionPOSplit  ActivityAction = "PO_SPLIT"
+	ActionPOMerge  ActivityAction = "PO_MERGE"
+	ActionPOCancel ActivityAction = "PO_CANCEL"
+
+	// OBC import actions (PPIC)
+	ActionImportRollback ActivityAction = "IMPORT_ROLLBACK"
 )
 
 // ActivityLog merupakan model untuk audit trail
@@ -22,9 +31,12 @@ const (
 type ActivityLog struct {
 	ID         uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
 	UserID     uint64         `gorm:"not null;index" json:"user_id"`
-	Action     ActivityAction `gorm:"type:enum('CREATE','UPDATE','DELETE','LOGIN','LOGOUT','PASSWORD_CHANGE');not null;index" json:"action"`
+	Action     ActivityAction `gorm:"type:varchar(50);not null;index" json:"action"` // CRUD/auth actions atau workflow action (START_COUNTING, dll)
 	EntityType string         `gorm:"type:varchar(50);not null;index" json:"entity_type"` // Table name atau entity type
 	EntityID   *uint64        `gorm:"type:bigint unsigned" json:"entity_id"`
+	// ProductionOrderID diisi oleh workflow stage produksi untuk timeline PO
+	ProductionOrderID *uint64 `gorm:"type:bigint unsigned null;index" json:"production_order_id,omitempty"`
+	Description       string  `gorm:"type:text" json:"description,omitempty"`
 	Changes    json.RawMessage `gorm:"type:json" json:"changes"` // Before/after values dalam JSON format
 	IPAddress  string         `gorm:"type:varchar(45)" json:"ip_address"`
 	UserAgent  string         `gorm:"type:text" json:"user_agent"`
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// AttachmentOwnerType merupakan enum untuk jenis entity pemilik lampiran bukti foto
+type AttachmentOwnerType string
+
+const (
+	AttachmentOwnerMaterialPrep AttachmentOwnerType = "MATERIAL_PREP"
+	AttachmentOwnerCuttingWaste AttachmentOwnerType = "CUTTING_WASTE"
+)
+
+// Attachment merupakan model untuk file bukti (foto material, foto waste) yang di-upload ke server
+// dan dihubungkan ke entity pemilik, lampiran tanpa pemilik dihapus oleh retention job
+type Attachment struct {
+	ID            uint64              `gorm:"primaryKey;autoIncrement" json:"id"`
+	OwnerType     AttachmentOwnerType `gorm:"type:varchar(30);not null;index:idx_attachment_owner,priority:1" json:"owner_type"`
+	OwnerID       *uint64             `gorm:"type:bigint unsigned null;index:idx_attachment_owner,priority:2" json:"owner_id"`
+	FileName      string              `gorm:"type:varchar(255)" json:"file_name"`
+	ContentType   string              `gorm:"type:varchar(50);not null" json:"content_type"`
+	SizeBytes     int64               `gorm:"not null" json:"size_bytes"`
+	Width         int                 `gorm:"not null;default:0" json:"width"`
+	Height        int                 `gorm:"not null;default:0" json:"height"`
+	Checksum      string              `gorm:"type:varchar(64);not null" json:"checksum"`
+	StoragePath   string              `gorm:"type:varchar(255);not null" json:"-"`
+	ThumbnailPath string              `gorm:"type:varchar(255);not null" json:"-"`
+	URL           string              `gorm:"type:varchar(500);not null" json:"url"`
+	ThumbnailURL  string              `gorm:"type:varchar(500);not null" json:"thumbnail_url"`
+	UploadedBy    uint64              `gorm:"not null;index" json:"uploaded_by"`
+	LinkedAt      *time.Time          `gorm:"type:timestamp null" json:"linked_at"`
+	CreatedAt     time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time           `gorm:"autoUpdateTime;index" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (Attachment) TableName() string {
+	return "attachments"
+}
+
+// IsLinked memeriksa apakah lampiran sudah terhubung ke entity pemilik
+func (a *Attachment) IsLinked() bool {
+	return a.OwnerID != nil
+}
+
+// IsLinkedTo memeriksa apakah lampiran terhubung ke entity pemilik tertentu
+func (a *Attachment) IsLinkedTo(ownerType AttachmentOwnerType, ownerID uint64) bool {
+	return a.OwnerType == ownerType && a.OwnerID != nil && *a.OwnerID == ownerID
+}
+
+// IsValidAttachmentOwnerType memeriksa apakah jenis pemilik termasuk yang dikenal
+func IsValidAttachmentOwnerType(ownerType AttachmentOwnerType) bool {
+	switch ownerType {
+	case AttachmentOwnerMaterialPrep, AttachmentOwnerCuttingWaste:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ImportJobStatus merupakan enum untuk status background import job
+type ImportJobStatus string
+
+const (
+	ImportJobQueued    ImportJobStatus = "QUEUED"
+	ImportJobRunning   ImportJobStatus = "RUNNING"
+	ImportJobCompleted ImportJobStatus = "COMPLETED"
+	ImportJobFailed    ImportJobStatus = "FAILED"
+)
+
+// ImportJob merupakan model untuk import OBC yang berjalan di background
+// yang mencakup progress per chunk, failed rows, dan hasil akhir import yang bisa diunduh
+type ImportJob struct {
+	ID             uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Status         ImportJobStatus `gorm:"type:enum('QUEUED','RUNNING','COMPLETED','FAILED');default:'QUEUED';index" json:"status"`
+	FileName       string          `gorm:"type:varchar(255)" json:"file_name"`
+	FileSize       int64           `gorm:"default:0" json:"file_size"`
+	SourceFormat   string          `gorm:"type:varchar(20)" json:"source_format"`
+	AutoGeneratePO bool            `gorm:"default:false" json:"auto_generate_po"`
+	TotalRows      int             `gorm:"default:0" json:"total_rows"`
+	ProcessedRows  int             `gorm:"default:0" json:"processed_rows"`
+	SuccessCount   int             `gorm:"default:0" json:"success_count"`
+	FailedCount    int             `gorm:"default:0" json:"failed_count"`
+	POsGenerated   int             `gorm:"column:pos_generated;default:0" json:"pos_generated"`
+	FailedRows     datatypes.JSON  `gorm:"type:json" json:"failed_rows"`
+	Result         datatypes.JSON  `gorm:"type:json" json:"-"`
+	ErrorMessage   string          `gorm:"type:text" json:"error_message,omitempty"`
+	BatchID        *uint64         `gorm:"type:bigint unsigned null" json:"batch_id"`
+	// PartiallyApplied menandai job FAILED yang sebagian chunk-nya sudah ter-commit ke import batch BatchID
+	PartiallyApplied bool       `gorm:"default:false" json:"partially_applied"`
+	HeartbeatAt      *time.Time `gorm:"index" json:"-"`
+	DurationMs       int64      `gorm:"default:0" json:"duration_ms"`
+	StartedAt        *time.Time `json:"started_at"`
+	FinishedAt       *time.Time `json:"finished_at"`
+	CreatedBy        uint64     `gorm:"not null;index" json:"created_by"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (ImportJob) TableName() string {
+	return "import_jobs"
+}
+
+// IsFinished memeriksa apakah job sudah selesai (berhasil maupun gagal)
+func (j *ImportJob) IsFinished() bool {
+	return j.Status == ImportJobCompleted || j.Status == ImportJobFailed
+}
+
+// ProgressPercentage menghitung persentase row yang sudah diproses
+func (j *ImportJob) ProgressPercentage() float64 {
+	if j.Status == ImportJobCompleted {
+		return 100
+	}
+	if j.TotalRows <= 0 {
+		return 0
+	}
+	return float64(j.ProcessedRows) / float64(j.TotalRows) * 100
+}
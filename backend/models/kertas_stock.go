@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// KertasMovementType merupakan enum untuk jenis mutasi stok kertas blanko
+type KertasMovementType string
+
+const (
+	KertasMovementReceipt KertasMovementType = "RECEIPT"
+	KertasMovementReserve KertasMovementType = "RESERVE"
+	KertasMovementConsume KertasMovementType = "CONSUME"
+	KertasMovementReturn  KertasMovementType = "RETURN"
+)
+
+// KertasReservationStatus merupakan enum untuk status reservasi kertas blanko per PO
+type KertasReservationStatus string
+
+const (
+	KertasReservationReserved KertasReservationStatus = "RESERVED"
+	KertasReservationConsumed KertasReservationStatus = "CONSUMED"
+)
+
+// KertasStock merupakan model untuk saldo stok kertas blanko per jenis kertas,
+// dimana kode jenis kertas sama dengan kode material pada OBC Master
+type KertasStock struct {
+	ID                 uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	PaperType          string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"paper_type" binding:"required"`
+	Description        string         `gorm:"type:varchar(255)" json:"description"`
+	Unit               string         `gorm:"type:varchar(20);default:'lembar'" json:"unit"`
+	OnHand             int64          `gorm:"not null;default:0" json:"on_hand"`
+	Reserved           int64          `gorm:"not null;default:0" json:"reserved"`
+	LowStockThreshold  int64          `gorm:"not null;default:0" json:"low_stock_threshold"`
+	LowStockNotifiedAt *time.Time     `gorm:"type:timestamp null" json:"low_stock_notified_at"`
+	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName menentukan nama tabel di database
+func (KertasStock) TableName() string {
+	return "kertas_stocks"
+}
+
+// Available menghitung stok yang masih bisa direservasi (on hand dikurangi reservasi aktif)
+func (k *KertasStock) Available() int64 {
+	return k.OnHand - k.Reserved
+}
+
+// IsLowStock memeriksa apakah stok tersedia sudah di bawah threshold,
+// threshold 0 berarti peringatan low stock tidak aktif
+func (k *KertasStock) IsLowStock() bool {
+	return k.LowStockThreshold > 0 && k.Available() < k.LowStockThreshold
+}
+
+// KertasStockMovement merupakan ledger mutasi stok kertas blanko
+// yang mencatat saldo on hand dan reserved setelah setiap mutasi
+type KertasStockMovement struct {
+	ID                uint64             `gorm:"primaryKey;autoIncrement" json:"id"`
+	KertasStockID     uint64             `gorm:"not null;index" json:"kertas_stock_id"`
+	Type              KertasMovementType `gorm:"type:enum('RECEIPT','RESERVE','CONSUME','RETURN');not null;index" json:"type"`
+	Quantity          int64              `gorm:"not null" json:"quantity"`
+	OnHandAfter       int64              `gorm:"not null" json:"on_hand_after"`
+	ReservedAfter     int64              `gorm:"not null" json:"reserved_after"`
+	ProductionOrderID *uint64            `gorm:"type:bigint unsigned null;index" json:"production_order_id"`
+	MaterialPrepID    *uint64            `gorm:"type:bigint unsigned null" json:"material_prep_id"`
+	Reference         string             `gorm:"type:varchar(100)" json:"reference"`
+	Notes             string             `gorm:"type:text" json:"notes"`
+	CreatedBy         uint64             `gorm:"not null" json:"created_by"`
+	CreatedAt         time.Time          `gorm:"autoCreateTime;index" json:"created_at"`
+
+	// Relations
+	CreatedByUser *User `gorm:"foreignKey:CreatedBy" json:"created_by_user,omitempty"`
+}
+
+// TableName menentukan nama tabel di database
+func (KertasStockMovement) TableName() string {
+	return "kertas_stock_movements"
+}
+
+// KertasReservation merupakan reservasi kertas blanko untuk satu PO
+// yang dibuat saat material prep dimulai dan dikonsumsi saat material prep selesai
+type KertasReservation struct {
+	ID                uint64                  `gorm:"primaryKey;autoIncrement" json:"id"`
+	KertasStockID     uint64                  `gorm:"not null;index" json:"kertas_stock_id"`
+	ProductionOrderID uint64                  `gorm:"uniqueIndex;not null" json:"production_order_id"`
+	MaterialPrepID    *uint64                 `gorm:"type:bigint unsigned null" json:"material_prep_id"`
+	Quantity          int64                   `gorm:"not null" json:"quantity"`
+	ConsumedQuantity  int64                   `gorm:"not null;default:0" json:"consumed_quantity"`
+	ReturnedQuantity  int64                   `gorm:"not null;default:0" json:"returned_quantity"`
+	Status            KertasReservationStatus `gorm:"type:enum('RESERVED','CONSUMED');default:'RESERVED';index" json:"status"`
+	ConsumedAt        *time.Time              `gorm:"type:timestamp null" json:"consumed_at"`
+	CreatedAt         time.Time               `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time               `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	KertasStock *KertasStock `gorm:"foreignKey:KertasStockID" json:"kertas_stock,omitempty"`
+}
+
+// TableName menentukan nama tabel di database
+func (KertasReservation) TableName() string {
+	return "kertas_reservations"
+}
+
+// ReturnableQuantity menghitung sisa kertas yang masih bisa dikembalikan ke stok
+func (r *KertasReservation) ReturnableQuantity() int64 {
+	if r.Status != KertasReservationConsumed {
+		return 0
+	}
+	return r.ConsumedQuantity - r.ReturnedQuantity
+}
@@ -23,7 +23,6 @@ type KhazwalMaterialPreparation struct {
 	ProductionOrderID          uint64             `gorm:"uniqueIndex;not null" json:"production_order_id" binding:"required"`
 	SAPPlatCode                      string             `gorm:"type:varchar(50);not null" json:"sap_plat_code" binding:"required"`
 	KertasBlankoQuantity             int                `gorm:"not null" json:"kertas_blanko_quantity" binding:"required,min=1"`
-	TintaRequirements                datatypes.JSON     `gorm:"type:json;not null" json:"tinta_requirements" binding:"required"`
 	
 	// Plat Confirmation
 	PlatRetrievedAt                  *time.Time         `gorm:"type:timestamp null" json:"plat_retrieved_at"`
@@ -36,10 +35,6 @@ type KhazwalMaterialPreparation struct {
 	KertasBlankoVariancePercentage   *float64           `gorm:"type:decimal(5,2)" json:"kertas_blanko_variance_percentage"`
 	KertasBlankoVarianceReason       string             `gorm:"type:varchar(500)" json:"kertas_blanko_variance_reason"`
 	
-	// Tinta Actual
-	TintaActual                      datatypes.JSON     `gorm:"type:json" json:"tinta_actual"`
-	TintaLowStockFlags               datatypes.JSON     `gorm:"type:json" json:"tinta_low_stock_flags"`
-	
 	// Photo Evidence
 	MaterialPhotos                   datatypes.JSON     `gorm:"type:json" json:"material_photos"`
 	Status                     MaterialPrepStatus `gorm:"type:enum('PENDING','IN_PROGRESS','COMPLETED');default:'PENDING'" json:"status"`
@@ -55,6 +50,8 @@ type KhazwalMaterialPreparation struct {
 	// Relationships
 	ProductionOrder *ProductionOrder `gorm:"foreignKey:ProductionOrderID" json:"production_order,omitempty"`
 	PreparedByUser  *User            `gorm:"foreignKey:PreparedBy" json:"prepared_by_user,omitempty"`
+	TintaItems      []KhazwalTintaItem `gorm:"foreignKey:MaterialPrepID" json:"tinta_items,omitempty"`
+	Attachments     []Attachment       `gorm:"polymorphic:Owner;polymorphicValue:MATERIAL_PREP" json:"attachments,omitempty"`
 }
 
 // TableName menentukan nama tabel di database
@@ -113,3 +110,14 @@ func (kmp *KhazwalMaterialPreparation) UpdateVariance() {
 	variance := kmp.CalculateVariance()
 	kmp.KertasBlankoVariance = &variance
 }
+
+// HasTintaActual memeriksa apakah tinta actual sudah diisi untuk minimal satu warna,
+// TintaItems harus sudah di-preload
+func (kmp *KhazwalMaterialPreparation) HasTintaActual() bool {
+	for i := range kmp.TintaItems {
+		if kmp.TintaItems[i].HasActual() {
+			return true
+		}
+	}
+	return false
+}
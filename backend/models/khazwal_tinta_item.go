@@ -0,0 +1,54 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// KhazwalTintaItem merupakan line item tinta per warna untuk satu material preparation
+// yang menyimpan kebutuhan dari PO dan jumlah actual yang disiapkan Staff Khazwal dalam kg
+type KhazwalTintaItem struct {
+	ID             uint64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	MaterialPrepID uint64   `gorm:"not null;uniqueIndex:idx_tinta_item_prep_color,priority:1" json:"material_prep_id"`
+	ColorCode      string   `gorm:"type:varchar(30);not null;uniqueIndex:idx_tinta_item_prep_color,priority:2" json:"color_code"`
+	RequiredKg     float64  `gorm:"type:decimal(12,3);not null;default:0" json:"required_kg"`
+	ActualKg       *float64 `gorm:"type:decimal(12,3)" json:"actual_kg"`
+	BatchNumber    string   `gorm:"type:varchar(50)" json:"batch_number"`
+	Checked        bool     `gorm:"not null;default:false" json:"checked"`
+
+	// LowStock menandai saldo inventory warna ini di bawah minimum saat actual terakhir diposting
+	LowStock  bool      `gorm:"not null;default:false" json:"low_stock"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (KhazwalTintaItem) TableName() string {
+	return "khazwal_tinta_items"
+}
+
+// HasActual memeriksa apakah jumlah actual warna ini sudah diisi
+func (t *KhazwalTintaItem) HasActual() bool {
+	return t.ActualKg != nil
+}
+
+// VarianceKg menghitung selisih actual terhadap kebutuhan dalam kg,
+// nil jika actual belum diisi
+func (t *KhazwalTintaItem) VarianceKg() *float64 {
+	if t.ActualKg == nil {
+		return nil
+	}
+	variance := math.Round((*t.ActualKg-t.RequiredKg)*1000) / 1000
+	return &variance
+}
+
+// VariancePercentage menghitung persentase selisih actual terhadap kebutuhan,
+// nil jika actual belum diisi atau warna tidak memiliki kebutuhan
+func (t *KhazwalTintaItem) VariancePercentage() *float64 {
+	variance := t.VarianceKg()
+	if variance == nil || t.RequiredKg <= 0 {
+		return nil
+	}
+	percentage := math.Round(*variance/t.RequiredKg*10000) / 100
+	return &percentage
+}
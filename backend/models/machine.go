@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MachineType merupakan enum untuk jenis mesin produksi
+type MachineType string
+
+const (
+	MachineTypeCetak   MachineType = "CETAK"
+	MachineTypeCutting MachineType = "CUTTING"
+)
+
+// MachineStatus merupakan enum untuk status operasional mesin
+type MachineStatus string
+
+const (
+	MachineRunning     MachineStatus = "RUNNING"
+	MachineIdle        MachineStatus = "IDLE"
+	MachineDown        MachineStatus = "DOWN"
+	MachineMaintenance MachineStatus = "MAINTENANCE"
+)
+
+// Machine merupakan model untuk mesin produksi (cetak dan potong)
+// yang di-assign ke print job maupun cutting
+type Machine struct {
+	ID                    uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code                  string         `gorm:"type:varchar(20);uniqueIndex;not null" json:"code" binding:"required"`
+	Name                  string         `gorm:"type:varchar(100);not null" json:"name" binding:"required"`
+	Type                  MachineType    `gorm:"type:enum('CETAK','CUTTING');not null;index" json:"type" binding:"required"`
+	CapacitySheetsPerHour int            `gorm:"not null;default:0" json:"capacity_sheets_per_hour"`
+	Status                MachineStatus  `gorm:"type:enum('RUNNING','IDLE','DOWN','MAINTENANCE');default:'IDLE';index" json:"status"`
+	Location              string         `gorm:"type:varchar(100)" json:"location"`
+	Notes                 string         `gorm:"type:text" json:"notes"`
+	CreatedAt             time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt             time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName menentukan nama tabel di database
+func (Machine) TableName() string {
+	return "machines"
+}
+
+// IsOperational memeriksa apakah mesin dapat dipilih untuk produksi
+// yaitu tidak sedang DOWN atau MAINTENANCE
+func (m *Machine) IsOperational() bool {
+	return m.Status == MachineRunning || m.Status == MachineIdle
+}
+
+// IsValidMachineStatus memeriksa apakah status termasuk status mesin yang dikenal
+func IsValidMachineStatus(status MachineStatus) bool {
+	switch status {
+	case MachineRunning, MachineIdle, MachineDown, MachineMaintenance:
+		return true
+	}
+	return false
+}
+
+// IsValidMachineType memeriksa apakah tipe termasuk tipe mesin yang dikenal
+func IsValidMachineType(machineType MachineType) bool {
+	return machineType == MachineTypeCetak || machineType == MachineTypeCutting
+}
@@ -15,17 +15,41 @@ const (
 	NotificationAchievement NotificationType = "ACHIEVEMENT"
 )
 
+// NotificationCategory merupakan enum untuk kategori notifikasi yang dipakai
+// untuk filter di notification center dan pengaturan mute per user
+type NotificationCategory string
+
+const (
+	NotificationCategoryHandover    NotificationCategory = "HANDOVER"
+	NotificationCategoryAlert       NotificationCategory = "ALERT"
+	NotificationCategoryAchievement NotificationCategory = "ACHIEVEMENT"
+	NotificationCategorySystem      NotificationCategory = "SYSTEM"
+)
+
+// notificationCategoryTTL merupakan masa berlaku notifikasi per kategori,
+// kategori tanpa entry (achievement) tidak pernah kedaluwarsa
+var notificationCategoryTTL = map[NotificationCategory]time.Duration{
+	NotificationCategoryHandover: 7 * 24 * time.Hour,
+	NotificationCategoryAlert:    30 * 24 * time.Hour,
+	NotificationCategorySystem:   30 * 24 * time.Hour,
+}
+
 // Notification merupakan model untuk in-app notifications
-// yang digunakan untuk menampilkan notifikasi kepada user
+// yang digunakan untuk menampilkan notifikasi kepada user,
+// POID dan Stage merupakan link ke PO dan stage terkait (kosong untuk notifikasi umum)
 type Notification struct {
-	ID        uint64           `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID    uint64           `gorm:"not null;index:idx_user_read,priority:1" json:"user_id"`
-	Title     string           `gorm:"type:varchar(255);not null" json:"title"`
-	Message   string           `gorm:"type:text;not null" json:"message"`
-	Type      NotificationType `gorm:"type:enum('INFO','SUCCESS','WARNING','ERROR','ACHIEVEMENT');default:'INFO'" json:"type"`
-	IsRead    bool             `gorm:"default:false;index:idx_user_read,priority:2" json:"is_read"`
-	ReadAt    *time.Time       `gorm:"type:timestamp null" json:"read_at"`
-	CreatedAt time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	ID        uint64               `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint64               `gorm:"not null;index:idx_user_read,priority:1" json:"user_id"`
+	Title     string               `gorm:"type:varchar(255);not null" json:"title"`
+	Message   string               `gorm:"type:text;not null" json:"message"`
+	Type      NotificationType     `gorm:"type:enum('INFO','SUCCESS','WARNING','ERROR','ACHIEVEMENT');default:'INFO'" json:"type"`
+	Category  NotificationCategory `gorm:"type:varchar(20);not null;default:'SYSTEM';index" json:"category"`
+	POID      *uint64              `gorm:"column:po_id;index" json:"po_id"`
+	Stage     POStage              `gorm:"type:varchar(50)" json:"stage,omitempty"`
+	IsRead    bool                 `gorm:"default:false;index:idx_user_read,priority:2" json:"is_read"`
+	ReadAt    *time.Time           `gorm:"type:timestamp null" json:"read_at"`
+	ExpiresAt *time.Time           `gorm:"type:timestamp null;index" json:"expires_at"`
+	CreatedAt time.Time            `gorm:"autoCreateTime" json:"created_at"`
 
 	// Relationship
 	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
@@ -42,3 +66,42 @@ func (n *Notification) MarkAsRead() {
 	now := time.Now()
 	n.ReadAt = &now
 }
+
+// IsExpired memeriksa apakah notifikasi sudah melewati masa berlaku
+func (n *Notification) IsExpired(now time.Time) bool {
+	return n.ExpiresAt != nil && !n.ExpiresAt.After(now)
+}
+
+// IsValid memeriksa apakah kategori notifikasi dikenal
+func (c NotificationCategory) IsValid() bool {
+	switch c {
+	case NotificationCategoryHandover, NotificationCategoryAlert, NotificationCategoryAchievement, NotificationCategorySystem:
+		return true
+	}
+	return false
+}
+
+// IsMutable memeriksa apakah kategori boleh di-mute user,
+// alert (eskalasi dan stok menipis) selalu dikirim agar tidak terlewat
+func (c NotificationCategory) IsMutable() bool {
+	return c.IsValid() && c != NotificationCategoryAlert
+}
+
+// NotificationCategoryForType menentukan kategori default untuk notifikasi tanpa kategori eksplisit
+func NotificationCategoryForType(notifType NotificationType) NotificationCategory {
+	if notifType == NotificationAchievement {
+		return NotificationCategoryAchievement
+	}
+	return NotificationCategorySystem
+}
+
+// NotificationExpiry menghitung waktu kedaluwarsa notifikasi berdasarkan kategori,
+// nil berarti notifikasi tidak pernah kedaluwarsa
+func NotificationExpiry(category NotificationCategory, from time.Time) *time.Time {
+	ttl, ok := notificationCategoryTTL[category]
+	if !ok {
+		return nil
+	}
+	expiresAt := from.Add(ttl)
+	return &expiresAt
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationChannel merupakan enum untuk channel pengiriman notifikasi di luar aplikasi
+type NotificationChannel string
+
+const (
+	ChannelEmail   NotificationChannel = "EMAIL"
+	ChannelWebhook NotificationChannel = "WEBHOOK"
+)
+
+// NotificationDeliveryKind merupakan enum untuk jenis pengiriman: alert langsung atau digest harian
+type NotificationDeliveryKind string
+
+const (
+	DeliveryKindAlert  NotificationDeliveryKind = "ALERT"
+	DeliveryKindDigest NotificationDeliveryKind = "DIGEST"
+	DeliveryKindTest   NotificationDeliveryKind = "TEST"
+)
+
+// NotificationDeliveryStatus merupakan enum untuk status pengiriman di delivery queue
+type NotificationDeliveryStatus string
+
+const (
+	DeliveryPending    NotificationDeliveryStatus = "PENDING"
+	DeliveryProcessing NotificationDeliveryStatus = "PROCESSING"
+	DeliverySent       NotificationDeliveryStatus = "SENT"
+	DeliveryFailed     NotificationDeliveryStatus = "FAILED"
+)
+
+const (
+	// NotificationDeliveryDefaultMaxAttempts merupakan batas percobaan sebelum pengiriman ditandai FAILED
+	NotificationDeliveryDefaultMaxAttempts = 6
+
+	notificationDeliveryBaseRetryDelay = 30 * time.Second
+	notificationDeliveryMaxRetryDelay  = 30 * time.Minute
+)
+
+// NotificationPreference merupakan preferensi channel notifikasi per user,
+// user tanpa preferensi hanya menerima notifikasi in-app
+type NotificationPreference struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID uint64 `gorm:"not null;uniqueIndex" json:"user_id"`
+	// EmailEnabled mengirim notifikasi kritis (WARNING dan ERROR) langsung ke email user
+	EmailEnabled bool `gorm:"not null;default:false" json:"email_enabled"`
+	// DigestEnabled mengirim ringkasan notifikasi yang belum dibaca setiap hari ke email user
+	DigestEnabled bool `gorm:"not null;default:false" json:"digest_enabled"`
+	// WebhookEnabled meneruskan notifikasi kritis ke WebhookURL, misalnya bridge chat pabrik
+	WebhookEnabled bool   `gorm:"not null;default:false" json:"webhook_enabled"`
+	WebhookURL     string `gorm:"type:varchar(500)" json:"webhook_url"`
+	// MutedCategories merupakan kategori notifikasi yang tidak ingin diterima user (in-app maupun email/webhook)
+	MutedCategories []NotificationCategory `gorm:"serializer:json;type:json" json:"muted_categories"`
+	LastDigestAt    *time.Time             `gorm:"type:timestamp null" json:"last_digest_at"`
+	CreatedAt       time.Time              `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time              `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// IsMuted memeriksa apakah user me-mute kategori notifikasi
+func (p *NotificationPreference) IsMuted(category NotificationCategory) bool {
+	for _, muted := range p.MutedCategories {
+		if muted == category {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationDelivery merupakan entry delivery queue untuk email dan webhook yang ditulis
+// dalam transaction yang sama dengan notifikasi, kemudian dikirim worker dengan retry
+type NotificationDelivery struct {
+	ID             uint64                     `gorm:"primaryKey;autoIncrement" json:"id"`
+	NotificationID *uint64                    `gorm:"type:bigint unsigned null;index" json:"notification_id"`
+	UserID         uint64                     `gorm:"not null;index" json:"user_id"`
+	Channel        NotificationChannel        `gorm:"type:varchar(20);not null" json:"channel"`
+	Kind           NotificationDeliveryKind   `gorm:"type:varchar(20);not null" json:"kind"`
+	Recipient      string                     `gorm:"type:varchar(500);not null" json:"recipient"`
+	Subject        string                     `gorm:"type:varchar(255);not null" json:"subject"`
+	Body           string                     `gorm:"type:text;not null" json:"body"`
+	IdempotencyKey string                     `gorm:"type:varchar(120);uniqueIndex;not null" json:"idempotency_key"`
+	Status         NotificationDeliveryStatus `gorm:"type:varchar(20);not null;default:'PENDING';index:idx_notification_delivery_due,priority:1" json:"status"`
+	Attempts       int                        `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts    int                        `gorm:"not null;default:6" json:"max_attempts"`
+	NextAttemptAt  time.Time                  `gorm:"not null;index:idx_notification_delivery_due,priority:2" json:"next_attempt_at"`
+	LastAttemptAt  *time.Time                 `gorm:"type:timestamp null" json:"last_attempt_at"`
+	LastError      string                     `gorm:"type:text" json:"last_error"`
+	LockedAt       *time.Time                 `gorm:"type:timestamp null" json:"locked_at"`
+	SentAt         *time.Time                 `gorm:"type:timestamp null" json:"sent_at"`
+	CreatedAt      time.Time                  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time                  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
+
+// CanRetry memeriksa apakah pengiriman boleh dijadwalkan ulang secara manual
+func (d *NotificationDelivery) CanRetry() bool {
+	return d.Status == DeliveryFailed || d.Status == DeliveryPending
+}
+
+// HasExhaustedAttempts memeriksa apakah jumlah percobaan sudah mencapai batas
+func (d *NotificationDelivery) HasExhaustedAttempts() bool {
+	return d.MaxAttempts > 0 && d.Attempts >= d.MaxAttempts
+}
+
+// IsCriticalNotification memeriksa apakah jenis notifikasi diteruskan ke email dan webhook
+func IsCriticalNotification(notifType NotificationType) bool {
+	return notifType == NotificationWarning || notifType == NotificationError
+}
+
+// NotificationDeliveryRetryDelay menghitung jeda exponential backoff setelah percobaan ke-n gagal,
+// dimulai dari 30 detik dan dibatasi maksimal 30 menit
+func NotificationDeliveryRetryDelay(attempts int) time.Duration {
+	delay := notificationDeliveryBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= notificationDeliveryMaxRetryDelay {
+			return notificationDeliveryMaxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// OBCImportBatchStatus merupakan enum untuk status batch import OBC
+type OBCImportBatchStatus string
+
+const (
+	ImportBatchApplied    OBCImportBatchStatus = "APPLIED"
+	ImportBatchRolledBack OBCImportBatchStatus = "ROLLED_BACK"
+)
+
+// OBCImportBatchSource merupakan enum untuk jalur import yang membuat batch
+type OBCImportBatchSource string
+
+const (
+	ImportBatchSourceUpload  OBCImportBatchSource = "UPLOAD"
+	ImportBatchSourcePreview OBCImportBatchSource = "PREVIEW"
+	ImportBatchSourceJob     OBCImportBatchSource = "JOB"
+)
+
+// OBCImportItemOutcome merupakan enum untuk hasil import per row di dalam batch
+type OBCImportItemOutcome string
+
+const (
+	ImportItemInserted  OBCImportItemOutcome = "INSERTED"
+	ImportItemUpdated   OBCImportItemOutcome = "UPDATED"
+	ImportItemUnchanged OBCImportItemOutcome = "UNCHANGED"
+	ImportItemFailed    OBCImportItemOutcome = "FAILED"
+)
+
+// OBCImportBatch merupakan model untuk riwayat satu kali import OBC
+// yang mencatat uploader, hash file, ringkasan hasil, dan status rollback
+type OBCImportBatch struct {
+	ID             uint64               `gorm:"primaryKey;autoIncrement" json:"id"`
+	Source         OBCImportBatchSource `gorm:"type:enum('UPLOAD','PREVIEW','JOB');not null" json:"source"`
+	FileName       string               `gorm:"type:varchar(255)" json:"file_name"`
+	FileHash       string               `gorm:"type:varchar(64);index" json:"file_hash"`
+	SourceFormat   string               `gorm:"type:varchar(20)" json:"source_format"`
+	AutoGeneratePO bool                 `gorm:"default:false" json:"auto_generate_po"`
+	TotalRows      int                  `gorm:"default:0" json:"total_rows"`
+	SuccessCount   int                  `gorm:"default:0" json:"success_count"`
+	FailedCount    int                  `gorm:"default:0" json:"failed_count"`
+	POsGenerated   int                  `gorm:"column:pos_generated;default:0" json:"pos_generated"`
+	Status         OBCImportBatchStatus `gorm:"type:enum('APPLIED','ROLLED_BACK');default:'APPLIED';index" json:"status"`
+	PreviewID      *uint64              `gorm:"type:bigint unsigned null" json:"preview_id,omitempty"`
+	ImportJobID    *uint64              `gorm:"type:bigint unsigned null" json:"import_job_id,omitempty"`
+	CreatedBy      uint64               `gorm:"not null;index" json:"created_by"`
+	RolledBackBy   *uint64              `gorm:"type:bigint unsigned null" json:"rolled_back_by"`
+	RolledBackAt   *time.Time           `json:"rolled_back_at"`
+	RollbackReason string               `gorm:"type:text" json:"rollback_reason,omitempty"`
+	CreatedAt      time.Time            `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt      time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Items []OBCImportBatchItem `gorm:"foreignKey:BatchID" json:"items,omitempty"`
+}
+
+// TableName menentukan nama tabel di database
+func (OBCImportBatch) TableName() string {
+	return "obc_import_batches"
+}
+
+// IsRolledBack memeriksa apakah batch sudah di-rollback
+func (b *OBCImportBatch) IsRolledBack() bool {
+	return b.Status == ImportBatchRolledBack
+}
+
+// OBCImportBatchItem merupakan hasil import satu row di dalam batch
+// beserta snapshot OBC sebelum di-update dan PO yang di-generate untuk keperluan rollback
+type OBCImportBatchItem struct {
+	ID             uint64               `gorm:"primaryKey;autoIncrement" json:"id"`
+	BatchID        uint64               `gorm:"not null;index" json:"batch_id"`
+	RowNumber      int                  `gorm:"not null" json:"row_number"`
+	OBCNumber      string               `gorm:"type:varchar(20)" json:"obc_number"`
+	OBCMasterID    *uint64              `gorm:"type:bigint unsigned null;index" json:"obc_master_id"`
+	Outcome        OBCImportItemOutcome `gorm:"type:enum('INSERTED','UPDATED','UNCHANGED','FAILED');not null" json:"outcome"`
+	Error          string               `gorm:"type:text" json:"error,omitempty"`
+	Changes        datatypes.JSON       `gorm:"type:json" json:"changes,omitempty"`
+	PreviousData   datatypes.JSON       `gorm:"type:json" json:"-"`
+	GeneratedPOIDs datatypes.JSON       `gorm:"type:json" json:"generated_po_ids,omitempty"`
+	CreatedAt      time.Time            `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (OBCImportBatchItem) TableName() string {
+	return "obc_import_batch_items"
+}
+
+// IsApplied memeriksa apakah row mengubah obc_masters sehingga perlu di-revert saat rollback
+func (i *OBCImportBatchItem) IsApplied() bool {
+	return i.Outcome == ImportItemInserted || i.Outcome == ImportItemUpdated
+}
+
+// POIDs mengambil daftar ID PO yang di-generate oleh row ini
+func (i *OBCImportBatchItem) POIDs() ([]uint64, error) {
+	ids := make([]uint64, 0)
+	if len(i.GeneratedPOIDs) == 0 {
+		return ids, nil
+	}
+	if err := json.Unmarshal(i.GeneratedPOIDs, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// OBCImportPreviewStatus merupakan enum untuk status preview import OBC
+type OBCImportPreviewStatus string
+
+const (
+	ImportPreviewPending   OBCImportPreviewStatus = "PENDING"
+	ImportPreviewCommitted OBCImportPreviewStatus = "COMMITTED"
+)
+
+// OBCImportRowAction merupakan enum untuk hasil preview per row import OBC
+type OBCImportRowAction string
+
+const (
+	ImportRowInsert    OBCImportRowAction = "INSERT"
+	ImportRowUpdate    OBCImportRowAction = "UPDATE"
+	ImportRowUnchanged OBCImportRowAction = "UNCHANGED"
+	ImportRowRejected  OBCImportRowAction = "REJECTED"
+)
+
+// OBCImportPreviewTTL merupakan masa berlaku token preview sebelum harus upload ulang
+const OBCImportPreviewTTL = 24 * time.Hour
+
+// OBCImportPreview merupakan model untuk hasil dry-run import OBC
+// yang menyimpan row hasil parsing sehingga batch yang di-preview bisa di-commit persis sama
+type OBCImportPreview struct {
+	ID             uint64                 `gorm:"primaryKey;autoIncrement" json:"id"`
+	Token          string                 `gorm:"type:varchar(64);uniqueIndex;not null" json:"token"`
+	FileName       string                 `gorm:"type:varchar(255)" json:"file_name"`
+	FileHash       string                 `gorm:"type:varchar(64)" json:"file_hash"`
+	SourceFormat   string                 `gorm:"type:varchar(20)" json:"source_format"`
+	AutoGeneratePO bool                   `gorm:"default:false" json:"auto_generate_po"`
+	TotalRows      int                    `gorm:"default:0" json:"total_rows"`
+	InsertCount    int                    `gorm:"default:0" json:"insert_count"`
+	UpdateCount    int                    `gorm:"default:0" json:"update_count"`
+	UnchangedCount int                    `gorm:"default:0" json:"unchanged_count"`
+	RejectedCount  int                    `gorm:"default:0" json:"rejected_count"`
+	Rows           datatypes.JSON         `gorm:"type:json" json:"-"`
+	Status         OBCImportPreviewStatus `gorm:"type:enum('PENDING','COMMITTED');default:'PENDING';index" json:"status"`
+	ExpiresAt      time.Time              `gorm:"not null;index" json:"expires_at"`
+	CreatedBy      uint64                 `gorm:"not null;index" json:"created_by"`
+	CommittedBy    *uint64                `gorm:"type:bigint unsigned null" json:"committed_by"`
+	CommittedAt    *time.Time             `json:"committed_at"`
+	CreatedAt      time.Time              `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time              `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (OBCImportPreview) TableName() string {
+	return "obc_import_previews"
+}
+
+// IsExpired memeriksa apakah token preview sudah melewati masa berlaku
+func (p *OBCImportPreview) IsExpired(now time.Time) bool {
+	return now.After(p.ExpiresAt)
+}
+
+// OBCImportPreviewRow merupakan hasil preview satu row Excel
+// beserta snapshot data existing untuk mendeteksi perubahan sebelum commit
+type OBCImportPreviewRow struct {
+	RowNumber         int                `json:"row_number"`
+	OBCNumber         string             `json:"obc_number"`
+	Action            OBCImportRowAction `json:"action"`
+	Changes           []OBCFieldChange   `json:"changes,omitempty"`
+	Error             string             `json:"error,omitempty"`
+	ExistingID        uint64             `json:"existing_id,omitempty"`
+	ExistingUpdatedAt *time.Time         `json:"existing_updated_at,omitempty"`
+	Data              *OBCMaster         `json:"data,omitempty"`
+}
@@ -1,11 +1,31 @@
 package models
 
 import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// OBCFieldChange merupakan perubahan satu field OBC Master antara data existing dan data import
+type OBCFieldChange struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"old_value"`
+	NewValue interface{} `json:"new_value"`
+}
+
+// obcDiffExcludedFields merupakan field yang tidak ikut dibandingkan karena dikelola sistem
+var obcDiffExcludedFields = map[string]bool{
+	"ID":               true,
+	"CreatedAt":        true,
+	"UpdatedAt":        true,
+	"DeletedAt":        true,
+	"ProductionOrders": true,
+}
+
 // OBCMaster merupakan model untuk master data OBC (Order Batch Confirmation)
 // yang mencakup semua spesifikasi produk dari SAP dan akan di-reference oleh ProductionOrder
 type OBCMaster struct {
@@ -83,14 +103,64 @@ func (o *OBCMaster) GetTotalPOQuantity() int {
 	return total
 }
 
-// CalculateTotalWithBuffer menghitung total quantity dengan buffer 6%
-// sesuai dengan rumus: Total = QTY + (QTY * 6%)
-func (o *OBCMaster) CalculateTotalWithBuffer() int {
-	buffer := float64(o.QuantityOrdered) * 0.06
-	return o.QuantityOrdered + int(buffer)
+// CalculateTotalWithBuffer menghitung total quantity dengan buffer dari production rule yang berlaku untuk OBC
+// sesuai dengan rumus: Total = QTY + (QTY * buffer%)
+func (o *OBCMaster) CalculateTotalWithBuffer(rule *ProductionRule) int {
+	return rule.CalculateTotalWithBuffer(o.QuantityOrdered)
 }
 
 // IsPersonalized memeriksa apakah OBC merupakan produk personalisasi
 func (o *OBCMaster) IsPersonalized() bool {
 	return o.Personalization == "Perso" || o.Personalization == "PERSO"
 }
+
+// DiffFields membandingkan data OBC existing dengan data baru dan mengembalikan field yang berubah,
+// tanggal dibandingkan per hari dan angka desimal dibulatkan 2 digit sesuai kolom database
+func (o *OBCMaster) DiffFields(updated *OBCMaster) []OBCFieldChange {
+	changes := make([]OBCFieldChange, 0)
+	oldValue := reflect.ValueOf(o).Elem()
+	newValue := reflect.ValueOf(updated).Elem()
+	structType := oldValue.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if obcDiffExcludedFields[field.Name] {
+			continue
+		}
+
+		before := normalizeOBCFieldValue(oldValue.Field(i).Interface())
+		after := normalizeOBCFieldValue(newValue.Field(i).Interface())
+		if before == after {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		changes = append(changes, OBCFieldChange{
+			Field:    name,
+			OldValue: before,
+			NewValue: after,
+		})
+	}
+	return changes
+}
+
+// normalizeOBCFieldValue menyamakan representasi value agar perbandingan tidak terpengaruh presisi/timezone
+func normalizeOBCFieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format("2006-01-02")
+	case time.Time:
+		return v.Format("2006-01-02")
+	case float64:
+		return math.Round(v*100) / 100
+	case string:
+		return strings.TrimSpace(v)
+	case int, int64, uint64, bool:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
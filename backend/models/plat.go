@@ -0,0 +1,109 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PlatStatus merupakan enum untuk status siklus hidup plat cetak
+type PlatStatus string
+
+const (
+	PlatAvailable   PlatStatus = "AVAILABLE"
+	PlatInUse       PlatStatus = "IN_USE"
+	PlatMaintenance PlatStatus = "MAINTENANCE"
+	PlatRetired     PlatStatus = "RETIRED"
+)
+
+// PlatReturnCondition merupakan enum untuk kondisi plat saat dikembalikan ke penyimpanan
+type PlatReturnCondition string
+
+const (
+	PlatConditionGood    PlatReturnCondition = "GOOD"
+	PlatConditionDamaged PlatReturnCondition = "DAMAGED"
+)
+
+// Plat merupakan model untuk registry plat cetak
+// yang mencakup lokasi penyimpanan, status, PO yang sedang memakai, dan akumulasi pemakaian
+type Plat struct {
+	ID               uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code             string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"code" binding:"required"`
+	Description      string         `gorm:"type:varchar(255)" json:"description"`
+	StorageLocation  string         `gorm:"type:varchar(100)" json:"storage_location"`
+	Status           PlatStatus     `gorm:"type:enum('AVAILABLE','IN_USE','MAINTENANCE','RETIRED');default:'AVAILABLE';index" json:"status"`
+	MaxImpressions   int64          `gorm:"not null;default:0" json:"max_impressions"`
+	TotalImpressions int64          `gorm:"not null;default:0" json:"total_impressions"`
+	UsageCount       int            `gorm:"not null;default:0" json:"usage_count"`
+	CurrentPOID      *uint64        `gorm:"type:bigint unsigned null;index" json:"current_po_id"`
+	CheckedOutAt     *time.Time     `json:"checked_out_at"`
+	CheckedOutBy     *uint64        `gorm:"type:bigint unsigned null" json:"checked_out_by"`
+	Notes            string         `gorm:"type:text" json:"notes"`
+	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relations
+	CurrentPO *ProductionOrder `gorm:"foreignKey:CurrentPOID" json:"current_po,omitempty"`
+}
+
+// TableName menentukan nama tabel di database
+func (Plat) TableName() string {
+	return "plats"
+}
+
+// IsAvailable memeriksa apakah plat bisa diambil untuk PO baru
+func (p *Plat) IsAvailable() bool {
+	return p.Status == PlatAvailable
+}
+
+// IsCheckedOutBy memeriksa apakah plat sedang dipakai oleh PO tertentu
+func (p *Plat) IsCheckedOutBy(poID uint64) bool {
+	return p.Status == PlatInUse && p.CurrentPOID != nil && *p.CurrentPOID == poID
+}
+
+// IsNearEndOfLife memeriksa apakah pemakaian plat sudah mencapai batas impression,
+// batas 0 berarti tidak ada batas
+func (p *Plat) IsNearEndOfLife() bool {
+	return p.MaxImpressions > 0 && p.TotalImpressions >= p.MaxImpressions
+}
+
+// IsValidPlatStatus memeriksa apakah status termasuk status plat yang dikenal
+func IsValidPlatStatus(status PlatStatus) bool {
+	switch status {
+	case PlatAvailable, PlatInUse, PlatMaintenance, PlatRetired:
+		return true
+	}
+	return false
+}
+
+// PlatCheckout merupakan riwayat pengambilan dan pengembalian plat untuk satu PO
+// beserta jumlah impression selama dipakai
+type PlatCheckout struct {
+	ID                uint64              `gorm:"primaryKey;autoIncrement" json:"id"`
+	PlatID            uint64              `gorm:"not null;index" json:"plat_id"`
+	ProductionOrderID uint64              `gorm:"not null;index" json:"production_order_id"`
+	MaterialPrepID    *uint64             `gorm:"type:bigint unsigned null" json:"material_prep_id"`
+	CheckedOutBy      uint64              `gorm:"not null" json:"checked_out_by"`
+	CheckedOutAt      time.Time           `gorm:"not null" json:"checked_out_at"`
+	ReturnedBy        *uint64             `gorm:"type:bigint unsigned null" json:"returned_by"`
+	ReturnedAt        *time.Time          `gorm:"index" json:"returned_at"`
+	ReturnCondition   PlatReturnCondition `gorm:"type:varchar(20)" json:"return_condition,omitempty"`
+	Impressions       int64               `gorm:"not null;default:0" json:"impressions"`
+	Notes             string              `gorm:"type:text" json:"notes"`
+	CreatedAt         time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	ProductionOrder *ProductionOrder `gorm:"foreignKey:ProductionOrderID" json:"production_order,omitempty"`
+}
+
+// TableName menentukan nama tabel di database
+func (PlatCheckout) TableName() string {
+	return "plat_checkouts"
+}
+
+// IsOpen memeriksa apakah plat dari checkout ini belum dikembalikan
+func (c *PlatCheckout) IsOpen() bool {
+	return c.ReturnedAt == nil
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPONumberFormat merupakan format nomor PO bawaan: tahun 4 digit, kode plant 4 digit,
+// dan running number 6 digit per tahun per plant, contoh 20251000000001
+const (
+	DefaultPONumberFormat = "{YYYY}{PLANT:4}{SEQ:6}"
+	DefaultPOPlantCode    = "0000"
+
+	// maxPONumberDigits menjaga nomor PO tetap muat di kolom BIGINT
+	maxPONumberDigits = 18
+)
+
+// Error untuk format dan sequence nomor PO
+var (
+	ErrInvalidPONumberFormat     = errors.New("format nomor PO tidak valid")
+	ErrInvalidPlantCode          = errors.New("kode plant untuk nomor PO tidak valid")
+	ErrPONumberSequenceExhausted = errors.New("running number PO sudah mencapai batas maksimal")
+)
+
+var (
+	poNumberTokenPattern = regexp.MustCompile(`\{([A-Z]+)(?::(\d+))?\}`)
+	nonDigitPattern      = regexp.MustCompile(`\D`)
+)
+
+// PONumberSequence merupakan model untuk running number PO per prefix (tahun + plant)
+// yang di-lock saat alokasi sehingga aman untuk generate PO secara bersamaan
+type PONumberSequence struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	SequenceKey string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"sequence_key"`
+	LastValue   int64     `gorm:"not null;default:0" json:"last_value"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (PONumberSequence) TableName() string {
+	return "po_number_sequences"
+}
+
+// PONumberFormat merupakan hasil parsing format nomor PO dengan token
+// {YYYY}, {YY}, {MM}, {PLANT:n}, dan {SEQ:n} (SEQ wajib di akhir)
+type PONumberFormat struct {
+	Pattern   string
+	SeqDigits int
+	prefix    string
+}
+
+// ParsePONumberFormat memvalidasi dan mem-parsing format nomor PO
+func ParsePONumberFormat(pattern string) (*PONumberFormat, error) {
+	pattern = strings.TrimSpace(pattern)
+	matches := poNumberTokenPattern.FindAllStringSubmatchIndex(pattern, -1)
+	if len(matches) == 0 {
+		return nil, ErrInvalidPONumberFormat
+	}
+
+	seq := matches[len(matches)-1]
+	if pattern[seq[2]:seq[3]] != "SEQ" || seq[1] != len(pattern) || seq[4] < 0 {
+		return nil, fmt.Errorf("%w: {SEQ:n} wajib berada di akhir format", ErrInvalidPONumberFormat)
+	}
+	seqDigits, _ := strconv.Atoi(pattern[seq[4]:seq[5]])
+	if seqDigits <= 0 {
+		return nil, fmt.Errorf("%w: jumlah digit SEQ harus lebih dari 0", ErrInvalidPONumberFormat)
+	}
+
+	totalDigits := seqDigits
+	cursor := 0
+	for _, m := range matches[:len(matches)-1] {
+		literal := pattern[cursor:m[0]]
+		if nonDigitPattern.MatchString(literal) {
+			return nil, fmt.Errorf("%w: literal hanya boleh berisi angka", ErrInvalidPONumberFormat)
+		}
+		totalDigits += len(literal)
+
+		switch token := pattern[m[2]:m[3]]; token {
+		case "YYYY":
+			totalDigits += 4
+		case "YY", "MM":
+			totalDigits += 2
+		case "PLANT":
+			if m[4] < 0 {
+				return nil, fmt.Errorf("%w: {PLANT:n} wajib menyebutkan jumlah digit", ErrInvalidPONumberFormat)
+			}
+			digits, _ := strconv.Atoi(pattern[m[4]:m[5]])
+			if digits <= 0 {
+				return nil, fmt.Errorf("%w: jumlah digit PLANT harus lebih dari 0", ErrInvalidPONumberFormat)
+			}
+			totalDigits += digits
+		default:
+			return nil, fmt.Errorf("%w: token {%s} tidak dikenal", ErrInvalidPONumberFormat, token)
+		}
+		cursor = m[1]
+	}
+	literal := pattern[cursor:seq[0]]
+	if nonDigitPattern.MatchString(literal) {
+		return nil, fmt.Errorf("%w: literal hanya boleh berisi angka", ErrInvalidPONumberFormat)
+	}
+	totalDigits += len(literal)
+
+	if totalDigits > maxPONumberDigits {
+		return nil, fmt.Errorf("%w: maksimal %d digit", ErrInvalidPONumberFormat, maxPONumberDigits)
+	}
+
+	return &PONumberFormat{
+		Pattern:   pattern,
+		SeqDigits: seqDigits,
+		prefix:    pattern[:seq[0]],
+	}, nil
+}
+
+// Prefix merender bagian format sebelum running number untuk tanggal dan plant tertentu,
+// prefix ini juga menjadi sequence key sehingga running number reset per tahun/plant
+func (f *PONumberFormat) Prefix(at time.Time, plantCode string) (string, error) {
+	plantDigits := nonDigitPattern.ReplaceAllString(strings.TrimSpace(plantCode), "")
+
+	var renderErr error
+	prefix := poNumberTokenPattern.ReplaceAllStringFunc(f.prefix, func(token string) string {
+		parts := poNumberTokenPattern.FindStringSubmatch(token)
+		switch parts[1] {
+		case "YYYY":
+			return at.Format("2006")
+		case "YY":
+			return at.Format("06")
+		case "MM":
+			return at.Format("01")
+		case "PLANT":
+			digits, _ := strconv.Atoi(parts[2])
+			if len(plantDigits) > digits {
+				renderErr = ErrInvalidPlantCode
+				return ""
+			}
+			return strings.Repeat("0", digits-len(plantDigits)) + plantDigits
+		}
+		return token
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+	return prefix, nil
+}
+
+// MaxSequence mengembalikan running number terbesar yang muat di format
+func (f *PONumberFormat) MaxSequence() int64 {
+	max := int64(1)
+	for i := 0; i < f.SeqDigits; i++ {
+		max *= 10
+	}
+	return max - 1
+}
+
+// Build menyusun nomor PO dari prefix dan running number
+func (f *PONumberFormat) Build(prefix string, sequence int64) (int64, error) {
+	if sequence <= 0 || sequence > f.MaxSequence() {
+		return 0, ErrPONumberSequenceExhausted
+	}
+	return strconv.ParseInt(fmt.Sprintf("%s%0*d", prefix, f.SeqDigits, sequence), 10, 64)
+}
+
+// SequenceRange mengembalikan rentang nomor PO yang dimiliki prefix,
+// digunakan untuk menyelaraskan sequence dengan nomor PO yang sudah ada
+func (f *PONumberFormat) SequenceRange(prefix string) (int64, int64, error) {
+	lower, err := f.Build(prefix, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	upper, err := f.Build(prefix, f.MaxSequence())
+	if err != nil {
+		return 0, 0, err
+	}
+	return lower, upper, nil
+}
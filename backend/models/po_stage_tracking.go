@@ -11,7 +11,7 @@ import (
 type POStageTracking struct {
 	ID                uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
 	ProductionOrderID uint64         `gorm:"index;not null" json:"production_order_id" binding:"required"`
-	Stage             POStage        `gorm:"type:enum('KHAZWAL_MATERIAL_PREP','CETAK','VERIFIKASI','KHAZKHIR','COMPLETED');not null" json:"stage" binding:"required"`
+	Stage             POStage        `gorm:"type:enum('KHAZWAL_MATERIAL_PREP','CETAK','KHAZWAL_COUNTING','KHAZWAL_CUTTING','VERIFIKASI','KHAZKHIR','COMPLETED');not null" json:"stage" binding:"required"`
 	Status            POStatus       `gorm:"type:varchar(50);not null" json:"status" binding:"required"`
 	StartedAt         *time.Time     `gorm:"type:timestamp null" json:"started_at"`
 	CompletedAt       *time.Time     `gorm:"type:timestamp null" json:"completed_at"`
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownPOStatus merupakan error untuk status PO yang tidak terdaftar di state machine
+var ErrUnknownPOStatus = errors.New("status PO tidak dikenal")
+
+// InvalidPOTransitionError merupakan typed error untuk perpindahan status PO
+// yang tidak diizinkan oleh state machine, misalnya loncat stage
+type InvalidPOTransitionError struct {
+	From POStatus
+	To   POStatus
+}
+
+func (e *InvalidPOTransitionError) Error() string {
+	return fmt.Sprintf("perpindahan status PO dari %s ke %s tidak diizinkan", e.From, e.To)
+}
+
+// poStatusStages merupakan mapping setiap status ke stage pemiliknya
+var poStatusStages = map[POStatus]POStage{
+	StatusWaitingMaterialPrep:    StageKhazwalMaterialPrep,
+	StatusMaterialPrepInProgress: StageKhazwalMaterialPrep,
+	StatusReadyForCetak:          StageCetak,
+	StatusCetakInProgress:        StageCetak,
+	StatusWaitingCounting:        StageKhazwalCounting,
+	StatusCountingInProgress:     StageKhazwalCounting,
+	StatusReadyForCutting:        StageKhazwalCutting,
+	StatusCuttingInProgress:      StageKhazwalCutting,
+	StatusReadyForVerifikasi:     StageVerifikasi,
+	StatusVerifikasiInProgress:   StageVerifikasi,
+	StatusReadyForKhazkhir:       StageKhazkhir,
+	StatusKhazkhirInProgress:     StageKhazkhir,
+	StatusPOCompleted:            StageCompleted,
+}
+
+// poTransitions merupakan graph perpindahan status yang legal
+// sesuai alur produksi: Khazwal → Cetak → Penghitungan → Pemotongan → Verifikasi → Khazkhir,
+// pembatalan (CANCELLED) hanya diizinkan dari status antrian sebelum produksi cetak dimulai
+var poTransitions = map[POStatus][]POStatus{
+	StatusWaitingMaterialPrep:    {StatusMaterialPrepInProgress, StatusPOCancelled},
+	StatusMaterialPrepInProgress: {StatusReadyForCetak},
+	StatusReadyForCetak:          {StatusCetakInProgress, StatusPOCancelled},
+	StatusCetakInProgress:        {StatusWaitingCounting},
+	StatusWaitingCounting:        {StatusCountingInProgress},
+	StatusCountingInProgress:     {StatusReadyForCutting},
+	StatusReadyForCutting:        {StatusCuttingInProgress},
+	StatusCuttingInProgress:      {StatusReadyForVerifikasi},
+	StatusReadyForVerifikasi:     {StatusVerifikasiInProgress},
+	StatusVerifikasiInProgress:   {StatusReadyForKhazkhir},
+	StatusReadyForKhazkhir:       {StatusKhazkhirInProgress},
+	StatusKhazkhirInProgress:     {StatusPOCompleted},
+	StatusPOCompleted:            {},
+}
+
+// StageForStatus mengembalikan stage pemilik dari status PO
+// (CANCELLED tidak memiliki stage, lihat StageAfterTransition)
+func StageForStatus(status POStatus) (POStage, error) {
+	stage, ok := poStatusStages[status]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownPOStatus, status)
+	}
+	return stage, nil
+}
+
+// StageAfterTransition mengembalikan stage PO setelah pindah ke status tujuan,
+// PO yang dibatalkan tetap berada di stage terakhirnya
+func StageAfterTransition(from, to POStatus) (POStage, error) {
+	if to == StatusPOCancelled {
+		return StageForStatus(from)
+	}
+	return StageForStatus(to)
+}
+
+// AllowedTransitions mengembalikan list status tujuan yang legal dari status tertentu
+func AllowedTransitions(from POStatus) []POStatus {
+	targets := poTransitions[from]
+	result := make([]POStatus, len(targets))
+	copy(result, targets)
+	return result
+}
+
+// CanTransition memeriksa apakah perpindahan status from → to diizinkan
+func CanTransition(from, to POStatus) bool {
+	for _, target := range poTransitions[from] {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition memvalidasi perpindahan status dengan typed error
+// yaitu ErrUnknownPOStatus untuk status asing dan *InvalidPOTransitionError untuk loncatan ilegal
+func ValidateTransition(from, to POStatus) error {
+	// PO yang dibatalkan bersifat terminal dan tidak bisa dipindahkan ke status manapun
+	if from == StatusPOCancelled {
+		return &InvalidPOTransitionError{From: from, To: to}
+	}
+	if _, ok := poStatusStages[from]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownPOStatus, from)
+	}
+	if _, ok := poStatusStages[to]; !ok && to != StatusPOCancelled {
+		return fmt.Errorf("%w: %s", ErrUnknownPOStatus, to)
+	}
+	if !CanTransition(from, to) {
+		return &InvalidPOTransitionError{From: from, To: to}
+	}
+	return nil
+}
+
+// IsInProgressStatus memeriksa apakah status menandakan pekerjaan sedang berjalan di suatu stage
+func IsInProgressStatus(status POStatus) bool {
+	switch status {
+	case StatusMaterialPrepInProgress, StatusCetakInProgress, StatusCountingInProgress,
+		StatusCuttingInProgress, StatusVerifikasiInProgress, StatusKhazkhirInProgress:
+		return true
+	}
+	return false
+}
+
+// CanCancelPO memeriksa apakah PO dengan status tertentu boleh dibatalkan,
+// yaitu hanya sebelum produksi dimulai (menunggu material prep atau siap cetak)
+func CanCancelPO(status POStatus) bool {
+	return CanTransition(status, StatusPOCancelled)
+}
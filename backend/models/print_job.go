@@ -0,0 +1,150 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PrintJobStatus merupakan enum untuk status print job di Unit Cetak
+type PrintJobStatus string
+
+const (
+	PrintJobInProgress PrintJobStatus = "IN_PROGRESS"
+	PrintJobPaused     PrintJobStatus = "PAUSED"
+	PrintJobCompleted  PrintJobStatus = "COMPLETED"
+)
+
+// PrintJobLogType merupakan enum untuk jenis event di progress log print job
+type PrintJobLogType string
+
+const (
+	PrintJobLogStart    PrintJobLogType = "START"
+	PrintJobLogPause    PrintJobLogType = "PAUSE"
+	PrintJobLogResume   PrintJobLogType = "RESUME"
+	PrintJobLogProgress PrintJobLogType = "PROGRESS"
+	PrintJobLogFinalize PrintJobLogType = "FINALIZE"
+)
+
+// PrintJob merupakan model untuk pekerjaan cetak satu Production Order
+// yang mencakup assignment mesin dan operator serta akumulasi lembar baik dan rusak
+type PrintJob struct {
+	ID                 uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductionOrderID  uint64         `gorm:"uniqueIndex;not null" json:"production_order_id"`
+	MachineID          uint64         `gorm:"not null;index" json:"machine_id"`
+	OperatorID         uint64         `gorm:"not null;index" json:"operator_id"`
+	Status             PrintJobStatus `gorm:"type:enum('IN_PROGRESS','PAUSED','COMPLETED');default:'IN_PROGRESS';index" json:"status"`
+	GoodSheets         int            `gorm:"default:0" json:"good_sheets"`
+	RejectedSheets     int            `gorm:"default:0" json:"rejected_sheets"`
+	StartedAt          *time.Time     `gorm:"type:timestamp null" json:"started_at"`
+	PausedAt           *time.Time     `gorm:"type:timestamp null" json:"paused_at"`
+	TotalPausedMinutes int            `gorm:"default:0" json:"total_paused_minutes"`
+	CompletedAt        *time.Time     `gorm:"type:timestamp null" json:"completed_at"`
+	DurationMinutes    *int           `gorm:"type:int null" json:"duration_minutes"`
+	Notes              string         `gorm:"type:text" json:"notes"`
+	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	ProductionOrder *ProductionOrder `gorm:"foreignKey:ProductionOrderID" json:"production_order,omitempty"`
+	Machine         *Machine         `gorm:"foreignKey:MachineID" json:"machine,omitempty"`
+	Operator        *User            `gorm:"foreignKey:OperatorID" json:"operator,omitempty"`
+	Logs            []PrintJobLog    `gorm:"foreignKey:PrintJobID" json:"logs,omitempty"`
+}
+
+// TableName menentukan nama tabel di database
+func (PrintJob) TableName() string {
+	return "print_jobs"
+}
+
+// IsInProgress memeriksa apakah print job sedang berjalan
+func (pj *PrintJob) IsInProgress() bool {
+	return pj.Status == PrintJobInProgress
+}
+
+// IsPaused memeriksa apakah print job sedang di-pause
+func (pj *PrintJob) IsPaused() bool {
+	return pj.Status == PrintJobPaused
+}
+
+// IsCompleted memeriksa apakah print job sudah selesai
+func (pj *PrintJob) IsCompleted() bool {
+	return pj.Status == PrintJobCompleted
+}
+
+// TotalSheets menghitung total lembar yang sudah dicetak (baik + rusak)
+func (pj *PrintJob) TotalSheets() int {
+	return pj.GoodSheets + pj.RejectedSheets
+}
+
+// RejectPercentage menghitung persentase lembar rusak dari total lembar
+func (pj *PrintJob) RejectPercentage() float64 {
+	total := pj.TotalSheets()
+	if total == 0 {
+		return 0
+	}
+	return float64(pj.RejectedSheets) / float64(total) * 100
+}
+
+// CalculateDurationMinutes menghitung durasi kerja efektif dari started_at ke completedAt
+// dikurangi total waktu pause
+func (pj *PrintJob) CalculateDurationMinutes(completedAt time.Time) int {
+	if pj.StartedAt == nil {
+		return 0
+	}
+	duration := int(completedAt.Sub(*pj.StartedAt).Minutes()) - pj.TotalPausedMinutes
+	if duration < 0 {
+		return 0
+	}
+	return duration
+}
+
+// PrintJobLog merupakan model untuk progress log print job
+// yang mencatat start, pause, resume, progress counts, dan finalize
+type PrintJobLog struct {
+	ID             uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	PrintJobID     uint64          `gorm:"not null;index" json:"print_job_id"`
+	Type           PrintJobLogType `gorm:"type:enum('START','PAUSE','RESUME','PROGRESS','FINALIZE');not null" json:"type"`
+	GoodSheets     int             `gorm:"default:0" json:"good_sheets"`
+	RejectedSheets int             `gorm:"default:0" json:"rejected_sheets"`
+	Notes          string          `gorm:"type:text" json:"notes"`
+	LoggedBy       uint64          `gorm:"not null" json:"logged_by"`
+	CreatedAt      time.Time       `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationships
+	LoggedByUser *User `gorm:"foreignKey:LoggedBy" json:"logged_by_user,omitempty"`
+}
+
+// TableName menentukan nama tabel di database
+func (PrintJobLog) TableName() string {
+	return "print_job_logs"
+}
+
+// PrintJobSummary merupakan model untuk ringkasan hasil cetak per PO
+// yang dibuat saat finalize dan digunakan oleh queue penghitungan
+type PrintJobSummary struct {
+	ID                  uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductionOrderID   uint64    `gorm:"uniqueIndex;not null" json:"production_order_id"`
+	PrintJobID          uint64    `gorm:"uniqueIndex;not null" json:"print_job_id"`
+	MachineID           uint64    `gorm:"not null;index" json:"machine_id"`
+	OperatorID          uint64    `gorm:"not null;index" json:"operator_id"`
+	TotalGoodSheets     int       `gorm:"not null" json:"total_good_sheets"`
+	TotalRejectedSheets int       `gorm:"not null" json:"total_rejected_sheets"`
+	RejectPercentage    float64   `gorm:"type:decimal(5,2)" json:"reject_percentage"`
+	DurationMinutes     int       `gorm:"not null" json:"duration_minutes"`
+	TotalPausedMinutes  int       `gorm:"default:0" json:"total_paused_minutes"`
+	FinalizedAt         time.Time `gorm:"type:timestamp;not null;index" json:"finalized_at"`
+	Notes               string    `gorm:"type:text" json:"notes"`
+	CreatedAt           time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationships
+	ProductionOrder *ProductionOrder `gorm:"foreignKey:ProductionOrderID" json:"production_order,omitempty"`
+	Machine         *Machine         `gorm:"foreignKey:MachineID" json:"machine,omitempty"`
+	Operator        *User            `gorm:"foreignKey:OperatorID" json:"operator,omitempty"`
+}
+
+// TableName menentukan nama tabel di database
+func (PrintJobSummary) TableName() string {
+	return "print_job_summaries"
+}
@@ -21,6 +21,8 @@ type POStage string
 const (
 	StageKhazwalMaterialPrep POStage = "KHAZWAL_MATERIAL_PREP"
 	StageCetak               POStage = "CETAK"
+	StageKhazwalCounting     POStage = "KHAZWAL_COUNTING"
+	StageKhazwalCutting      POStage = "KHAZWAL_CUTTING"
 	StageVerifikasi          POStage = "VERIFIKASI"
 	StageKhazkhir            POStage = "KHAZKHIR"
 	StageCompleted           POStage = "COMPLETED"
@@ -34,11 +36,16 @@ const (
 	StatusMaterialPrepInProgress POStatus = "MATERIAL_PREP_IN_PROGRESS"
 	StatusReadyForCetak       POStatus = "READY_FOR_CETAK"
 	StatusCetakInProgress     POStatus = "CETAK_IN_PROGRESS"
+	StatusWaitingCounting     POStatus = "WAITING_COUNTING"
+	StatusCountingInProgress  POStatus = "COUNTING_IN_PROGRESS"
+	StatusReadyForCutting     POStatus = "READY_FOR_CUTTING"
+	StatusCuttingInProgress   POStatus = "CUTTING_IN_PROGRESS"
 	StatusReadyForVerifikasi  POStatus = "READY_FOR_VERIFIKASI"
 	StatusVerifikasiInProgress POStatus = "VERIFIKASI_IN_PROGRESS"
 	StatusReadyForKhazkhir    POStatus = "READY_FOR_KHAZKHIR"
 	StatusKhazkhirInProgress  POStatus = "KHAZKHIR_IN_PROGRESS"
 	StatusPOCompleted         POStatus = "COMPLETED"
+	StatusPOCancelled         POStatus = "CANCELLED"
 )
 
 // ProductionOrder merupakan model untuk entitas Production Order
@@ -63,9 +70,23 @@ type ProductionOrder struct {
 	DueDate                   time.Time      `gorm:"type:date;not null" json:"due_date" binding:"required"`
 	Priority                  POPriority     `gorm:"type:enum('URGENT','NORMAL','LOW');default:'NORMAL'" json:"priority"`
 	PriorityScore             int            `gorm:"default:50" json:"priority_score"`
-	CurrentStage              POStage        `gorm:"type:enum('KHAZWAL_MATERIAL_PREP','CETAK','VERIFIKASI','KHAZKHIR','COMPLETED');default:'KHAZWAL_MATERIAL_PREP'" json:"current_stage"`
-	CurrentStatus             POStatus       `gorm:"type:enum('WAITING_MATERIAL_PREP','MATERIAL_PREP_IN_PROGRESS','READY_FOR_CETAK','CETAK_IN_PROGRESS','READY_FOR_VERIFIKASI','VERIFIKASI_IN_PROGRESS','READY_FOR_KHAZKHIR','KHAZKHIR_IN_PROGRESS','COMPLETED');default:'WAITING_MATERIAL_PREP'" json:"current_status"`
+	CurrentStage              POStage        `gorm:"type:enum('KHAZWAL_MATERIAL_PREP','CETAK','KHAZWAL_COUNTING','KHAZWAL_CUTTING','VERIFIKASI','KHAZKHIR','COMPLETED');default:'KHAZWAL_MATERIAL_PREP'" json:"current_stage"`
+	CurrentStatus             POStatus       `gorm:"type:enum('WAITING_MATERIAL_PREP','MATERIAL_PREP_IN_PROGRESS','READY_FOR_CETAK','CETAK_IN_PROGRESS','WAITING_COUNTING','COUNTING_IN_PROGRESS','READY_FOR_CUTTING','CUTTING_IN_PROGRESS','READY_FOR_VERIFIKASI','VERIFIKASI_IN_PROGRESS','READY_FOR_KHAZKHIR','KHAZKHIR_IN_PROGRESS','COMPLETED','CANCELLED');default:'WAITING_MATERIAL_PREP'" json:"current_status"`
 	Notes                     string         `gorm:"type:text" json:"notes"`
+
+	// Production rule yang dipakai saat generate PO
+	ProductionRuleID          *uint64        `gorm:"type:bigint unsigned null;index" json:"production_rule_id"`
+	ProductionRuleVersion     int            `gorm:"default:0" json:"production_rule_version"`
+
+	// Split/Merge lineage
+	ParentPOID                *uint64        `gorm:"type:bigint unsigned null;index" json:"parent_po_id"`
+	MergedIntoPOID            *uint64        `gorm:"type:bigint unsigned null;index" json:"merged_into_po_id"`
+
+	// Cancellation
+	CancelledAt               *time.Time     `gorm:"type:timestamp null" json:"cancelled_at"`
+	CancelledBy               *uint64        `gorm:"type:bigint unsigned null" json:"cancelled_by"`
+	CancellationReason        string         `gorm:"type:text" json:"cancellation_reason"`
+
 	CreatedAt                 time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt                 time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt                 gorm.DeletedAt `gorm:"index" json:"-"`
@@ -86,6 +107,28 @@ func (po *ProductionOrder) IsUrgent() bool {
 	return po.Priority == PriorityUrgent
 }
 
+// IsCancelled memeriksa apakah PO sudah dibatalkan
+func (po *ProductionOrder) IsCancelled() bool {
+	return po.CurrentStatus == StatusPOCancelled
+}
+
+// IsEditable memeriksa apakah PO masih bisa diubah oleh PPIC (belum selesai atau dibatalkan)
+func (po *ProductionOrder) IsEditable() bool {
+	return po.CurrentStatus != StatusPOCompleted && po.CurrentStatus != StatusPOCancelled
+}
+
+// IsMaterialPrepStarted memeriksa apakah PO sudah mulai material prep atau sudah melewati stage tersebut,
+// PO yang dibatalkan sebelum material prep dianggap belum mulai
+func (po *ProductionOrder) IsMaterialPrepStarted() bool {
+	if po.CurrentStatus == StatusWaitingMaterialPrep {
+		return false
+	}
+	if po.CurrentStatus == StatusPOCancelled && po.CurrentStage == StageKhazwalMaterialPrep {
+		return false
+	}
+	return true
+}
+
 // IsPastDue memeriksa apakah PO sudah melewati due date
 func (po *ProductionOrder) IsPastDue() bool {
 	return time.Now().After(po.DueDate)
@@ -132,3 +175,11 @@ func (po *ProductionOrder) CalculatePriorityScore() int {
 func (po *ProductionOrder) UpdatePriorityScore() {
 	po.PriorityScore = po.CalculatePriorityScore()
 }
+
+// ApplyQuantity mengubah quantity PO beserta target lembar besar dan estimasi rim
+// sesuai production rule, digunakan saat generate, split, dan merge PO
+func (po *ProductionOrder) ApplyQuantity(quantity int, rule *ProductionRule) {
+	po.QuantityOrdered = quantity
+	po.QuantityTargetLembarBesar = rule.CalculateTargetLembarBesar(quantity)
+	po.EstimatedRims = rule.CalculateEstimatedRims(quantity)
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+// Default production rule sesuai Flow.md: buffer 6%, maks 40.000 lembar/PO,
+// 1 rim = 500 lembar kirim, dan 1 lembar besar = 2 lembar kirim
+const (
+	DefaultProductionRuleCode    = "DEFAULT"
+	DefaultBufferPercentage      = 6.0
+	DefaultMaxQuantityPerPO      = 40000
+	DefaultSheetsPerRim          = 500
+	DefaultLembarBesarMultiplier = 2
+)
+
+// Error untuk validasi production rule
+var (
+	ErrInvalidBufferPercentage      = errors.New("buffer_percentage harus antara 0 dan 100")
+	ErrInvalidMaxQuantityPerPO      = errors.New("max_quantity_per_po harus lebih dari 0")
+	ErrInvalidSheetsPerRim          = errors.New("sheets_per_rim harus lebih dari 0")
+	ErrInvalidLembarBesarMultiplier = errors.New("lembar_besar_multiplier harus lebih dari 0")
+)
+
+// ProductionRule merupakan model untuk aturan pembagian OBC → PO yang versioned
+// yang mencakup buffer produksi, batas quantity per PO, konversi rim, dan multiplier lembar besar.
+// Setiap perubahan membuat versi baru dengan Code yang sama sehingga PO tetap mereferensikan versi yang dipakai
+type ProductionRule struct {
+	ID                    uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code                  string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_rule_code_version" json:"code"`
+	Version               int       `gorm:"not null;uniqueIndex:idx_rule_code_version" json:"version"`
+	Name                  string    `gorm:"type:varchar(100);not null" json:"name"`
+	ProductType           string    `gorm:"type:varchar(50);index" json:"product_type"`    // Kosong = berlaku untuk semua tipe produk
+	Personalization       string    `gorm:"type:varchar(20);index" json:"personalization"` // Kosong = berlaku untuk perso & non-perso
+	BufferPercentage      float64   `gorm:"type:decimal(5,2);not null" json:"buffer_percentage"`
+	MaxQuantityPerPO      int       `gorm:"not null" json:"max_quantity_per_po"`
+	SheetsPerRim          int       `gorm:"not null" json:"sheets_per_rim"`
+	LembarBesarMultiplier int       `gorm:"not null" json:"lembar_besar_multiplier"`
+	IsActive              bool      `gorm:"default:true;index" json:"is_active"`
+	Notes                 string    `gorm:"type:text" json:"notes"`
+	CreatedBy             *uint64   `gorm:"type:bigint unsigned null" json:"created_by"`
+	CreatedAt             time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt             time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (ProductionRule) TableName() string {
+	return "production_rules"
+}
+
+// DefaultProductionRule mengembalikan aturan bawaan sesuai Flow.md
+func DefaultProductionRule() ProductionRule {
+	return ProductionRule{
+		Code:                  DefaultProductionRuleCode,
+		Version:               1,
+		Name:                  "Default Rule",
+		BufferPercentage:      DefaultBufferPercentage,
+		MaxQuantityPerPO:      DefaultMaxQuantityPerPO,
+		SheetsPerRim:          DefaultSheetsPerRim,
+		LembarBesarMultiplier: DefaultLembarBesarMultiplier,
+		IsActive:              true,
+	}
+}
+
+// Validate memvalidasi parameter production rule
+func (r *ProductionRule) Validate() error {
+	if r.BufferPercentage < 0 || r.BufferPercentage > 100 {
+		return ErrInvalidBufferPercentage
+	}
+	if r.MaxQuantityPerPO <= 0 {
+		return ErrInvalidMaxQuantityPerPO
+	}
+	if r.SheetsPerRim <= 0 {
+		return ErrInvalidSheetsPerRim
+	}
+	if r.LembarBesarMultiplier <= 0 {
+		return ErrInvalidLembarBesarMultiplier
+	}
+	return nil
+}
+
+// Matches memeriksa apakah rule berlaku untuk tipe produk dan personalisasi OBC
+func (r *ProductionRule) Matches(productType, personalization string) bool {
+	if r.ProductType != "" && !strings.EqualFold(r.ProductType, productType) {
+		return false
+	}
+	if r.Personalization != "" && !strings.EqualFold(r.Personalization, personalization) {
+		return false
+	}
+	return true
+}
+
+// Specificity mengembalikan tingkat kespesifikan rule, rule yang lebih spesifik didahulukan
+func (r *ProductionRule) Specificity() int {
+	score := 0
+	if r.ProductType != "" {
+		score += 2
+	}
+	if r.Personalization != "" {
+		score++
+	}
+	return score
+}
+
+// CalculateTotalWithBuffer menghitung total produksi: Total = QTY + (QTY × buffer%)
+func (r *ProductionRule) CalculateTotalWithBuffer(quantity int) int {
+	buffer := float64(quantity) * r.BufferPercentage / 100
+	return quantity + int(buffer)
+}
+
+// SplitQuantities membagi total produksi menjadi quantity per PO dengan batas MaxQuantityPerPO
+func (r *ProductionRule) SplitQuantities(total int) []int {
+	if total <= 0 || r.MaxQuantityPerPO <= 0 {
+		return []int{}
+	}
+
+	count := int(math.Ceil(float64(total) / float64(r.MaxQuantityPerPO)))
+	quantities := make([]int, 0, count)
+	remaining := total
+	for remaining > 0 {
+		qty := r.MaxQuantityPerPO
+		if remaining < qty {
+			qty = remaining
+		}
+		quantities = append(quantities, qty)
+		remaining -= qty
+	}
+	return quantities
+}
+
+// CalculateTargetLembarBesar menghitung target lembar besar dari quantity lembar kirim
+func (r *ProductionRule) CalculateTargetLembarBesar(quantity int) int {
+	if r.LembarBesarMultiplier <= 0 {
+		return quantity
+	}
+	return int(math.Ceil(float64(quantity) / float64(r.LembarBesarMultiplier)))
+}
+
+// CalculateEstimatedRims menghitung estimasi rim dari quantity lembar kirim
+func (r *ProductionRule) CalculateEstimatedRims(quantity int) int {
+	if r.SheetsPerRim <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(quantity) / float64(r.SheetsPerRim)))
+}
@@ -0,0 +1,10 @@
+package models
+
+// Threshold kualitas produksi dalam persen, dipakai bersama oleh validasi di setiap stage
+// dan eskalasi ke manager serta PPIC saat finalize
+const (
+	RejectThresholdPercentage   = 5.0 // lembar rusak hasil cetak
+	DefectThresholdPercentage   = 5.0 // rusak counting dan verifikasi, di atasnya wajib breakdown
+	WasteThresholdPercentage    = 2.0 // waste cutting, di atasnya wajib reason dan foto
+	VarianceThresholdPercentage = 5.0 // variance material prep, di atasnya wajib alasan
+)
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// SAPOutboxEventType merupakan enum untuk jenis posting ke SAP
+type SAPOutboxEventType string
+
+const (
+	SAPEventMaterialPrepFinalized SAPOutboxEventType = "MATERIAL_PREP_FINALIZED"
+	SAPEventCountingFinalized     SAPOutboxEventType = "COUNTING_FINALIZED"
+	SAPEventCuttingFinalized      SAPOutboxEventType = "CUTTING_FINALIZED"
+)
+
+// SAPOutboxStatus merupakan enum untuk status pengiriman entry outbox ke SAP
+type SAPOutboxStatus string
+
+const (
+	SAPOutboxPending    SAPOutboxStatus = "PENDING"
+	SAPOutboxProcessing SAPOutboxStatus = "PROCESSING"
+	SAPOutboxDelivered  SAPOutboxStatus = "DELIVERED"
+	SAPOutboxFailed     SAPOutboxStatus = "FAILED"
+)
+
+const (
+	// SAPOutboxDefaultMaxAttempts merupakan batas percobaan pengiriman sebelum entry ditandai FAILED
+	SAPOutboxDefaultMaxAttempts = 8
+
+	sapOutboxBaseRetryDelay = 30 * time.Second
+	sapOutboxMaxRetryDelay  = time.Hour
+)
+
+// SAPOutboxEntry merupakan entry integration outbox yang ditulis dalam transaction yang sama
+// dengan proses finalize, kemudian dikirim ke SAP oleh worker secara asynchronous
+type SAPOutboxEntry struct {
+	ID                uint64             `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventType         SAPOutboxEventType `gorm:"type:varchar(50);not null;index" json:"event_type"`
+	AggregateType     string             `gorm:"type:varchar(50);not null" json:"aggregate_type"`
+	AggregateID       uint64             `gorm:"not null" json:"aggregate_id"`
+	ProductionOrderID uint64             `gorm:"not null;index" json:"production_order_id"`
+	IdempotencyKey    string             `gorm:"type:varchar(120);uniqueIndex;not null" json:"idempotency_key"`
+	Payload           datatypes.JSON     `gorm:"type:json;not null" json:"payload"`
+	Status            SAPOutboxStatus    `gorm:"type:varchar(20);not null;default:'PENDING';index:idx_sap_outbox_due,priority:1" json:"status"`
+	Attempts          int                `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts       int                `gorm:"not null;default:8" json:"max_attempts"`
+	NextAttemptAt     time.Time          `gorm:"not null;index:idx_sap_outbox_due,priority:2" json:"next_attempt_at"`
+	LastAttemptAt     *time.Time         `gorm:"type:timestamp null" json:"last_attempt_at"`
+	LastError         string             `gorm:"type:text" json:"last_error"`
+	LockedAt          *time.Time         `gorm:"type:timestamp null" json:"locked_at"`
+	DeliveredAt       *time.Time         `gorm:"type:timestamp null" json:"delivered_at"`
+	ExternalReference string             `gorm:"type:varchar(100)" json:"external_reference"`
+	CreatedAt         time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (SAPOutboxEntry) TableName() string {
+	return "sap_outbox_entries"
+}
+
+// IsDelivered memeriksa apakah entry sudah berhasil diterima SAP
+func (e *SAPOutboxEntry) IsDelivered() bool {
+	return e.Status == SAPOutboxDelivered
+}
+
+// CanRetry memeriksa apakah entry boleh dijadwalkan ulang secara manual
+func (e *SAPOutboxEntry) CanRetry() bool {
+	return e.Status == SAPOutboxFailed || e.Status == SAPOutboxPending
+}
+
+// HasExhaustedAttempts memeriksa apakah jumlah percobaan sudah mencapai batas
+func (e *SAPOutboxEntry) HasExhaustedAttempts() bool {
+	return e.MaxAttempts > 0 && e.Attempts >= e.MaxAttempts
+}
+
+// SAPOutboxRetryDelay menghitung jeda exponential backoff setelah percobaan ke-n gagal,
+// dimulai dari 30 detik dan dibatasi maksimal 1 jam
+func SAPOutboxRetryDelay(attempts int) time.Duration {
+	if attempts <= 1 {
+		return sapOutboxBaseRetryDelay
+	}
+	delay := sapOutboxBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= sapOutboxMaxRetryDelay {
+			return sapOutboxMaxRetryDelay
+		}
+	}
+	return delay
+}
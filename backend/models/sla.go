@@ -0,0 +1,143 @@
+package models
+
+import (
+	"time"
+)
+
+// SLALevel merupakan enum untuk status SLA PO yang menunggu di antrian stage
+type SLALevel string
+
+const (
+	SLAOnTrack  SLALevel = "ON_TRACK"
+	SLAAtRisk   SLALevel = "AT_RISK"
+	SLABreached SLALevel = "BREACHED"
+)
+
+// SLAThreshold merupakan batas waktu tunggu dalam menit: lewat WarningMinutes PO dianggap
+// hampir melanggar SLA (AT_RISK) dan lewat BreachMinutes PO dianggap overdue (BREACHED)
+type SLAThreshold struct {
+	WarningMinutes int `json:"warning_minutes"`
+	BreachMinutes  int `json:"breach_minutes"`
+}
+
+// IsValid memeriksa apakah threshold positif dan warning lebih kecil dari breach
+func (t SLAThreshold) IsValid() bool {
+	return t.WarningMinutes > 0 && t.BreachMinutes > t.WarningMinutes
+}
+
+// LevelFor menentukan status SLA dari lama waktu tunggu dalam menit
+func (t SLAThreshold) LevelFor(waitingMinutes int) SLALevel {
+	switch {
+	case waitingMinutes > t.BreachMinutes:
+		return SLABreached
+	case waitingMinutes > t.WarningMinutes:
+		return SLAAtRisk
+	default:
+		return SLAOnTrack
+	}
+}
+
+// defaultSLAThresholds merupakan SLA bawaan per stage untuk priority NORMAL dan LOW,
+// dipakai jika belum ada SLA policy di database
+var defaultSLAThresholds = map[POStage]SLAThreshold{
+	StageKhazwalMaterialPrep: {WarningMinutes: 180, BreachMinutes: 240},
+	StageCetak:               {WarningMinutes: 360, BreachMinutes: 480},
+	StageKhazwalCounting:     {WarningMinutes: 90, BreachMinutes: 120},
+	StageKhazwalCutting:      {WarningMinutes: 45, BreachMinutes: 60},
+	StageVerifikasi:          {WarningMinutes: 180, BreachMinutes: 240},
+	StageKhazkhir:            {WarningMinutes: 180, BreachMinutes: 240},
+}
+
+// slaQueueStatuses merupakan status antrian (menunggu diambil) yang dipantau SLA untuk setiap stage
+var slaQueueStatuses = map[POStage]POStatus{
+	StageKhazwalMaterialPrep: StatusWaitingMaterialPrep,
+	StageCetak:               StatusReadyForCetak,
+	StageKhazwalCounting:     StatusWaitingCounting,
+	StageKhazwalCutting:      StatusReadyForCutting,
+	StageVerifikasi:          StatusReadyForVerifikasi,
+	StageKhazkhir:            StatusReadyForKhazkhir,
+}
+
+// DefaultSLAThreshold mengembalikan SLA bawaan untuk stage dan priority,
+// PO URGENT mendapat setengah dari batas waktu normal
+func DefaultSLAThreshold(stage POStage, priority POPriority) (SLAThreshold, bool) {
+	threshold, ok := defaultSLAThresholds[stage]
+	if !ok {
+		return SLAThreshold{}, false
+	}
+	if priority == PriorityUrgent {
+		threshold.WarningMinutes /= 2
+		threshold.BreachMinutes /= 2
+	}
+	return threshold, true
+}
+
+// SLAStages mengembalikan list stage yang memiliki SLA sesuai urutan alur produksi
+func SLAStages() []POStage {
+	return []POStage{
+		StageKhazwalMaterialPrep,
+		StageCetak,
+		StageKhazwalCounting,
+		StageKhazwalCutting,
+		StageVerifikasi,
+		StageKhazkhir,
+	}
+}
+
+// SLAQueueStatus mengembalikan status antrian yang dipantau SLA untuk stage
+func SLAQueueStatus(stage POStage) (POStatus, bool) {
+	status, ok := slaQueueStatuses[stage]
+	return status, ok
+}
+
+// SLAPolicy merupakan konfigurasi SLA per stage dan priority yang mengganti SLA bawaan,
+// Priority kosong berarti berlaku untuk semua priority di stage tersebut
+type SLAPolicy struct {
+	ID             uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Stage          POStage    `gorm:"type:varchar(50);not null;uniqueIndex:idx_sla_policy_stage_priority,priority:1" json:"stage"`
+	Priority       POPriority `gorm:"type:varchar(20);not null;default:'';uniqueIndex:idx_sla_policy_stage_priority,priority:2" json:"priority"`
+	WarningMinutes int        `gorm:"not null" json:"warning_minutes"`
+	BreachMinutes  int        `gorm:"not null" json:"breach_minutes"`
+	UpdatedBy      *uint64    `gorm:"type:bigint unsigned null" json:"updated_by"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (SLAPolicy) TableName() string {
+	return "sla_policies"
+}
+
+// Threshold mengembalikan batas waktu dari policy
+func (p *SLAPolicy) Threshold() SLAThreshold {
+	return SLAThreshold{WarningMinutes: p.WarningMinutes, BreachMinutes: p.BreachMinutes}
+}
+
+// SLABreach merupakan history PO yang hampir melanggar (AT_RISK) atau melanggar (BREACHED) SLA
+// di antrian stage, satu record per level untuk setiap kali PO masuk antrian (WaitingSince)
+// sehingga eskalasi hanya dikirim sekali, dan ResolvedAt diisi saat PO diambil dari antrian
+type SLABreach struct {
+	ID                uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductionOrderID uint64     `gorm:"not null;uniqueIndex:idx_sla_breach_dedup,priority:1" json:"production_order_id"`
+	Stage             POStage    `gorm:"type:varchar(50);not null;index;uniqueIndex:idx_sla_breach_dedup,priority:2" json:"stage"`
+	Level             SLALevel   `gorm:"type:varchar(20);not null;uniqueIndex:idx_sla_breach_dedup,priority:3" json:"level"`
+	WaitingSince      time.Time  `gorm:"not null;uniqueIndex:idx_sla_breach_dedup,priority:4" json:"waiting_since"`
+	QueueStatus       POStatus   `gorm:"type:varchar(50);not null" json:"queue_status"`
+	Priority          POPriority `gorm:"type:varchar(20);not null" json:"priority"`
+	ThresholdMinutes  int        `gorm:"not null" json:"threshold_minutes"`
+	WaitingMinutes    int        `gorm:"not null" json:"waiting_minutes"`
+	DetectedAt        time.Time  `gorm:"not null;index" json:"detected_at"`
+	ResolvedAt        *time.Time `gorm:"type:timestamp null;index" json:"resolved_at"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName menentukan nama tabel di database
+func (SLABreach) TableName() string {
+	return "sla_breaches"
+}
+
+// IsResolved memeriksa apakah PO sudah keluar dari antrian
+func (b *SLABreach) IsResolved() bool {
+	return b.ResolvedAt != nil
+}
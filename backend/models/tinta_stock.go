@@ -0,0 +1,71 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TintaMovementType merupakan enum untuk jenis mutasi stok tinta
+type TintaMovementType string
+
+const (
+	TintaMovementReceipt TintaMovementType = "RECEIPT"
+	TintaMovementIssue   TintaMovementType = "ISSUE"
+	TintaMovementReturn  TintaMovementType = "RETURN"
+)
+
+// TintaStock merupakan model untuk saldo stok tinta per warna dalam kg
+// dengan batas minimum yang bisa dikonfigurasi untuk peringatan low stock
+type TintaStock struct {
+	ID                 uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	ColorCode          string         `gorm:"type:varchar(30);uniqueIndex;not null" json:"color_code" binding:"required"`
+	Name               string         `gorm:"type:varchar(100)" json:"name"`
+	OnHandKg           float64        `gorm:"type:decimal(12,3);not null;default:0" json:"on_hand_kg"`
+	MinimumKg          float64        `gorm:"type:decimal(12,3);not null;default:0" json:"minimum_kg"`
+	LowStockNotifiedAt *time.Time     `gorm:"type:timestamp null" json:"low_stock_notified_at"`
+	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName menentukan nama tabel di database
+func (TintaStock) TableName() string {
+	return "tinta_stocks"
+}
+
+// IsLowStock memeriksa apakah stok tinta sudah di bawah batas minimum,
+// batas minimum 0 berarti peringatan low stock tidak aktif
+func (t *TintaStock) IsLowStock() bool {
+	return t.MinimumKg > 0 && t.OnHandKg < t.MinimumKg
+}
+
+// TintaStockMovement merupakan ledger mutasi stok tinta
+// yang mencatat saldo on hand setelah setiap mutasi
+type TintaStockMovement struct {
+	ID                uint64            `gorm:"primaryKey;autoIncrement" json:"id"`
+	TintaStockID      uint64            `gorm:"not null;index" json:"tinta_stock_id"`
+	Type              TintaMovementType `gorm:"type:enum('RECEIPT','ISSUE','RETURN');not null;index" json:"type"`
+	QuantityKg        float64           `gorm:"type:decimal(12,3);not null" json:"quantity_kg"`
+	OnHandAfterKg     float64           `gorm:"type:decimal(12,3);not null" json:"on_hand_after_kg"`
+	ProductionOrderID *uint64           `gorm:"type:bigint unsigned null;index" json:"production_order_id"`
+	MaterialPrepID    *uint64           `gorm:"type:bigint unsigned null;index" json:"material_prep_id"`
+	Reference         string            `gorm:"type:varchar(100)" json:"reference"`
+	Notes             string            `gorm:"type:text" json:"notes"`
+	CreatedBy         uint64            `gorm:"not null" json:"created_by"`
+	CreatedAt         time.Time         `gorm:"autoCreateTime;index" json:"created_at"`
+
+	// Relations
+	CreatedByUser *User `gorm:"foreignKey:CreatedBy" json:"created_by_user,omitempty"`
+}
+
+// TableName menentukan nama tabel di database
+func (TintaStockMovement) TableName() string {
+	return "tinta_stock_movements"
+}
+
+// NormalizeTintaColor menyeragamkan nama warna tinta agar input "Cyan " dan "cyan" merujuk ke stok yang sama
+func NormalizeTintaColor(color string) string {
+	return strings.ToLower(strings.TrimSpace(color))
+}
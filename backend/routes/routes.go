@@ -1,18 +1,26 @@
 package routes
 
 import (
+	"context"
+	"log"
 	"sirine-go/backend/config"
 	"sirine-go/backend/database"
 	"sirine-go/backend/handlers"
 	"sirine-go/backend/internal/counting"
 	"sirine-go/backend/internal/cutting"
+	"sirine-go/backend/internal/khazkhir"
+	"sirine-go/backend/internal/verifikasi"
 	"sirine-go/backend/middleware"
 	"sirine-go/backend/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine, cfg *config.Config) {
+// SetupRoutes mendaftarkan semua routes dan mengembalikan background worker dari service yang dibuat,
+// worker tidak dijalankan di sini melainkan oleh main dengan context yang dibatalkan saat shutdown
+func SetupRoutes(r *gin.Engine, cfg *config.Config) *BackgroundWorkers {
+	workers := &BackgroundWorkers{}
+
 	// Apply CORS middleware
 	r.Use(middleware.CORS())
 
@@ -139,7 +147,183 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 			adminUsers.GET("/:id/achievements", achievementHandler.GetAchievementsByUserID)
 		}
 
+		// Machine Registry routes (Admin/Manager)
+		machineService := services.NewMachineService(db)
+		machineHandler := handlers.NewMachineHandler(machineService)
+
+		adminMachines := api.Group("/admin/machines")
+		adminMachines.Use(middleware.AuthMiddleware(db, cfg))
+		adminMachines.Use(middleware.RequireRole("ADMIN", "MANAGER"))
+		adminMachines.Use(middleware.ActivityLogger(db))
+		{
+			adminMachines.GET("", machineHandler.List)
+			adminMachines.GET("/:id", machineHandler.Detail)
+			adminMachines.POST("", middleware.RequireRole("ADMIN"), machineHandler.Create)
+			adminMachines.PUT("/:id", middleware.RequireRole("ADMIN"), machineHandler.Update)
+			adminMachines.PATCH("/:id/status", machineHandler.UpdateStatus)
+			adminMachines.DELETE("/:id", middleware.RequireRole("ADMIN"), machineHandler.Delete)
+		}
+
+		// Plat Registry routes (Admin/Manager)
+		platService := services.NewPlatService(db)
+		platHandler := handlers.NewPlatHandler(platService)
+
+		adminPlats := api.Group("/admin/plats")
+		adminPlats.Use(middleware.AuthMiddleware(db, cfg))
+		adminPlats.Use(middleware.RequireRole("ADMIN", "MANAGER"))
+		adminPlats.Use(middleware.ActivityLogger(db))
+		{
+			adminPlats.GET("", platHandler.List)
+			adminPlats.GET("/:id", platHandler.Detail)
+			adminPlats.POST("", middleware.RequireRole("ADMIN"), platHandler.Create)
+			adminPlats.PUT("/:id", middleware.RequireRole("ADMIN"), platHandler.Update)
+			adminPlats.PATCH("/:id/status", platHandler.UpdateStatus)
+		}
+
+		// Plat routes untuk staff Khazwal dan operator cetak (lihat stok, riwayat, dan pengembalian plat)
+		plats := api.Group("/plats")
+		plats.Use(middleware.AuthMiddleware(db, cfg))
+		plats.Use(middleware.RequireRole("STAFF_KHAZWAL", "OPERATOR_CETAK", "ADMIN", "MANAGER"))
+		plats.Use(middleware.ActivityLogger(db))
+		{
+			plats.GET("", platHandler.List)
+			plats.GET("/:id", platHandler.Detail)
+			plats.GET("/:id/history", platHandler.History)
+			plats.POST("/:id/return", platHandler.Return)
+		}
+
+		// Kertas Blanko stock ledger routes (saldo, penerimaan, retur, dan riwayat mutasi)
+		kertasStockService := services.NewKertasStockService(db)
+		kertasStockHandler := handlers.NewKertasStockHandler(kertasStockService)
+
+		kertasStocks := api.Group("/kertas-stocks")
+		kertasStocks.Use(middleware.AuthMiddleware(db, cfg))
+		kertasStocks.Use(middleware.RequireRole("STAFF_KHAZWAL", "PPIC", "ADMIN", "MANAGER"))
+		kertasStocks.Use(middleware.ActivityLogger(db))
+		{
+			kertasStocks.GET("", kertasStockHandler.List)
+			kertasStocks.GET("/:id", kertasStockHandler.Detail)
+			kertasStocks.GET("/:id/movements", kertasStockHandler.Movements)
+			kertasStocks.POST("", middleware.RequireRole("ADMIN"), kertasStockHandler.Create)
+			kertasStocks.PUT("/:id", middleware.RequireRole("ADMIN", "MANAGER"), kertasStockHandler.Update)
+			kertasStocks.POST("/:id/receipts", middleware.RequireRole("STAFF_KHAZWAL", "ADMIN", "MANAGER"), kertasStockHandler.Receive)
+			kertasStocks.POST("/returns", middleware.RequireRole("STAFF_KHAZWAL", "ADMIN", "MANAGER"), kertasStockHandler.Return)
+		}
+
+		// Tinta inventory routes (saldo per warna, batas minimum, penerimaan, dan riwayat mutasi)
+		tintaStockService := services.NewTintaStockService(db)
+		tintaStockHandler := handlers.NewTintaStockHandler(tintaStockService)
+
+		tintaStocks := api.Group("/tinta-stocks")
+		tintaStocks.Use(middleware.AuthMiddleware(db, cfg))
+		tintaStocks.Use(middleware.RequireRole("STAFF_KHAZWAL", "PPIC", "ADMIN", "MANAGER"))
+		tintaStocks.Use(middleware.ActivityLogger(db))
+		{
+			tintaStocks.GET("", tintaStockHandler.List)
+			tintaStocks.GET("/:id", tintaStockHandler.Detail)
+			tintaStocks.GET("/:id/movements", tintaStockHandler.Movements)
+			tintaStocks.POST("", middleware.RequireRole("ADMIN"), tintaStockHandler.Create)
+			tintaStocks.PUT("/:id", middleware.RequireRole("ADMIN", "MANAGER"), tintaStockHandler.Update)
+			tintaStocks.POST("/:id/receipts", middleware.RequireRole("STAFF_KHAZWAL", "ADMIN", "MANAGER"), tintaStockHandler.Receive)
+		}
+
+		// SAP integration outbox routes (monitoring posting material dan retry entry yang gagal)
+		sapClient, err := services.NewSAPClientFromConfig(cfg)
+		if err != nil {
+			log.Printf("SAP outbox: %v, worker tidak dijalankan", err)
+		}
+		sapOutboxService := services.NewSAPOutboxService(db, sapClient, cfg.SAPOutboxPollInterval)
+		workers.add(sapOutboxService.StartWorker)
+		sapOutboxHandler := handlers.NewSAPOutboxHandler(sapOutboxService)
+
+		sapOutbox := api.Group("/admin/sap-outbox")
+		sapOutbox.Use(middleware.AuthMiddleware(db, cfg))
+		sapOutbox.Use(middleware.RequireRole("ADMIN", "MANAGER"))
+		sapOutbox.Use(middleware.ActivityLogger(db))
+		{
+			sapOutbox.GET("", sapOutboxHandler.List)
+			sapOutbox.GET("/summary", sapOutboxHandler.Summary)
+			sapOutbox.GET("/:id", sapOutboxHandler.Detail)
+			sapOutbox.POST("/:id/retry", middleware.RequireRole("ADMIN"), sapOutboxHandler.Retry)
+		}
+
+		// Evidence attachment routes (upload foto material dan foto waste sebelum dihubungkan ke entity)
+		attachmentService := services.NewAttachmentService(db, "./public/uploads/evidence", "/uploads/evidence", int64(cfg.EvidenceMaxUploadMB)*1024*1024)
+		workers.add(func(ctx context.Context) {
+			attachmentService.StartCleanupJob(ctx, cfg.EvidenceCleanupInterval, cfg.EvidenceOrphanRetention)
+		})
+		attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
+
+		attachments := api.Group("/attachments")
+		attachments.Use(middleware.AuthMiddleware(db, cfg))
+		attachments.Use(middleware.RequireRole("STAFF_KHAZWAL", "ADMIN", "MANAGER"))
+		attachments.Use(middleware.ActivityLogger(db))
+		{
+			attachments.POST("", attachmentHandler.Upload)
+			attachments.GET("", attachmentHandler.List)
+			attachments.GET("/:id", attachmentHandler.Detail)
+			attachments.DELETE("/:id", attachmentHandler.Delete)
+		}
+
+		// Event stream routes (SSE untuk update queue dan notifikasi secara real-time)
+		if err := services.RegisterDomainEventCallbacks(db); err != nil {
+			log.Printf("Gagal memasang callback domain event: %v", err)
+		}
+		eventStreamHandler := handlers.NewEventStreamHandler(services.DefaultEventBus())
+
+		events := api.Group("/events")
+		events.Use(middleware.AuthMiddleware(db, cfg))
+		{
+			events.GET("", eventStreamHandler.Stream)
+		}
+
+		// SLA routes (konfigurasi SLA antrian stage dan laporan history breach)
+		slaService := services.NewSLAService(db)
+		workers.add(func(ctx context.Context) {
+			slaService.StartScheduler(ctx, cfg.SLAScanInterval)
+		})
+		slaHandler := handlers.NewSLAHandler(slaService)
+
+		adminSLA := api.Group("/admin/sla")
+		adminSLA.Use(middleware.AuthMiddleware(db, cfg))
+		adminSLA.Use(middleware.RequireRole("ADMIN", "PPIC", "MANAGER"))
+		adminSLA.Use(middleware.ActivityLogger(db))
+		{
+			adminSLA.GET("/policies", slaHandler.ListPolicies)
+			adminSLA.PUT("/policies", middleware.RequireRole("ADMIN", "MANAGER"), slaHandler.UpsertPolicy)
+			adminSLA.DELETE("/policies/:id", middleware.RequireRole("ADMIN", "MANAGER"), slaHandler.DeletePolicy)
+			adminSLA.GET("/breaches", slaHandler.ListBreaches)
+			adminSLA.GET("/breaches/summary", slaHandler.GetBreachSummary)
+		}
+
+		// Production Rule routes (aturan pembagian OBC → PO yang versioned)
+		productionRuleService := services.NewProductionRuleService(db)
+		productionRuleHandler := handlers.NewProductionRuleHandler(productionRuleService)
+
+		adminProductionRules := api.Group("/admin/production-rules")
+		adminProductionRules.Use(middleware.AuthMiddleware(db, cfg))
+		adminProductionRules.Use(middleware.RequireRole("ADMIN", "PPIC", "MANAGER"))
+		adminProductionRules.Use(middleware.ActivityLogger(db))
+		{
+			adminProductionRules.GET("", productionRuleHandler.List)
+			adminProductionRules.GET("/:id", productionRuleHandler.Detail)
+			adminProductionRules.POST("", middleware.RequireRole("ADMIN", "PPIC"), productionRuleHandler.Create)
+			adminProductionRules.PUT("/:id", middleware.RequireRole("ADMIN", "PPIC"), productionRuleHandler.Update)
+			adminProductionRules.DELETE("/:id", middleware.RequireRole("ADMIN", "PPIC"), productionRuleHandler.Deactivate)
+		}
+
+		// Machine read-only routes (untuk operator memilih mesin saat start cetak/potong)
+		machinesReadOnly := api.Group("/machines")
+		machinesReadOnly.Use(middleware.AuthMiddleware(db, cfg))
+		machinesReadOnly.Use(middleware.RequireRole("OPERATOR_CETAK", "STAFF_KHAZWAL", "ADMIN", "MANAGER"))
+		{
+			machinesReadOnly.GET("", machineHandler.List)
+		}
+
 		// Notification routes (Protected - All authenticated users)
+		workers.add(func(ctx context.Context) {
+			notificationService.StartCleanupJob(ctx, cfg.NotificationCleanupInterval, cfg.NotificationReadRetention)
+		})
 		notificationHandler := handlers.NewNotificationHandler(notificationService)
 
 		notifications := api.Group("/notifications")
@@ -150,9 +334,42 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 			notifications.GET("/recent", notificationHandler.GetRecentNotifications)
 			notifications.PUT("/:id/read", notificationHandler.MarkAsRead)
 			notifications.PUT("/read-all", notificationHandler.MarkAllAsRead)
+			notifications.POST("/broadcast", middleware.RequireRole("ADMIN", "MANAGER", "PPIC"), middleware.ActivityLogger(db), notificationHandler.BroadcastNotification)
 			notifications.DELETE("/:id", notificationHandler.DeleteNotification)
 		}
 
+		// Notification delivery routes (preferensi email/webhook dan monitoring delivery queue)
+		if err := services.RegisterNotificationDeliveryCallbacks(db); err != nil {
+			log.Printf("Gagal memasang callback notification delivery: %v", err)
+		}
+		webhookGuard := services.NewWebhookGuardFromConfig(cfg)
+		emailSender, webhookSender := services.NewNotificationSendersFromConfig(cfg, webhookGuard)
+		notificationDeliveryService := services.NewNotificationDeliveryService(db, emailSender, webhookSender, services.NotificationDeliverySettings{
+			PollInterval: cfg.NotificationDeliveryPollInterval,
+			DigestHour:   cfg.NotificationDigestHour,
+			AppURL:       cfg.FrontendURL,
+			WebhookGuard: webhookGuard,
+		})
+		workers.add(notificationDeliveryService.StartWorker)
+		notificationDeliveryHandler := handlers.NewNotificationDeliveryHandler(notificationDeliveryService)
+
+		notificationPreferences := api.Group("/notifications/preferences")
+		notificationPreferences.Use(middleware.AuthMiddleware(db, cfg))
+		{
+			notificationPreferences.GET("", notificationDeliveryHandler.GetPreferences)
+			notificationPreferences.PUT("", notificationDeliveryHandler.UpdatePreferences)
+			notificationPreferences.POST("/test", notificationDeliveryHandler.SendTest)
+		}
+
+		notificationDeliveries := api.Group("/admin/notification-deliveries")
+		notificationDeliveries.Use(middleware.AuthMiddleware(db, cfg))
+		notificationDeliveries.Use(middleware.RequireRole("ADMIN", "MANAGER"))
+		notificationDeliveries.Use(middleware.ActivityLogger(db))
+		{
+			notificationDeliveries.GET("", notificationDeliveryHandler.List)
+			notificationDeliveries.POST("/:id/retry", middleware.RequireRole("ADMIN"), notificationDeliveryHandler.Retry)
+		}
+
 		// Activity Log routes (Admin only)
 		activityLogService := services.NewActivityLogService(db)
 		activityLogHandler := handlers.NewActivityLogHandler(activityLogService)
@@ -174,9 +391,18 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 			profileActivity.GET("/activity", activityLogHandler.GetMyActivity)
 		}
 
+		// PO number generator dipakai bersama oleh import OBC dan manajemen PO
+		poNumberGenerator := services.NewPONumberGenerator(cfg.PONumberFormat, cfg.PONumberDefaultPlant)
+
 		// OBC Master routes (Admin/PPIC only)
-		obcService := services.NewOBCImportService(db)
+		obcService := services.NewOBCImportService(db, poNumberGenerator, services.ParseHeaderAliases(cfg.OBCImportHeaderAliases))
 		obcHandler := handlers.NewOBCHandler(obcService)
+		importJobService := services.NewImportJobService(db, obcService)
+		workers.add(importJobService.StartWorker)
+		workers.importJobService = importJobService
+		importJobHandler := handlers.NewImportJobHandler(importJobService)
+		importBatchService := services.NewOBCImportBatchService(db)
+		importBatchHandler := handlers.NewOBCImportBatchHandler(importBatchService)
 
 		obc := api.Group("/obc")
 		obc.Use(middleware.AuthMiddleware(db, cfg))
@@ -184,6 +410,20 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 		obc.Use(middleware.ActivityLogger(db))
 		{
 			obc.POST("/import", obcHandler.Import)
+			obc.POST("/import/commit", obcHandler.CommitImport)
+
+			// Background import job dengan progress tracking
+			obc.POST("/import-jobs", importJobHandler.Create)
+			obc.GET("/import-jobs", importJobHandler.List)
+			obc.GET("/import-jobs/:id", importJobHandler.Detail)
+			obc.GET("/import-jobs/:id/stream", importJobHandler.Stream)
+			obc.GET("/import-jobs/:id/result", importJobHandler.Result)
+
+			// Riwayat import dan rollback batch
+			obc.GET("/import-batches", importBatchHandler.List)
+			obc.GET("/import-batches/:id", importBatchHandler.Detail)
+			obc.POST("/import-batches/:id/rollback", importBatchHandler.Rollback)
+
 			obc.GET("", obcHandler.List)
 			obc.GET("/:id", obcHandler.Detail)
 			obc.POST("/:id/generate-po", obcHandler.GeneratePO)
@@ -198,8 +438,33 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 			obcReadOnly.GET("/detail/:id", obcHandler.Detail)
 		}
 
+		// Production Order routes
+		productionOrderService := services.NewProductionOrderService(db, poNumberGenerator)
+		poTimelineService := services.NewPOTimelineService(db)
+		productionOrderHandler := handlers.NewProductionOrderHandler(productionOrderService, poTimelineService)
+
+		// Timeline PO (Protected - All authenticated users)
+		productionOrderTimeline := api.Group("/production-orders")
+		productionOrderTimeline.Use(middleware.AuthMiddleware(db, cfg))
+		{
+			productionOrderTimeline.GET("/:id/timeline", productionOrderHandler.GetTimeline)
+		}
+
+		// PO Management (PPIC) - write actions untuk PPIC & Admin, Manager read-only
+		productionOrders := api.Group("/production-orders")
+		productionOrders.Use(middleware.AuthMiddleware(db, cfg))
+		productionOrders.Use(middleware.RequireRole("PPIC", "ADMIN", "MANAGER"))
+		{
+			productionOrders.GET("", productionOrderHandler.List)
+			productionOrders.GET("/:id", productionOrderHandler.Detail)
+			productionOrders.PATCH("/:id", middleware.RequireRole("PPIC", "ADMIN"), productionOrderHandler.Update)
+			productionOrders.POST("/:id/split", middleware.RequireRole("PPIC", "ADMIN"), productionOrderHandler.Split)
+			productionOrders.POST("/merge", middleware.RequireRole("PPIC", "ADMIN"), productionOrderHandler.Merge)
+			productionOrders.POST("/:id/cancel", middleware.RequireRole("PPIC", "ADMIN"), productionOrderHandler.Cancel)
+		}
+
 		// Khazwal Material Preparation routes
-		khazwalService := services.NewKhazwalService(db)
+		khazwalService := services.NewKhazwalService(db, cfg.PlatAllowUnregistered)
 		khazwalHandler := handlers.NewKhazwalHandler(khazwalService)
 
 		khazwal := api.Group("/khazwal")
@@ -260,6 +525,43 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 		cuttingGroup.POST("/:id/finalize", cuttingHandler.FinalizeCutting)
 	}
 
+	// Verifikasi (QC) routes
+	verifikasiHandler := verifikasi.NewVerifikasiHandler(db)
+
+	verifikasiGroup := api.Group("/verifikasi")
+	verifikasiGroup.Use(middleware.AuthMiddleware(db, cfg))
+	verifikasiGroup.Use(middleware.RequireRole("VERIFIKATOR", "QC_INSPECTOR", "ADMIN", "MANAGER"))
+	verifikasiGroup.Use(middleware.ActivityLogger(db))
+	{
+		// Verifikasi Queue & Detail
+		verifikasiGroup.GET("/queue", verifikasiHandler.GetVerifikasiQueue)
+		verifikasiGroup.GET("/po/:po_id", verifikasiHandler.GetVerifikasiDetail)
+
+		// Verifikasi Workflow Actions
+		verifikasiGroup.POST("/po/:po_id/start", verifikasiHandler.StartVerifikasi)
+		verifikasiGroup.PATCH("/labels/:id/result", verifikasiHandler.UpdateLabelResult)
+		verifikasiGroup.POST("/po/:po_id/finalize", verifikasiHandler.FinalizeVerifikasi)
+	}
+
+	// Khazanah Akhir routes (penghitungan akhir, pengemasan, pengiriman)
+	khazkhirHandler := khazkhir.NewKhazkhirHandler(db)
+
+	khazkhirGroup := api.Group("/khazkhir")
+	khazkhirGroup.Use(middleware.AuthMiddleware(db, cfg))
+	khazkhirGroup.Use(middleware.RequireRole("STAFF_KHAZKHIR", "ADMIN", "MANAGER"))
+	khazkhirGroup.Use(middleware.ActivityLogger(db))
+	{
+		// Khazkhir Queue & Detail
+		khazkhirGroup.GET("/queue", khazkhirHandler.GetKhazkhirQueue)
+		khazkhirGroup.GET("/po/:po_id", khazkhirHandler.GetKhazkhirDetail)
+
+		// Khazkhir Workflow Actions
+		khazkhirGroup.POST("/po/:po_id/start", khazkhirHandler.StartKhazkhir)
+		khazkhirGroup.PATCH("/po/:po_id/count", khazkhirHandler.SubmitFinalCount)
+		khazkhirGroup.POST("/po/:po_id/pack", khazkhirHandler.PackHCS)
+		khazkhirGroup.POST("/po/:po_id/dispatch", khazkhirHandler.Dispatch)
+	}
+
 		// Khazwal Monitoring routes (Supervisor only) - Sprint 5
 		khazwalMonitoring := api.Group("/khazwal")
 		khazwalMonitoring.Use(middleware.AuthMiddleware(db, cfg))
@@ -271,6 +573,8 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 	// Cetak routes (Sprint 5)
 	cetakService := services.NewCetakService(db)
 	cetakHandler := handlers.NewCetakHandler(cetakService)
+	printJobService := services.NewPrintJobService(db)
+	printJobHandler := handlers.NewPrintJobHandler(printJobService)
 
 	cetak := api.Group("/cetak")
 	cetak.Use(middleware.AuthMiddleware(db, cfg))
@@ -279,6 +583,16 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 	{
 		cetak.GET("/queue", cetakHandler.GetQueue)
 		cetak.GET("/queue/:id", cetakHandler.GetDetail)
+
+		// Print Job Workflow - start, pause/resume, progress, finalize
+		cetak.POST("/po/:id/start", printJobHandler.Start)
+		cetak.GET("/po/:id/job", printJobHandler.GetJobByPO)
+		cetak.GET("/jobs/active", printJobHandler.GetActiveJobs)
+		cetak.GET("/jobs/:id", printJobHandler.GetJob)
+		cetak.POST("/jobs/:id/pause", printJobHandler.Pause)
+		cetak.POST("/jobs/:id/resume", printJobHandler.Resume)
+		cetak.POST("/jobs/:id/progress", printJobHandler.LogProgress)
+		cetak.POST("/jobs/:id/finalize", printJobHandler.Finalize)
 	}
 
 		// Example routes (protected) - Commented out for Sprint 1
@@ -305,4 +619,6 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 	// r.NoRoute(func(c *gin.Context) {
 	// 	c.File("./frontend/dist/index.html")
 	// })
+
+	return workers
 }
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"context"
+	"sirine-go/backend/services"
+)
+
+// BackgroundWorkers menyimpan background worker (outbox SAP, cleanup, scheduler SLA, delivery notifikasi, import job)
+// dari service yang dibuat saat setup routes agar lifecycle-nya dikendalikan oleh main
+type BackgroundWorkers struct {
+	starters         []func(ctx context.Context)
+	importJobService *services.ImportJobService
+}
+
+// add mendaftarkan fungsi start worker yang berhenti saat context dibatalkan
+func (w *BackgroundWorkers) add(start func(ctx context.Context)) {
+	w.starters = append(w.starters, start)
+}
+
+// Start menjalankan semua background worker sampai ctx dibatalkan
+func (w *BackgroundWorkers) Start(ctx context.Context) {
+	for _, start := range w.starters {
+		start(ctx)
+	}
+}
+
+// Wait menunggu import job yang sedang berjalan berhenti di batas chunk setelah ctx dibatalkan
+func (w *BackgroundWorkers) Wait() {
+	if w.importJobService != nil {
+		w.importJobService.Wait()
+	}
+}
@@ -0,0 +1,524 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png" // Register PNG decoder
+	"io"
+	"io/fs"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sirine-go/backend/models"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nfnt/resize"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk evidence attachment
+var (
+	ErrAttachmentNotFound        = errors.New("lampiran tidak ditemukan")
+	ErrAttachmentTooLarge        = errors.New("ukuran file melebihi batas maksimal")
+	ErrAttachmentUnsupportedType = errors.New("format file tidak didukung, gunakan JPG atau PNG")
+	ErrAttachmentInvalidImage    = errors.New("file bukan gambar yang valid")
+	ErrAttachmentInvalidOwner    = errors.New("jenis pemilik lampiran tidak sesuai")
+	ErrAttachmentAlreadyLinked   = errors.New("lampiran sudah terhubung ke data lain")
+	ErrAttachmentTooMany         = errors.New("jumlah lampiran melebihi batas")
+	ErrAttachmentLocked          = errors.New("lampiran yang sudah terhubung tidak dapat dihapus")
+	ErrAttachmentForbidden       = errors.New("lampiran hanya dapat dihapus oleh pengunggah")
+)
+
+const (
+	attachmentThumbnailSize = 320
+	attachmentMaxPixels     = 40_000_000
+	attachmentCleanupBatch  = 200
+)
+
+// attachmentExtensions memetakan content type hasil sniffing ke extension file yang disimpan
+var attachmentExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// attachmentMaxPerOwner merupakan batas jumlah lampiran per jenis pemilik
+var attachmentMaxPerOwner = map[models.AttachmentOwnerType]int{
+	models.AttachmentOwnerMaterialPrep: 10,
+	models.AttachmentOwnerCuttingWaste: 1,
+}
+
+// AttachmentService merupakan service untuk evidence attachment (foto material dan foto waste)
+// yang mencakup upload dengan content sniffing, thumbnail, linking ke entity pemilik, dan retention
+type AttachmentService struct {
+	db          *gorm.DB
+	baseDir     string
+	baseURL     string
+	maxFileSize int64
+}
+
+// NewAttachmentService membuat instance baru dari AttachmentService
+// dengan folder penyimpanan dan prefix URL publik untuk folder tersebut
+func NewAttachmentService(db *gorm.DB, baseDir, baseURL string, maxFileSize int64) *AttachmentService {
+	os.MkdirAll(baseDir, 0755)
+
+	return &AttachmentService{
+		db:          db,
+		baseDir:     baseDir,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		maxFileSize: maxFileSize,
+	}
+}
+
+// AttachmentCleanupResult merupakan ringkasan hasil retention job
+type AttachmentCleanupResult struct {
+	DeletedAttachments int `json:"deleted_attachments"`
+	DeletedFiles       int `json:"deleted_files"`
+}
+
+// Upload menyimpan file bukti yang belum terhubung ke entity pemilik,
+// format ditentukan dari isi file (bukan extension) dan thumbnail JPEG dibuat di server
+func (s *AttachmentService) Upload(ownerType models.AttachmentOwnerType, fileHeader *multipart.FileHeader, userID uint64) (*models.Attachment, error) {
+	if !models.IsValidAttachmentOwnerType(ownerType) {
+		return nil, ErrAttachmentInvalidOwner
+	}
+	if fileHeader.Size > s.maxFileSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Baca maksimal batas + 1 byte untuk mendeteksi file yang melebihi batas
+	data, err := io.ReadAll(io.LimitReader(file, s.maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxFileSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	contentType, ext, err := sniffAttachmentType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cek dimensi sebelum decode penuh untuk menolak gambar yang terlalu besar di memory
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+		return nil, ErrAttachmentInvalidImage
+	}
+	if cfg.Width*cfg.Height > attachmentMaxPixels {
+		return nil, ErrAttachmentTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrAttachmentInvalidImage
+	}
+
+	name, err := randomAttachmentName()
+	if err != nil {
+		return nil, err
+	}
+	dir := path.Join(strings.ToLower(string(ownerType)), time.Now().Format("2006/01"))
+	storagePath := path.Join(dir, name+ext)
+	thumbnailPath := path.Join(dir, name+"_thumb.jpg")
+
+	if err := s.writeFile(storagePath, data); err != nil {
+		return nil, err
+	}
+
+	var thumb bytes.Buffer
+	thumbnail := resize.Thumbnail(attachmentThumbnailSize, attachmentThumbnailSize, img, resize.Lanczos3)
+	if err := jpeg.Encode(&thumb, thumbnail, &jpeg.Options{Quality: 80}); err != nil {
+		s.removeFiles(storagePath)
+		return nil, err
+	}
+	if err := s.writeFile(thumbnailPath, thumb.Bytes()); err != nil {
+		s.removeFiles(storagePath)
+		return nil, err
+	}
+
+	checksum := sha256.Sum256(data)
+	attachment := models.Attachment{
+		OwnerType:     ownerType,
+		FileName:      truncateAttachmentName(filepath.Base(fileHeader.Filename)),
+		ContentType:   contentType,
+		SizeBytes:     int64(len(data)),
+		Width:         cfg.Width,
+		Height:        cfg.Height,
+		Checksum:      hex.EncodeToString(checksum[:]),
+		StoragePath:   storagePath,
+		ThumbnailPath: thumbnailPath,
+		URL:           s.baseURL + "/" + storagePath,
+		ThumbnailURL:  s.baseURL + "/" + thumbnailPath,
+		UploadedBy:    userID,
+	}
+	if err := s.db.Create(&attachment).Error; err != nil {
+		// Rollback: hapus file jika insert database gagal
+		s.removeFiles(storagePath, thumbnailPath)
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// GetAttachment mengambil satu lampiran berdasarkan ID
+func (s *AttachmentService) GetAttachment(id uint64) (*models.Attachment, error) {
+	var attachment models.Attachment
+	if err := s.db.First(&attachment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// ListByOwner mengambil lampiran yang terhubung ke satu entity pemilik
+func (s *AttachmentService) ListByOwner(ownerType models.AttachmentOwnerType, ownerID uint64) ([]models.Attachment, error) {
+	if !models.IsValidAttachmentOwnerType(ownerType) {
+		return nil, ErrAttachmentInvalidOwner
+	}
+
+	var attachments []models.Attachment
+	err := s.db.Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("id ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// DeleteAttachment menghapus lampiran yang belum terhubung ke entity pemilik,
+// hanya pengunggah atau admin yang dapat menghapus
+func (s *AttachmentService) DeleteAttachment(id uint64, userID uint64, isAdmin bool) error {
+	var attachment models.Attachment
+	if err := s.db.First(&attachment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAttachmentNotFound
+		}
+		return err
+	}
+	if attachment.IsLinked() {
+		return ErrAttachmentLocked
+	}
+	if attachment.UploadedBy != userID && !isAdmin {
+		return ErrAttachmentForbidden
+	}
+
+	if err := s.db.Delete(&attachment).Error; err != nil {
+		return err
+	}
+	s.removeFiles(attachment.StoragePath, attachment.ThumbnailPath)
+	return nil
+}
+
+// LinkAttachmentsInTx menghubungkan lampiran ke entity pemilik di dalam transaction milik caller,
+// lampiran yang sebelumnya terhubung ke pemilik yang sama tetapi tidak ada di daftar dilepas
+// dan akan dihapus oleh retention job
+func LinkAttachmentsInTx(tx *gorm.DB, ownerType models.AttachmentOwnerType, ownerID uint64, ids []uint64) ([]models.Attachment, error) {
+	if !models.IsValidAttachmentOwnerType(ownerType) {
+		return nil, ErrAttachmentInvalidOwner
+	}
+
+	unique := make([]uint64, 0, len(ids))
+	seen := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > attachmentMaxPerOwner[ownerType] {
+		return nil, fmt.Errorf("%w: maksimal %d lampiran", ErrAttachmentTooMany, attachmentMaxPerOwner[ownerType])
+	}
+
+	var attachments []models.Attachment
+	if len(unique) > 0 {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", unique).
+			Order("id ASC").
+			Find(&attachments).Error; err != nil {
+			return nil, err
+		}
+		if len(attachments) != len(unique) {
+			return nil, ErrAttachmentNotFound
+		}
+		for _, attachment := range attachments {
+			if attachment.OwnerType != ownerType {
+				return nil, ErrAttachmentInvalidOwner
+			}
+			if attachment.IsLinked() && !attachment.IsLinkedTo(ownerType, ownerID) {
+				return nil, ErrAttachmentAlreadyLinked
+			}
+		}
+	}
+
+	// Lepas lampiran lama yang digantikan
+	release := tx.Model(&models.Attachment{}).
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID)
+	if len(unique) > 0 {
+		release = release.Where("id NOT IN ?", unique)
+	}
+	if err := release.Updates(map[string]interface{}{
+		"owner_id":   nil,
+		"linked_at":  nil,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if len(unique) == 0 {
+		return attachments, nil
+	}
+
+	now := time.Now()
+	if err := tx.Model(&models.Attachment{}).
+		Where("id IN ? AND owner_id IS NULL", unique).
+		Updates(map[string]interface{}{
+			"owner_id":   ownerID,
+			"linked_at":  now,
+			"updated_at": now,
+		}).Error; err != nil {
+		return nil, err
+	}
+	for i := range attachments {
+		if attachments[i].LinkedAt == nil {
+			attachments[i].OwnerID = &ownerID
+			attachments[i].LinkedAt = &now
+		}
+	}
+
+	return attachments, nil
+}
+
+// attachmentURLs mengambil URL publik dari daftar lampiran dengan urutan yang sama
+func attachmentURLs(attachments []models.Attachment) []string {
+	urls := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		urls = append(urls, attachment.URL)
+	}
+	return urls
+}
+
+// StartCleanupJob menjalankan retention job secara periodik di background
+// sampai context dibatalkan
+func (s *AttachmentService) StartCleanupJob(ctx context.Context, interval, retention time.Duration) {
+	if interval <= 0 || retention <= 0 {
+		log.Println("Evidence attachment: retention job tidak dijalankan")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			result, err := s.CleanupOrphans(retention)
+			if err != nil {
+				log.Printf("Evidence attachment: gagal membersihkan lampiran: %v", err)
+			} else if result.DeletedAttachments > 0 || result.DeletedFiles > 0 {
+				log.Printf("Evidence attachment: %d lampiran dan %d file orphan dihapus", result.DeletedAttachments, result.DeletedFiles)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("Evidence attachment retention job berjalan (interval %s, retention %s)", interval, retention)
+}
+
+// CleanupOrphans menghapus lampiran yang tidak terhubung ke entity pemilik lebih lama dari retention,
+// serta file di folder penyimpanan yang tidak tercatat di database (misalnya upload yang terputus)
+func (s *AttachmentService) CleanupOrphans(retention time.Duration) (*AttachmentCleanupResult, error) {
+	result := &AttachmentCleanupResult{}
+	cutoff := time.Now().Add(-retention)
+
+	// 1. Lampiran tanpa pemilik yang melewati masa retention
+	for {
+		var orphans []models.Attachment
+		if err := s.db.Where("owner_id IS NULL AND updated_at < ?", cutoff).
+			Order("id ASC").
+			Limit(attachmentCleanupBatch).
+			Find(&orphans).Error; err != nil {
+			return result, err
+		}
+		if len(orphans) == 0 {
+			break
+		}
+
+		ids := make([]uint64, 0, len(orphans))
+		for _, orphan := range orphans {
+			ids = append(ids, orphan.ID)
+		}
+		// Kondisi owner_id diulang agar lampiran yang baru saja di-link tidak ikut terhapus
+		deleted := s.db.Where("id IN ? AND owner_id IS NULL", ids).Delete(&models.Attachment{})
+		if deleted.Error != nil {
+			return result, deleted.Error
+		}
+		result.DeletedAttachments += int(deleted.RowsAffected)
+
+		var remaining []models.Attachment
+		if err := s.db.Select("id").Where("id IN ?", ids).Find(&remaining).Error; err != nil {
+			return result, err
+		}
+		kept := make(map[uint64]bool, len(remaining))
+		for _, attachment := range remaining {
+			kept[attachment.ID] = true
+		}
+		for _, orphan := range orphans {
+			if !kept[orphan.ID] {
+				result.DeletedFiles += s.removeFiles(orphan.StoragePath, orphan.ThumbnailPath)
+			}
+		}
+
+		if len(orphans) < attachmentCleanupBatch {
+			break
+		}
+	}
+
+	// 2. File yang tidak memiliki record di database
+	strays, err := s.findStrayFiles(cutoff)
+	if err != nil {
+		return result, err
+	}
+	result.DeletedFiles += s.removeFiles(strays...)
+
+	return result, nil
+}
+
+// findStrayFiles mencari file lebih lama dari cutoff yang tidak direferensikan oleh lampiran manapun
+func (s *AttachmentService) findStrayFiles(cutoff time.Time) ([]string, error) {
+	var candidates []string
+	err := filepath.WalkDir(s.baseDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		rel, err := filepath.Rel(s.baseDir, p)
+		if err != nil {
+			return nil
+		}
+		candidates = append(candidates, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var strays []string
+	for start := 0; start < len(candidates); start += attachmentCleanupBatch {
+		end := start + attachmentCleanupBatch
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+		batch := candidates[start:end]
+
+		var known []models.Attachment
+		if err := s.db.Select("storage_path", "thumbnail_path").
+			Where("storage_path IN ? OR thumbnail_path IN ?", batch, batch).
+			Find(&known).Error; err != nil {
+			return nil, err
+		}
+		referenced := make(map[string]bool, len(known)*2)
+		for _, attachment := range known {
+			referenced[attachment.StoragePath] = true
+			referenced[attachment.ThumbnailPath] = true
+		}
+		for _, candidate := range batch {
+			if !referenced[candidate] {
+				strays = append(strays, candidate)
+			}
+		}
+	}
+	sort.Strings(strays)
+	return strays, nil
+}
+
+// writeFile menulis file secara atomic (tulis ke file sementara lalu rename)
+func (s *AttachmentService) writeFile(relPath string, data []byte) error {
+	fullPath := filepath.Join(s.baseDir, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	tmpPath := fullPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, fullPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// removeFiles menghapus file relatif terhadap folder penyimpanan dan mengembalikan jumlah yang terhapus
+func (s *AttachmentService) removeFiles(relPaths ...string) int {
+	removed := 0
+	for _, relPath := range relPaths {
+		if relPath == "" {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.baseDir, filepath.FromSlash(relPath))); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// sniffAttachmentType menentukan content type dari isi file dan extension yang dipakai untuk menyimpan
+func sniffAttachmentType(data []byte) (string, string, error) {
+	head := data
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	contentType := http.DetectContentType(head)
+	ext, ok := attachmentExtensions[contentType]
+	if !ok {
+		return "", "", ErrAttachmentUnsupportedType
+	}
+	return contentType, ext, nil
+}
+
+// randomAttachmentName membuat nama file acak agar URL lampiran tidak bisa ditebak
+func randomAttachmentName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// truncateAttachmentName memotong nama file asli agar muat di kolom file_name
+func truncateAttachmentName(name string) string {
+	if len(name) <= 255 {
+		return name
+	}
+	return name[:255]
+}
@@ -56,6 +56,9 @@ type CetakQueueItem struct {
 	DueDate         time.Time       `json:"due_date"`
 	DaysUntilDue    int             `json:"days_until_due"`
 	IsPastDue       bool            `json:"is_past_due"`
+	WaitingMinutes  int             `json:"waiting_minutes"`
+	SLALevel        models.SLALevel `json:"sla_level"`
+	IsOverdue       bool            `json:"is_overdue"`
 	MaterialReadyAt time.Time       `json:"material_ready_at"`
 	PreparedByID    uint64          `json:"prepared_by_id"`
 	PreparedByName  string          `json:"prepared_by_name"`
@@ -113,8 +116,7 @@ type MaterialPrep struct {
 	KertasBlankoQuantity int       `json:"kertas_blanko_quantity"`
 	KertasBlankoActual   *int      `json:"kertas_blanko_actual"`
 	KertasBlankoVariance int       `json:"kertas_blanko_variance"`
-	TintaRequirements    interface{} `json:"tinta_requirements"`
-	TintaActual          interface{} `json:"tinta_actual"`
+	TintaItems           []TintaVariance `json:"tinta_items"`
 	MaterialPhotos       []string  `json:"material_photos"`
 	Notes                string    `json:"notes"`
 }
@@ -167,20 +169,35 @@ func (s *CetakService) GetCetakQueue(filters CetakQueueFilters) (*CetakQueueResp
 		return nil, err
 	}
 
+	// Hitung status SLA antrian cetak sejak PO berstatus READY_FOR_CETAK
+	thresholds, err := LoadSLAThresholds(s.db)
+	if err != nil {
+		return nil, err
+	}
+	waitingSince, err := QueueWaitingSince(s.db, pos)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
 	// Transform ke CetakQueueItem
 	items := make([]CetakQueueItem, 0, len(pos))
 	for _, po := range pos {
+		sla := thresholds.Status(models.StageCetak, po.Priority, waitingSince[po.ID], now)
 		item := CetakQueueItem{
-			POID:          po.ID,
-			PONumber:      po.PONumber,
-			OBCNumber:     po.OBCNumber,
-			ProductName:   po.ProductName,
-			Priority:      string(po.Priority),
-			PriorityScore: po.PriorityScore,
-			Quantity:      po.QuantityOrdered,
-			DueDate:       po.DueDate,
-			DaysUntilDue:  po.DaysUntilDue(),
-			IsPastDue:     po.IsPastDue(),
+			POID:           po.ID,
+			PONumber:       po.PONumber,
+			OBCNumber:      po.OBCNumber,
+			ProductName:    po.ProductName,
+			Priority:       string(po.Priority),
+			PriorityScore:  po.PriorityScore,
+			Quantity:       po.QuantityOrdered,
+			DueDate:        po.DueDate,
+			DaysUntilDue:   po.DaysUntilDue(),
+			IsPastDue:      po.IsPastDue(),
+			WaitingMinutes: sla.WaitingMinutes,
+			SLALevel:       sla.Level,
+			IsOverdue:      sla.IsOverdue,
 		}
 
 		// Add OBC Master info jika ada
@@ -248,6 +265,7 @@ func (s *CetakService) GetCetakDetail(poID uint64) (*CetakDetail, error) {
 	if err := s.db.
 		Preload("OBCMaster").
 		Preload("KhazwalMaterialPrep.PreparedByUser").
+		Preload("KhazwalMaterialPrep.TintaItems").
 		First(&po, poID).Error; err != nil {
 		return nil, err
 	}
@@ -323,21 +341,8 @@ func (s *CetakService) GetCetakDetail(poID uint64) (*CetakDetail, error) {
 			materialPrep.PreparedByName = prep.PreparedByUser.FullName
 		}
 
-		// Parse tinta requirements
-		if prep.TintaRequirements != nil {
-			var tintaReq interface{}
-			if err := json.Unmarshal(prep.TintaRequirements, &tintaReq); err == nil {
-				materialPrep.TintaRequirements = tintaReq
-			}
-		}
-
-		// Parse tinta actual
-		if prep.TintaActual != nil {
-			var tintaAct interface{}
-			if err := json.Unmarshal(prep.TintaActual, &tintaAct); err == nil {
-				materialPrep.TintaActual = tintaAct
-			}
-		}
+		// Variance kebutuhan terhadap actual per warna tinta
+		materialPrep.TintaItems = BuildTintaVariances(prep.TintaItems)
 
 		// Parse material photos
 		if prep.MaterialPhotos != nil {
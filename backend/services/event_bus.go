@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"sirine-go/backend/models"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DomainEventType merupakan jenis domain event yang dipublish ke event stream
+type DomainEventType string
+
+const (
+	EventPOStatusChanged       DomainEventType = "PO_STATUS_CHANGED"
+	EventNotificationCreated   DomainEventType = "NOTIFICATION_CREATED"
+	EventMaterialPrepFinalized DomainEventType = "MATERIAL_PREP_FINALIZED"
+	EventPrintJobFinalized     DomainEventType = "PRINT_JOB_FINALIZED"
+	EventCountingFinalized     DomainEventType = "COUNTING_FINALIZED"
+	EventCuttingFinalized      DomainEventType = "CUTTING_FINALIZED"
+	EventVerifikasiFinalized   DomainEventType = "VERIFIKASI_FINALIZED"
+	EventKhazkhirFinalized     DomainEventType = "KHAZKHIR_FINALIZED"
+)
+
+// Topic event stream, satu topic queue untuk setiap stage produksi
+const (
+	TopicNotifications = "notifications"
+	TopicMaterialPrep  = "queue.material_prep"
+	TopicCetak         = "queue.cetak"
+	TopicCounting      = "queue.counting"
+	TopicCutting       = "queue.cutting"
+	TopicVerifikasi    = "queue.verifikasi"
+	TopicKhazkhir      = "queue.khazkhir"
+)
+
+// stageTopics memetakan stage PO ke topic queue yang terdampak perpindahan status
+var stageTopics = map[models.POStage]string{
+	models.StageKhazwalMaterialPrep: TopicMaterialPrep,
+	models.StageCetak:               TopicCetak,
+	models.StageKhazwalCounting:     TopicCounting,
+	models.StageKhazwalCutting:      TopicCutting,
+	models.StageVerifikasi:          TopicVerifikasi,
+	models.StageKhazkhir:            TopicKhazkhir,
+}
+
+var allQueueTopics = []string{TopicMaterialPrep, TopicCetak, TopicCounting, TopicCutting, TopicVerifikasi, TopicKhazkhir}
+
+// roleTopics merupakan topic queue yang boleh diterima setiap role,
+// topic notifications selalu diizinkan tetapi hanya berisi notifikasi milik user sendiri
+var roleTopics = map[models.UserRole][]string{
+	models.RoleAdmin:         allQueueTopics,
+	models.RoleManager:       allQueueTopics,
+	models.RolePPIC:          allQueueTopics,
+	models.RoleStaffKhazwal:  {TopicMaterialPrep, TopicCounting, TopicCutting},
+	models.RoleOperatorCetak: {TopicCetak},
+	models.RoleQCInspector:   {TopicVerifikasi},
+	models.RoleVerifikator:   {TopicVerifikasi},
+	models.RoleStaffKhazkhir: {TopicKhazkhir},
+}
+
+// TopicsForRole mengembalikan semua topic yang boleh di-subscribe oleh role
+func TopicsForRole(role models.UserRole) []string {
+	topics := []string{TopicNotifications}
+	return append(topics, roleTopics[role]...)
+}
+
+// TopicForStage mengembalikan topic queue untuk stage PO, string kosong untuk stage tanpa queue
+func TopicForStage(stage models.POStage) string {
+	return stageTopics[stage]
+}
+
+// DomainEvent merupakan event yang dipublish setelah perubahan data di-commit
+type DomainEvent struct {
+	ID     uint64          `json:"id"`
+	Type   DomainEventType `json:"type"`
+	Topics []string        `json:"topics"`
+	// UserID diisi untuk event yang hanya boleh diterima satu user (notifikasi)
+	UserID     *uint64     `json:"-"`
+	Data       interface{} `json:"data"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+// POStatusChangedData merupakan payload event perpindahan status PO
+type POStatusChangedData struct {
+	POID       uint64          `json:"po_id"`
+	PONumber   int64           `json:"po_number"`
+	OBCNumber  string          `json:"obc_number"`
+	FromStage  models.POStage  `json:"from_stage"`
+	FromStatus models.POStatus `json:"from_status"`
+	ToStage    models.POStage  `json:"to_stage"`
+	ToStatus   models.POStatus `json:"to_status"`
+	HandledBy  *uint64         `json:"handled_by,omitempty"`
+}
+
+// FinalizedEventData merupakan payload event finalize proses produksi
+type FinalizedEventData struct {
+	ProductionOrderID uint64  `json:"production_order_id"`
+	AggregateID       uint64  `json:"aggregate_id"`
+	HandledBy         *uint64 `json:"handled_by,omitempty"`
+}
+
+// NotificationEventData merupakan payload event notifikasi baru
+type NotificationEventData struct {
+	ID        uint64                      `json:"id"`
+	Title     string                      `json:"title"`
+	Message   string                      `json:"message"`
+	Type      models.NotificationType     `json:"type"`
+	Category  models.NotificationCategory `json:"category"`
+	POID      *uint64                     `json:"po_id"`
+	Stage     models.POStage              `json:"stage,omitempty"`
+	CreatedAt time.Time                   `json:"created_at"`
+}
+
+// NewFinalizedEvent membuat event finalize untuk topic stage yang diselesaikan
+func NewFinalizedEvent(eventType DomainEventType, stage models.POStage, poID, aggregateID uint64, handledBy *uint64) DomainEvent {
+	return DomainEvent{
+		Type:   eventType,
+		Topics: []string{TopicForStage(stage)},
+		Data: FinalizedEventData{
+			ProductionOrderID: poID,
+			AggregateID:       aggregateID,
+			HandledBy:         handledBy,
+		},
+	}
+}
+
+// EventFilter menentukan apakah event diteruskan ke satu subscriber
+type EventFilter func(event DomainEvent) bool
+
+// NewUserEventFilter membuat filter untuk user berdasarkan role dan topic yang diminta,
+// topic kosong berarti semua topic yang diizinkan untuk role tersebut
+func NewUserEventFilter(userID uint64, role models.UserRole, topics []string) EventFilter {
+	allowed := make(map[string]bool)
+	for _, topic := range TopicsForRole(role) {
+		allowed[topic] = len(topics) == 0
+	}
+	for _, topic := range topics {
+		if _, ok := allowed[topic]; ok {
+			allowed[topic] = true
+		}
+	}
+
+	return func(event DomainEvent) bool {
+		if event.UserID != nil && *event.UserID != userID {
+			return false
+		}
+		for _, topic := range event.Topics {
+			if allowed[topic] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// EventSubscription merupakan satu subscriber event bus,
+// channel ditutup saat unsubscribe atau saat subscriber terlalu lambat membaca event
+type EventSubscription struct {
+	id     uint64
+	events chan DomainEvent
+	filter EventFilter
+	closed bool
+}
+
+// Events mengembalikan channel event untuk subscriber
+func (s *EventSubscription) Events() <-chan DomainEvent {
+	return s.events
+}
+
+// EventBus merupakan in-process pub/sub untuk domain event yang di-fan-out ke event stream
+type EventBus struct {
+	mu          sync.Mutex
+	subscribers map[uint64]*EventSubscription
+	nextSubID   uint64
+	nextEventID atomic.Uint64
+	bufferSize  int
+}
+
+// NewEventBus membuat instance baru dari EventBus dengan buffer per subscriber
+func NewEventBus(bufferSize int) *EventBus {
+	if bufferSize <= 0 {
+		bufferSize = 64
+	}
+	return &EventBus{
+		subscribers: make(map[uint64]*EventSubscription),
+		bufferSize:  bufferSize,
+	}
+}
+
+var defaultEventBus = NewEventBus(64)
+
+// DefaultEventBus mengembalikan event bus yang dipakai oleh semua service
+func DefaultEventBus() *EventBus {
+	return defaultEventBus
+}
+
+// Subscribe mendaftarkan subscriber baru dengan filter event
+func (b *EventBus) Subscribe(filter EventFilter) *EventSubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextSubID++
+	sub := &EventSubscription{
+		id:     b.nextSubID,
+		events: make(chan DomainEvent, b.bufferSize),
+		filter: filter,
+	}
+	b.subscribers[sub.id] = sub
+	return sub
+}
+
+// Unsubscribe menghapus subscriber dan menutup channel event-nya
+func (b *EventBus) Unsubscribe(sub *EventSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closeLocked(sub)
+}
+
+// CloseAll menutup semua subscriber aktif, dipanggil saat server shutdown
+// agar event stream yang masih terbuka segera berakhir
+func (b *EventBus) CloseAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sub := range b.subscribers {
+		b.closeLocked(sub)
+	}
+}
+
+// SubscriberCount mengembalikan jumlah subscriber aktif
+func (b *EventBus) SubscriberCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Publish mengirim event ke semua subscriber yang cocok tanpa blocking,
+// subscriber yang buffer-nya penuh diputus agar client melakukan reconnect dan memuat ulang data
+func (b *EventBus) Publish(events ...DomainEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, event := range events {
+		event.ID = b.nextEventID.Add(1)
+		if event.OccurredAt.IsZero() {
+			event.OccurredAt = time.Now()
+		}
+
+		for _, sub := range b.subscribers {
+			if sub.filter != nil && !sub.filter(event) {
+				continue
+			}
+			select {
+			case sub.events <- event:
+			default:
+				b.closeLocked(sub)
+			}
+		}
+	}
+}
+
+// closeLocked menutup subscriber, mu harus sudah di-lock oleh caller
+func (b *EventBus) closeLocked(sub *EventSubscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(b.subscribers, sub.id)
+	close(sub.events)
+}
+
+// DomainEventBatch mengumpulkan event selama transaction berjalan
+// sehingga event hanya dipublish jika transaction berhasil di-commit
+type DomainEventBatch struct {
+	mu     sync.Mutex
+	bus    *EventBus
+	events []DomainEvent
+}
+
+type domainEventBatchKey struct{}
+
+// WithDomainEvents memasang batch event ke context db, transaction yang dimulai dari db hasilnya
+// mengumpulkan event ke batch, caller memanggil Publish setelah commit berhasil
+func WithDomainEvents(db *gorm.DB) (*gorm.DB, *DomainEventBatch) {
+	batch := &DomainEventBatch{bus: defaultEventBus}
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return db.WithContext(context.WithValue(ctx, domainEventBatchKey{}, batch)), batch
+}
+
+// TransactionWithEvents menjalankan fn dalam transaction dan mempublish event setelah commit
+func TransactionWithEvents(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	eventDB, batch := WithDomainEvents(db)
+	if err := eventDB.Transaction(fn); err != nil {
+		return err
+	}
+	batch.Publish()
+	return nil
+}
+
+// Publish mengirim semua event yang terkumpul ke event bus lalu mengosongkan batch
+func (b *DomainEventBatch) Publish() {
+	b.mu.Lock()
+	events := b.events
+	b.events = nil
+	b.mu.Unlock()
+
+	if len(events) > 0 {
+		b.bus.Publish(events...)
+	}
+}
+
+func (b *DomainEventBatch) add(event DomainEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+}
+
+// RecordDomainEventInTx mencatat event ke batch transaction milik caller,
+// tanpa batch (query di luar transaction) event langsung dipublish
+func RecordDomainEventInTx(tx *gorm.DB, event DomainEvent) {
+	if tx.Statement.Context != nil {
+		if batch, ok := tx.Statement.Context.Value(domainEventBatchKey{}).(*DomainEventBatch); ok {
+			batch.add(event)
+			return
+		}
+	}
+	defaultEventBus.Publish(event)
+}
+
+// RegisterDomainEventCallbacks memasang GORM callback yang mencatat event NOTIFICATION_CREATED
+// untuk setiap notifikasi yang dibuat, termasuk notifikasi yang dibuat di dalam transaction
+func RegisterDomainEventCallbacks(db *gorm.DB) error {
+	return db.Callback().Create().After("gorm:create").
+		Register("domain_events:notification_created", func(tx *gorm.DB) {
+			if tx.Error != nil || tx.Statement.Schema == nil || tx.Statement.Schema.Table != "notifications" {
+				return
+			}
+
+			var notifications []models.Notification
+			switch dest := tx.Statement.Dest.(type) {
+			case *models.Notification:
+				notifications = []models.Notification{*dest}
+			case []models.Notification:
+				notifications = dest
+			case *[]models.Notification:
+				notifications = *dest
+			}
+
+			for _, notification := range notifications {
+				userID := notification.UserID
+				RecordDomainEventInTx(tx, DomainEvent{
+					Type:   EventNotificationCreated,
+					Topics: []string{TopicNotifications},
+					UserID: &userID,
+					Data: NotificationEventData{
+						ID:        notification.ID,
+						Title:     notification.Title,
+						Message:   notification.Message,
+						Type:      notification.Type,
+						Category:  notification.Category,
+						POID:      notification.POID,
+						Stage:     notification.Stage,
+						CreatedAt: notification.CreatedAt,
+					},
+				})
+			}
+		})
+}
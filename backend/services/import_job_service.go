@@ -0,0 +1,438 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sirine-go/backend/models"
+	"sync"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// importJobChunkSize merupakan jumlah row per transaction saat import di background
+	importJobChunkSize = 200
+	// maxConcurrentImportJobs membatasi jumlah import job yang diproses bersamaan
+	maxConcurrentImportJobs = 2
+	// importJobHeartbeatInterval merupakan interval instance pemroses memperbarui heartbeat_at job
+	importJobHeartbeatInterval = 30 * time.Second
+	// importJobStaleAfter merupakan batas heartbeat terakhir sebelum job QUEUED/RUNNING dianggap terputus
+	importJobStaleAfter = 3 * importJobHeartbeatInterval
+)
+
+// Error untuk background import job
+var (
+	ErrImportJobNotFound    = errors.New("import job tidak ditemukan")
+	ErrImportJobNotFinished = errors.New("import job belum selesai diproses")
+	ErrImportJobFailed      = errors.New("import job gagal diproses dan tidak memiliki hasil")
+	ErrImportJobUnavailable = errors.New("server sedang shutdown, import job tidak dapat dibuat")
+)
+
+// ImportJobService merupakan service untuk menjalankan import OBC di background
+// yang memproses file per chunk dan mencatat progress ke tabel import_jobs
+type ImportJobService struct {
+	db         *gorm.DB
+	obcService *OBCImportService
+	slots      chan struct{}
+
+	mu      sync.Mutex
+	ctx     context.Context
+	running sync.WaitGroup
+}
+
+// NewImportJobService membuat instance baru dari ImportJobService
+func NewImportJobService(db *gorm.DB, obcService *OBCImportService) *ImportJobService {
+	return &ImportJobService{
+		db:         db,
+		obcService: obcService,
+		slots:      make(chan struct{}, maxConcurrentImportJobs),
+		ctx:        context.Background(),
+	}
+}
+
+// ImportJobFilters merupakan struct untuk filter dan pagination list import job
+type ImportJobFilters struct {
+	Status  string `form:"status"`
+	Page    int    `form:"page"`
+	PerPage int    `form:"per_page"`
+}
+
+// ImportJobResponse merupakan struct untuk detail import job beserta persentase progress
+type ImportJobResponse struct {
+	models.ImportJob
+	ProgressPercentage float64 `json:"progress_percentage"`
+}
+
+// ImportJobListResponse merupakan struct untuk paginated list import job
+type ImportJobListResponse struct {
+	Items      []ImportJobResponse `json:"items"`
+	Total      int                 `json:"total"`
+	Page       int                 `json:"page"`
+	PerPage    int                 `json:"per_page"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+// StartWorker menjalankan pengecekan berkala job yang instance pemrosesnya berhenti,
+// import job yang sedang berjalan dihentikan di batas chunk saat ctx dibatalkan
+func (s *ImportJobService) StartWorker(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(importJobHeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			if count, err := s.FailStaleJobs(); err != nil {
+				log.Printf("Import job: gagal menandai job yang terputus: %v", err)
+			} else if count > 0 {
+				log.Printf("Import job: %d job terputus ditandai FAILED", count)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("Import job worker berjalan (heartbeat %s)", importJobHeartbeatInterval)
+}
+
+// Wait menunggu semua import job yang sedang diproses instance ini berhenti, dipakai saat graceful shutdown
+func (s *ImportJobService) Wait() {
+	s.running.Wait()
+}
+
+// StartImportJob membuat import job baru dengan status QUEUED dan memprosesnya di background
+func (s *ImportJobService) StartImportJob(content []byte, fileName string, autoGeneratePO bool, userID uint64) (*ImportJobResponse, error) {
+	s.mu.Lock()
+	ctx := s.ctx
+	s.mu.Unlock()
+	if ctx.Err() != nil {
+		return nil, ErrImportJobUnavailable
+	}
+
+	now := time.Now()
+	job := models.ImportJob{
+		Status:         models.ImportJobQueued,
+		FileName:       fileName,
+		FileSize:       int64(len(content)),
+		AutoGeneratePO: autoGeneratePO,
+		FailedRows:     datatypes.JSON("[]"),
+		HeartbeatAt:    &now,
+		CreatedBy:      userID,
+	}
+	if err := s.db.Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.process(ctx, job.ID, content, fileName, autoGeneratePO, userID)
+	}()
+
+	return newImportJobResponse(&job), nil
+}
+
+// FailStaleJobs menandai job QUEUED/RUNNING yang heartbeat-nya tidak diperbarui lagi sebagai FAILED,
+// yaitu job dari instance yang berhenti atau restart, job yang masih diproses instance lain tidak tersentuh
+func (s *ImportJobService) FailStaleJobs() (int64, error) {
+	now := time.Now()
+	cutoff := now.Add(-importJobStaleAfter)
+	result := s.db.Model(&models.ImportJob{}).
+		Where("status IN ?", []models.ImportJobStatus{models.ImportJobQueued, models.ImportJobRunning}).
+		Where("heartbeat_at < ? OR (heartbeat_at IS NULL AND updated_at < ?)", cutoff, cutoff).
+		Updates(map[string]interface{}{
+			"status":            models.ImportJobFailed,
+			"error_message":     "import terhenti karena server yang memproses berhenti, silakan upload ulang",
+			"partially_applied": gorm.Expr("success_count > 0"),
+			"finished_at":       now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// GetJob mengambil detail import job berdasarkan ID
+func (s *ImportJobService) GetJob(id uint64) (*ImportJobResponse, error) {
+	var job models.ImportJob
+	if err := s.db.First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImportJobNotFound
+		}
+		return nil, err
+	}
+	return newImportJobResponse(&job), nil
+}
+
+// ListJobs mengambil list import job dengan filter status dan pagination, terbaru di atas
+func (s *ImportJobService) ListJobs(filters ImportJobFilters) (*ImportJobListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.ImportJob{})
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var jobs []models.ImportJob
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Omit("failed_rows", "result").
+		Order("created_at DESC, id DESC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]ImportJobResponse, 0, len(jobs))
+	for i := range jobs {
+		items = append(items, *newImportJobResponse(&jobs[i]))
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &ImportJobListResponse{
+		Items:      items,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetJobResult mengambil ImportResult final dari job yang sudah selesai
+func (s *ImportJobService) GetJobResult(id uint64) (*ImportResult, error) {
+	job, err := s.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if !job.IsFinished() {
+		return nil, ErrImportJobNotFinished
+	}
+	if len(job.Result) == 0 {
+		return nil, ErrImportJobFailed
+	}
+
+	var result ImportResult
+	if err := json.Unmarshal(job.Result, &result); err != nil {
+		return nil, fmt.Errorf("gagal membaca hasil import job: %w", err)
+	}
+	return &result, nil
+}
+
+// process menjaga heartbeat job selama menunggu slot dan diproses, lalu menjalankan import
+func (s *ImportJobService) process(ctx context.Context, jobID uint64, content []byte, fileName string, autoGeneratePO bool, userID uint64) {
+	done := make(chan struct{})
+	defer close(done)
+	go s.keepAlive(jobID, done)
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		s.finishJob(jobID, &ImportResult{FailedRows: make([]FailedRow, 0)}, time.Now(), errImportJobShutdown)
+		return
+	}
+	defer func() { <-s.slots }()
+
+	s.run(ctx, jobID, content, fileName, autoGeneratePO, userID)
+}
+
+// errImportJobShutdown merupakan alasan job dihentikan karena server shutdown
+var errImportJobShutdown = errors.New("import dihentikan karena server shutdown, silakan upload ulang")
+
+// keepAlive memperbarui heartbeat_at job secara berkala sampai done ditutup
+func (s *ImportJobService) keepAlive(jobID uint64, done <-chan struct{}) {
+	ticker := time.NewTicker(importJobHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			if err := s.db.Model(&models.ImportJob{}).Where("id = ?", jobID).
+				Update("heartbeat_at", now).Error; err != nil {
+				log.Printf("Import job %d: gagal memperbarui heartbeat: %v", jobID, err)
+			}
+		}
+	}
+}
+
+// run memproses import job per chunk, setiap chunk di-commit dalam transaction terpisah
+// dan progress disimpan setelah setiap chunk sehingga bisa di-poll oleh client.
+// Pembatalan ctx dicek di antara chunk sehingga chunk yang sedang berjalan tetap selesai di-commit
+func (s *ImportJobService) run(ctx context.Context, jobID uint64, content []byte, fileName string, autoGeneratePO bool, userID uint64) {
+	startTime := time.Now()
+	result := &ImportResult{FailedRows: make([]FailedRow, 0)}
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.finishJob(jobID, result, startTime, fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	if err := s.db.Model(&models.ImportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":       models.ImportJobRunning,
+		"started_at":   startTime,
+		"heartbeat_at": startTime,
+	}).Error; err != nil {
+		log.Printf("Import job %d: gagal update status RUNNING: %v", jobID, err)
+	}
+
+	rows, format, err := s.obcService.readImportRows(bytes.NewReader(content), fileName)
+	if err != nil {
+		s.finishJob(jobID, result, startTime, err)
+		return
+	}
+	result.SourceFormat = format
+	result.TotalRows = len(rows)
+
+	// Import batch dibuat sekali untuk seluruh chunk agar job bisa di-rollback sebagai satu kesatuan
+	batch := models.OBCImportBatch{
+		Source:         models.ImportBatchSourceJob,
+		FileName:       fileName,
+		FileHash:       hashImportFile(content),
+		SourceFormat:   string(format),
+		AutoGeneratePO: autoGeneratePO,
+		TotalRows:      len(rows),
+		Status:         models.ImportBatchApplied,
+		ImportJobID:    &jobID,
+		CreatedBy:      userID,
+	}
+	if err := s.db.Create(&batch).Error; err != nil {
+		s.finishJob(jobID, result, startTime, fmt.Errorf("gagal membuat import batch: %w", err))
+		return
+	}
+	result.BatchID = batch.ID
+
+	if err := s.db.Model(&models.ImportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"source_format": string(format),
+		"total_rows":    len(rows),
+		"batch_id":      batch.ID,
+	}).Error; err != nil {
+		log.Printf("Import job %d: gagal update total rows: %v", jobID, err)
+	}
+
+	processed := 0
+	for start := 0; start < len(rows); start += importJobChunkSize {
+		if ctx.Err() != nil {
+			s.finishJob(jobID, result, startTime, errImportJobShutdown)
+			return
+		}
+		end := int(math.Min(float64(start+importJobChunkSize), float64(len(rows))))
+
+		var chunkResult *ImportResult
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var applyErr error
+			chunkResult, applyErr = s.obcService.applyRowsInTx(tx, batch.ID, rows[start:end], autoGeneratePO, false)
+			if applyErr != nil {
+				return applyErr
+			}
+			return updateImportBatchSummary(tx, batch.ID, chunkResult)
+		})
+		if err != nil {
+			s.finishJob(jobID, result, startTime, fmt.Errorf("transaction error: %w", err))
+			return
+		}
+
+		processed = end
+		result.SuccessCount += chunkResult.SuccessCount
+		result.FailedCount += chunkResult.FailedCount
+		result.POsGenerated += chunkResult.POsGenerated
+		result.FailedRows = append(result.FailedRows, chunkResult.FailedRows...)
+
+		if err := s.saveProgress(jobID, processed, result); err != nil {
+			log.Printf("Import job %d: gagal menyimpan progress: %v", jobID, err)
+		}
+	}
+
+	s.finishJob(jobID, result, startTime, nil)
+}
+
+// saveProgress menyimpan jumlah row yang sudah diproses beserta ringkasan sementara
+func (s *ImportJobService) saveProgress(jobID uint64, processed int, result *ImportResult) error {
+	failedRows, err := json.Marshal(result.FailedRows)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Model(&models.ImportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"processed_rows": processed,
+		"success_count":  result.SuccessCount,
+		"failed_count":   result.FailedCount,
+		"pos_generated":  result.POsGenerated,
+		"failed_rows":    datatypes.JSON(failedRows),
+		"heartbeat_at":   time.Now(),
+	}).Error
+}
+
+// finishJob menandai job COMPLETED atau FAILED dan menyimpan ImportResult final,
+// job yang gagal setelah sebagian chunk ter-commit ditandai partially_applied dan menunjuk import batch-nya
+// agar perubahan yang sudah tersimpan bisa di-rollback
+func (s *ImportJobService) finishJob(jobID uint64, result *ImportResult, startTime time.Time, jobErr error) {
+	finishedAt := time.Now()
+	result.DurationMs = finishedAt.Sub(startTime).Milliseconds()
+
+	failedRows, _ := json.Marshal(result.FailedRows)
+	updates := map[string]interface{}{
+		"success_count": result.SuccessCount,
+		"failed_count":  result.FailedCount,
+		"pos_generated": result.POsGenerated,
+		"failed_rows":   datatypes.JSON(failedRows),
+		"duration_ms":   result.DurationMs,
+		"finished_at":   finishedAt,
+	}
+
+	if jobErr != nil {
+		updates["status"] = models.ImportJobFailed
+		updates["error_message"] = jobErr.Error()
+		if result.SuccessCount > 0 {
+			updates["partially_applied"] = true
+			updates["error_message"] = fmt.Sprintf("%s (%d row sudah tersimpan di import batch #%d, rollback batch tersebut untuk membatalkan)",
+				jobErr.Error(), result.SuccessCount, result.BatchID)
+		}
+	} else {
+		resultJSON, err := json.Marshal(result)
+		if err != nil {
+			updates["status"] = models.ImportJobFailed
+			updates["error_message"] = fmt.Sprintf("gagal menyimpan hasil import: %v", err)
+		} else {
+			updates["status"] = models.ImportJobCompleted
+			updates["processed_rows"] = result.TotalRows
+			updates["result"] = datatypes.JSON(resultJSON)
+		}
+	}
+
+	if err := s.db.Model(&models.ImportJob{}).Where("id = ?", jobID).Updates(updates).Error; err != nil {
+		log.Printf("Import job %d: gagal menyimpan status akhir: %v", jobID, err)
+	}
+}
+
+// newImportJobResponse menyusun response import job beserta persentase progress
+func newImportJobResponse(job *models.ImportJob) *ImportJobResponse {
+	return &ImportJobResponse{
+		ImportJob:          *job,
+		ProgressPercentage: math.Round(job.ProgressPercentage()*100) / 100,
+	}
+}
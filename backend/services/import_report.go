@@ -0,0 +1,207 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ImportReportFormat merupakan enum untuk format file report hasil import
+type ImportReportFormat string
+
+const (
+	ImportReportXLSX ImportReportFormat = "xlsx"
+	ImportReportCSV  ImportReportFormat = "csv"
+)
+
+// ImportReportErrorColumn merupakan nama kolom error yang ditambahkan ke file report,
+// kolom ini diabaikan saat file report di-upload ulang
+const ImportReportErrorColumn = "Import Error"
+
+// ErrInvalidImportReportFormat merupakan error untuk format report yang tidak didukung
+var ErrInvalidImportReportFormat = errors.New("format report tidak valid, gunakan xlsx atau csv")
+
+// ImportReportOptions merupakan opsi pembuatan file report hasil import
+type ImportReportOptions struct {
+	Format     ImportReportFormat
+	FailedOnly bool
+}
+
+// ImportReport merupakan file report hasil import yang siap diunduh
+type ImportReport struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// ParseImportReportOptions mem-parsing format dan scope report,
+// scope "all" menyertakan seluruh row sedangkan default hanya row yang gagal
+func ParseImportReportOptions(format, scope string) (ImportReportOptions, error) {
+	opts := ImportReportOptions{FailedOnly: true}
+
+	switch ImportReportFormat(strings.ToLower(strings.TrimSpace(format))) {
+	case ImportReportXLSX:
+		opts.Format = ImportReportXLSX
+	case ImportReportCSV:
+		opts.Format = ImportReportCSV
+	default:
+		return opts, ErrInvalidImportReportFormat
+	}
+
+	switch strings.ToLower(strings.TrimSpace(scope)) {
+	case "", "failed":
+	case "all":
+		opts.FailedOnly = false
+	default:
+		return opts, fmt.Errorf("report_scope tidak valid: %s, gunakan failed atau all", scope)
+	}
+	return opts, nil
+}
+
+// buildImportReport menyusun ulang rows file import asli dengan kolom error tambahan,
+// rowErrors di-key dengan nomor row di file (header adalah row 1)
+func buildImportReport(records [][]string, rowErrors map[int]string, sourceName string, opts ImportReportOptions) (*ImportReport, error) {
+	if len(records) == 0 {
+		return nil, errors.New("file import tidak memiliki header")
+	}
+	records = stripImportReportColumn(records)
+
+	header := append(append([]string{}, records[0]...), ImportReportErrorColumn)
+	width := len(records[0])
+
+	report := [][]string{header}
+	for i := 1; i < len(records); i++ {
+		message, failed := rowErrors[i+1]
+		if opts.FailedOnly && !failed {
+			continue
+		}
+
+		// Row disamakan lebarnya dengan header agar kolom error selalu di posisi yang sama
+		row := make([]string, width, width+1)
+		copy(row, records[i])
+		report = append(report, append(row, message))
+	}
+
+	baseName := strings.TrimSuffix(filepath.Base(sourceName), filepath.Ext(sourceName))
+	if baseName == "" || baseName == "." {
+		baseName = "import"
+	}
+	suffix := "import_report"
+	if opts.FailedOnly {
+		suffix = "failed_rows"
+	}
+	fileName := fmt.Sprintf("%s_%s.%s", baseName, suffix, opts.Format)
+
+	if opts.Format == ImportReportXLSX {
+		content, err := writeImportReportXLSX(report)
+		if err != nil {
+			return nil, err
+		}
+		return &ImportReport{
+			FileName:    fileName,
+			ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			Content:     content,
+		}, nil
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(report); err != nil {
+		return nil, fmt.Errorf("gagal menulis report csv: %w", err)
+	}
+	return &ImportReport{
+		FileName:    fileName,
+		ContentType: "text/csv",
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// writeImportReportXLSX menulis report ke sheet Excel dengan kolom error yang ditandai
+func writeImportReportXLSX(rows [][]string) ([]byte, error) {
+	xlsxFile := excelize.NewFile()
+	defer xlsxFile.Close()
+
+	sheet := xlsxFile.GetSheetName(0)
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(row))
+		for j, value := range row {
+			values[j] = value
+		}
+		if err := xlsxFile.SetSheetRow(sheet, cell, &values); err != nil {
+			return nil, fmt.Errorf("gagal menulis report excel: %w", err)
+		}
+	}
+
+	// Header dibuat bold dan kolom error diberi warna agar mudah ditemukan
+	errorColumn, err := excelize.ColumnNumberToName(len(rows[0]))
+	if err != nil {
+		return nil, err
+	}
+	headerStyle, err := xlsxFile.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+	errorStyle, err := xlsxFile.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "9C0006"},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FFC7CE"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := xlsxFile.SetCellStyle(sheet, "A1", errorColumn+"1", headerStyle); err != nil {
+		return nil, err
+	}
+	if len(rows) > 1 {
+		if err := xlsxFile.SetCellStyle(sheet, errorColumn+"2", fmt.Sprintf("%s%d", errorColumn, len(rows)), errorStyle); err != nil {
+			return nil, err
+		}
+	}
+	if err := xlsxFile.SetColWidth(sheet, errorColumn, errorColumn, 60); err != nil {
+		return nil, err
+	}
+
+	buf, err := xlsxFile.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("gagal menulis report excel: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// stripImportReportColumn membuang kolom error dari file report yang di-upload ulang
+func stripImportReportColumn(records [][]string) [][]string {
+	if len(records) == 0 {
+		return records
+	}
+
+	column := -1
+	for i, header := range records[0] {
+		if normalizeHeader(header) == normalizeHeader(ImportReportErrorColumn) {
+			column = i
+			break
+		}
+	}
+	if column < 0 {
+		return records
+	}
+
+	stripped := make([][]string, len(records))
+	for i, record := range records {
+		if column >= len(record) {
+			stripped[i] = record
+			continue
+		}
+		row := make([]string, 0, len(record)-1)
+		row = append(row, record[:column]...)
+		stripped[i] = append(row, record[column+1:]...)
+	}
+	return stripped
+}
@@ -0,0 +1,671 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sirine-go/backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk stock ledger kertas blanko
+var (
+	ErrKertasStockNotFound       = errors.New("stok kertas tidak ditemukan")
+	ErrKertasPaperTypeExists     = errors.New("jenis kertas sudah terdaftar")
+	ErrKertasPaperTypeNotFound   = errors.New("jenis kertas PO belum terdaftar di stock ledger")
+	ErrKertasStockInsufficient   = errors.New("stok kertas blanko tidak mencukupi")
+	ErrKertasReservationNotFound = errors.New("reservasi kertas untuk PO tidak ditemukan")
+	ErrKertasReturnExceeded      = errors.New("jumlah retur melebihi kertas yang dikonsumsi PO")
+	ErrInvalidKertasQuantity     = errors.New("jumlah kertas harus lebih dari 0")
+	ErrInvalidKertasThreshold    = errors.New("threshold low stock tidak boleh negatif")
+)
+
+// materialLowStockRoles merupakan role yang menerima notifikasi low stock material (kertas dan tinta)
+var materialLowStockRoles = []models.UserRole{models.RoleAdmin, models.RoleManager, models.RolePPIC}
+
+// KertasStockService merupakan service untuk stock ledger kertas blanko
+// yang mencakup saldo per jenis kertas, penerimaan, retur, dan riwayat mutasi
+type KertasStockService struct {
+	db *gorm.DB
+}
+
+// NewKertasStockService membuat instance baru dari KertasStockService
+func NewKertasStockService(db *gorm.DB) *KertasStockService {
+	return &KertasStockService{
+		db: db,
+	}
+}
+
+// KertasStockFilters merupakan struct untuk filter dan pagination list stok kertas
+type KertasStockFilters struct {
+	Search   string `form:"search"`
+	LowStock bool   `form:"low_stock"`
+	Page     int    `form:"page"`
+	PerPage  int    `form:"per_page"`
+}
+
+// KertasStockLevel merupakan saldo stok kertas beserta stok tersedia dan flag low stock
+type KertasStockLevel struct {
+	models.KertasStock
+	Available  int64 `json:"available"`
+	IsLowStock bool  `json:"is_low_stock"`
+}
+
+// KertasStockListResponse merupakan struct untuk paginated list stok kertas
+type KertasStockListResponse struct {
+	Items      []KertasStockLevel `json:"items"`
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	PerPage    int                `json:"per_page"`
+	TotalPages int                `json:"total_pages"`
+}
+
+// KertasMovementFilters merupakan struct untuk filter dan pagination riwayat mutasi stok
+type KertasMovementFilters struct {
+	Type              string `form:"type"`
+	ProductionOrderID uint64 `form:"production_order_id"`
+	Page              int    `form:"page"`
+	PerPage           int    `form:"per_page"`
+}
+
+// KertasMovementListResponse merupakan struct untuk paginated riwayat mutasi stok
+type KertasMovementListResponse struct {
+	Items      []models.KertasStockMovement `json:"items"`
+	Total      int                          `json:"total"`
+	Page       int                          `json:"page"`
+	PerPage    int                          `json:"per_page"`
+	TotalPages int                          `json:"total_pages"`
+}
+
+// CreateKertasStockRequest merupakan request untuk mendaftarkan jenis kertas baru
+type CreateKertasStockRequest struct {
+	PaperType         string `json:"paper_type" binding:"required,max=50"`
+	Description       string `json:"description" binding:"max=255"`
+	Unit              string `json:"unit" binding:"max=20"`
+	LowStockThreshold int64  `json:"low_stock_threshold"`
+}
+
+// UpdateKertasStockRequest merupakan request untuk update data jenis kertas
+type UpdateKertasStockRequest struct {
+	Description       *string `json:"description" binding:"omitempty,max=255"`
+	Unit              *string `json:"unit" binding:"omitempty,max=20"`
+	LowStockThreshold *int64  `json:"low_stock_threshold"`
+}
+
+// ReceiveKertasRequest merupakan request untuk mencatat penerimaan kertas ke gudang
+type ReceiveKertasRequest struct {
+	Quantity  int64  `json:"quantity" binding:"required"`
+	Reference string `json:"reference" binding:"max=100"`
+	Notes     string `json:"notes"`
+}
+
+// ReturnKertasRequest merupakan request untuk mengembalikan sisa kertas yang tidak terpakai dari PO
+type ReturnKertasRequest struct {
+	ProductionOrderID uint64 `json:"production_order_id" binding:"required"`
+	Quantity          int64  `json:"quantity" binding:"required"`
+	Notes             string `json:"notes"`
+}
+
+// ListStocks mengambil list saldo stok kertas dengan filter search, low stock, dan pagination
+func (s *KertasStockService) ListStocks(filters KertasStockFilters) (*KertasStockListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.KertasStock{})
+	if filters.Search != "" {
+		searchPattern := "%" + filters.Search + "%"
+		query = query.Where("paper_type LIKE ? OR description LIKE ?", searchPattern, searchPattern)
+	}
+	if filters.LowStock {
+		query = query.Where("low_stock_threshold > 0 AND on_hand - reserved < low_stock_threshold")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var stocks []models.KertasStock
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Order("paper_type ASC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]KertasStockLevel, 0, len(stocks))
+	for i := range stocks {
+		items = append(items, newKertasStockLevel(&stocks[i]))
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &KertasStockListResponse{
+		Items:      items,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetStock mengambil saldo stok satu jenis kertas berdasarkan ID
+func (s *KertasStockService) GetStock(id uint64) (*KertasStockLevel, error) {
+	var stock models.KertasStock
+	if err := s.db.First(&stock, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrKertasStockNotFound
+		}
+		return nil, err
+	}
+	level := newKertasStockLevel(&stock)
+	return &level, nil
+}
+
+// CreateStock mendaftarkan jenis kertas baru dengan saldo awal 0,
+// saldo awal dicatat lewat penerimaan agar tercatat di ledger
+func (s *KertasStockService) CreateStock(req CreateKertasStockRequest) (*KertasStockLevel, error) {
+	if req.LowStockThreshold < 0 {
+		return nil, ErrInvalidKertasThreshold
+	}
+
+	paperType := strings.TrimSpace(req.PaperType)
+	var existing int64
+	if err := s.db.Unscoped().Model(&models.KertasStock{}).Where("paper_type = ?", paperType).Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrKertasPaperTypeExists
+	}
+
+	unit := strings.TrimSpace(req.Unit)
+	if unit == "" {
+		unit = "lembar"
+	}
+
+	stock := models.KertasStock{
+		PaperType:         paperType,
+		Description:       req.Description,
+		Unit:              unit,
+		LowStockThreshold: req.LowStockThreshold,
+	}
+	if err := s.db.Create(&stock).Error; err != nil {
+		return nil, err
+	}
+	return s.GetStock(stock.ID)
+}
+
+// UpdateStock mengupdate deskripsi, satuan, dan threshold low stock,
+// perubahan threshold langsung dievaluasi untuk notifikasi low stock
+func (s *KertasStockService) UpdateStock(id uint64, req UpdateKertasStockRequest) (*KertasStockLevel, error) {
+	if req.LowStockThreshold != nil && *req.LowStockThreshold < 0 {
+		return nil, ErrInvalidKertasThreshold
+	}
+
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		stock, err := lockKertasStock(tx, "id = ?", id)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if req.Description != nil {
+			updates["description"] = *req.Description
+		}
+		if req.Unit != nil && strings.TrimSpace(*req.Unit) != "" {
+			updates["unit"] = strings.TrimSpace(*req.Unit)
+		}
+		if req.LowStockThreshold != nil {
+			updates["low_stock_threshold"] = *req.LowStockThreshold
+			stock.LowStockThreshold = *req.LowStockThreshold
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := tx.Model(stock).Updates(updates).Error; err != nil {
+			return err
+		}
+		return checkKertasLowStockInTx(tx, stock)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStock(id)
+}
+
+// ReceiveStock mencatat penerimaan kertas ke gudang dan menambah saldo on hand
+func (s *KertasStockService) ReceiveStock(id uint64, req ReceiveKertasRequest, userID uint64) (*KertasStockLevel, error) {
+	if req.Quantity <= 0 {
+		return nil, ErrInvalidKertasQuantity
+	}
+
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		stock, err := lockKertasStock(tx, "id = ?", id)
+		if err != nil {
+			return err
+		}
+		return applyKertasMovementInTx(tx, stock, kertasMovement{
+			Type:        models.KertasMovementReceipt,
+			Quantity:    req.Quantity,
+			OnHandDelta: req.Quantity,
+			Reference:   strings.TrimSpace(req.Reference),
+			Notes:       req.Notes,
+			CreatedBy:   userID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStock(id)
+}
+
+// ReturnStock mengembalikan sisa kertas yang tidak terpakai dari PO ke saldo on hand,
+// jumlah retur dibatasi oleh kertas yang sudah dikonsumsi PO tersebut
+func (s *KertasStockService) ReturnStock(req ReturnKertasRequest, userID uint64) (*KertasStockLevel, error) {
+	if req.Quantity <= 0 {
+		return nil, ErrInvalidKertasQuantity
+	}
+
+	var stockID uint64
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		var reservation models.KertasReservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("production_order_id = ?", req.ProductionOrderID).
+			First(&reservation).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrKertasReservationNotFound
+			}
+			return err
+		}
+		if req.Quantity > reservation.ReturnableQuantity() {
+			return fmt.Errorf("%w (maksimal %d)", ErrKertasReturnExceeded, reservation.ReturnableQuantity())
+		}
+
+		stock, err := lockKertasStock(tx, "id = ?", reservation.KertasStockID)
+		if err != nil {
+			return err
+		}
+		stockID = stock.ID
+
+		if err := tx.Model(&reservation).
+			Update("returned_quantity", gorm.Expr("returned_quantity + ?", req.Quantity)).Error; err != nil {
+			return err
+		}
+
+		poID := reservation.ProductionOrderID
+		return applyKertasMovementInTx(tx, stock, kertasMovement{
+			Type:              models.KertasMovementReturn,
+			Quantity:          req.Quantity,
+			OnHandDelta:       req.Quantity,
+			ProductionOrderID: &poID,
+			MaterialPrepID:    reservation.MaterialPrepID,
+			Notes:             req.Notes,
+			CreatedBy:         userID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStock(stockID)
+}
+
+// ListMovements mengambil riwayat mutasi stok satu jenis kertas, terbaru di atas
+func (s *KertasStockService) ListMovements(stockID uint64, filters KertasMovementFilters) (*KertasMovementListResponse, error) {
+	if _, err := s.GetStock(stockID); err != nil {
+		return nil, err
+	}
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.KertasStockMovement{}).Where("kertas_stock_id = ?", stockID)
+	if filters.Type != "" {
+		query = query.Where("type = ?", strings.ToUpper(filters.Type))
+	}
+	if filters.ProductionOrderID > 0 {
+		query = query.Where("production_order_id = ?", filters.ProductionOrderID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var movements []models.KertasStockMovement
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Preload("CreatedByUser", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "nip", "full_name")
+		}).
+		Order("created_at DESC, id DESC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&movements).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &KertasMovementListResponse{
+		Items:      movements,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// reserveKertasInTx membuat reservasi kertas blanko untuk PO yang memulai material prep
+// di dalam transaction milik caller, jenis kertas yang belum terdaftar di ledger ditolak
+func reserveKertasInTx(tx *gorm.DB, prep *models.KhazwalMaterialPreparation, userID uint64) error {
+	quantity := int64(prep.KertasBlankoQuantity)
+	if quantity <= 0 {
+		return nil
+	}
+
+	// Reservasi hanya dibuat sekali per PO
+	var existing int64
+	if err := tx.Model(&models.KertasReservation{}).
+		Where("production_order_id = ?", prep.ProductionOrderID).
+		Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	paperType, err := paperTypeForPO(tx, prep.ProductionOrderID)
+	if err != nil || paperType == "" {
+		return err
+	}
+	stock, err := lockPaperTypeStock(tx, paperType)
+	if err != nil {
+		return err
+	}
+
+	if stock.Available() < quantity {
+		return fmt.Errorf("%w: %s tersedia %d, dibutuhkan %d", ErrKertasStockInsufficient, stock.PaperType, stock.Available(), quantity)
+	}
+
+	prepID := prep.ID
+	reservation := models.KertasReservation{
+		KertasStockID:     stock.ID,
+		ProductionOrderID: prep.ProductionOrderID,
+		MaterialPrepID:    &prepID,
+		Quantity:          quantity,
+		Status:            models.KertasReservationReserved,
+	}
+	if err := tx.Create(&reservation).Error; err != nil {
+		return err
+	}
+
+	poID := prep.ProductionOrderID
+	return applyKertasMovementInTx(tx, stock, kertasMovement{
+		Type:              models.KertasMovementReserve,
+		Quantity:          quantity,
+		ReservedDelta:     quantity,
+		ProductionOrderID: &poID,
+		MaterialPrepID:    &prepID,
+		CreatedBy:         userID,
+	})
+}
+
+// consumeKertasInTx memposting pemakaian kertas blanko actual saat material prep selesai
+// di dalam transaction milik caller, reservasi dilepas dan saldo on hand dikurangi sejumlah actual
+func consumeKertasInTx(tx *gorm.DB, prep *models.KhazwalMaterialPreparation, userID uint64) error {
+	if prep.KertasBlankoActual == nil {
+		return nil
+	}
+	actual := int64(*prep.KertasBlankoActual)
+
+	var reservation models.KertasReservation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("production_order_id = ? AND status = ?", prep.ProductionOrderID, models.KertasReservationReserved).
+		First(&reservation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return consumeUnreservedKertasInTx(tx, prep, actual, userID)
+	}
+	if err != nil {
+		return err
+	}
+
+	stock, err := lockKertasStock(tx, "id = ?", reservation.KertasStockID)
+	if err != nil {
+		return err
+	}
+
+	// Pemakaian actual boleh melebihi reservasi selama tidak memakai stok yang direservasi PO lain
+	if actual > stock.Available()+reservation.Quantity {
+		return fmt.Errorf("%w: %s tersedia %d, dipakai %d", ErrKertasStockInsufficient, stock.PaperType, stock.Available()+reservation.Quantity, actual)
+	}
+
+	now := time.Now()
+	if err := tx.Model(&reservation).Updates(map[string]interface{}{
+		"status":            models.KertasReservationConsumed,
+		"consumed_quantity": actual,
+		"consumed_at":       now,
+	}).Error; err != nil {
+		return err
+	}
+
+	poID := prep.ProductionOrderID
+	prepID := prep.ID
+	return applyKertasMovementInTx(tx, stock, kertasMovement{
+		Type:              models.KertasMovementConsume,
+		Quantity:          actual,
+		OnHandDelta:       -actual,
+		ReservedDelta:     -reservation.Quantity,
+		ProductionOrderID: &poID,
+		MaterialPrepID:    &prepID,
+		Notes:             fmt.Sprintf("Reservasi %d, actual %d", reservation.Quantity, actual),
+		CreatedBy:         userID,
+	})
+}
+
+// consumeUnreservedKertasInTx memposting pemakaian kertas blanko untuk material prep tanpa reservasi
+// (dimulai sebelum jenis kertasnya terdaftar) langsung ke stok jenis kertas PO, reservasi dicatat
+// sebagai CONSUMED agar sisa kertas tetap bisa diretur
+func consumeUnreservedKertasInTx(tx *gorm.DB, prep *models.KhazwalMaterialPreparation, actual int64, userID uint64) error {
+	if actual <= 0 {
+		return nil
+	}
+
+	var consumed int64
+	if err := tx.Model(&models.KertasReservation{}).
+		Where("production_order_id = ?", prep.ProductionOrderID).
+		Count(&consumed).Error; err != nil {
+		return err
+	}
+	if consumed > 0 {
+		return nil
+	}
+
+	paperType, err := paperTypeForPO(tx, prep.ProductionOrderID)
+	if err != nil || paperType == "" {
+		return err
+	}
+	stock, err := lockPaperTypeStock(tx, paperType)
+	if err != nil {
+		return err
+	}
+
+	if actual > stock.Available() {
+		return fmt.Errorf("%w: %s tersedia %d, dipakai %d", ErrKertasStockInsufficient, stock.PaperType, stock.Available(), actual)
+	}
+
+	now := time.Now()
+	prepID := prep.ID
+	reservation := models.KertasReservation{
+		KertasStockID:     stock.ID,
+		ProductionOrderID: prep.ProductionOrderID,
+		MaterialPrepID:    &prepID,
+		ConsumedQuantity:  actual,
+		Status:            models.KertasReservationConsumed,
+		ConsumedAt:        &now,
+	}
+	if err := tx.Create(&reservation).Error; err != nil {
+		return err
+	}
+
+	poID := prep.ProductionOrderID
+	return applyKertasMovementInTx(tx, stock, kertasMovement{
+		Type:              models.KertasMovementConsume,
+		Quantity:          actual,
+		OnHandDelta:       -actual,
+		ProductionOrderID: &poID,
+		MaterialPrepID:    &prepID,
+		Notes:             fmt.Sprintf("Tanpa reservasi, actual %d", actual),
+		CreatedBy:         userID,
+	})
+}
+
+// kertasMovement merupakan parameter mutasi stok yang diposting ke ledger
+type kertasMovement struct {
+	Type              models.KertasMovementType
+	Quantity          int64
+	OnHandDelta       int64
+	ReservedDelta     int64
+	ProductionOrderID *uint64
+	MaterialPrepID    *uint64
+	Reference         string
+	Notes             string
+	CreatedBy         uint64
+}
+
+// applyKertasMovementInTx mengupdate saldo stok yang sudah di-lock, mencatat mutasi ke ledger,
+// dan mengevaluasi notifikasi low stock setelah saldo berubah
+func applyKertasMovementInTx(tx *gorm.DB, stock *models.KertasStock, movement kertasMovement) error {
+	stock.OnHand += movement.OnHandDelta
+	stock.Reserved += movement.ReservedDelta
+
+	if err := tx.Model(stock).Updates(map[string]interface{}{
+		"on_hand":  stock.OnHand,
+		"reserved": stock.Reserved,
+	}).Error; err != nil {
+		return err
+	}
+
+	record := models.KertasStockMovement{
+		KertasStockID:     stock.ID,
+		Type:              movement.Type,
+		Quantity:          movement.Quantity,
+		OnHandAfter:       stock.OnHand,
+		ReservedAfter:     stock.Reserved,
+		ProductionOrderID: movement.ProductionOrderID,
+		MaterialPrepID:    movement.MaterialPrepID,
+		Reference:         movement.Reference,
+		Notes:             movement.Notes,
+		CreatedBy:         movement.CreatedBy,
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return err
+	}
+
+	return checkKertasLowStockInTx(tx, stock)
+}
+
+// checkKertasLowStockInTx mengirim notifikasi low stock sekali saat stok tersedia turun di bawah threshold,
+// flag notifikasi di-reset ketika stok kembali di atas threshold
+func checkKertasLowStockInTx(tx *gorm.DB, stock *models.KertasStock) error {
+	if !stock.IsLowStock() {
+		if stock.LowStockNotifiedAt == nil {
+			return nil
+		}
+		stock.LowStockNotifiedAt = nil
+		return tx.Model(stock).Update("low_stock_notified_at", nil).Error
+	}
+	if stock.LowStockNotifiedAt != nil {
+		return nil
+	}
+
+	title := "Stok Kertas Rendah - " + stock.PaperType
+	message := fmt.Sprintf("Stok tersedia kertas %s tinggal %d %s (threshold %d). Segera lakukan pengadaan.", stock.PaperType, stock.Available(), stock.Unit, stock.LowStockThreshold)
+	if err := notifyMaterialLowStockInTx(tx, title, message); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	stock.LowStockNotifiedAt = &now
+	return tx.Model(stock).Update("low_stock_notified_at", now).Error
+}
+
+// notifyMaterialLowStockInTx membuat notifikasi WARNING low stock untuk semua user aktif dengan role pengelola material
+func notifyMaterialLowStockInTx(tx *gorm.DB, title, message string) error {
+	_, err := NotifyAudiencesInTx(tx, NotificationContent{
+		Title:    title,
+		Message:  message,
+		Type:     models.NotificationWarning,
+		Category: models.NotificationCategoryAlert,
+	}, NotificationAudience{Roles: materialLowStockRoles})
+	return err
+}
+
+// paperTypeForPO mengambil jenis kertas PO dari kode material di OBC Master
+func paperTypeForPO(tx *gorm.DB, poID uint64) (string, error) {
+	var po models.ProductionOrder
+	if err := tx.Select("id", "obc_master_id").
+		Preload("OBCMaster", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped().Select("id", "material")
+		}).
+		First(&po, poID).Error; err != nil {
+		return "", err
+	}
+	if po.OBCMaster == nil {
+		return "", nil
+	}
+	return strings.TrimSpace(po.OBCMaster.Material), nil
+}
+
+// lockKertasStock mengambil stok kertas dengan row lock di dalam transaction milik caller
+func lockKertasStock(tx *gorm.DB, condition string, value interface{}) (*models.KertasStock, error) {
+	var stock models.KertasStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(condition, value).
+		First(&stock).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrKertasStockNotFound
+		}
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// lockPaperTypeStock mengambil stok jenis kertas PO dengan row lock,
+// jenis kertas yang belum terdaftar di ledger dikembalikan sebagai ErrKertasPaperTypeNotFound
+func lockPaperTypeStock(tx *gorm.DB, paperType string) (*models.KertasStock, error) {
+	stock, err := lockKertasStock(tx, "paper_type = ?", paperType)
+	if errors.Is(err, ErrKertasStockNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrKertasPaperTypeNotFound, paperType)
+	}
+	return stock, err
+}
+
+// newKertasStockLevel menyusun saldo stok beserta stok tersedia dan flag low stock
+func newKertasStockLevel(stock *models.KertasStock) KertasStockLevel {
+	return KertasStockLevel{
+		KertasStock: *stock,
+		Available:   stock.Available(),
+		IsLowStock:  stock.IsLowStock(),
+	}
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"sirine-go/backend/models"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -22,13 +24,18 @@ func (e *PlatNumberMissingError) Error() string {
 // KhazwalService merupakan service untuk Khazwal Material Preparation operations
 // yang mencakup queue management, material prep workflow, dan tracking
 type KhazwalService struct {
-	db *gorm.DB
+	db                    *gorm.DB
+	transitions           *POTransitionService
+	allowUnregisteredPlat bool
 }
 
-// NewKhazwalService membuat instance baru dari KhazwalService
-func NewKhazwalService(db *gorm.DB) *KhazwalService {
+// NewKhazwalService membuat instance baru dari KhazwalService,
+// allowUnregisteredPlat mengaktifkan fallback legacy untuk kode plat yang belum terdaftar di registry
+func NewKhazwalService(db *gorm.DB, allowUnregisteredPlat bool) *KhazwalService {
 	return &KhazwalService{
-		db: db,
+		db:                    db,
+		transitions:           NewPOTransitionService(db),
+		allowUnregisteredPlat: allowUnregisteredPlat,
 	}
 }
 
@@ -95,6 +102,12 @@ func (s *KhazwalService) GetMaterialPrepQueue(filters QueueFilters) (*QueueRespo
 		return nil, err
 	}
 
+	// Hitung status SLA untuk PO yang masih menunggu material preparation
+	sla, err := s.materialPrepQueueSLA(pos)
+	if err != nil {
+		return nil, err
+	}
+
 	// Calculate total pages
 	totalPages := int(total) / filters.PerPage
 	if int(total)%filters.PerPage > 0 {
@@ -103,6 +116,7 @@ func (s *KhazwalService) GetMaterialPrepQueue(filters QueueFilters) (*QueueRespo
 
 	return &QueueResponse{
 		Items:      pos,
+		SLA:        sla,
 		Total:      int(total),
 		Page:       filters.Page,
 		PerPage:    filters.PerPage,
@@ -110,6 +124,37 @@ func (s *KhazwalService) GetMaterialPrepQueue(filters QueueFilters) (*QueueRespo
 	}, nil
 }
 
+// materialPrepQueueSLA menghitung status SLA PO berstatus WAITING_MATERIAL_PREP,
+// PO yang sudah mulai dipersiapkan tidak lagi dihitung sebagai antrian
+func (s *KhazwalService) materialPrepQueueSLA(pos []models.ProductionOrder) (map[uint64]QueueSLAStatus, error) {
+	var waiting []models.ProductionOrder
+	for _, po := range pos {
+		if po.CurrentStatus == models.StatusWaitingMaterialPrep {
+			waiting = append(waiting, po)
+		}
+	}
+
+	result := make(map[uint64]QueueSLAStatus, len(waiting))
+	if len(waiting) == 0 {
+		return result, nil
+	}
+
+	thresholds, err := LoadSLAThresholds(s.db)
+	if err != nil {
+		return nil, err
+	}
+	waitingSince, err := QueueWaitingSince(s.db, waiting)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, po := range waiting {
+		result[po.ID] = thresholds.Status(models.StageKhazwalMaterialPrep, po.Priority, waitingSince[po.ID], now)
+	}
+	return result, nil
+}
+
 // GetMaterialPrepDetail mengambil detail PO beserta material prep info
 // dengan preload relations untuk full information display
 func (s *KhazwalService) GetMaterialPrepDetail(id uint64) (*models.ProductionOrder, error) {
@@ -119,6 +164,8 @@ func (s *KhazwalService) GetMaterialPrepDetail(id uint64) (*models.ProductionOrd
 	if err := s.db.
 		Preload("OBCMaster").
 		Preload("KhazwalMaterialPrep.PreparedByUser").
+		Preload("KhazwalMaterialPrep.TintaItems").
+		Preload("KhazwalMaterialPrep.Attachments").
 		Preload("StageTracking", func(db *gorm.DB) *gorm.DB {
 			return db.Order("created_at DESC")
 		}).
@@ -133,37 +180,35 @@ func (s *KhazwalService) GetMaterialPrepDetail(id uint64) (*models.ProductionOrd
 // dengan business logic validation dan transaction untuk data consistency
 func (s *KhazwalService) StartMaterialPrep(poID uint64, userID uint64) (*models.ProductionOrder, error) {
 	// Start transaction untuk ensure data consistency
-	tx := s.db.Begin()
+	eventDB, events := WithDomainEvents(s.db)
+	tx := eventDB.Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
 		}
 	}()
 
-	// Get PO dengan lock untuk prevent concurrent updates
-	var po models.ProductionOrder
-	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-		Preload("KhazwalMaterialPrep").
-		First(&po, poID).Error; err != nil {
+	// Pindahkan PO ke MATERIAL_PREP_IN_PROGRESS via state machine
+	// (termasuk row lock, validasi status, dan POStageTracking record)
+	if _, err := s.transitions.TransitionInTx(tx, POTransitionRequest{
+		POID:      poID,
+		To:        models.StatusMaterialPrepInProgress,
+		HandledBy: &userID,
+		Notes:     "Material preparation dimulai",
+	}); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	// Validasi: PO harus dalam status WAITING_MATERIAL_PREP
-	if po.CurrentStatus != models.StatusWaitingMaterialPrep {
+	// Get PO beserta material prep setelah transition
+	var po models.ProductionOrder
+	if err := tx.Preload("KhazwalMaterialPrep").
+		First(&po, poID).Error; err != nil {
 		tx.Rollback()
-		return nil, gorm.ErrInvalidData
+		return nil, err
 	}
 
-	// Update PO status ke IN_PROGRESS
 	now := time.Now()
-	if err := tx.Model(&po).Updates(map[string]interface{}{
-		"current_status": models.StatusMaterialPrepInProgress,
-		"updated_at":     now,
-	}).Error; err != nil {
-		tx.Rollback()
-		return nil, err
-	}
 
 	// Update KhazwalMaterialPrep status dan timestamps
 	if po.KhazwalMaterialPrep != nil {
@@ -178,31 +223,26 @@ func (s *KhazwalService) StartMaterialPrep(poID uint64, userID uint64) (*models.
 			tx.Rollback()
 			return nil, err
 		}
-	}
 
-	// Create POStageTracking record untuk audit trail
-	tracking := models.POStageTracking{
-		ProductionOrderID: poID,
-		Stage:             models.StageKhazwalMaterialPrep,
-		Status:            models.StatusMaterialPrepInProgress,
-		StartedAt:         &now,
-		HandledBy:         &userID,
-		Notes:             "Material preparation dimulai",
-	}
-	if err := tx.Create(&tracking).Error; err != nil {
-		tx.Rollback()
-		return nil, err
+		// Reservasi kertas blanko di stock ledger sesuai kebutuhan PO
+		if err := reserveKertasInTx(tx, po.KhazwalMaterialPrep, userID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	events.Publish()
 
 	// Reload PO dengan updated data dan relations
 	if err := s.db.
 		Preload("OBCMaster").
 		Preload("KhazwalMaterialPrep.PreparedByUser").
+		Preload("KhazwalMaterialPrep.TintaItems").
+		Preload("KhazwalMaterialPrep.Attachments").
 		Preload("StageTracking").
 		First(&po, poID).Error; err != nil {
 		return nil, err
@@ -215,7 +255,8 @@ func (s *KhazwalService) StartMaterialPrep(poID uint64, userID uint64) (*models.
 // untuk memastikan plat yang diambil sesuai dengan yang ditentukan di SAP
 func (s *KhazwalService) ConfirmPlatRetrieval(prepID uint64, scannedCode string, userID uint64) error {
 	// Start transaction untuk ensure data consistency
-	tx := s.db.Begin()
+	eventDB, events := WithDomainEvents(s.db)
+	tx := eventDB.Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -257,6 +298,17 @@ func (s *KhazwalService) ConfirmPlatRetrieval(prepID uint64, scannedCode string,
 	
 	isMatch := (scannedCode == expectedPlat)
 
+	// Catat pengambilan di registry plat, ditolak jika plat dipakai PO lain, maintenance, atau retired
+	var plat *models.Plat
+	if isMatch {
+		var err error
+		plat, err = checkoutPlatInTx(tx, scannedCode, prep.ProductionOrderID, &prep.ID, userID, s.allowUnregisteredPlat)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	// Update plat retrieved timestamp dan match status
 	now := time.Now()
 	updates := map[string]interface{}{
@@ -275,6 +327,9 @@ func (s *KhazwalService) ConfirmPlatRetrieval(prepID uint64, scannedCode string,
 	notes := "Plat di-scan: " + scannedCode
 	if isMatch {
 		notes += " (match dengan expected)"
+		if plat != nil && plat.StorageLocation != "" {
+			notes += ", diambil dari " + plat.StorageLocation
+		}
 	} else {
 		notes += " (tidak match! Expected: " + expectedPlat + ")"
 	}
@@ -295,6 +350,7 @@ func (s *KhazwalService) ConfirmPlatRetrieval(prepID uint64, scannedCode string,
 	if err := tx.Commit().Error; err != nil {
 		return err
 	}
+	events.Publish()
 
 	// Jika tidak match, return error
 	if !isMatch {
@@ -308,7 +364,8 @@ func (s *KhazwalService) ConfirmPlatRetrieval(prepID uint64, scannedCode string,
 // dimana variance > 5% memerlukan alasan untuk accountability
 func (s *KhazwalService) UpdateKertasBlanko(prepID uint64, actualQty int, varianceReason string, userID uint64) error {
 	// Start transaction untuk ensure data consistency
-	tx := s.db.Begin()
+	eventDB, events := WithDomainEvents(s.db)
+	tx := eventDB.Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -342,7 +399,7 @@ func (s *KhazwalService) UpdateKertasBlanko(prepID uint64, actualQty int, varian
 	if absVariancePercentage < 0 {
 		absVariancePercentage = -absVariancePercentage
 	}
-	if absVariancePercentage > 5.0 {
+	if absVariancePercentage > models.VarianceThresholdPercentage {
 		if varianceReason == "" {
 			tx.Rollback()
 			return gorm.ErrInvalidData
@@ -387,16 +444,23 @@ func (s *KhazwalService) UpdateKertasBlanko(prepID uint64, actualQty int, varian
 	if err := tx.Commit().Error; err != nil {
 		return err
 	}
+	events.Publish()
 
 	return nil
 }
 
-// UpdateTinta mengupdate informasi tinta yang digunakan dengan checklist validation
-// untuk memastikan semua warna tinta tersedia sesuai requirements, serta melakukan
-// pengecekan low stock warning untuk inventory management
-func (s *KhazwalService) UpdateTinta(prepID uint64, tintaActual interface{}, userID uint64) error {
+// UpdateTinta mengupdate jumlah actual tinta per warna yang sudah divalidasi (warna, satuan, dan jumlah),
+// memposting pemakaian tinta ke inventory, dan menyimpan low stock warning dari saldo stok ke line item
+func (s *KhazwalService) UpdateTinta(prepID uint64, tintaActual []TintaActualInput, userID uint64) (*UpdateTintaResult, error) {
+	// Validasi input sebelum membuka transaction
+	usage, err := ValidateTintaActual(tintaActual)
+	if err != nil {
+		return nil, err
+	}
+
 	// Start transaction untuk ensure data consistency
-	tx := s.db.Begin()
+	eventDB, events := WithDomainEvents(s.db)
+	tx := eventDB.Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -409,44 +473,38 @@ func (s *KhazwalService) UpdateTinta(prepID uint64, tintaActual interface{}, use
 		Preload("ProductionOrder").
 		First(&prep, prepID).Error; err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
 	// Validasi: prep harus dalam status IN_PROGRESS
 	if !prep.IsInProgress() {
 		tx.Rollback()
-		return gorm.ErrInvalidData
+		return nil, gorm.ErrInvalidData
 	}
 
-	// Convert tintaActual ke JSON
-	tintaJSON, err := json.Marshal(tintaActual)
+	// Posting pemakaian tinta ke inventory, low stock dihitung dari saldo stok di server
+	lowStock, err := postTintaUsageInTx(tx, &prep, usage, userID)
 	if err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
-	// Check for low stock warnings (TODO: integrate dengan SAP inventory API)
-	lowStockFlags := s.checkLowStockTinta(tintaActual)
-	lowStockJSON, err := json.Marshal(lowStockFlags)
+	// Simpan actual per warna ke line item tinta
+	items, err := syncTintaItemsInTx(tx, prep.ID, usage, lowStock)
 	if err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
-	// Update tinta actual dan low stock flags
 	now := time.Now()
-	if err := tx.Model(&prep).Updates(map[string]interface{}{
-		"tinta_actual":          tintaJSON,
-		"tinta_low_stock_flags": lowStockJSON,
-		"updated_at":            now,
-	}).Error; err != nil {
+	if err := tx.Model(&prep).Update("updated_at", now).Error; err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
 	// Create POStageTracking record untuk audit trail
 	notes := "Tinta berhasil diupdate"
-	if len(lowStockFlags) > 0 {
+	if len(lowStock) > 0 {
 		notes += " (dengan low stock warning)"
 	}
 	
@@ -459,57 +517,19 @@ func (s *KhazwalService) UpdateTinta(prepID uint64, tintaActual interface{}, use
 	}
 	if err := tx.Create(&tracking).Error; err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		return err
+		return nil, err
 	}
+	events.Publish()
 
-	return nil
-}
-
-// checkLowStockTinta memeriksa low stock warnings untuk tinta
-// dengan threshold < 10kg untuk early warning inventory management
-// TODO: Integrate dengan SAP inventory API untuk real-time stock check
-func (s *KhazwalService) checkLowStockTinta(tintaActual interface{}) map[string]interface{} {
-	lowStockFlags := make(map[string]interface{})
-	
-	// Parse tintaActual untuk extract color information
-	tintaMap, ok := tintaActual.(map[string]interface{})
-	if !ok {
-		return lowStockFlags
-	}
-	
-	// Check each color untuk low stock (placeholder logic)
-	// Real implementation harus query SAP inventory API
-	colors, ok := tintaMap["colors"].([]interface{})
-	if !ok {
-		return lowStockFlags
-	}
-	
-	lowStockColors := make([]string, 0)
-	for _, colorData := range colors {
-		colorInfo, ok := colorData.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		
-		// Placeholder: check stock_after field (jika ada)
-		stockAfter, ok := colorInfo["stock_after"].(float64)
-		if ok && stockAfter < 10.0 {
-			colorName, _ := colorInfo["color"].(string)
-			lowStockColors = append(lowStockColors, colorName)
-		}
-	}
-	
-	if len(lowStockColors) > 0 {
-		lowStockFlags["low_stock_colors"] = lowStockColors
-		lowStockFlags["warning"] = "Beberapa warna tinta memiliki stock rendah"
-	}
-	
-	return lowStockFlags
+	return &UpdateTintaResult{
+		TintaItems: items,
+		LowStock:   lowStock,
+	}, nil
 }
 
 // FinalizeResult merupakan struct untuk response finalize material preparation
@@ -526,9 +546,10 @@ type FinalizeResult struct {
 
 // FinalizeMaterialPrep menyelesaikan proses material preparation
 // dengan validation untuk semua steps complete dan notification ke Unit Cetak
-func (s *KhazwalService) FinalizeMaterialPrep(prepID uint64, photos []string, notes string, userID uint64) (*FinalizeResult, error) {
+func (s *KhazwalService) FinalizeMaterialPrep(prepID uint64, attachmentIDs []uint64, notes string, userID uint64) (*FinalizeResult, error) {
 	// Start transaction untuk ensure data consistency
-	tx := s.db.Begin()
+	eventDB, events := WithDomainEvents(s.db)
+	tx := eventDB.Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -540,6 +561,7 @@ func (s *KhazwalService) FinalizeMaterialPrep(prepID uint64, photos []string, no
 	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 		Preload("ProductionOrder").
 		Preload("PreparedByUser").
+		Preload("TintaItems").
 		First(&prep, prepID).Error; err != nil {
 		tx.Rollback()
 		return nil, err
@@ -560,7 +582,7 @@ func (s *KhazwalService) FinalizeMaterialPrep(prepID uint64, photos []string, no
 		tx.Rollback()
 		return nil, gorm.ErrInvalidData
 	}
-	if prep.TintaActual == nil {
+	if !prep.HasTintaActual() {
 		tx.Rollback()
 		return nil, gorm.ErrInvalidData
 	}
@@ -573,11 +595,16 @@ func (s *KhazwalService) FinalizeMaterialPrep(prepID uint64, photos []string, no
 		durationMinutes = int(duration.Minutes())
 	}
 
-	// 5. Convert photos ke JSON
+	// 5. Hubungkan foto bukti yang sudah di-upload ke material prep,
+	// URL foto tetap disimpan di material_photos untuk tampilan unit cetak
+	attachments, err := LinkAttachmentsInTx(tx, models.AttachmentOwnerMaterialPrep, prep.ID, attachmentIDs)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 	var photosJSON []byte
-	var err error
-	if len(photos) > 0 {
-		photosJSON, err = json.Marshal(photos)
+	if len(attachments) > 0 {
+		photosJSON, err = json.Marshal(attachmentURLs(attachments))
 		if err != nil {
 			tx.Rollback()
 			return nil, err
@@ -603,42 +630,33 @@ func (s *KhazwalService) FinalizeMaterialPrep(prepID uint64, photos []string, no
 		return nil, err
 	}
 
-	// 7. Update Production Order stage & status ke CETAK
-	if err := tx.Model(&models.ProductionOrder{}).
-		Where("id = ?", prep.ProductionOrderID).
-		Updates(map[string]interface{}{
-			"current_stage":  models.StageCetak,
-			"current_status": models.StatusReadyForCetak,
-			"updated_at":     now,
-		}).Error; err != nil {
+	// 7. Posting pemakaian kertas blanko actual ke stock ledger dan lepas reservasinya
+	if err := consumeKertasInTx(tx, &prep, userID); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	// 8. Create POStageTracking record untuk audit trail
-	tracking := models.POStageTracking{
-		ProductionOrderID: prep.ProductionOrderID,
-		Stage:             models.StageKhazwalMaterialPrep,
-		Status:            models.StatusReadyForCetak,
-		StartedAt:         prep.StartedAt,
-		CompletedAt:       &now,
-		HandledBy:         &userID,
-		Notes:             "Material preparation selesai. Durasi: " + string(rune(durationMinutes)) + " menit",
-	}
-	if err := tx.Create(&tracking).Error; err != nil {
+	// 8. Tulis posting goods issue material ke SAP outbox, dikirim worker setelah commit
+	if err := enqueueMaterialPrepPostingInTx(tx, &prep); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	// 9. Create notifications ke semua OPERATOR_CETAK users
-	var cetakOperators []models.User
-	if err := tx.Where("role = ? AND status = ?", models.RoleOperatorCetak, models.StatusActive).
-		Find(&cetakOperators).Error; err != nil {
+	// 9. Pindahkan Production Order ke READY_FOR_CETAK via state machine
+	// yang sekaligus mencatat POStageTracking untuk penyelesaian stage material prep
+	if _, err := s.transitions.TransitionInTx(tx, POTransitionRequest{
+		POID:      prep.ProductionOrderID,
+		To:        models.StatusReadyForCetak,
+		HandledBy: &userID,
+		Notes:     fmt.Sprintf("Material preparation selesai. Durasi: %d menit", durationMinutes),
+		StartedAt: prep.StartedAt,
+	}); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
+	RecordDomainEventInTx(tx, NewFinalizedEvent(EventMaterialPrepFinalized, models.StageKhazwalMaterialPrep, prep.ProductionOrderID, prep.ID, &userID))
 
-	// Buat notifikasi untuk setiap operator cetak
+	// 10. Notifikasi serah terima ke operator Unit Cetak
 	poNumber := int64(0)
 	obcNumber := ""
 	if prep.ProductionOrder != nil {
@@ -646,24 +664,30 @@ func (s *KhazwalService) FinalizeMaterialPrep(prepID uint64, photos []string, no
 		obcNumber = prep.ProductionOrder.OBCNumber
 	}
 
-	for _, operator := range cetakOperators {
-		notification := models.Notification{
-			UserID:  operator.ID,
-			Title:   "Material Siap - PO #" + obcNumber,
-			Message: "Material untuk PO #" + obcNumber + " telah siap. Silakan proses di unit cetak.",
-			Type:    models.NotificationInfo,
-			IsRead:  false,
-		}
-		if err := tx.Create(&notification).Error; err != nil {
+	if err := NotifyHandoverInTx(tx, prep.ProductionOrderID, models.StageCetak,
+		"Material Siap - PO #"+obcNumber,
+		fmt.Sprintf("Material untuk PO #%d (OBC %s) telah siap. Silakan proses di unit cetak.", poNumber, obcNumber),
+	); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 11. Eskalasi ke manager Khazwal dan PPIC jika variance kertas atau tinta melebihi threshold
+	if variances := materialVarianceSummary(&prep); len(variances) > 0 {
+		if err := NotifyEscalationInTx(tx, prep.ProductionOrderID, models.StageKhazwalMaterialPrep,
+			"Variance Material Tinggi - PO #"+obcNumber,
+			fmt.Sprintf("Material prep PO #%d (OBC %s) selesai dengan variance di atas %.0f%%: %s.", poNumber, obcNumber, models.VarianceThresholdPercentage, strings.Join(variances, ", ")),
+		); err != nil {
 			tx.Rollback()
 			return nil, err
 		}
 	}
 
-	// 10. Commit transaction
+	// 12. Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	events.Publish()
 
 	// Prepare result
 	preparedByName := ""
@@ -678,7 +702,7 @@ func (s *KhazwalService) FinalizeMaterialPrep(prepID uint64, photos []string, no
 		DurationMinutes: durationMinutes,
 		CompletedAt:     now,
 		PreparedByName:  preparedByName,
-		PhotosCount:     len(photos),
+		PhotosCount:     len(attachments),
 	}, nil
 }
 
@@ -695,11 +719,13 @@ type QueueFilters struct {
 // QueueResponse merupakan struct untuk paginated queue response
 // yang mencakup items, total count, dan pagination metadata
 type QueueResponse struct {
-	Items      []models.ProductionOrder `json:"items"`
-	Total      int                      `json:"total"`
-	Page       int                      `json:"page"`
-	PerPage    int                      `json:"per_page"`
-	TotalPages int                      `json:"total_pages"`
+	Items []models.ProductionOrder `json:"items"`
+	// SLA merupakan status SLA antrian per PO ID untuk PO yang masih WAITING_MATERIAL_PREP
+	SLA        map[uint64]QueueSLAStatus `json:"sla"`
+	Total      int                       `json:"total"`
+	Page       int                       `json:"page"`
+	PerPage    int                       `json:"per_page"`
+	TotalPages int                       `json:"total_pages"`
 }
 
 // HistoryFilters merupakan struct untuk filter history query
@@ -1003,3 +1029,18 @@ func (s *KhazwalService) GetMonitoringStats() (*MonitoringStats, error) {
 
 	return stats, nil
 }
+
+// materialVarianceSummary mengembalikan ringkasan variance kertas blanko dan tinta yang melebihi
+// models.VarianceThresholdPercentage, TintaItems harus sudah di-preload
+func materialVarianceSummary(prep *models.KhazwalMaterialPreparation) []string {
+	var variances []string
+	if pct := prep.KertasBlankoVariancePercentage; pct != nil && math.Abs(*pct) > models.VarianceThresholdPercentage {
+		variances = append(variances, fmt.Sprintf("kertas blanko %.2f%%", *pct))
+	}
+	for i := range prep.TintaItems {
+		if pct := prep.TintaItems[i].VariancePercentage(); pct != nil && math.Abs(*pct) > models.VarianceThresholdPercentage {
+			variances = append(variances, fmt.Sprintf("tinta %s %.2f%%", prep.TintaItems[i].ColorCode, *pct))
+		}
+	}
+	return variances
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk machine registry operations
+var (
+	ErrMachineNotFound      = errors.New("mesin tidak ditemukan")
+	ErrMachineNotAvailable  = errors.New("mesin sedang DOWN/MAINTENANCE atau tipe mesin tidak sesuai")
+	ErrMachineBusy          = errors.New("mesin sedang digunakan oleh pekerjaan lain")
+	ErrMachineCodeExists    = errors.New("kode mesin sudah terdaftar")
+	ErrInvalidMachineType   = errors.New("tipe mesin harus CETAK atau CUTTING")
+	ErrInvalidMachineStatus = errors.New("status mesin harus RUNNING, IDLE, DOWN, atau MAINTENANCE")
+	ErrInvalidCapacity      = errors.New("kapasitas mesin tidak boleh negatif")
+)
+
+// MachineService merupakan service untuk registry mesin produksi
+// yang mencakup CRUD mesin cetak dan potong serta manajemen status mesin
+type MachineService struct {
+	db *gorm.DB
+}
+
+// NewMachineService membuat instance baru dari MachineService
+func NewMachineService(db *gorm.DB) *MachineService {
+	return &MachineService{
+		db: db,
+	}
+}
+
+// MachineFilters merupakan struct untuk filter dan pagination list mesin
+type MachineFilters struct {
+	Type    string `form:"type"`
+	Status  string `form:"status"`
+	Search  string `form:"search"`
+	Page    int    `form:"page"`
+	PerPage int    `form:"per_page"`
+}
+
+// MachineListResponse merupakan struct untuk paginated list mesin
+type MachineListResponse struct {
+	Items      []models.Machine `json:"items"`
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	PerPage    int              `json:"per_page"`
+	TotalPages int              `json:"total_pages"`
+}
+
+// CreateMachineRequest merupakan request untuk mendaftarkan mesin baru
+type CreateMachineRequest struct {
+	Code                  string               `json:"code" binding:"required,max=20"`
+	Name                  string               `json:"name" binding:"required,max=100"`
+	Type                  models.MachineType   `json:"type" binding:"required"`
+	CapacitySheetsPerHour int                  `json:"capacity_sheets_per_hour"`
+	Status                models.MachineStatus `json:"status"`
+	Location              string               `json:"location"`
+	Notes                 string               `json:"notes"`
+}
+
+// UpdateMachineRequest merupakan request untuk update data mesin
+type UpdateMachineRequest struct {
+	Name                  *string `json:"name" binding:"omitempty,max=100"`
+	CapacitySheetsPerHour *int    `json:"capacity_sheets_per_hour"`
+	Location              *string `json:"location"`
+	Notes                 *string `json:"notes"`
+}
+
+// UpdateMachineStatusRequest merupakan request untuk mengubah status operasional mesin
+type UpdateMachineStatusRequest struct {
+	Status models.MachineStatus `json:"status" binding:"required"`
+	Notes  string               `json:"notes"`
+}
+
+// ListMachines mengambil list mesin dengan filter tipe, status, search, dan pagination
+func (s *MachineService) ListMachines(filters MachineFilters) (*MachineListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.Machine{})
+	if filters.Type != "" {
+		query = query.Where("type = ?", filters.Type)
+	}
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.Search != "" {
+		searchPattern := "%" + filters.Search + "%"
+		query = query.Where("code LIKE ? OR name LIKE ?", searchPattern, searchPattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var machines []models.Machine
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Order("type ASC, code ASC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&machines).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &MachineListResponse{
+		Items:      machines,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetMachine mengambil detail mesin berdasarkan ID
+func (s *MachineService) GetMachine(id uint64) (*models.Machine, error) {
+	var machine models.Machine
+	if err := s.db.First(&machine, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrMachineNotFound
+		}
+		return nil, err
+	}
+	return &machine, nil
+}
+
+// CreateMachine mendaftarkan mesin baru ke registry dengan validasi kode unik
+func (s *MachineService) CreateMachine(req CreateMachineRequest) (*models.Machine, error) {
+	if !models.IsValidMachineType(req.Type) {
+		return nil, ErrInvalidMachineType
+	}
+	if req.Status == "" {
+		req.Status = models.MachineIdle
+	}
+	if !models.IsValidMachineStatus(req.Status) {
+		return nil, ErrInvalidMachineStatus
+	}
+	if req.CapacitySheetsPerHour < 0 {
+		return nil, ErrInvalidCapacity
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	var existing int64
+	if err := s.db.Unscoped().Model(&models.Machine{}).Where("code = ?", code).Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrMachineCodeExists
+	}
+
+	machine := models.Machine{
+		Code:                  code,
+		Name:                  req.Name,
+		Type:                  req.Type,
+		CapacitySheetsPerHour: req.CapacitySheetsPerHour,
+		Status:                req.Status,
+		Location:              req.Location,
+		Notes:                 req.Notes,
+	}
+	if err := s.db.Create(&machine).Error; err != nil {
+		return nil, err
+	}
+	return &machine, nil
+}
+
+// UpdateMachine mengupdate data mesin (nama, kapasitas, lokasi, catatan)
+func (s *MachineService) UpdateMachine(id uint64, req UpdateMachineRequest) (*models.Machine, error) {
+	machine, err := s.GetMachine(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.CapacitySheetsPerHour != nil {
+		if *req.CapacitySheetsPerHour < 0 {
+			return nil, ErrInvalidCapacity
+		}
+		updates["capacity_sheets_per_hour"] = *req.CapacitySheetsPerHour
+	}
+	if req.Location != nil {
+		updates["location"] = *req.Location
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(machine).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetMachine(id)
+}
+
+// UpdateMachineStatus mengubah status operasional mesin (RUNNING, IDLE, DOWN, MAINTENANCE)
+func (s *MachineService) UpdateMachineStatus(id uint64, req UpdateMachineStatusRequest) (*models.Machine, error) {
+	if !models.IsValidMachineStatus(req.Status) {
+		return nil, ErrInvalidMachineStatus
+	}
+
+	machine, err := s.GetMachine(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"status": req.Status,
+	}
+	if req.Notes != "" {
+		updates["notes"] = req.Notes
+	}
+	if err := s.db.Model(machine).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetMachine(id)
+}
+
+// DeleteMachine menghapus mesin dari registry (soft delete)
+// selama mesin tidak sedang dipakai oleh print job aktif atau cutting yang sedang berjalan.
+// Row mesin di-lock agar tidak ada pekerjaan baru yang dimulai di antara pengecekan dan penghapusan
+func (s *MachineService) DeleteMachine(id uint64) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var machine models.Machine
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&machine, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrMachineNotFound
+			}
+			return err
+		}
+
+		var activeJobs int64
+		if err := tx.Model(&models.PrintJob{}).
+			Where("machine_id = ?", id).
+			Where("status IN ?", []models.PrintJobStatus{models.PrintJobInProgress, models.PrintJobPaused}).
+			Count(&activeJobs).Error; err != nil {
+			return err
+		}
+		if activeJobs > 0 {
+			return ErrMachineBusy
+		}
+
+		var activeCuttings int64
+		if err := tx.Table("khazwal_cutting_results").
+			Where("cutting_machine = ?", machine.Code).
+			Where("status = ?", "IN_PROGRESS").
+			Where("deleted_at IS NULL").
+			Count(&activeCuttings).Error; err != nil {
+			return err
+		}
+		if activeCuttings > 0 {
+			return ErrMachineBusy
+		}
+
+		return tx.Delete(&machine).Error
+	})
+}
+
+// findOperationalMachine mengambil mesin dari registry dan memastikan tipe sesuai
+// serta status mesin tidak DOWN atau MAINTENANCE
+func findOperationalMachine(tx *gorm.DB, machineID uint64, machineType models.MachineType) (*models.Machine, error) {
+	var machine models.Machine
+	if err := tx.First(&machine, machineID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrMachineNotFound
+		}
+		return nil, err
+	}
+	if machine.Type != machineType || !machine.IsOperational() {
+		return nil, ErrMachineNotAvailable
+	}
+	return &machine, nil
+}
+
+// setMachineStatus mengubah status mesin di dalam transaction milik caller
+func setMachineStatus(tx *gorm.DB, machineID uint64, status models.MachineStatus) error {
+	return tx.Model(&models.Machine{}).
+		Where("id = ?", machineID).
+		Update("status", status).Error
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrWebhookRejected merupakan error untuk webhook yang membalas dengan status selain 2xx
+var ErrWebhookRejected = errors.New("webhook menolak notifikasi")
+
+// EmailSender merupakan kontrak pengiriman email untuk delivery queue notifikasi
+type EmailSender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// WebhookSender merupakan kontrak pengiriman payload JSON ke webhook eksternal
+type WebhookSender interface {
+	Post(ctx context.Context, url, deliveryKey string, payload []byte) error
+}
+
+// SMTPEmailSender merupakan EmailSender yang mengirim email melalui SMTP server,
+// STARTTLS dipakai jika server mendukung dan AUTH hanya jika username diisi
+// sehingga bisa langsung diuji dengan SMTP catcher lokal (MailHog, Mailpit)
+type SMTPEmailSender struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	timeout  time.Duration
+}
+
+// NewSMTPEmailSender membuat instance baru dari SMTPEmailSender
+func NewSMTPEmailSender(host string, port int, username, password, from string, timeout time.Duration) *SMTPEmailSender {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &SMTPEmailSender{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		timeout:  timeout,
+	}
+}
+
+// Send mengirim satu email plain text UTF-8
+func (s *SMTPEmailSender) Send(ctx context.Context, to, subject, body string) error {
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(buildEmailMessage(s.from, to, subject, body)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildEmailMessage menyusun email plain text dengan subject yang di-encode untuk karakter non-ASCII
+func buildEmailMessage(from, to, subject, body string) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes()
+}
+
+// HTTPWebhookSender merupakan WebhookSender yang mengirim payload JSON melalui HTTP POST,
+// payload ditandatangani HMAC-SHA256 jika secret diisi. Tujuan dibatasi WebhookGuard saat request
+// dibuat dan saat koneksi dibuka, dan redirect hanya diikuti ke host yang sama
+type HTTPWebhookSender struct {
+	secret     string
+	guard      *WebhookGuard
+	httpClient *http.Client
+}
+
+// NewHTTPWebhookSender membuat instance baru dari HTTPWebhookSender
+func NewHTTPWebhookSender(secret string, timeout time.Duration, guard *WebhookGuard) *HTTPWebhookSender {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if guard == nil {
+		guard = NewWebhookGuard(nil, false)
+	}
+
+	dialer := &net.Dialer{Timeout: timeout, Control: guard.dialControl}
+	transport := &http.Transport{
+		// Proxy tidak dipakai agar pemeriksaan alamat di dialer berlaku untuk tujuan webhook
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+	}
+	return &HTTPWebhookSender{
+		secret: secret,
+		guard:  guard,
+		httpClient: &http.Client{
+			Timeout:       timeout,
+			Transport:     transport,
+			CheckRedirect: checkWebhookRedirect,
+		},
+	}
+}
+
+// checkWebhookRedirect hanya mengikuti redirect ke host yang sama dengan request awal, maksimal 3 kali
+func checkWebhookRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 3 || !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		return ErrWebhookRedirectBlocked
+	}
+	return nil
+}
+
+// Post mengirim payload ke webhook, status selain 2xx dianggap gagal dan dijadwalkan ulang
+func (s *HTTPWebhookSender) Post(ctx context.Context, url, deliveryKey string, payload []byte) error {
+	if _, err := s.guard.checkURL(url); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sirine-Delivery", deliveryKey)
+	if s.secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.secret))
+		mac.Write(payload)
+		req.Header.Set("X-Sirine-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: HTTP %d %s", ErrWebhookRejected, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
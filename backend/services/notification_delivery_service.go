@@ -0,0 +1,698 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sirine-go/backend/config"
+	"sirine-go/backend/models"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk delivery queue notifikasi
+var (
+	ErrNotificationDeliveryNotFound     = errors.New("pengiriman notifikasi tidak ditemukan")
+	ErrNotificationDeliveryNotRetryable = errors.New("pengiriman notifikasi sudah terkirim atau sedang diproses")
+	ErrWebhookURLInvalid                = errors.New("webhook_url harus berupa URL http atau https")
+	ErrNotificationChannelNotEnabled    = errors.New("belum ada channel email atau webhook yang aktif")
+	ErrNotificationEmailMissing         = errors.New("email user belum diisi")
+	ErrNotificationCategoryNotMutable   = errors.New("kategori notifikasi tidak valid atau tidak dapat di-mute")
+)
+
+const (
+	notificationDeliveryBatchSize         = 50
+	notificationDeliveryProcessingTimeout = 5 * time.Minute
+	notificationDigestMaxItems            = 50
+)
+
+// NotificationDeliverySettings merupakan pengaturan worker delivery queue dan digest harian
+type NotificationDeliverySettings struct {
+	PollInterval time.Duration
+	// DigestHour merupakan jam lokal (0-23) digest harian mulai dikirim
+	DigestHour int
+	// AppURL dipakai untuk link ke halaman notifikasi di email
+	AppURL string
+	// WebhookGuard membatasi webhook_url yang boleh disimpan user, nil berarti semua webhook ditolak
+	WebhookGuard *WebhookGuard
+}
+
+// NotificationDeliveryService merupakan service untuk pengiriman notifikasi melalui email dan webhook
+// yang mencakup preferensi channel per user, delivery queue dengan retry, dan digest email harian
+type NotificationDeliveryService struct {
+	db       *gorm.DB
+	email    EmailSender
+	webhook  WebhookSender
+	settings NotificationDeliverySettings
+}
+
+// NewNotificationDeliveryService membuat instance baru dari NotificationDeliveryService,
+// sender nil berarti pengiriman channel tersebut tetap PENDING sampai sender dikonfigurasi
+func NewNotificationDeliveryService(db *gorm.DB, email EmailSender, webhook WebhookSender, settings NotificationDeliverySettings) *NotificationDeliveryService {
+	if settings.PollInterval <= 0 {
+		settings.PollInterval = 30 * time.Second
+	}
+	if settings.DigestHour < 0 || settings.DigestHour > 23 {
+		settings.DigestHour = 7
+	}
+	if settings.WebhookGuard == nil {
+		settings.WebhookGuard = NewWebhookGuard(nil, false)
+	}
+	return &NotificationDeliveryService{
+		db:       db,
+		email:    email,
+		webhook:  webhook,
+		settings: settings,
+	}
+}
+
+// NewWebhookGuardFromConfig membuat WebhookGuard dari NOTIFICATION_WEBHOOK_ALLOWED_HOSTS
+func NewWebhookGuardFromConfig(cfg *config.Config) *WebhookGuard {
+	return NewWebhookGuard(cfg.NotificationWebhookAllowedHosts, cfg.NotificationWebhookAllowPrivate)
+}
+
+// NewNotificationSendersFromConfig membuat sender email (SMTP) dan webhook dari konfigurasi,
+// email dinonaktifkan jika EMAIL_SMTP_HOST dikosongkan
+func NewNotificationSendersFromConfig(cfg *config.Config, guard *WebhookGuard) (EmailSender, WebhookSender) {
+	var email EmailSender
+	if cfg.EmailSMTPHost != "" {
+		email = NewSMTPEmailSender(cfg.EmailSMTPHost, cfg.EmailSMTPPort, cfg.EmailUsername, cfg.EmailPassword, cfg.EmailFromAddress, 30*time.Second)
+	}
+	return email, NewHTTPWebhookSender(cfg.NotificationWebhookSecret, cfg.NotificationWebhookTimeout, guard)
+}
+
+// UpdateNotificationPreferenceRequest merupakan request untuk mengubah preferensi channel notifikasi
+type UpdateNotificationPreferenceRequest struct {
+	EmailEnabled   bool   `json:"email_enabled"`
+	DigestEnabled  bool   `json:"digest_enabled"`
+	WebhookEnabled bool   `json:"webhook_enabled"`
+	WebhookURL     string `json:"webhook_url"`
+	// MutedCategories merupakan kategori yang tidak ingin diterima, kategori ALERT tidak dapat di-mute
+	MutedCategories []models.NotificationCategory `json:"muted_categories"`
+}
+
+// NotificationDeliveryFilters merupakan struct untuk filter dan pagination list pengiriman
+type NotificationDeliveryFilters struct {
+	Status  string `form:"status"`
+	Channel string `form:"channel"`
+	UserID  uint64 `form:"user_id"`
+	Page    int    `form:"page"`
+	PerPage int    `form:"per_page"`
+}
+
+// NotificationDeliveryListResponse merupakan struct untuk paginated list pengiriman
+type NotificationDeliveryListResponse struct {
+	Items      []models.NotificationDelivery `json:"items"`
+	Total      int                           `json:"total"`
+	Page       int                           `json:"page"`
+	PerPage    int                           `json:"per_page"`
+	TotalPages int                           `json:"total_pages"`
+}
+
+// notificationWebhookPayload merupakan payload JSON yang dikirim ke webhook
+type notificationWebhookPayload struct {
+	Event     string                  `json:"event"`
+	Title     string                  `json:"title"`
+	Message   string                  `json:"message"`
+	Type      models.NotificationType `json:"type"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// deliveryRecipient merupakan data user yang dibutuhkan untuk menyusun pengiriman
+type deliveryRecipient struct {
+	ID       uint64
+	FullName string
+	Email    string
+}
+
+// RegisterNotificationDeliveryCallbacks memasang GORM callback yang menulis pengiriman email dan webhook
+// untuk notifikasi kritis dalam transaction yang sama dengan notifikasinya
+func RegisterNotificationDeliveryCallbacks(db *gorm.DB) error {
+	return db.Callback().Create().After("gorm:create").
+		Register("notification_delivery:enqueue", func(tx *gorm.DB) {
+			if tx.Error != nil || tx.Statement.Schema == nil || tx.Statement.Schema.Table != "notifications" {
+				return
+			}
+
+			var notifications []models.Notification
+			switch dest := tx.Statement.Dest.(type) {
+			case *models.Notification:
+				notifications = []models.Notification{*dest}
+			case []models.Notification:
+				notifications = dest
+			case *[]models.Notification:
+				notifications = *dest
+			}
+
+			if err := enqueueAlertDeliveriesInTx(tx.Session(&gorm.Session{NewDB: true}), notifications); err != nil {
+				tx.AddError(err)
+			}
+		})
+}
+
+// enqueueAlertDeliveriesInTx menulis pengiriman untuk notifikasi kritis sesuai preferensi penerimanya,
+// webhook dengan URL yang sama hanya menerima satu pengiriman untuk notifikasi yang dibuat bersamaan
+func enqueueAlertDeliveriesInTx(tx *gorm.DB, notifications []models.Notification) error {
+	var critical []models.Notification
+	userIDs := make([]uint64, 0, len(notifications))
+	for _, notification := range notifications {
+		if models.IsCriticalNotification(notification.Type) {
+			critical = append(critical, notification)
+			userIDs = append(userIDs, notification.UserID)
+		}
+	}
+	if len(critical) == 0 {
+		return nil
+	}
+
+	var preferences []models.NotificationPreference
+	if err := tx.Where("user_id IN ? AND (email_enabled = ? OR webhook_enabled = ?)", userIDs, true, true).
+		Find(&preferences).Error; err != nil {
+		return err
+	}
+	if len(preferences) == 0 {
+		return nil
+	}
+	preferenceByUser := make(map[uint64]models.NotificationPreference, len(preferences))
+	for _, preference := range preferences {
+		preferenceByUser[preference.UserID] = preference
+	}
+
+	recipients, err := loadDeliveryRecipients(tx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	webhookSeen := make(map[string]bool)
+	var deliveries []models.NotificationDelivery
+	for _, notification := range critical {
+		preference, ok := preferenceByUser[notification.UserID]
+		if !ok {
+			continue
+		}
+		notificationID := notification.ID
+
+		if recipient, ok := recipients[notification.UserID]; ok && preference.EmailEnabled && recipient.Email != "" {
+			deliveries = append(deliveries, models.NotificationDelivery{
+				NotificationID: &notificationID,
+				UserID:         notification.UserID,
+				Channel:        models.ChannelEmail,
+				Kind:           models.DeliveryKindAlert,
+				Recipient:      recipient.Email,
+				Subject:        "[Sirine] " + notification.Title,
+				Body:           notification.Message,
+				IdempotencyKey: fmt.Sprintf("ALERT-EMAIL-%d", notification.ID),
+				Status:         models.DeliveryPending,
+				MaxAttempts:    models.NotificationDeliveryDefaultMaxAttempts,
+				NextAttemptAt:  now,
+			})
+		}
+
+		if preference.WebhookEnabled && preference.WebhookURL != "" && !webhookSeen[preference.WebhookURL] {
+			webhookSeen[preference.WebhookURL] = true
+			payload, err := json.Marshal(notificationWebhookPayload{
+				Event:     "notification",
+				Title:     notification.Title,
+				Message:   notification.Message,
+				Type:      notification.Type,
+				CreatedAt: notification.CreatedAt,
+			})
+			if err != nil {
+				return err
+			}
+			urlHash := sha256.Sum256([]byte(preference.WebhookURL))
+			deliveries = append(deliveries, models.NotificationDelivery{
+				NotificationID: &notificationID,
+				UserID:         notification.UserID,
+				Channel:        models.ChannelWebhook,
+				Kind:           models.DeliveryKindAlert,
+				Recipient:      preference.WebhookURL,
+				Subject:        notification.Title,
+				Body:           string(payload),
+				IdempotencyKey: fmt.Sprintf("ALERT-WEBHOOK-%d-%s", notification.ID, hex.EncodeToString(urlHash[:8])),
+				Status:         models.DeliveryPending,
+				MaxAttempts:    models.NotificationDeliveryDefaultMaxAttempts,
+				NextAttemptAt:  now,
+			})
+		}
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// loadDeliveryRecipients mengambil nama dan email user aktif untuk pengiriman
+func loadDeliveryRecipients(tx *gorm.DB, userIDs []uint64) (map[uint64]deliveryRecipient, error) {
+	var rows []deliveryRecipient
+	if err := tx.Model(&models.User{}).
+		Select("id", "full_name", "email").
+		Where("id IN ? AND status = ?", userIDs, models.StatusActive).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	recipients := make(map[uint64]deliveryRecipient, len(rows))
+	for _, row := range rows {
+		recipients[row.ID] = row
+	}
+	return recipients, nil
+}
+
+// GetPreference mengambil preferensi channel user, user tanpa preferensi mendapat nilai default (in-app saja)
+func (s *NotificationDeliveryService) GetPreference(userID uint64) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := s.db.Where("user_id = ?", userID).First(&preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.NotificationPreference{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// UpdatePreference menyimpan preferensi channel user
+func (s *NotificationDeliveryService) UpdatePreference(userID uint64, req UpdateNotificationPreferenceRequest) (*models.NotificationPreference, error) {
+	req.WebhookURL = strings.TrimSpace(req.WebhookURL)
+	if req.WebhookEnabled || req.WebhookURL != "" {
+		if err := s.settings.WebhookGuard.ValidateURL(context.Background(), req.WebhookURL); err != nil {
+			return nil, err
+		}
+	}
+
+	mutedCategories := make([]models.NotificationCategory, 0, len(req.MutedCategories))
+	for _, category := range req.MutedCategories {
+		category = models.NotificationCategory(strings.ToUpper(string(category)))
+		if !category.IsMutable() {
+			return nil, ErrNotificationCategoryNotMutable
+		}
+		if !slices.Contains(mutedCategories, category) {
+			mutedCategories = append(mutedCategories, category)
+		}
+	}
+
+	preference, err := s.GetPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+	preference.EmailEnabled = req.EmailEnabled
+	preference.DigestEnabled = req.DigestEnabled
+	preference.WebhookEnabled = req.WebhookEnabled
+	preference.WebhookURL = req.WebhookURL
+	preference.MutedCategories = mutedCategories
+
+	if err := s.db.Save(preference).Error; err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
+
+// SendTest menulis pengiriman uji coba ke setiap channel aktif milik user
+// agar user bisa memastikan email dan webhook sudah diterima
+func (s *NotificationDeliveryService) SendTest(userID uint64) ([]models.NotificationDelivery, error) {
+	preference, err := s.GetPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+	recipients, err := loadDeliveryRecipients(s.db, []uint64{userID})
+	if err != nil {
+		return nil, err
+	}
+	recipient := recipients[userID]
+
+	now := time.Now()
+	suffix := now.Format("20060102150405.000")
+	var deliveries []models.NotificationDelivery
+
+	if preference.EmailEnabled || preference.DigestEnabled {
+		if recipient.Email == "" {
+			return nil, ErrNotificationEmailMissing
+		}
+		deliveries = append(deliveries, models.NotificationDelivery{
+			UserID:         userID,
+			Channel:        models.ChannelEmail,
+			Kind:           models.DeliveryKindTest,
+			Recipient:      recipient.Email,
+			Subject:        "[Sirine] Uji coba notifikasi email",
+			Body:           fmt.Sprintf("Halo %s,\n\nEmail ini menandakan notifikasi Sirine sudah dapat dikirim ke alamat Anda.", recipient.FullName),
+			IdempotencyKey: fmt.Sprintf("TEST-EMAIL-%d-%s", userID, suffix),
+		})
+	}
+	if preference.WebhookEnabled && preference.WebhookURL != "" {
+		// URL diperiksa ulang karena allowlist server dapat berubah setelah preferensi disimpan
+		if err := s.settings.WebhookGuard.ValidateURL(context.Background(), preference.WebhookURL); err != nil {
+			return nil, err
+		}
+		payload, err := json.Marshal(notificationWebhookPayload{
+			Event:     "test",
+			Title:     "Uji coba webhook notifikasi",
+			Message:   fmt.Sprintf("Webhook notifikasi Sirine untuk %s sudah terhubung.", recipient.FullName),
+			Type:      models.NotificationInfo,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, models.NotificationDelivery{
+			UserID:         userID,
+			Channel:        models.ChannelWebhook,
+			Kind:           models.DeliveryKindTest,
+			Recipient:      preference.WebhookURL,
+			Subject:        "Uji coba webhook notifikasi",
+			Body:           string(payload),
+			IdempotencyKey: fmt.Sprintf("TEST-WEBHOOK-%d-%s", userID, suffix),
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil, ErrNotificationChannelNotEnabled
+	}
+
+	for i := range deliveries {
+		deliveries[i].Status = models.DeliveryPending
+		deliveries[i].MaxAttempts = models.NotificationDeliveryDefaultMaxAttempts
+		deliveries[i].NextAttemptAt = now
+	}
+	if err := s.db.Create(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// StartWorker menjalankan worker digest dan pengiriman di background sampai context dibatalkan
+func (s *NotificationDeliveryService) StartWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.settings.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.EnqueueDailyDigests(time.Now()); err != nil {
+				log.Printf("Notification delivery: gagal menyusun digest: %v", err)
+			}
+			if _, err := s.ProcessDue(ctx); err != nil {
+				log.Printf("Notification delivery: gagal memproses pengiriman: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("Notification delivery worker berjalan (interval %s, digest pukul %02d:00)", s.settings.PollInterval, s.settings.DigestHour)
+}
+
+// EnqueueDailyDigests menulis digest email harian untuk user yang mengaktifkannya,
+// berisi notifikasi belum dibaca sejak digest terakhir, dan dijalankan sekali per hari
+// setelah jam DigestHour
+func (s *NotificationDeliveryService) EnqueueDailyDigests(now time.Time) (int, error) {
+	digestAt := time.Date(now.Year(), now.Month(), now.Day(), s.settings.DigestHour, 0, 0, 0, now.Location())
+	if now.Before(digestAt) {
+		return 0, nil
+	}
+
+	var preferences []models.NotificationPreference
+	if err := s.db.Where("digest_enabled = ? AND (last_digest_at IS NULL OR last_digest_at < ?)", true, digestAt).
+		Find(&preferences).Error; err != nil {
+		return 0, err
+	}
+
+	enqueued := 0
+	for i := range preferences {
+		created, err := s.enqueueDigest(&preferences[i], now)
+		if err != nil {
+			return enqueued, err
+		}
+		if created {
+			enqueued++
+		}
+	}
+	return enqueued, nil
+}
+
+// enqueueDigest menyusun satu digest user, user tanpa notifikasi baru hanya dicatat waktunya
+func (s *NotificationDeliveryService) enqueueDigest(preference *models.NotificationPreference, now time.Time) (bool, error) {
+	since := now.Add(-24 * time.Hour)
+	if preference.LastDigestAt != nil {
+		since = *preference.LastDigestAt
+	}
+
+	created := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var notifications []models.Notification
+		if err := tx.Where("user_id = ? AND is_read = ? AND created_at > ? AND created_at <= ?", preference.UserID, false, since, now).
+			Where("expires_at IS NULL OR expires_at > ?", now).
+			Order("created_at DESC").
+			Find(&notifications).Error; err != nil {
+			return err
+		}
+
+		recipients, err := loadDeliveryRecipients(tx, []uint64{preference.UserID})
+		if err != nil {
+			return err
+		}
+		recipient, ok := recipients[preference.UserID]
+
+		if len(notifications) > 0 && ok && recipient.Email != "" {
+			delivery := models.NotificationDelivery{
+				UserID:         preference.UserID,
+				Channel:        models.ChannelEmail,
+				Kind:           models.DeliveryKindDigest,
+				Recipient:      recipient.Email,
+				Subject:        fmt.Sprintf("[Sirine] Ringkasan %d notifikasi belum dibaca", len(notifications)),
+				Body:           s.buildDigestBody(recipient.FullName, since, notifications),
+				IdempotencyKey: fmt.Sprintf("DIGEST-%d-%s", preference.UserID, now.Format("20060102")),
+				Status:         models.DeliveryPending,
+				MaxAttempts:    models.NotificationDeliveryDefaultMaxAttempts,
+				NextAttemptAt:  now,
+			}
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery)
+			if result.Error != nil {
+				return result.Error
+			}
+			created = result.RowsAffected == 1
+		}
+
+		return tx.Model(preference).Update("last_digest_at", now).Error
+	})
+	return created, err
+}
+
+// buildDigestBody menyusun isi digest email plain text
+func (s *NotificationDeliveryService) buildDigestBody(name string, since time.Time, notifications []models.Notification) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Halo %s,\n\n", name)
+	fmt.Fprintf(&body, "Anda memiliki %d notifikasi belum dibaca sejak %s:\n\n", len(notifications), since.Format("02/01/2006 15:04"))
+
+	for i, notification := range notifications {
+		if i == notificationDigestMaxItems {
+			fmt.Fprintf(&body, "... dan %d notifikasi lainnya\n\n", len(notifications)-notificationDigestMaxItems)
+			break
+		}
+		fmt.Fprintf(&body, "- [%s] %s %s\n  %s\n\n", notification.Type, notification.CreatedAt.Format("02/01 15:04"), notification.Title, notification.Message)
+	}
+
+	if s.settings.AppURL != "" {
+		fmt.Fprintf(&body, "Buka notifikasi: %s/notifications\n", strings.TrimRight(s.settings.AppURL, "/"))
+	}
+	return body.String()
+}
+
+// ProcessDue mengirim pengiriman PENDING yang sudah jatuh tempo dan mengembalikan jumlah yang terkirim,
+// pengiriman untuk channel tanpa sender dibiarkan PENDING
+func (s *NotificationDeliveryService) ProcessDue(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	// Pengiriman PROCESSING yang tertahan (mis. server restart saat mengirim) dikembalikan ke antrian
+	if err := s.db.Model(&models.NotificationDelivery{}).
+		Where("status = ? AND locked_at < ?", models.DeliveryProcessing, now.Add(-notificationDeliveryProcessingTimeout)).
+		Updates(map[string]interface{}{
+			"status":    models.DeliveryPending,
+			"locked_at": nil,
+		}).Error; err != nil {
+		return 0, err
+	}
+
+	var channels []models.NotificationChannel
+	if s.email != nil {
+		channels = append(channels, models.ChannelEmail)
+	}
+	if s.webhook != nil {
+		channels = append(channels, models.ChannelWebhook)
+	}
+	if len(channels) == 0 {
+		return 0, nil
+	}
+
+	var candidates []models.NotificationDelivery
+	if err := s.db.Where("status = ? AND next_attempt_at <= ? AND channel IN ?", models.DeliveryPending, now, channels).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(notificationDeliveryBatchSize).
+		Find(&candidates).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		delivery := &candidates[i]
+		claimed, err := s.claim(delivery)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.deliver(ctx, delivery); err != nil {
+			log.Printf("Notification delivery: %s %d gagal dikirim (percobaan %d): %v", delivery.Channel, delivery.ID, delivery.Attempts, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// claim menandai pengiriman sebagai PROCESSING secara atomic agar tidak dikirim dua kali
+func (s *NotificationDeliveryService) claim(delivery *models.NotificationDelivery) (bool, error) {
+	result := s.db.Model(&models.NotificationDelivery{}).
+		Where("id = ? AND status = ?", delivery.ID, models.DeliveryPending).
+		Updates(map[string]interface{}{
+			"status":    models.DeliveryProcessing,
+			"locked_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// deliver mengirim satu pengiriman dan mencatat hasilnya, kegagalan dijadwalkan ulang dengan
+// exponential backoff sampai batas percobaan lalu ditandai FAILED
+func (s *NotificationDeliveryService) deliver(ctx context.Context, delivery *models.NotificationDelivery) error {
+	var sendErr error
+	switch delivery.Channel {
+	case models.ChannelEmail:
+		sendErr = s.email.Send(ctx, delivery.Recipient, delivery.Subject, delivery.Body)
+	case models.ChannelWebhook:
+		sendErr = s.webhook.Post(ctx, delivery.Recipient, delivery.IdempotencyKey, []byte(delivery.Body))
+	default:
+		sendErr = fmt.Errorf("channel tidak dikenal: %s", delivery.Channel)
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	updates := map[string]interface{}{
+		"attempts":        delivery.Attempts,
+		"last_attempt_at": now,
+		"locked_at":       nil,
+	}
+
+	if sendErr == nil {
+		updates["status"] = models.DeliverySent
+		updates["sent_at"] = now
+		updates["last_error"] = ""
+	} else {
+		updates["last_error"] = sendErr.Error()
+		if delivery.HasExhaustedAttempts() {
+			updates["status"] = models.DeliveryFailed
+		} else {
+			updates["status"] = models.DeliveryPending
+			updates["next_attempt_at"] = now.Add(models.NotificationDeliveryRetryDelay(delivery.Attempts))
+		}
+	}
+
+	if err := s.db.Model(&models.NotificationDelivery{}).Where("id = ?", delivery.ID).Updates(updates).Error; err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// ListDeliveries mengambil list pengiriman dengan filter status, channel, user, dan pagination
+func (s *NotificationDeliveryService) ListDeliveries(filters NotificationDeliveryFilters) (*NotificationDeliveryListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.NotificationDelivery{})
+	if filters.Status != "" {
+		query = query.Where("status = ?", strings.ToUpper(filters.Status))
+	}
+	if filters.Channel != "" {
+		query = query.Where("channel = ?", strings.ToUpper(filters.Channel))
+	}
+	if filters.UserID > 0 {
+		query = query.Where("user_id = ?", filters.UserID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var deliveries []models.NotificationDelivery
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Order("id DESC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &NotificationDeliveryListResponse{
+		Items:      deliveries,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// RetryDelivery menjadwalkan ulang pengiriman FAILED atau PENDING untuk segera dikirim
+// dengan jumlah percobaan di-reset
+func (s *NotificationDeliveryService) RetryDelivery(id uint64) (*models.NotificationDelivery, error) {
+	var delivery models.NotificationDelivery
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&delivery, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrNotificationDeliveryNotFound
+			}
+			return err
+		}
+		if !delivery.CanRetry() {
+			return ErrNotificationDeliveryNotRetryable
+		}
+
+		return tx.Model(&delivery).Updates(map[string]interface{}{
+			"status":          models.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
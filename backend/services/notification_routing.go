@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrNotificationAudienceEmpty = errors.New("penerima notifikasi harus memiliki minimal satu role")
+
+// NotificationAudience merupakan target penerima notifikasi: semua user aktif dengan salah satu role
+// di departemen tertentu, Department kosong berarti semua departemen
+type NotificationAudience struct {
+	Roles      []models.UserRole `json:"roles"`
+	Department models.Department `json:"department,omitempty"`
+}
+
+// Audience serah terima untuk setiap unit produksi
+var (
+	AudienceUnitCetak  = NotificationAudience{Roles: []models.UserRole{models.RoleOperatorCetak}, Department: models.DeptCetak}
+	AudienceKhazwal    = NotificationAudience{Roles: []models.UserRole{models.RoleStaffKhazwal}, Department: models.DeptKhazwal}
+	AudienceVerifikasi = NotificationAudience{Roles: []models.UserRole{models.RoleVerifikator, models.RoleQCInspector}, Department: models.DeptVerifikasi}
+	AudienceKhazkhir   = NotificationAudience{Roles: []models.UserRole{models.RoleStaffKhazkhir}, Department: models.DeptKhazkhir}
+	AudiencePPIC       = NotificationAudience{Roles: []models.UserRole{models.RolePPIC}, Department: models.DeptPPIC}
+)
+
+// handoverAudiences memetakan stage tujuan PO ke unit yang menerima notifikasi serah terima
+var handoverAudiences = map[models.POStage]NotificationAudience{
+	models.StageCetak:           AudienceUnitCetak,
+	models.StageKhazwalCounting: AudienceKhazwal,
+	models.StageKhazwalCutting:  AudienceKhazwal,
+	models.StageVerifikasi:      AudienceVerifikasi,
+	models.StageKhazkhir:        AudienceKhazkhir,
+	models.StageCompleted:       AudiencePPIC,
+}
+
+// stageDepartments memetakan stage PO ke departemen yang mengerjakannya, dipakai untuk eskalasi ke manager departemen
+var stageDepartments = map[models.POStage]models.Department{
+	models.StageKhazwalMaterialPrep: models.DeptKhazwal,
+	models.StageCetak:               models.DeptCetak,
+	models.StageKhazwalCounting:     models.DeptKhazwal,
+	models.StageKhazwalCutting:      models.DeptKhazwal,
+	models.StageVerifikasi:          models.DeptVerifikasi,
+	models.StageKhazkhir:            models.DeptKhazkhir,
+}
+
+// NotificationContent merupakan isi notifikasi beserta kategori dan link ke PO dan stage terkait,
+// Category kosong ditentukan dari Type dan POID 0 berarti notifikasi tidak terkait PO
+type NotificationContent struct {
+	Title    string
+	Message  string
+	Type     models.NotificationType
+	Category models.NotificationCategory
+	POID     uint64
+	Stage    models.POStage
+}
+
+// newNotification menyusun notifikasi untuk satu user dengan kategori dan masa berlaku default
+func newNotification(userID uint64, content NotificationContent, now time.Time) models.Notification {
+	notifType := content.Type
+	if notifType == "" {
+		notifType = models.NotificationInfo
+	}
+	category := content.Category
+	if category == "" {
+		category = models.NotificationCategoryForType(notifType)
+	}
+
+	notification := models.Notification{
+		UserID:    userID,
+		Title:     content.Title,
+		Message:   content.Message,
+		Type:      notifType,
+		Category:  category,
+		Stage:     content.Stage,
+		IsRead:    false,
+		ExpiresAt: models.NotificationExpiry(category, now),
+	}
+	if content.POID != 0 {
+		poID := content.POID
+		notification.POID = &poID
+	}
+	return notification
+}
+
+// mutedUserIDsInTx mengambil user yang me-mute kategori notifikasi dari daftar userIDs
+func mutedUserIDsInTx(tx *gorm.DB, category models.NotificationCategory, userIDs []uint64) (map[uint64]bool, error) {
+	muted := make(map[uint64]bool)
+	if !category.IsMutable() || len(userIDs) == 0 {
+		return muted, nil
+	}
+
+	var preferences []models.NotificationPreference
+	if err := tx.Select("id", "user_id", "muted_categories").
+		Where("user_id IN ? AND muted_categories IS NOT NULL", userIDs).
+		Find(&preferences).Error; err != nil {
+		return nil, err
+	}
+	for i := range preferences {
+		if preferences[i].IsMuted(category) {
+			muted[preferences[i].UserID] = true
+		}
+	}
+	return muted, nil
+}
+
+// NotifyAudiencesInTx membuat notifikasi untuk semua user aktif yang cocok dengan salah satu audience,
+// user yang cocok dengan lebih dari satu audience hanya menerima satu notifikasi dan user yang
+// me-mute kategori notifikasi dilewati
+func NotifyAudiencesInTx(tx *gorm.DB, content NotificationContent, audiences ...NotificationAudience) (int, error) {
+	seen := make(map[uint64]bool)
+	var userIDs []uint64
+
+	for _, audience := range audiences {
+		if len(audience.Roles) == 0 {
+			return 0, ErrNotificationAudienceEmpty
+		}
+
+		query := tx.Model(&models.User{}).
+			Where("role IN ? AND status = ?", audience.Roles, models.StatusActive)
+		if audience.Department != "" {
+			query = query.Where("department = ?", audience.Department)
+		}
+
+		var audienceUserIDs []uint64
+		if err := query.Pluck("id", &audienceUserIDs).Error; err != nil {
+			return 0, err
+		}
+
+		for _, userID := range audienceUserIDs {
+			if !seen[userID] {
+				seen[userID] = true
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+
+	if content.Category == "" {
+		content.Category = models.NotificationCategoryForType(content.Type)
+	}
+	muted, err := mutedUserIDsInTx(tx, content.Category, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var notifications []models.Notification
+	for _, userID := range userIDs {
+		if !muted[userID] {
+			notifications = append(notifications, newNotification(userID, content, now))
+		}
+	}
+
+	if len(notifications) == 0 {
+		return 0, nil
+	}
+	if err := tx.Create(&notifications).Error; err != nil {
+		return 0, err
+	}
+	return len(notifications), nil
+}
+
+// NotifyHandoverInTx mengirim notifikasi serah terima PO ke unit yang mengerjakan stage tujuan,
+// PO yang selesai (stage COMPLETED) dikirim ke PPIC sebagai notifikasi SUCCESS
+func NotifyHandoverInTx(tx *gorm.DB, poID uint64, toStage models.POStage, title, message string) error {
+	audience, ok := handoverAudiences[toStage]
+	if !ok {
+		return nil
+	}
+
+	notifType := models.NotificationInfo
+	if toStage == models.StageCompleted {
+		notifType = models.NotificationSuccess
+	}
+	_, err := NotifyAudiencesInTx(tx, NotificationContent{
+		Title:    title,
+		Message:  message,
+		Type:     notifType,
+		Category: models.NotificationCategoryHandover,
+		POID:     poID,
+		Stage:    toStage,
+	}, audience)
+	return err
+}
+
+// NotifyEscalationInTx mengirim notifikasi WARNING ke manager departemen yang mengerjakan stage dan ke PPIC,
+// dipakai saat finalize menghasilkan defect, waste, atau variance di atas threshold
+func NotifyEscalationInTx(tx *gorm.DB, poID uint64, stage models.POStage, title, message string) error {
+	audiences := []NotificationAudience{AudiencePPIC}
+	if department, ok := stageDepartments[stage]; ok {
+		audiences = append(audiences, NotificationAudience{
+			Roles:      []models.UserRole{models.RoleManager},
+			Department: department,
+		})
+	}
+
+	_, err := NotifyAudiencesInTx(tx, NotificationContent{
+		Title:    title,
+		Message:  message,
+		Type:     models.NotificationWarning,
+		Category: models.NotificationCategoryAlert,
+		POID:     poID,
+		Stage:    stage,
+	}, audiences...)
+	return err
+}
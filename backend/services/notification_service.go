@@ -1,12 +1,16 @@
 package services
 
 import (
+	"context"
+	"log"
 	"sirine-go/backend/models"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+const notificationCleanupBatch = 500
+
 // NotificationService merupakan service untuk management notifikasi
 // yang bertujuan untuk handling in-app notifications kepada users
 type NotificationService struct {
@@ -18,15 +22,31 @@ func NewNotificationService(db *gorm.DB) *NotificationService {
 	return &NotificationService{db: db}
 }
 
-// GetUserNotifications mengambil semua notifikasi user
-// dengan optional filter untuk unread only
-func (s *NotificationService) GetUserNotifications(userID uint64, unreadOnly bool) ([]models.Notification, error) {
+// NotificationCleanupResult merupakan ringkasan hasil cleanup job notifikasi
+type NotificationCleanupResult struct {
+	DeletedExpired int `json:"deleted_expired"`
+	DeletedRead    int `json:"deleted_read"`
+}
+
+// activeNotifications membatasi query ke notifikasi milik user yang belum kedaluwarsa
+func (s *NotificationService) activeNotifications(userID uint64) *gorm.DB {
+	return s.db.Model(&models.Notification{}).
+		Where("user_id = ?", userID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now())
+}
+
+// GetUserNotifications mengambil semua notifikasi user yang belum kedaluwarsa
+// dengan optional filter untuk unread only dan kategori
+func (s *NotificationService) GetUserNotifications(userID uint64, unreadOnly bool, category models.NotificationCategory) ([]models.Notification, error) {
 	var notifications []models.Notification
-	query := s.db.Where("user_id = ?", userID)
+	query := s.activeNotifications(userID)
 
 	if unreadOnly {
 		query = query.Where("is_read = ?", false)
 	}
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
 
 	err := query.Order("created_at DESC").Find(&notifications).Error
 	if err != nil {
@@ -40,8 +60,8 @@ func (s *NotificationService) GetUserNotifications(userID uint64, unreadOnly boo
 // untuk badge display di notification bell
 func (s *NotificationService) GetUnreadCount(userID uint64) (int64, error) {
 	var count int64
-	err := s.db.Model(&models.Notification{}).
-		Where("user_id = ? AND is_read = ?", userID, false).
+	err := s.activeNotifications(userID).
+		Where("is_read = ?", false).
 		Count(&count).Error
 
 	if err != nil {
@@ -86,29 +106,47 @@ func (s *NotificationService) MarkAllAsRead(userID uint64) error {
 }
 
 // CreateNotification membuat notifikasi baru untuk user
-// dengan type yang ditentukan (INFO, SUCCESS, WARNING, ERROR, ACHIEVEMENT)
+// dengan type yang ditentukan (INFO, SUCCESS, WARNING, ERROR, ACHIEVEMENT),
+// mengembalikan nil tanpa error jika user me-mute kategori notifikasi tersebut
 func (s *NotificationService) CreateNotification(userID uint64, title, message string, notifType models.NotificationType) (*models.Notification, error) {
-	notification := &models.Notification{
-		UserID:  userID,
+	notification := newNotification(userID, NotificationContent{
 		Title:   title,
 		Message: message,
 		Type:    notifType,
-		IsRead:  false,
-	}
+	}, time.Now())
 
-	err := s.db.Create(notification).Error
+	muted, err := mutedUserIDsInTx(s.db, notification.Category, []uint64{userID})
 	if err != nil {
 		return nil, err
 	}
+	if muted[userID] {
+		return nil, nil
+	}
+
+	if err := s.db.Create(&notification).Error; err != nil {
+		return nil, err
+	}
+
+	return &notification, nil
+}
 
-	return notification, nil
+// NotifyAudiences membuat notifikasi untuk semua user aktif dengan role dan departemen tertentu
+// dan mengembalikan jumlah penerima
+func (s *NotificationService) NotifyAudiences(content NotificationContent, audiences ...NotificationAudience) (int, error) {
+	var count int
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		var err error
+		count, err = NotifyAudiencesInTx(tx, content, audiences...)
+		return err
+	})
+	return count, err
 }
 
 // GetRecentNotifications mengambil N notifikasi terbaru
 // untuk quick preview di notification bell dropdown
 func (s *NotificationService) GetRecentNotifications(userID uint64, limit int) ([]models.Notification, error) {
 	var notifications []models.Notification
-	err := s.db.Where("user_id = ?", userID).
+	err := s.activeNotifications(userID).
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&notifications).Error
@@ -136,3 +174,77 @@ func (s *NotificationService) DeleteNotification(notificationID uint64, userID u
 
 	return nil
 }
+
+// StartCleanupJob menjalankan cleanup notifikasi kedaluwarsa dan notifikasi lama yang sudah dibaca
+// secara periodik di background sampai context dibatalkan
+func (s *NotificationService) StartCleanupJob(ctx context.Context, interval, readRetention time.Duration) {
+	if interval <= 0 || readRetention <= 0 {
+		log.Println("Notification: cleanup job tidak dijalankan")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			result, err := s.CleanupNotifications(time.Now(), readRetention)
+			if err != nil {
+				log.Printf("Notification: gagal membersihkan notifikasi: %v", err)
+			} else if result.DeletedExpired > 0 || result.DeletedRead > 0 {
+				log.Printf("Notification: %d notifikasi kedaluwarsa dan %d notifikasi lama yang sudah dibaca dihapus", result.DeletedExpired, result.DeletedRead)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("Notification cleanup job berjalan (interval %s, retention dibaca %s)", interval, readRetention)
+}
+
+// CleanupNotifications menghapus notifikasi yang sudah kedaluwarsa serta notifikasi yang sudah dibaca
+// lebih lama dari readRetention, notifikasi belum dibaca yang belum kedaluwarsa tidak pernah dihapus
+func (s *NotificationService) CleanupNotifications(now time.Time, readRetention time.Duration) (*NotificationCleanupResult, error) {
+	result := &NotificationCleanupResult{}
+
+	expired, err := s.deleteNotificationsInBatches("expires_at IS NOT NULL AND expires_at <= ?", now)
+	result.DeletedExpired = expired
+	if err != nil {
+		return result, err
+	}
+
+	read, err := s.deleteNotificationsInBatches("is_read = ? AND read_at < ?", true, now.Add(-readRetention))
+	result.DeletedRead = read
+	return result, err
+}
+
+// deleteNotificationsInBatches menghapus notifikasi yang cocok dengan kondisi per batch
+// agar tidak mengunci tabel notifications terlalu lama
+func (s *NotificationService) deleteNotificationsInBatches(condition string, args ...interface{}) (int, error) {
+	deleted := 0
+	for {
+		var ids []uint64
+		if err := s.db.Model(&models.Notification{}).
+			Where(condition, args...).
+			Order("id ASC").
+			Limit(notificationCleanupBatch).
+			Pluck("id", &ids).Error; err != nil {
+			return deleted, err
+		}
+		if len(ids) == 0 {
+			return deleted, nil
+		}
+
+		result := s.db.Where("id IN ?", ids).Delete(&models.Notification{})
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		deleted += int(result.RowsAffected)
+
+		if len(ids) < notificationCleanupBatch {
+			return deleted, nil
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sirine-go/backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk riwayat dan rollback import batch
+var (
+	ErrImportBatchNotFound            = errors.New("import batch tidak ditemukan")
+	ErrImportBatchRolledBack          = errors.New("import batch sudah di-rollback sebelumnya")
+	ErrImportBatchSuperseded          = errors.New("OBC sudah diubah oleh import batch yang lebih baru, rollback batch tersebut terlebih dulu")
+	ErrImportBatchMaterialPrepStarted = errors.New("import batch tidak bisa di-rollback karena PO sudah mulai material prep")
+	ErrRollbackReasonRequired         = errors.New("alasan rollback wajib diisi")
+)
+
+// OBCImportBatchService merupakan service untuk riwayat import OBC
+// yang mencakup list batch, detail hasil per row, dan rollback batch
+type OBCImportBatchService struct {
+	db *gorm.DB
+}
+
+// NewOBCImportBatchService membuat instance baru dari OBCImportBatchService
+func NewOBCImportBatchService(db *gorm.DB) *OBCImportBatchService {
+	return &OBCImportBatchService{db: db}
+}
+
+// ImportBatchFilters merupakan struct untuk filter dan pagination list import batch
+type ImportBatchFilters struct {
+	Status    string `form:"status"`
+	Source    string `form:"source"`
+	FileHash  string `form:"file_hash"`
+	CreatedBy uint64 `form:"created_by"`
+	Page      int    `form:"page"`
+	PerPage   int    `form:"per_page"`
+}
+
+// ImportBatchListResponse merupakan struct untuk paginated list import batch
+type ImportBatchListResponse struct {
+	Items      []models.OBCImportBatch `json:"items"`
+	Total      int                     `json:"total"`
+	Page       int                     `json:"page"`
+	PerPage    int                     `json:"per_page"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+// ImportBatchRollbackResult merupakan ringkasan perubahan yang di-revert oleh rollback
+type ImportBatchRollbackResult struct {
+	Batch            models.OBCImportBatch `json:"batch"`
+	OBCRestored      int                   `json:"obc_restored"`
+	OBCDeleted       int                   `json:"obc_deleted"`
+	POsDeleted       int                   `json:"pos_deleted"`
+	DeletedPONumbers []int64               `json:"deleted_po_numbers"`
+}
+
+// ListBatches mengambil riwayat import batch dengan filter dan pagination, terbaru di atas
+func (s *OBCImportBatchService) ListBatches(filters ImportBatchFilters) (*ImportBatchListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.OBCImportBatch{})
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.Source != "" {
+		query = query.Where("source = ?", filters.Source)
+	}
+	if filters.FileHash != "" {
+		query = query.Where("file_hash = ?", strings.ToLower(filters.FileHash))
+	}
+	if filters.CreatedBy > 0 {
+		query = query.Where("created_by = ?", filters.CreatedBy)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	batches := make([]models.OBCImportBatch, 0)
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&batches).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &ImportBatchListResponse{
+		Items:      batches,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetBatch mengambil detail import batch beserta hasil per row
+func (s *OBCImportBatchService) GetBatch(id uint64) (*models.OBCImportBatch, error) {
+	var batch models.OBCImportBatch
+	err := s.db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("row_number ASC")
+		}).
+		First(&batch, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImportBatchNotFound
+		}
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// RollbackBatch me-revert OBC Master yang diubah oleh batch dan soft-delete PO yang di-generate,
+// rollback ditolak jika ada PO yang sudah mulai material prep atau OBC sudah diubah batch yang lebih baru
+func (s *OBCImportBatchService) RollbackBatch(id uint64, userID uint64, reason string) (*ImportBatchRollbackResult, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrRollbackReasonRequired
+	}
+
+	result := &ImportBatchRollbackResult{DeletedPONumbers: make([]int64, 0)}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var batch models.OBCImportBatch
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&batch, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrImportBatchNotFound
+			}
+			return err
+		}
+		if batch.IsRolledBack() {
+			return ErrImportBatchRolledBack
+		}
+
+		var items []models.OBCImportBatchItem
+		if err := tx.Where("batch_id = ? AND outcome IN ?", batch.ID,
+			[]models.OBCImportItemOutcome{models.ImportItemInserted, models.ImportItemUpdated, models.ImportItemUnchanged}).
+			Order("row_number ASC").
+			Find(&items).Error; err != nil {
+			return err
+		}
+
+		if err := s.checkSuperseded(tx, batch.ID, items); err != nil {
+			return err
+		}
+
+		pos, err := s.collectRollbackPOs(tx, items)
+		if err != nil {
+			return err
+		}
+		if err := s.checkMaterialPrep(tx, pos); err != nil {
+			return err
+		}
+
+		// Soft-delete PO beserta material prep yang belum dimulai
+		if len(pos) > 0 {
+			poIDs := make([]uint64, len(pos))
+			for i, po := range pos {
+				poIDs[i] = po.ID
+				result.DeletedPONumbers = append(result.DeletedPONumbers, po.PONumber)
+			}
+			if err := tx.Where("production_order_id IN ?", poIDs).Delete(&models.KhazwalMaterialPreparation{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&models.ProductionOrder{}, poIDs).Error; err != nil {
+				return err
+			}
+			for _, po := range pos {
+				description := fmt.Sprintf("PO %d dihapus karena rollback import batch #%d: %s", po.PONumber, batch.ID, reason)
+				if err := logImportRollbackAudit(tx, userID, "production_orders", po.ID, &po.ID, description, po); err != nil {
+					return err
+				}
+			}
+			result.POsDeleted = len(pos)
+		}
+
+		// Revert OBC Master: restore snapshot untuk UPDATED, soft-delete untuk INSERTED
+		for _, item := range items {
+			if item.OBCMasterID == nil {
+				continue
+			}
+			switch item.Outcome {
+			case models.ImportItemUpdated:
+				var previous models.OBCMaster
+				if err := json.Unmarshal(item.PreviousData, &previous); err != nil {
+					return fmt.Errorf("gagal membaca snapshot OBC %s: %w", item.OBCNumber, err)
+				}
+				previous.ID = *item.OBCMasterID
+				if err := tx.Save(&previous).Error; err != nil {
+					return err
+				}
+				result.OBCRestored++
+			case models.ImportItemInserted:
+				if err := tx.Delete(&models.OBCMaster{}, *item.OBCMasterID).Error; err != nil {
+					return err
+				}
+				result.OBCDeleted++
+			}
+		}
+
+		now := time.Now()
+		if err := tx.Model(&batch).Updates(map[string]interface{}{
+			"status":          models.ImportBatchRolledBack,
+			"rolled_back_by":  userID,
+			"rolled_back_at":  now,
+			"rollback_reason": reason,
+		}).Error; err != nil {
+			return err
+		}
+
+		description := fmt.Sprintf("Rollback import batch #%d (%s): %d OBC di-restore, %d OBC dihapus, %d PO dihapus",
+			batch.ID, batch.FileName, result.OBCRestored, result.OBCDeleted, result.POsDeleted)
+		if err := logImportRollbackAudit(tx, userID, "obc_import_batches", batch.ID, nil, description, reason); err != nil {
+			return err
+		}
+
+		result.Batch = batch
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkSuperseded memastikan OBC yang diubah batch tidak diubah lagi oleh batch lain yang lebih baru
+func (s *OBCImportBatchService) checkSuperseded(tx *gorm.DB, batchID uint64, items []models.OBCImportBatchItem) error {
+	masterIDs := make([]uint64, 0, len(items))
+	for _, item := range items {
+		if item.IsApplied() && item.OBCMasterID != nil {
+			masterIDs = append(masterIDs, *item.OBCMasterID)
+		}
+	}
+	if len(masterIDs) == 0 {
+		return nil
+	}
+
+	var newer struct {
+		BatchID   uint64
+		OBCNumber string
+	}
+	err := tx.Table("obc_import_batch_items AS i").
+		Select("i.batch_id, i.obc_number").
+		Joins("JOIN obc_import_batches AS b ON b.id = i.batch_id").
+		Where("i.obc_master_id IN ? AND i.batch_id > ? AND b.status = ? AND i.outcome IN ?",
+			masterIDs, batchID, models.ImportBatchApplied,
+			[]models.OBCImportItemOutcome{models.ImportItemInserted, models.ImportItemUpdated}).
+		Order("i.batch_id ASC").
+		Limit(1).
+		Scan(&newer).Error
+	if err != nil {
+		return err
+	}
+	if newer.BatchID != 0 {
+		return fmt.Errorf("%w: OBC %s (batch #%d)", ErrImportBatchSuperseded, newer.OBCNumber, newer.BatchID)
+	}
+	return nil
+}
+
+// collectRollbackPOs mengumpulkan PO aktif yang harus dihapus saat rollback,
+// yaitu PO yang di-generate batch beserta turunannya (hasil split/merge)
+// dan seluruh PO milik OBC yang dibuat oleh batch
+func (s *OBCImportBatchService) collectRollbackPOs(tx *gorm.DB, items []models.OBCImportBatchItem) ([]models.ProductionOrder, error) {
+	poIDs := make([]uint64, 0)
+	insertedMasterIDs := make([]uint64, 0)
+	for _, item := range items {
+		ids, err := item.POIDs()
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca PO hasil import OBC %s: %w", item.OBCNumber, err)
+		}
+		poIDs = append(poIDs, ids...)
+		if item.Outcome == models.ImportItemInserted && item.OBCMasterID != nil {
+			insertedMasterIDs = append(insertedMasterIDs, *item.OBCMasterID)
+		}
+	}
+
+	seen := make(map[uint64]bool)
+	pos := make([]models.ProductionOrder, 0)
+	appendPOs := func(found []models.ProductionOrder) []uint64 {
+		added := make([]uint64, 0)
+		for _, po := range found {
+			if seen[po.ID] {
+				continue
+			}
+			seen[po.ID] = true
+			pos = append(pos, po)
+			added = append(added, po.ID)
+		}
+		return added
+	}
+
+	// PO milik OBC baru ikut dihapus karena OBC-nya di-soft delete
+	if len(insertedMasterIDs) > 0 {
+		var owned []models.ProductionOrder
+		if err := tx.Where("obc_master_id IN ?", insertedMasterIDs).Find(&owned).Error; err != nil {
+			return nil, err
+		}
+		appendPOs(owned)
+	}
+
+	// Frontier berisi PO yang belum ditelusuri turunannya, termasuk PO asal yang sudah di-soft delete
+	frontier := poIDs
+	if len(poIDs) > 0 {
+		var generated []models.ProductionOrder
+		if err := tx.Where("id IN ?", poIDs).Find(&generated).Error; err != nil {
+			return nil, err
+		}
+		appendPOs(generated)
+	}
+	for _, po := range pos {
+		frontier = append(frontier, po.ID)
+	}
+
+	for len(frontier) > 0 {
+		var derived []models.ProductionOrder
+		mergedTargets := tx.Unscoped().Model(&models.ProductionOrder{}).
+			Select("merged_into_po_id").
+			Where("id IN ? AND merged_into_po_id IS NOT NULL", frontier)
+		if err := tx.Where("parent_po_id IN ? OR id IN (?)", frontier, mergedTargets).Find(&derived).Error; err != nil {
+			return nil, err
+		}
+		frontier = appendPOs(derived)
+	}
+
+	return pos, nil
+}
+
+// checkMaterialPrep memastikan belum ada PO yang mulai material prep
+func (s *OBCImportBatchService) checkMaterialPrep(tx *gorm.DB, pos []models.ProductionOrder) error {
+	if len(pos) == 0 {
+		return nil
+	}
+
+	poIDs := make([]uint64, len(pos))
+	for i := range pos {
+		if pos[i].IsMaterialPrepStarted() {
+			return fmt.Errorf("%w: PO %d berstatus %s", ErrImportBatchMaterialPrepStarted, pos[i].PONumber, pos[i].CurrentStatus)
+		}
+		poIDs[i] = pos[i].ID
+	}
+
+	var started models.KhazwalMaterialPreparation
+	err := tx.Where("production_order_id IN ? AND status <> ?", poIDs, models.MaterialPrepPending).
+		First(&started).Error
+	if err == nil {
+		for _, po := range pos {
+			if po.ID == started.ProductionOrderID {
+				return fmt.Errorf("%w: PO %d", ErrImportBatchMaterialPrepStarted, po.PONumber)
+			}
+		}
+		return ErrImportBatchMaterialPrepStarted
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// logImportRollbackAudit mencatat activity log untuk rollback import batch
+func logImportRollbackAudit(tx *gorm.DB, userID uint64, entityType string, entityID uint64, poID *uint64, description string, before interface{}) error {
+	changes, err := json.Marshal(models.ChangeData{Before: before})
+	if err != nil {
+		return err
+	}
+
+	log := models.ActivityLog{
+		UserID:            userID,
+		Action:            models.ActionImportRollback,
+		EntityType:        entityType,
+		EntityID:          &entityID,
+		ProductionOrderID: poID,
+		Description:       description,
+		Changes:           changes,
+	}
+	return tx.Create(&log).Error
+}
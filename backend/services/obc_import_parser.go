@@ -0,0 +1,210 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ImportFileFormat merupakan enum untuk format file sumber import OBC
+type ImportFileFormat string
+
+const (
+	ImportFormatXLSX      ImportFileFormat = "XLSX"
+	ImportFormatCSV       ImportFileFormat = "CSV"
+	ImportFormatTSV       ImportFileFormat = "TSV"
+	ImportFormatSemicolon ImportFileFormat = "SEMICOLON"
+)
+
+// ErrUnsupportedImportFormat merupakan error untuk file yang bukan Excel maupun text berdelimiter
+var ErrUnsupportedImportFormat = errors.New("format file tidak didukung, gunakan .xlsx, .csv, .tsv, atau text export SAP")
+
+// xlsxSignature merupakan magic bytes file zip (xlsx)
+var xlsxSignature = []byte("PK\x03\x04")
+
+// utf8BOM merupakan byte order mark yang sering ada di export SAP/Excel
+var utf8BOM = []byte("\xEF\xBB\xBF")
+
+// defaultHeaderAliases merupakan alias header bawaan yang sering muncul di export SAP,
+// key adalah alias dan value adalah header kanonik di excelColumnMapping
+var defaultHeaderAliases = map[string]string{
+	"OBC No":               "No OBC",
+	"Nomor OBC":            "No OBC",
+	"Tanggal OBC":          "Tgl OBC",
+	"Qty":                  "QTY PESAN",
+	"Order Quantity":       "QTY PESAN",
+	"Tgl Jatuh Tempo":      "Tgl JTempo",
+	"Due Date":             "Tgl JTempo",
+	"Personalisasi":        "Perso / non Perso",
+	"Sales Document":       "Sales Doc.",
+	"Plant":                "Plnt",
+	"Base Unit":            "BUn",
+	"Base Unit of Measure": "BUn",
+}
+
+// DetectImportFormat menentukan format file dari magic bytes, ekstensi, dan delimiter di baris header
+func DetectImportFormat(fileName string, content []byte) (ImportFileFormat, error) {
+	if bytes.HasPrefix(content, xlsxSignature) {
+		return ImportFormatXLSX, nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if ext == ".xlsx" || ext == ".xls" {
+		return "", ErrUnsupportedImportFormat
+	}
+	if ext == ".tsv" {
+		return ImportFormatTSV, nil
+	}
+
+	headerLine := bytes.TrimPrefix(content, utf8BOM)
+	if idx := bytes.IndexAny(headerLine, "\r\n"); idx >= 0 {
+		headerLine = headerLine[:idx]
+	}
+	if len(bytes.TrimSpace(headerLine)) == 0 {
+		return "", ErrUnsupportedImportFormat
+	}
+
+	// Delimiter dengan jumlah kemunculan terbanyak di header dianggap delimiter file
+	counts := map[ImportFileFormat]int{
+		ImportFormatCSV:       bytes.Count(headerLine, []byte(",")),
+		ImportFormatTSV:       bytes.Count(headerLine, []byte("\t")),
+		ImportFormatSemicolon: bytes.Count(headerLine, []byte(";")),
+	}
+	detected := ImportFormatCSV
+	for _, format := range []ImportFileFormat{ImportFormatTSV, ImportFormatSemicolon} {
+		if counts[format] > counts[detected] {
+			detected = format
+		}
+	}
+	if counts[detected] == 0 && ext != ".csv" && ext != ".txt" {
+		return "", ErrUnsupportedImportFormat
+	}
+	return detected, nil
+}
+
+// readTabularRows membaca seluruh rows dari file import (xlsx sheet pertama atau text berdelimiter)
+func readTabularRows(fileReader io.Reader, fileName string) ([][]string, ImportFileFormat, error) {
+	content, err := io.ReadAll(fileReader)
+	if err != nil {
+		return nil, "", fmt.Errorf("gagal membaca file: %w", err)
+	}
+
+	format, err := DetectImportFormat(fileName, content)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var rows [][]string
+	if format == ImportFormatXLSX {
+		rows, err = readXLSXRows(content)
+	} else {
+		rows, err = readDelimitedRows(content, format)
+	}
+	if err != nil {
+		return nil, format, err
+	}
+	return rows, format, nil
+}
+
+// readXLSXRows membaca rows dari sheet pertama file Excel
+func readXLSXRows(content []byte) ([][]string, error) {
+	xlsxFile, err := excelize.OpenReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca Excel file: %w", err)
+	}
+	defer xlsxFile.Close()
+
+	sheets := xlsxFile.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("Excel file tidak memiliki sheet")
+	}
+
+	rows, err := xlsxFile.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca rows dari sheet: %w", err)
+	}
+	return rows, nil
+}
+
+// readDelimitedRows membaca rows dari file CSV/TSV/semicolon dan melewati baris kosong
+func readDelimitedRows(content []byte, format ImportFileFormat) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, utf8BOM)))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	switch format {
+	case ImportFormatTSV:
+		reader.Comma = '\t'
+	case ImportFormatSemicolon:
+		reader.Comma = ';'
+	default:
+		reader.Comma = ','
+	}
+
+	rows := make([][]string, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca file %s: %w", strings.ToLower(string(format)), err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// isBlankRecord memeriksa apakah semua kolom record kosong
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseHeaderAliases mem-parsing konfigurasi alias header dengan format "Alias=Header Kanonik;Alias2=Header2"
+// dan menggabungkannya dengan alias bawaan, alias dari konfigurasi menimpa alias bawaan
+func ParseHeaderAliases(raw string) map[string]string {
+	aliases := make(map[string]string, len(defaultHeaderAliases))
+	for alias, canonical := range defaultHeaderAliases {
+		aliases[alias] = canonical
+	}
+
+	for _, pair := range strings.Split(raw, ";") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		alias := strings.TrimSpace(parts[0])
+		canonical := strings.TrimSpace(parts[1])
+		if alias == "" || canonical == "" {
+			continue
+		}
+		aliases[alias] = canonical
+	}
+	return aliases
+}
+
+// hashImportFile menghitung SHA-256 isi file import untuk riwayat import batch
+func hashImportFile(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeHeader menyamakan header agar perbandingan tidak sensitif terhadap huruf besar dan spasi
+func normalizeHeader(header string) string {
+	return strings.ToLower(strings.Join(strings.Fields(header), " "))
+}
@@ -1,6 +1,10 @@
 package services
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,30 +14,59 @@ import (
 	"strings"
 	"time"
 
-	"github.com/xuri/excelize/v2"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// OBCImportService merupakan service untuk import data OBC Master dari Excel
+// Error untuk preview dan commit import OBC
+var (
+	ErrImportPreviewNotFound  = errors.New("token preview import tidak ditemukan")
+	ErrImportPreviewExpired   = errors.New("token preview import sudah kedaluwarsa, silakan upload ulang")
+	ErrImportPreviewCommitted = errors.New("preview import sudah di-commit sebelumnya")
+)
+
+// OBCImportService merupakan service untuk import data OBC Master dari Excel, CSV, TSV, dan text export SAP
 // yang mencakup parsing, validation, dan upsert logic
 type OBCImportService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	poNumbers     *PONumberGenerator
+	headerAliases map[string]string
 }
 
-// NewOBCImportService membuat instance baru dari OBCImportService
-func NewOBCImportService(db *gorm.DB) *OBCImportService {
-	return &OBCImportService{db: db}
+// NewOBCImportService membuat instance baru dari OBCImportService,
+// headerAliases memetakan header alternatif ke header kanonik di excelColumnMapping
+func NewOBCImportService(db *gorm.DB, poNumbers *PONumberGenerator, headerAliases map[string]string) *OBCImportService {
+	return &OBCImportService{db: db, poNumbers: poNumbers, headerAliases: headerAliases}
 }
 
-// ImportResult merupakan struktur hasil dari import Excel
-// yang mencakup summary dan detail errors
+// ImportResult merupakan struktur hasil dari import file OBC
+// yang mencakup summary dan detail errors, sama untuk semua format sumber
 type ImportResult struct {
-	TotalRows     int         `json:"total_rows"`
-	SuccessCount  int         `json:"success_count"`
-	FailedCount   int         `json:"failed_count"`
-	FailedRows    []FailedRow `json:"failed_rows"`
-	POsGenerated  int         `json:"pos_generated"`
-	DurationMs    int64       `json:"duration_ms"`
+	SourceFormat  ImportFileFormat `json:"source_format"`
+	BatchID       uint64           `json:"batch_id"`
+	TotalRows     int              `json:"total_rows"`
+	SuccessCount  int              `json:"success_count"`
+	FailedCount   int              `json:"failed_count"`
+	FailedRows    []FailedRow      `json:"failed_rows"`
+	POsGenerated  int              `json:"pos_generated"`
+	DurationMs    int64            `json:"duration_ms"`
+}
+
+// ImportPreviewResult merupakan struktur hasil dry-run import
+// yang berisi token commit, ringkasan action, dan detail per row
+type ImportPreviewResult struct {
+	SourceFormat   ImportFileFormat             `json:"source_format"`
+	Token          string                       `json:"token"`
+	ExpiresAt      time.Time                    `json:"expires_at"`
+	FileName       string                       `json:"file_name"`
+	AutoGeneratePO bool                         `json:"auto_generate_po"`
+	TotalRows      int                          `json:"total_rows"`
+	InsertCount    int                          `json:"insert_count"`
+	UpdateCount    int                          `json:"update_count"`
+	UnchangedCount int                          `json:"unchanged_count"`
+	RejectedCount  int                          `json:"rejected_count"`
+	Rows           []models.OBCImportPreviewRow `json:"rows"`
 }
 
 // FailedRow merupakan struktur untuk row yang gagal di-import
@@ -43,8 +76,8 @@ type FailedRow struct {
 	Error     string `json:"error"`
 }
 
-// excelColumnMapping merupakan mapping dari Excel column header ke field name OBCMaster
-// dimana key adalah header di Excel dan value adalah field name di struct
+// excelColumnMapping merupakan mapping dari column header file import ke field name OBCMaster
+// dimana key adalah header kanonik (Excel/CSV) dan value adalah field name di struct
 var excelColumnMapping = map[string]string{
 	"No OBC":                "OBCNumber",
 	"Tgl OBC":               "OBCDate",
@@ -84,33 +117,209 @@ var excelColumnMapping = map[string]string{
 	"Warna MMEA":            "MMEAColorCode",
 }
 
-// ImportFromExcel melakukan import data OBC dari Excel file
-// dengan parsing, validation, dan upsert logic dalam transaction
-func (s *OBCImportService) ImportFromExcel(fileReader io.Reader, autoGeneratePO bool) (*ImportResult, error) {
+// ImportFromFile melakukan import data OBC dari file Excel, CSV, TSV, atau text export SAP
+// dengan deteksi format otomatis, parsing, validation, dan upsert logic dalam transaction,
+// setiap import dicatat sebagai import batch agar bisa ditelusuri dan di-rollback
+func (s *OBCImportService) ImportFromFile(fileReader io.Reader, fileName string, autoGeneratePO bool, userID uint64) (*ImportResult, error) {
+	startTime := time.Now()
+
+	content, err := io.ReadAll(fileReader)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca file: %w", err)
+	}
+
+	rows, format, err := s.readImportRows(bytes.NewReader(content), fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := models.OBCImportBatch{
+		Source:         models.ImportBatchSourceUpload,
+		FileName:       fileName,
+		FileHash:       hashImportFile(content),
+		SourceFormat:   string(format),
+		AutoGeneratePO: autoGeneratePO,
+		TotalRows:      len(rows),
+		Status:         models.ImportBatchApplied,
+		CreatedBy:      userID,
+	}
+
+	var result *ImportResult
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&batch).Error; err != nil {
+			return err
+		}
+
+		var applyErr error
+		result, applyErr = s.applyRowsInTx(tx, batch.ID, rows, autoGeneratePO, false)
+		if applyErr != nil {
+			return applyErr
+		}
+		return updateImportBatchSummary(tx, batch.ID, result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("transaction error: %w", err)
+	}
+
+	result.SourceFormat = format
+	result.BatchID = batch.ID
+	result.DurationMs = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+// PreviewImport melakukan dry-run import file tanpa mengubah obc_masters,
+// setiap row diklasifikasikan INSERT/UPDATE/UNCHANGED/REJECTED beserta diff per field
+// dan hasilnya disimpan dengan token agar batch yang sama bisa di-commit kemudian
+func (s *OBCImportService) PreviewImport(fileReader io.Reader, fileName string, autoGeneratePO bool, userID uint64) (*ImportPreviewResult, error) {
+	content, err := io.ReadAll(fileReader)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca file: %w", err)
+	}
+
+	rows, format, err := s.readImportRows(bytes.NewReader(content), fileName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.classifyRows(rows); err != nil {
+		return nil, err
+	}
+
+	token, err := generateImportPreviewToken()
+	if err != nil {
+		return nil, err
+	}
+
+	preview := models.OBCImportPreview{
+		Token:          token,
+		FileName:       fileName,
+		FileHash:       hashImportFile(content),
+		SourceFormat:   string(format),
+		AutoGeneratePO: autoGeneratePO,
+		TotalRows:      len(rows),
+		Status:         models.ImportPreviewPending,
+		ExpiresAt:      time.Now().Add(models.OBCImportPreviewTTL),
+		CreatedBy:      userID,
+	}
+	for _, row := range rows {
+		switch row.Action {
+		case models.ImportRowInsert:
+			preview.InsertCount++
+		case models.ImportRowUpdate:
+			preview.UpdateCount++
+		case models.ImportRowUnchanged:
+			preview.UnchangedCount++
+		case models.ImportRowRejected:
+			preview.RejectedCount++
+		}
+	}
+
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyimpan hasil preview: %w", err)
+	}
+	preview.Rows = datatypes.JSON(payload)
+	if err := s.db.Create(&preview).Error; err != nil {
+		return nil, err
+	}
+
+	return newImportPreviewResult(&preview, rows), nil
+}
+
+// CommitPreview meng-commit batch yang sudah di-preview berdasarkan token,
+// row yang data existing-nya berubah setelah preview ditolak agar hasil commit sesuai preview
+func (s *OBCImportService) CommitPreview(token string, userID uint64) (*ImportResult, error) {
 	startTime := time.Now()
-	
-	// Baca Excel file
-	xlsxFile, err := excelize.OpenReader(fileReader)
+
+	var result *ImportResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var preview models.OBCImportPreview
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token = ?", token).
+			First(&preview).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrImportPreviewNotFound
+			}
+			return err
+		}
+		if preview.Status == models.ImportPreviewCommitted {
+			return ErrImportPreviewCommitted
+		}
+		if preview.IsExpired(time.Now()) {
+			return ErrImportPreviewExpired
+		}
+
+		var rows []models.OBCImportPreviewRow
+		if err := json.Unmarshal(preview.Rows, &rows); err != nil {
+			return fmt.Errorf("gagal membaca hasil preview: %w", err)
+		}
+
+		previewID := preview.ID
+		batch := models.OBCImportBatch{
+			Source:         models.ImportBatchSourcePreview,
+			FileName:       preview.FileName,
+			FileHash:       preview.FileHash,
+			SourceFormat:   preview.SourceFormat,
+			AutoGeneratePO: preview.AutoGeneratePO,
+			TotalRows:      len(rows),
+			Status:         models.ImportBatchApplied,
+			PreviewID:      &previewID,
+			CreatedBy:      userID,
+		}
+		if err := tx.Create(&batch).Error; err != nil {
+			return err
+		}
+
+		var applyErr error
+		result, applyErr = s.applyRowsInTx(tx, batch.ID, rows, preview.AutoGeneratePO, true)
+		if applyErr != nil {
+			return applyErr
+		}
+		if err := updateImportBatchSummary(tx, batch.ID, result); err != nil {
+			return err
+		}
+		result.SourceFormat = ImportFileFormat(preview.SourceFormat)
+		result.BatchID = batch.ID
+
+		now := time.Now()
+		return tx.Model(&preview).Updates(map[string]interface{}{
+			"status":       models.ImportPreviewCommitted,
+			"committed_by": userID,
+			"committed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.DurationMs = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+// BuildFailedRowsReport menyusun file import asli dengan kolom error untuk setiap row yang gagal,
+// row yang sudah diperbaiki dari report ini bisa langsung di-upload ulang
+func (s *OBCImportService) BuildFailedRowsReport(content []byte, fileName string, failedRows []FailedRow, opts ImportReportOptions) (*ImportReport, error) {
+	records, _, err := readTabularRows(bytes.NewReader(content), fileName)
 	if err != nil {
-		return nil, fmt.Errorf("gagal membaca Excel file: %w", err)
+		return nil, err
 	}
-	defer xlsxFile.Close()
 
-	// Ambil sheet pertama
-	sheets := xlsxFile.GetSheetList()
-	if len(sheets) == 0 {
-		return nil, errors.New("Excel file tidak memiliki sheet")
+	rowErrors := make(map[int]string, len(failedRows))
+	for _, failed := range failedRows {
+		rowErrors[failed.RowNumber] = failed.Error
 	}
-	sheetName := sheets[0]
+	return buildImportReport(records, rowErrors, fileName, opts)
+}
 
-	// Baca semua rows
-	rows, err := xlsxFile.GetRows(sheetName)
+// readImportRows membaca file import dengan deteksi format dan mem-parsing setiap row menjadi OBCMaster,
+// row yang gagal di-parse ditandai REJECTED beserta alasan error
+func (s *OBCImportService) readImportRows(fileReader io.Reader, fileName string) ([]models.OBCImportPreviewRow, ImportFileFormat, error) {
+	rows, format, err := readTabularRows(fileReader, fileName)
 	if err != nil {
-		return nil, fmt.Errorf("gagal membaca rows dari sheet: %w", err)
+		return nil, format, err
 	}
 
 	if len(rows) < 2 {
-		return nil, errors.New("Excel file harus memiliki minimal 2 rows (header + data)")
+		return nil, format, errors.New("file import harus memiliki minimal 2 rows (header + data)")
 	}
 
 	// Parse header untuk mendapatkan column index mapping
@@ -121,106 +330,289 @@ func (s *OBCImportService) ImportFromExcel(fileReader io.Reader, autoGeneratePO
 	requiredColumns := []string{"No OBC"}
 	for _, col := range requiredColumns {
 		if _, exists := columnIndexMap[col]; !exists {
-			return nil, fmt.Errorf("kolom required '%s' tidak ditemukan di Excel", col)
+			return nil, format, fmt.Errorf("kolom required '%s' tidak ditemukan di file import", col)
 		}
 	}
 
-	// Process rows dalam transaction
-	result := &ImportResult{
-		TotalRows:    len(rows) - 1, // exclude header
-		FailedRows:   make([]FailedRow, 0),
+	parsed := make([]models.OBCImportPreviewRow, 0, len(rows)-1)
+	for i := 1; i < len(rows); i++ {
+		rowData := rows[i]
+		row := models.OBCImportPreviewRow{
+			RowNumber: i + 1,
+			OBCNumber: s.getValueFromRow(rowData, columnIndexMap, "No OBC"),
+		}
+
+		// Parse row ke OBCMaster struct
+		obcMaster, err := s.parseRowToOBCMaster(rowData, columnIndexMap)
+		if err != nil {
+			row.Action = models.ImportRowRejected
+			row.Error = err.Error()
+		} else {
+			row.Data = obcMaster
+		}
+		parsed = append(parsed, row)
 	}
+	return parsed, format, nil
+}
 
-	posGenerated := 0
+// classifyRows membandingkan row hasil parsing dengan obc_masters existing
+// untuk menentukan action preview dan diff per field
+func (s *OBCImportService) classifyRows(rows []models.OBCImportPreviewRow) error {
+	for i := range rows {
+		row := &rows[i]
+		if row.Action == models.ImportRowRejected {
+			continue
+		}
 
-	// Start transaction
-	err = s.db.Transaction(func(tx *gorm.DB) error {
-		for i := 1; i < len(rows); i++ {
-			rowData := rows[i]
-			rowNumber := i + 1
+		var existingOBC models.OBCMaster
+		err := s.db.Where("obc_number = ?", row.Data.OBCNumber).First(&existingOBC).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			row.Action = models.ImportRowInsert
+			continue
+		}
+		if err != nil {
+			return err
+		}
 
-			// Parse row ke OBCMaster struct
-			obcMaster, err := s.parseRowToOBCMaster(rowData, columnIndexMap)
-			if err != nil {
-				result.FailedRows = append(result.FailedRows, FailedRow{
-					RowNumber: rowNumber,
-					OBCNumber: s.getValueFromRow(rowData, columnIndexMap, "No OBC"),
-					Error:     err.Error(),
-				})
-				result.FailedCount++
+		updatedAt := existingOBC.UpdatedAt
+		row.ExistingID = existingOBC.ID
+		row.ExistingUpdatedAt = &updatedAt
+		row.Changes = existingOBC.DiffFields(row.Data)
+		if len(row.Changes) == 0 {
+			row.Action = models.ImportRowUnchanged
+		} else {
+			row.Action = models.ImportRowUpdate
+		}
+	}
+	return nil
+}
+
+// applyRowsInTx melakukan upsert row ke obc_masters dalam transaction dan mencatat hasil per row ke import batch,
+// jika verifySnapshot aktif row dibandingkan dengan snapshot saat preview sebelum disimpan
+func (s *OBCImportService) applyRowsInTx(tx *gorm.DB, batchID uint64, rows []models.OBCImportPreviewRow, autoGeneratePO bool, verifySnapshot bool) (*ImportResult, error) {
+	result := &ImportResult{
+		TotalRows:  len(rows),
+		FailedRows: make([]FailedRow, 0),
+	}
+	items := make([]models.OBCImportBatchItem, 0, len(rows))
+
+	recordFailure := func(rowNumber int, obcNumber, message string) {
+		result.FailedRows = append(result.FailedRows, FailedRow{
+			RowNumber: rowNumber,
+			OBCNumber: obcNumber,
+			Error:     message,
+		})
+		result.FailedCount++
+		items = append(items, models.OBCImportBatchItem{
+			BatchID:   batchID,
+			RowNumber: rowNumber,
+			OBCNumber: obcNumber,
+			Outcome:   models.ImportItemFailed,
+			Error:     message,
+		})
+	}
+
+	for i, row := range rows {
+		if row.Action == models.ImportRowRejected {
+			recordFailure(row.RowNumber, row.OBCNumber, row.Error)
+			continue
+		}
+		obcMaster := row.Data
+
+		// Upsert logic: update jika OBC sudah ada, create jika baru,
+		// OBC yang sudah di-soft delete (misalnya karena rollback batch) dipulihkan dengan data baru
+		var existingOBC models.OBCMaster
+		findErr := tx.Unscoped().Where("obc_number = ?", obcMaster.OBCNumber).First(&existingOBC).Error
+
+		if findErr != nil && !errors.Is(findErr, gorm.ErrRecordNotFound) {
+			// Database error
+			recordFailure(row.RowNumber, obcMaster.OBCNumber, fmt.Sprintf("database error: %v", findErr))
+			continue
+		}
+		found := findErr == nil
+		active := found && !existingOBC.DeletedAt.Valid
+
+		if verifySnapshot {
+			if staleErr := checkPreviewSnapshot(row, active, existingOBC); staleErr != "" {
+				recordFailure(row.RowNumber, obcMaster.OBCNumber, staleErr)
 				continue
 			}
+		}
+
+		// Savepoint per row agar OBC yang sudah disimpan ikut dibatalkan jika generate PO gagal
+		savepoint := fmt.Sprintf("import_row_%d", i)
+		if autoGeneratePO {
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		item := models.OBCImportBatchItem{
+			BatchID:   batchID,
+			RowNumber: row.RowNumber,
+			OBCNumber: obcMaster.OBCNumber,
+			Outcome:   models.ImportItemInserted,
+		}
+		if verifySnapshot && row.Action == models.ImportRowUnchanged {
+			// Row tidak berubah sejak preview sehingga OBC tidak perlu disimpan ulang
+			item.Outcome = models.ImportItemUnchanged
+			obcMaster = &existingOBC
+		} else {
+			if active {
+				// Simpan snapshot sebelum update agar batch bisa di-rollback
+				item.Outcome = models.ImportItemUnchanged
+				if changes := existingOBC.DiffFields(obcMaster); len(changes) > 0 {
+					changesJSON, err := json.Marshal(changes)
+					if err != nil {
+						return nil, err
+					}
+					previousJSON, err := json.Marshal(existingOBC)
+					if err != nil {
+						return nil, err
+					}
+					item.Outcome = models.ImportItemUpdated
+					item.Changes = datatypes.JSON(changesJSON)
+					item.PreviousData = datatypes.JSON(previousJSON)
+				}
+			}
 
-			// Upsert logic: update jika OBC sudah ada, create jika baru
-			var existingOBC models.OBCMaster
-			findErr := tx.Where("obc_number = ?", obcMaster.OBCNumber).First(&existingOBC).Error
-			
-			if findErr == nil {
-				// Update existing
+			if found {
+				// Update existing (termasuk restore OBC yang sudah di-soft delete)
 				obcMaster.ID = existingOBC.ID
 				obcMaster.CreatedAt = existingOBC.CreatedAt
-				if err := tx.Save(&obcMaster).Error; err != nil {
-					result.FailedRows = append(result.FailedRows, FailedRow{
-						RowNumber: rowNumber,
-						OBCNumber: obcMaster.OBCNumber,
-						Error:     fmt.Sprintf("gagal update: %v", err),
-					})
-					result.FailedCount++
+				if err := tx.Unscoped().Save(obcMaster).Error; err != nil {
+					recordFailure(row.RowNumber, obcMaster.OBCNumber, fmt.Sprintf("gagal update: %v", err))
 					continue
 				}
-			} else if errors.Is(findErr, gorm.ErrRecordNotFound) {
+			} else {
 				// Create new
-				if err := tx.Create(&obcMaster).Error; err != nil {
-					result.FailedRows = append(result.FailedRows, FailedRow{
-						RowNumber: rowNumber,
-						OBCNumber: obcMaster.OBCNumber,
-						Error:     fmt.Sprintf("gagal create: %v", err),
-					})
-					result.FailedCount++
+				if err := tx.Create(obcMaster).Error; err != nil {
+					recordFailure(row.RowNumber, obcMaster.OBCNumber, fmt.Sprintf("gagal create: %v", err))
 					continue
 				}
-			} else {
-				// Database error
-				result.FailedRows = append(result.FailedRows, FailedRow{
-					RowNumber: rowNumber,
-					OBCNumber: obcMaster.OBCNumber,
-					Error:     fmt.Sprintf("database error: %v", findErr),
-				})
-				result.FailedCount++
-				continue
 			}
+		}
 
-			result.SuccessCount++
+		masterID := obcMaster.ID
+		item.OBCMasterID = &masterID
 
-			// Auto generate PO jika diminta
-			if autoGeneratePO && obcMaster.QuantityOrdered > 0 {
-				pos, err := s.generatePOsFromOBCInTx(tx, obcMaster.ID)
-				if err == nil {
-					posGenerated += len(pos)
+		// Auto generate PO jika diminta, langkah yang sama untuk import langsung dan commit preview
+		if autoGeneratePO && obcMaster.QuantityOrdered > 0 {
+			pos, err := s.generatePOsFromOBCInTx(tx, obcMaster.ID)
+			if err != nil {
+				if rollbackErr := tx.RollbackTo(savepoint).Error; rollbackErr != nil {
+					return nil, rollbackErr
 				}
+				recordFailure(row.RowNumber, obcMaster.OBCNumber, fmt.Sprintf("gagal generate PO: %v", err))
+				continue
+			}
+			result.POsGenerated += len(pos)
+
+			poIDs := make([]uint64, len(pos))
+			for i, po := range pos {
+				poIDs[i] = po.ID
+			}
+			poIDsJSON, err := json.Marshal(poIDs)
+			if err != nil {
+				return nil, err
 			}
+			item.GeneratedPOIDs = datatypes.JSON(poIDsJSON)
 		}
 
-		return nil
-	})
+		result.SuccessCount++
+		items = append(items, item)
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("transaction error: %w", err)
+	if len(items) > 0 {
+		if err := tx.CreateInBatches(&items, 100).Error; err != nil {
+			return nil, fmt.Errorf("gagal menyimpan riwayat import batch: %w", err)
+		}
 	}
+	return result, nil
+}
 
-	result.POsGenerated = posGenerated
-	result.DurationMs = time.Since(startTime).Milliseconds()
+// updateImportBatchSummary menyimpan ringkasan hasil import ke import batch
+func updateImportBatchSummary(tx *gorm.DB, batchID uint64, result *ImportResult) error {
+	return tx.Model(&models.OBCImportBatch{}).Where("id = ?", batchID).Updates(map[string]interface{}{
+		"success_count": gorm.Expr("success_count + ?", result.SuccessCount),
+		"failed_count":  gorm.Expr("failed_count + ?", result.FailedCount),
+		"pos_generated": gorm.Expr("pos_generated + ?", result.POsGenerated),
+	}).Error
+}
 
-	return result, nil
+// checkPreviewSnapshot memastikan data existing masih sama dengan saat preview dibuat
+func checkPreviewSnapshot(row models.OBCImportPreviewRow, exists bool, existingOBC models.OBCMaster) string {
+	if row.Action == models.ImportRowInsert {
+		if exists {
+			return "OBC sudah dibuat setelah preview, silakan preview ulang"
+		}
+		return ""
+	}
+	if !exists || existingOBC.ID != row.ExistingID ||
+		row.ExistingUpdatedAt == nil || !existingOBC.UpdatedAt.Equal(*row.ExistingUpdatedAt) {
+		return "data OBC berubah setelah preview, silakan preview ulang"
+	}
+	return ""
 }
 
-// buildColumnIndexMap membuat mapping dari column name ke column index
+// generateImportPreviewToken membuat token acak untuk preview import
+func generateImportPreviewToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("gagal generate token preview: %w", err)
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
+
+// newImportPreviewResult menyusun response preview tanpa payload data lengkap per row
+func newImportPreviewResult(preview *models.OBCImportPreview, rows []models.OBCImportPreviewRow) *ImportPreviewResult {
+	items := make([]models.OBCImportPreviewRow, len(rows))
+	for i, row := range rows {
+		row.Data = nil
+		items[i] = row
+	}
+
+	return &ImportPreviewResult{
+		SourceFormat:   ImportFileFormat(preview.SourceFormat),
+		Token:          preview.Token,
+		ExpiresAt:      preview.ExpiresAt,
+		FileName:       preview.FileName,
+		AutoGeneratePO: preview.AutoGeneratePO,
+		TotalRows:      preview.TotalRows,
+		InsertCount:    preview.InsertCount,
+		UpdateCount:    preview.UpdateCount,
+		UnchangedCount: preview.UnchangedCount,
+		RejectedCount:  preview.RejectedCount,
+		Rows:           items,
+	}
+}
+
+// buildColumnIndexMap membuat mapping dari header kanonik ke column index,
+// header dicocokkan tanpa memperhatikan huruf besar/spasi dan alias di-resolve ke header kanonik
 func (s *OBCImportService) buildColumnIndexMap(headerRow []string) map[string]int {
+	canonicalHeaders := make(map[string]string, len(excelColumnMapping)+len(s.headerAliases))
+	for header := range excelColumnMapping {
+		canonicalHeaders[normalizeHeader(header)] = header
+	}
+	for alias, canonical := range s.headerAliases {
+		if _, known := excelColumnMapping[canonical]; !known {
+			continue
+		}
+		if _, exists := canonicalHeaders[normalizeHeader(alias)]; !exists {
+			canonicalHeaders[normalizeHeader(alias)] = canonical
+		}
+	}
+
 	indexMap := make(map[string]int)
 	for i, header := range headerRow {
 		// Trim whitespace dan normalize
 		normalizedHeader := strings.TrimSpace(header)
-		indexMap[normalizedHeader] = i
+		if canonical, ok := canonicalHeaders[normalizeHeader(header)]; ok {
+			normalizedHeader = canonical
+		}
+		if _, exists := indexMap[normalizedHeader]; !exists {
+			indexMap[normalizedHeader] = i
+		}
 	}
 	return indexMap
 }
@@ -349,39 +741,67 @@ func (s *OBCImportService) parseRowToOBCMaster(rowData []string, columnIndexMap
 
 // parseInteger melakukan parsing string ke integer dengan handling berbagai format
 func (s *OBCImportService) parseInteger(val string) (int, error) {
-	// Remove thousands separator
-	val = strings.ReplaceAll(val, ",", "")
-	val = strings.ReplaceAll(val, ".", "")
-	val = strings.TrimSpace(val)
-	
-	if val == "" {
+	normalized := normalizeNumber(val, true)
+	if normalized == "" {
 		return 0, nil
 	}
-	
-	intVal, err := strconv.Atoi(val)
-	if err != nil {
-		return 0, fmt.Errorf("invalid integer: %s", val)
+
+	floatVal, err := strconv.ParseFloat(normalized, 64)
+	if err != nil || floatVal != math.Trunc(floatVal) {
+		return 0, fmt.Errorf("invalid integer: %s", strings.TrimSpace(val))
 	}
-	return intVal, nil
+	return int(floatVal), nil
 }
 
 // parseFloat melakukan parsing string ke float dengan handling berbagai format
 func (s *OBCImportService) parseFloat(val string) (float64, error) {
-	// Replace comma dengan dot untuk decimal separator
-	val = strings.ReplaceAll(val, ",", ".")
-	val = strings.TrimSpace(val)
-	
-	if val == "" {
+	normalized := normalizeNumber(val, false)
+	if normalized == "" {
 		return 0, nil
 	}
-	
-	floatVal, err := strconv.ParseFloat(val, 64)
+
+	floatVal, err := strconv.ParseFloat(normalized, 64)
 	if err != nil {
-		return 0, fmt.Errorf("invalid float: %s", val)
+		return 0, fmt.Errorf("invalid float: %s", strings.TrimSpace(val))
 	}
 	return floatVal, nil
 }
 
+// normalizeNumber mengubah angka berformat lokal (1.000,50 atau 1,000.50) ke format standar (1000.50),
+// separator terakhir dianggap desimal jika ada dua jenis separator, dan satu separator dengan 3 digit
+// di belakangnya dianggap ribuan jika preferThousands aktif (untuk kolom integer)
+func normalizeNumber(val string, preferThousands bool) string {
+	val = strings.ReplaceAll(strings.TrimSpace(val), " ", "")
+	if val == "" {
+		return ""
+	}
+
+	lastDot := strings.LastIndex(val, ".")
+	lastComma := strings.LastIndex(val, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastComma > lastDot {
+			val = strings.ReplaceAll(val, ".", "")
+			return strings.Replace(val, ",", ".", 1)
+		}
+		return strings.ReplaceAll(val, ",", "")
+	case lastDot < 0 && lastComma < 0:
+		return val
+	}
+
+	separator := "."
+	if lastComma >= 0 {
+		separator = ","
+	}
+	if strings.Count(val, separator) > 1 {
+		return strings.ReplaceAll(val, separator, "")
+	}
+	if preferThousands && len(val)-strings.Index(val, separator)-1 == 3 {
+		return strings.ReplaceAll(val, separator, "")
+	}
+	return strings.Replace(val, separator, ".", 1)
+}
+
 // parseDate melakukan parsing string atau Excel serial date ke time.Time
 func (s *OBCImportService) parseDate(val string) (*time.Time, error) {
 	val = strings.TrimSpace(val)
@@ -417,9 +837,19 @@ func (s *OBCImportService) parseDate(val string) (*time.Time, error) {
 }
 
 // GeneratePOsFromOBC melakukan generate Production Orders dari OBC Master
-// dengan formula: Total = QTY + (QTY * 6%), PO Count = CEIL(Total / 40000)
+// dengan formula: Total = QTY + (QTY * 6%), PO Count = CEIL(Total / 40000),
+// dijalankan dalam satu transaction agar lock sequence nomor PO bertahan sampai semua PO tersimpan
 func (s *OBCImportService) GeneratePOsFromOBC(obcID uint64) ([]models.ProductionOrder, error) {
-	return s.generatePOsFromOBCInTx(s.db, obcID)
+	var pos []models.ProductionOrder
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		var err error
+		pos, err = s.generatePOsFromOBCInTx(tx, obcID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pos, nil
 }
 
 // generatePOsFromOBCInTx melakukan generate PO dalam transaction context
@@ -434,30 +864,27 @@ func (s *OBCImportService) generatePOsFromOBCInTx(tx *gorm.DB, obcID uint64) ([]
 		return nil, errors.New("QuantityOrdered harus lebih dari 0")
 	}
 
-	// Calculate total dengan buffer 6%
-	totalWithBuffer := obc.CalculateTotalWithBuffer()
-	
-	// Calculate jumlah PO yang diperlukan (max 40000 per PO)
-	const maxQuantityPerPO = 40000
-	poCount := int(math.Ceil(float64(totalWithBuffer) / float64(maxQuantityPerPO)))
+	// Resolve production rule sesuai tipe produk dan personalisasi OBC
+	rule, err := resolveProductionRule(tx, obc.Type, obc.Personalization)
+	if err != nil {
+		return nil, err
+	}
 
-	// Generate PO number base (timestamp-based untuk uniqueness)
-	poNumberBase := time.Now().Unix()
+	// Calculate total dengan buffer dan bagi per PO sesuai rule
+	totalWithBuffer := rule.CalculateTotalWithBuffer(obc.QuantityOrdered)
+	quantities := rule.SplitQuantities(totalWithBuffer)
+	poCount := len(quantities)
+
+	// Alokasikan nomor PO dari sequence sesuai plant OBC
+	poNumbers, err := s.poNumbers.Next(tx, obc.PlantCode, poCount)
+	if err != nil {
+		return nil, fmt.Errorf("gagal generate nomor PO: %w", err)
+	}
 
 	pos := make([]models.ProductionOrder, 0, poCount)
 
 	// Create Production Orders
-	for i := 0; i < poCount; i++ {
-		// Calculate quantity untuk PO ini
-		remainingQty := totalWithBuffer - (i * maxQuantityPerPO)
-		poQty := maxQuantityPerPO
-		if remainingQty < maxQuantityPerPO {
-			poQty = remainingQty
-		}
-
-		// Generate unique PO Number
-		poNumber := poNumberBase + int64(i+1)
-
+	for i, poQty := range quantities {
 		// Set due date dari OBC atau 30 hari dari sekarang
 		dueDate := time.Now().AddDate(0, 0, 30)
 		if obc.DueDate != nil {
@@ -477,26 +904,33 @@ func (s *OBCImportService) generatePOsFromOBCInTx(tx *gorm.DB, obcID uint64) ([]
 			"pca_category":          obc.PCACategory,
 			"alcohol_percentage":    obc.AlcoholPercentage,
 		}
+		// Disimpan sebagai JSON karena driver SQL tidak dapat menulis map secara langsung
+		productSpecsJSON, err := json.Marshal(productSpecs)
+		if err != nil {
+			return nil, fmt.Errorf("gagal menyusun spesifikasi produk: %w", err)
+		}
 
 		po := models.ProductionOrder{
-			PONumber:                  poNumber,
+			PONumber:                  poNumbers[i],
 			OBCMasterID:               obc.ID,
 			// Denormalized fields dari OBCMaster
 			OBCNumber:                 obc.OBCNumber,
 			ProductName:               obc.MaterialDescription, // Gunakan MaterialDescription sebagai ProductName
 			SAPCustomerCode:           obc.ItemCode,             // Map ItemCode sebagai customer code untuk sementara
 			SAPProductCode:            obc.ItemCode,
-			ProductSpecifications:     productSpecs,
+			ProductSpecifications:     datatypes.JSON(productSpecsJSON),
 			// PO fields
 			QuantityOrdered:           poQty,
-			QuantityTargetLembarBesar: poQty, // Default sama dengan ordered
-			EstimatedRims:             int(math.Ceil(float64(poQty) / 1000.0)), // Estimasi rim
+			QuantityTargetLembarBesar: rule.CalculateTargetLembarBesar(poQty),
+			EstimatedRims:             rule.CalculateEstimatedRims(poQty),
 			OrderDate:                 time.Now(),
 			DueDate:                   dueDate,
 			Priority:                  models.PriorityNormal,
 			PriorityScore:             50,
 			CurrentStage:              models.StageKhazwalMaterialPrep,
 			CurrentStatus:             models.StatusWaitingMaterialPrep,
+			ProductionRuleID:          &rule.ID,
+			ProductionRuleVersion:     rule.Version,
 		}
 
 		// Calculate priority score
@@ -523,6 +957,12 @@ func (s *OBCImportService) GetOBCMasterByID(id uint64) (*models.OBCMaster, error
 	return &obc, nil
 }
 
+// ResolveProductionRule memilih production rule yang berlaku untuk OBC sesuai tipe produk dan personalisasi,
+// sama dengan rule yang dipakai saat generate PO
+func (s *OBCImportService) ResolveProductionRule(obc *models.OBCMaster) (*models.ProductionRule, error) {
+	return resolveProductionRule(s.db, obc.Type, obc.Personalization)
+}
+
 // ListOBCMasters mengambil list OBC Masters dengan pagination dan filter
 func (s *OBCImportService) ListOBCMasters(page, pageSize int, filters map[string]string) ([]models.OBCMaster, int64, error) {
 	var obcs []models.OBCMaster
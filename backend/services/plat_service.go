@@ -0,0 +1,493 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sirine-go/backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk plat registry operations
+var (
+	ErrPlatNotFound         = errors.New("plat tidak ditemukan")
+	ErrPlatNotRegistered    = errors.New("kode plat belum terdaftar di registry plat")
+	ErrPlatCodeExists       = errors.New("kode plat sudah terdaftar")
+	ErrPlatCheckedOut       = errors.New("plat sedang dipakai oleh PO lain")
+	ErrPlatRetired          = errors.New("plat sudah tidak digunakan (RETIRED)")
+	ErrPlatInMaintenance    = errors.New("plat sedang dalam maintenance")
+	ErrPlatNotCheckedOut    = errors.New("plat tidak sedang dipakai")
+	ErrPlatInUse            = errors.New("plat sedang dipakai, kembalikan plat terlebih dulu")
+	ErrInvalidPlatStatus    = errors.New("status plat harus AVAILABLE, MAINTENANCE, atau RETIRED")
+	ErrInvalidPlatCondition = errors.New("kondisi plat harus GOOD atau DAMAGED")
+	ErrInvalidImpressions   = errors.New("batas impression tidak boleh negatif")
+)
+
+// PlatService merupakan service untuk registry plat cetak
+// yang mencakup CRUD plat, status siklus hidup, pengembalian plat, dan riwayat pemakaian
+type PlatService struct {
+	db *gorm.DB
+}
+
+// NewPlatService membuat instance baru dari PlatService
+func NewPlatService(db *gorm.DB) *PlatService {
+	return &PlatService{
+		db: db,
+	}
+}
+
+// PlatFilters merupakan struct untuk filter dan pagination list plat
+type PlatFilters struct {
+	Status          string `form:"status"`
+	StorageLocation string `form:"storage_location"`
+	Search          string `form:"search"`
+	Page            int    `form:"page"`
+	PerPage         int    `form:"per_page"`
+}
+
+// PlatListResponse merupakan struct untuk paginated list plat
+type PlatListResponse struct {
+	Items      []models.Plat `json:"items"`
+	Total      int           `json:"total"`
+	Page       int           `json:"page"`
+	PerPage    int           `json:"per_page"`
+	TotalPages int           `json:"total_pages"`
+}
+
+// PlatCheckoutListResponse merupakan struct untuk paginated riwayat pemakaian plat
+type PlatCheckoutListResponse struct {
+	Items      []models.PlatCheckout `json:"items"`
+	Total      int                   `json:"total"`
+	Page       int                   `json:"page"`
+	PerPage    int                   `json:"per_page"`
+	TotalPages int                   `json:"total_pages"`
+}
+
+// CreatePlatRequest merupakan request untuk mendaftarkan plat baru
+type CreatePlatRequest struct {
+	Code            string `json:"code" binding:"required,max=50"`
+	Description     string `json:"description" binding:"max=255"`
+	StorageLocation string `json:"storage_location" binding:"max=100"`
+	MaxImpressions  int64  `json:"max_impressions"`
+	Notes           string `json:"notes"`
+}
+
+// UpdatePlatRequest merupakan request untuk update data plat
+type UpdatePlatRequest struct {
+	Description     *string `json:"description" binding:"omitempty,max=255"`
+	StorageLocation *string `json:"storage_location" binding:"omitempty,max=100"`
+	MaxImpressions  *int64  `json:"max_impressions"`
+	Notes           *string `json:"notes"`
+}
+
+// UpdatePlatStatusRequest merupakan request untuk mengubah status plat (maintenance, retire, atau available)
+type UpdatePlatStatusRequest struct {
+	Status models.PlatStatus `json:"status" binding:"required"`
+	Notes  string            `json:"notes"`
+}
+
+// ReturnPlatRequest merupakan request untuk mengembalikan plat ke penyimpanan
+type ReturnPlatRequest struct {
+	Condition       models.PlatReturnCondition `json:"condition" binding:"required"`
+	StorageLocation string                     `json:"storage_location" binding:"max=100"`
+	Notes           string                     `json:"notes"`
+}
+
+// ListPlats mengambil list plat dengan filter status, lokasi, search, dan pagination
+func (s *PlatService) ListPlats(filters PlatFilters) (*PlatListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.Plat{})
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.StorageLocation != "" {
+		query = query.Where("storage_location = ?", filters.StorageLocation)
+	}
+	if filters.Search != "" {
+		searchPattern := "%" + filters.Search + "%"
+		query = query.Where("code LIKE ? OR description LIKE ?", searchPattern, searchPattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var plats []models.Plat
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Order("code ASC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&plats).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &PlatListResponse{
+		Items:      plats,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetPlat mengambil detail plat beserta PO yang sedang memakai
+func (s *PlatService) GetPlat(id uint64) (*models.Plat, error) {
+	var plat models.Plat
+	if err := s.db.Preload("CurrentPO").First(&plat, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPlatNotFound
+		}
+		return nil, err
+	}
+	return &plat, nil
+}
+
+// CreatePlat mendaftarkan plat baru ke registry dengan validasi kode unik
+func (s *PlatService) CreatePlat(req CreatePlatRequest) (*models.Plat, error) {
+	if req.MaxImpressions < 0 {
+		return nil, ErrInvalidImpressions
+	}
+
+	// Kode plat disimpan apa adanya karena dicocokkan dengan hasil scan barcode
+	code := strings.TrimSpace(req.Code)
+	var existing int64
+	if err := s.db.Unscoped().Model(&models.Plat{}).Where("code = ?", code).Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrPlatCodeExists
+	}
+
+	plat := models.Plat{
+		Code:            code,
+		Description:     req.Description,
+		StorageLocation: req.StorageLocation,
+		Status:          models.PlatAvailable,
+		MaxImpressions:  req.MaxImpressions,
+		Notes:           req.Notes,
+	}
+	if err := s.db.Create(&plat).Error; err != nil {
+		return nil, err
+	}
+	return &plat, nil
+}
+
+// UpdatePlat mengupdate data plat (deskripsi, lokasi, batas impression, catatan)
+func (s *PlatService) UpdatePlat(id uint64, req UpdatePlatRequest) (*models.Plat, error) {
+	plat, err := s.GetPlat(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.StorageLocation != nil {
+		updates["storage_location"] = *req.StorageLocation
+	}
+	if req.MaxImpressions != nil {
+		if *req.MaxImpressions < 0 {
+			return nil, ErrInvalidImpressions
+		}
+		updates["max_impressions"] = *req.MaxImpressions
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(plat).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetPlat(id)
+}
+
+// UpdatePlatStatus mengubah status plat ke AVAILABLE, MAINTENANCE, atau RETIRED,
+// status IN_USE hanya diatur lewat pengambilan plat di material prep
+func (s *PlatService) UpdatePlatStatus(id uint64, req UpdatePlatStatusRequest) (*models.Plat, error) {
+	if !models.IsValidPlatStatus(req.Status) || req.Status == models.PlatInUse {
+		return nil, ErrInvalidPlatStatus
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		plat, err := lockPlat(tx, "id = ?", id)
+		if err != nil {
+			return err
+		}
+		if plat.Status == models.PlatInUse {
+			return ErrPlatInUse
+		}
+		if plat.Status == models.PlatRetired {
+			return ErrPlatRetired
+		}
+
+		updates := map[string]interface{}{
+			"status": req.Status,
+		}
+		if req.Notes != "" {
+			updates["notes"] = req.Notes
+		}
+		return tx.Model(plat).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPlat(id)
+}
+
+// ReturnPlat mengembalikan plat yang sedang dipakai ke penyimpanan dan menutup riwayat pemakaian,
+// plat yang rusak otomatis masuk MAINTENANCE
+func (s *PlatService) ReturnPlat(id uint64, req ReturnPlatRequest, userID uint64) (*models.Plat, error) {
+	if req.Condition != models.PlatConditionGood && req.Condition != models.PlatConditionDamaged {
+		return nil, ErrInvalidPlatCondition
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		plat, err := lockPlat(tx, "id = ?", id)
+		if err != nil {
+			return err
+		}
+		if plat.Status != models.PlatInUse {
+			return ErrPlatNotCheckedOut
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.PlatCheckout{}).
+			Where("plat_id = ? AND returned_at IS NULL", plat.ID).
+			Updates(map[string]interface{}{
+				"returned_by":      userID,
+				"returned_at":      now,
+				"return_condition": req.Condition,
+				"notes":            req.Notes,
+			}).Error; err != nil {
+			return err
+		}
+
+		nextStatus := models.PlatAvailable
+		if req.Condition == models.PlatConditionDamaged {
+			nextStatus = models.PlatMaintenance
+		}
+		updates := map[string]interface{}{
+			"status":         nextStatus,
+			"current_po_id":  nil,
+			"checked_out_at": nil,
+			"checked_out_by": nil,
+		}
+		if strings.TrimSpace(req.StorageLocation) != "" {
+			updates["storage_location"] = strings.TrimSpace(req.StorageLocation)
+		}
+		return tx.Model(plat).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPlat(id)
+}
+
+// ListCheckouts mengambil riwayat pengambilan dan pengembalian plat, terbaru di atas
+func (s *PlatService) ListCheckouts(platID uint64, page, perPage int) (*PlatCheckoutListResponse, error) {
+	if _, err := s.GetPlat(platID); err != nil {
+		return nil, err
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 || perPage > 100 {
+		perPage = 20
+	}
+
+	query := s.db.Model(&models.PlatCheckout{}).Where("plat_id = ?", platID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var checkouts []models.PlatCheckout
+	offset := (page - 1) * perPage
+	if err := query.
+		Preload("ProductionOrder", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped().Select("id", "po_number", "obc_number", "current_stage", "current_status")
+		}).
+		Order("checked_out_at DESC, id DESC").
+		Limit(perPage).
+		Offset(offset).
+		Find(&checkouts).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / perPage
+	if int(total)%perPage > 0 {
+		totalPages++
+	}
+
+	return &PlatCheckoutListResponse{
+		Items:      checkouts,
+		Total:      int(total),
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// checkoutPlatInTx mencatat pengambilan plat untuk PO di dalam transaction milik caller,
+// plat yang belum terdaftar di registry ditolak kecuali fallback legacy allowUnregistered aktif (nil, nil)
+func checkoutPlatInTx(tx *gorm.DB, code string, poID uint64, prepID *uint64, userID uint64, allowUnregistered bool) (*models.Plat, error) {
+	plat, err := lockPlat(tx, "code = ?", code)
+	if err != nil {
+		if errors.Is(err, ErrPlatNotFound) {
+			if allowUnregistered {
+				return nil, nil
+			}
+			return nil, ErrPlatNotRegistered
+		}
+		return nil, err
+	}
+
+	// Scan ulang untuk PO yang sama tidak membuat riwayat baru
+	if plat.IsCheckedOutBy(poID) {
+		return plat, nil
+	}
+
+	switch plat.Status {
+	case models.PlatRetired:
+		return nil, ErrPlatRetired
+	case models.PlatMaintenance:
+		return nil, ErrPlatInMaintenance
+	case models.PlatInUse:
+		var holder models.ProductionOrder
+		if plat.CurrentPOID != nil && tx.Unscoped().Select("id", "po_number").First(&holder, *plat.CurrentPOID).Error == nil {
+			return nil, fmt.Errorf("%w (PO %d)", ErrPlatCheckedOut, holder.PONumber)
+		}
+		return nil, ErrPlatCheckedOut
+	}
+
+	now := time.Now()
+	if err := tx.Model(plat).Updates(map[string]interface{}{
+		"status":         models.PlatInUse,
+		"current_po_id":  poID,
+		"checked_out_at": now,
+		"checked_out_by": userID,
+		"usage_count":    gorm.Expr("usage_count + 1"),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	checkout := models.PlatCheckout{
+		PlatID:            plat.ID,
+		ProductionOrderID: poID,
+		MaterialPrepID:    prepID,
+		CheckedOutBy:      userID,
+		CheckedOutAt:      now,
+	}
+	if err := tx.Create(&checkout).Error; err != nil {
+		return nil, err
+	}
+	return plat, nil
+}
+
+// releasePlatsInTx mengembalikan plat yang masih dipakai PO ke status AVAILABLE dan menutup riwayat pemakaiannya
+// di dalam transaction milik caller, digunakan saat PO dibatalkan sebelum produksi. Mengembalikan kode plat yang dilepas
+func releasePlatsInTx(tx *gorm.DB, poID uint64, userID uint64, notes string) ([]string, error) {
+	var plats []models.Plat
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("current_po_id = ? AND status = ?", poID, models.PlatInUse).
+		Find(&plats).Error; err != nil {
+		return nil, err
+	}
+	if len(plats) == 0 {
+		return []string{}, nil
+	}
+
+	now := time.Now()
+	if err := tx.Model(&models.PlatCheckout{}).
+		Where("production_order_id = ? AND returned_at IS NULL", poID).
+		Updates(map[string]interface{}{
+			"returned_by":      userID,
+			"returned_at":      now,
+			"return_condition": models.PlatConditionGood,
+			"notes":            notes,
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, len(plats))
+	codes := make([]string, len(plats))
+	for i, plat := range plats {
+		ids[i] = plat.ID
+		codes[i] = plat.Code
+	}
+	if err := tx.Model(&models.Plat{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"status":         models.PlatAvailable,
+			"current_po_id":  nil,
+			"checked_out_at": nil,
+			"checked_out_by": nil,
+		}).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// recordPlatImpressions menambahkan jumlah impression ke plat yang sedang dipakai PO
+// di dalam transaction milik caller, tidak melakukan apa-apa jika PO tidak memakai plat terdaftar
+func recordPlatImpressions(tx *gorm.DB, poID uint64, impressions int64) error {
+	if impressions <= 0 {
+		return nil
+	}
+
+	var checkout models.PlatCheckout
+	err := tx.Where("production_order_id = ? AND returned_at IS NULL", poID).
+		Order("id DESC").
+		First(&checkout).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Model(&checkout).
+		Update("impressions", gorm.Expr("impressions + ?", impressions)).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Plat{}).
+		Where("id = ?", checkout.PlatID).
+		Update("total_impressions", gorm.Expr("total_impressions + ?", impressions)).Error
+}
+
+// lockPlat mengambil plat dengan row lock di dalam transaction milik caller
+func lockPlat(tx *gorm.DB, condition string, value interface{}) (*models.Plat, error) {
+	var plat models.Plat
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(condition, value).
+		First(&plat).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPlatNotFound
+		}
+		return nil, err
+	}
+	return &plat, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sirine-go/backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// globalSequenceKey dipakai sebagai sequence key untuk format tanpa prefix
+const globalSequenceKey = "GLOBAL"
+
+// PONumberGenerator merupakan generator nomor PO berbasis sequence table
+// yang menghasilkan nomor deterministik sesuai format dan aman untuk generate PO bersamaan
+type PONumberGenerator struct {
+	format       *models.PONumberFormat
+	defaultPlant string
+}
+
+// NewPONumberGenerator membuat instance baru dari PONumberGenerator,
+// format yang tidak valid akan fallback ke DefaultPONumberFormat
+func NewPONumberGenerator(pattern, defaultPlant string) *PONumberGenerator {
+	format, err := models.ParsePONumberFormat(pattern)
+	if err != nil {
+		log.Printf("PO number format %q tidak valid (%v), menggunakan format default %s", pattern, err, models.DefaultPONumberFormat)
+		format, _ = models.ParsePONumberFormat(models.DefaultPONumberFormat)
+	}
+	if strings.TrimSpace(defaultPlant) == "" {
+		defaultPlant = models.DefaultPOPlantCode
+	}
+
+	return &PONumberGenerator{
+		format:       format,
+		defaultPlant: defaultPlant,
+	}
+}
+
+// Next mengalokasikan sejumlah nomor PO berurutan untuk plant tertentu dalam transaction,
+// row sequence di-lock sampai transaction selesai sehingga tidak ada nomor yang bentrok
+func (g *PONumberGenerator) Next(tx *gorm.DB, plantCode string, count int) ([]int64, error) {
+	if count <= 0 {
+		return []int64{}, nil
+	}
+	if strings.TrimSpace(plantCode) == "" {
+		plantCode = g.defaultPlant
+	}
+
+	prefix, err := g.format.Prefix(time.Now(), plantCode)
+	if err != nil {
+		return nil, err
+	}
+	sequence, err := g.lockSequence(tx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if sequence.LastValue+int64(count) > g.format.MaxSequence() {
+		return nil, models.ErrPONumberSequenceExhausted
+	}
+
+	numbers := make([]int64, count)
+	for i := range numbers {
+		number, err := g.format.Build(prefix, sequence.LastValue+int64(i+1))
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = number
+	}
+
+	if err := tx.Model(&models.PONumberSequence{}).
+		Where("id = ?", sequence.ID).
+		Update("last_value", sequence.LastValue+int64(count)).Error; err != nil {
+		return nil, err
+	}
+	return numbers, nil
+}
+
+// lockSequence membuat row sequence untuk prefix jika belum ada lalu mengambilnya dengan row lock,
+// row selalu dibuat lebih dulu agar lock tidak jatuh ke gap index (deadlock saat prefix baru dipakai bersamaan)
+func (g *PONumberGenerator) lockSequence(tx *gorm.DB, prefix string) (*models.PONumberSequence, error) {
+	key := prefix
+	if key == "" {
+		key = globalSequenceKey
+	}
+
+	var existing int64
+	if err := tx.Model(&models.PONumberSequence{}).Where("sequence_key = ?", key).Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing == 0 {
+		// Sequence baru diselaraskan dengan nomor PO yang sudah ada di rentang prefix,
+		// row yang dibuat transaction lain secara bersamaan dibiarkan (DO NOTHING)
+		lastValue, err := g.existingMaxSequence(tx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		seed := models.PONumberSequence{SequenceKey: key, LastValue: lastValue}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&seed).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var sequence models.PONumberSequence
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("sequence_key = ?", key).
+		First(&sequence).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("sequence nomor PO %s tidak ditemukan: %w", key, err)
+		}
+		return nil, err
+	}
+	return &sequence, nil
+}
+
+// existingMaxSequence mengambil running number terbesar dari PO yang sudah ada di rentang prefix
+func (g *PONumberGenerator) existingMaxSequence(tx *gorm.DB, prefix string) (int64, error) {
+	lower, upper, err := g.format.SequenceRange(prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	var maxNumber int64
+	if err := tx.Unscoped().Model(&models.ProductionOrder{}).
+		Where("po_number BETWEEN ? AND ?", lower, upper).
+		Select("COALESCE(MAX(po_number), 0)").
+		Scan(&maxNumber).Error; err != nil {
+		return 0, err
+	}
+	if maxNumber == 0 {
+		return 0, nil
+	}
+	return maxNumber - lower + 1, nil
+}
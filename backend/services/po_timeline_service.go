@@ -0,0 +1,581 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sirine-go/backend/models"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sumber event pada timeline PO
+const (
+	TimelineSourcePO           = "PRODUCTION_ORDER"
+	TimelineSourceMaterialPrep = "MATERIAL_PREP"
+	TimelineSourcePrintJob     = "PRINT_JOB"
+	TimelineSourceCounting     = "COUNTING"
+	TimelineSourceCutting      = "CUTTING"
+	TimelineSourceVerifikasi   = "VERIFIKASI"
+	TimelineSourceKhazkhir     = "KHAZKHIR"
+	TimelineSourceStage        = "STAGE_TRACKING"
+	TimelineSourceActivity     = "ACTIVITY_LOG"
+)
+
+// timelineDedupeWindow merupakan selisih waktu maksimal antara event stage tracking atau activity log
+// dengan event record stage yang dianggap kejadian yang sama, karena ketiganya ditulis dalam transaction yang sama
+const timelineDedupeWindow = 5 * time.Second
+
+// activityLogStages memetakan entity_type activity log workflow produksi ke stage PO
+var activityLogStages = map[string]models.POStage{
+	"COUNTING":   models.StageKhazwalCounting,
+	"VERIFIKASI": models.StageVerifikasi,
+	"KHAZKHIR":   models.StageKhazkhir,
+}
+
+// POTimelineService merupakan service untuk menyusun timeline end-to-end Production Order
+// dari production_orders, record setiap stage, po_stage_trackings, dan activity_logs
+type POTimelineService struct {
+	db *gorm.DB
+}
+
+// NewPOTimelineService membuat instance baru dari POTimelineService
+func NewPOTimelineService(db *gorm.DB) *POTimelineService {
+	return &POTimelineService{db: db}
+}
+
+// TimelineEvent merupakan satu kejadian pada timeline PO
+type TimelineEvent struct {
+	Timestamp   time.Time              `json:"timestamp"`
+	Source      string                 `json:"source"`
+	Stage       models.POStage         `json:"stage,omitempty"`
+	Event       string                 `json:"event"`
+	Description string                 `json:"description"`
+	HandledBy   *uint64                `json:"handled_by"`
+	HandlerName string                 `json:"handler_name,omitempty"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+}
+
+// TimelineStage merupakan ringkasan satu stage produksi dengan durasi, handler, variance, dan waste
+type TimelineStage struct {
+	Stage           models.POStage `json:"stage"`
+	Status          string         `json:"status"`
+	StartedAt       *time.Time     `json:"started_at"`
+	CompletedAt     *time.Time     `json:"completed_at"`
+	DurationMinutes *int           `json:"duration_minutes"`
+	HandledBy       *uint64        `json:"handled_by"`
+	HandlerName     string         `json:"handler_name,omitempty"`
+	Variance        *int           `json:"variance,omitempty"`
+	VarianceReason  string         `json:"variance_reason,omitempty"`
+	WasteQuantity   *int           `json:"waste_quantity,omitempty"`
+	WastePercentage *float64       `json:"waste_percentage,omitempty"`
+}
+
+// POTimelineResponse merupakan response timeline lengkap satu PO
+type POTimelineResponse struct {
+	POID                 uint64          `json:"po_id"`
+	PONumber             int64           `json:"po_number"`
+	OBCNumber            string          `json:"obc_number"`
+	CurrentStage         models.POStage  `json:"current_stage"`
+	CurrentStatus        models.POStatus `json:"current_status"`
+	CreatedAt            time.Time       `json:"created_at"`
+	TotalDurationMinutes *int            `json:"total_duration_minutes"`
+	Stages               []TimelineStage `json:"stages"`
+	Events               []TimelineEvent `json:"events"`
+}
+
+// timelineBuilder mengumpulkan event dan stage sebelum resolve nama handler
+type timelineBuilder struct {
+	stages []TimelineStage
+	events []TimelineEvent
+}
+
+// addEvent menambahkan event ke timeline, dilewati jika timestamp belum ada
+func (b *timelineBuilder) addEvent(at *time.Time, source string, stage models.POStage, event, description string, handledBy *uint64, details map[string]interface{}) {
+	if at == nil {
+		return
+	}
+	b.events = append(b.events, TimelineEvent{
+		Timestamp:   *at,
+		Source:      source,
+		Stage:       stage,
+		Event:       event,
+		Description: description,
+		HandledBy:   handledBy,
+		Details:     details,
+	})
+}
+
+// isRecordSource memeriksa apakah event berasal dari PO atau record stage,
+// yaitu sumber utama event mulai dan selesai setiap stage
+func isRecordSource(source string) bool {
+	return source != TimelineSourceStage && source != TimelineSourceActivity
+}
+
+// dedupeEvents membuang event stage tracking dan activity log yang mencatat kejadian yang sama
+// dengan event record stage (stage sama dan timestamp berdekatan), sehingga setiap mulai/selesai stage
+// hanya muncul sekali dengan detail dari record stage
+func (b *timelineBuilder) dedupeEvents() {
+	recordTimes := make(map[models.POStage][]time.Time)
+	for _, event := range b.events {
+		if isRecordSource(event.Source) {
+			recordTimes[event.Stage] = append(recordTimes[event.Stage], event.Timestamp)
+		}
+	}
+
+	events := b.events[:0]
+	for _, event := range b.events {
+		if !isRecordSource(event.Source) && event.Stage != "" && hasNearbyTime(recordTimes[event.Stage], event.Timestamp) {
+			continue
+		}
+		events = append(events, event)
+	}
+	b.events = events
+}
+
+// hasNearbyTime memeriksa apakah ada timestamp dalam rentang timelineDedupeWindow dari at
+func hasNearbyTime(times []time.Time, at time.Time) bool {
+	for _, t := range times {
+		diff := at.Sub(t)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= timelineDedupeWindow {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTimeline menyusun timeline PO yang berisi ringkasan per stage dan event terurut waktu
+func (s *POTimelineService) GetTimeline(poID uint64) (*POTimelineResponse, error) {
+	var po models.ProductionOrder
+	if err := s.db.Select("id", "po_number", "obc_number", "current_stage", "current_status", "created_at").
+		First(&po, poID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPONotFound
+		}
+		return nil, fmt.Errorf("gagal mengambil PO: %w", err)
+	}
+
+	b := &timelineBuilder{}
+	createdAt := po.CreatedAt
+	b.addEvent(&createdAt, TimelineSourcePO, models.StageKhazwalMaterialPrep, "PO_CREATED",
+		fmt.Sprintf("PO %d dibuat untuk OBC %s", po.PONumber, po.OBCNumber), nil, nil)
+
+	loaders := []func(uint64, *timelineBuilder) error{
+		s.loadMaterialPrep,
+		s.loadPrintJob,
+		s.loadCounting,
+		s.loadCutting,
+		s.loadVerifikasi,
+		s.loadKhazkhir,
+		s.loadStageTrackings,
+		s.loadActivityLogs,
+	}
+	for _, load := range loaders {
+		if err := load(poID, b); err != nil {
+			return nil, err
+		}
+	}
+	b.dedupeEvents()
+
+	if err := s.resolveHandlerNames(b); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(b.events, func(i, j int) bool {
+		return b.events[i].Timestamp.Before(b.events[j].Timestamp)
+	})
+
+	response := &POTimelineResponse{
+		POID:          po.ID,
+		PONumber:      po.PONumber,
+		OBCNumber:     po.OBCNumber,
+		CurrentStage:  po.CurrentStage,
+		CurrentStatus: po.CurrentStatus,
+		CreatedAt:     po.CreatedAt,
+		Stages:        b.stages,
+		Events:        b.events,
+	}
+	if response.Stages == nil {
+		response.Stages = []TimelineStage{}
+	}
+
+	// Total durasi dari PO dibuat sampai stage terakhir selesai (atau sekarang jika belum selesai)
+	end := time.Now()
+	if po.CurrentStatus == models.StatusPOCompleted && len(b.events) > 0 {
+		end = b.events[len(b.events)-1].Timestamp
+	}
+	total := int(end.Sub(po.CreatedAt).Minutes())
+	response.TotalDurationMinutes = &total
+
+	return response, nil
+}
+
+// loadMaterialPrep menambahkan stage persiapan material beserta variance kertas blanko
+func (s *POTimelineService) loadMaterialPrep(poID uint64, b *timelineBuilder) error {
+	var prep models.KhazwalMaterialPreparation
+	err := s.db.Where("production_order_id = ?", poID).First(&prep).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("gagal mengambil material preparation: %w", err)
+	}
+
+	b.stages = append(b.stages, TimelineStage{
+		Stage:           models.StageKhazwalMaterialPrep,
+		Status:          string(prep.Status),
+		StartedAt:       prep.StartedAt,
+		CompletedAt:     prep.CompletedAt,
+		DurationMinutes: prep.DurationMinutes,
+		HandledBy:       prep.PreparedBy,
+		Variance:        prep.KertasBlankoVariance,
+		VarianceReason:  prep.KertasBlankoVarianceReason,
+	})
+
+	b.addEvent(prep.StartedAt, TimelineSourceMaterialPrep, models.StageKhazwalMaterialPrep, "MATERIAL_PREP_STARTED",
+		"Persiapan material dimulai", prep.PreparedBy, nil)
+	b.addEvent(prep.PlatRetrievedAt, TimelineSourceMaterialPrep, models.StageKhazwalMaterialPrep, "PLAT_RETRIEVED",
+		"Plat diambil dan dikonfirmasi", prep.PreparedBy, map[string]interface{}{"plat_match": prep.PlatMatch})
+	b.addEvent(prep.CompletedAt, TimelineSourceMaterialPrep, models.StageKhazwalMaterialPrep, "MATERIAL_PREP_COMPLETED",
+		"Persiapan material selesai", prep.PreparedBy, map[string]interface{}{
+			"kertas_blanko_quantity": prep.KertasBlankoQuantity,
+			"kertas_blanko_actual":   prep.KertasBlankoActual,
+			"kertas_blanko_variance": prep.KertasBlankoVariance,
+		})
+	return nil
+}
+
+// loadPrintJob menambahkan stage cetak beserta lembar rusak sebagai waste
+func (s *POTimelineService) loadPrintJob(poID uint64, b *timelineBuilder) error {
+	var job models.PrintJob
+	err := s.db.Where("production_order_id = ?", poID).First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("gagal mengambil print job: %w", err)
+	}
+
+	stage := TimelineStage{
+		Stage:           models.StageCetak,
+		Status:          string(job.Status),
+		StartedAt:       job.StartedAt,
+		CompletedAt:     job.CompletedAt,
+		DurationMinutes: job.DurationMinutes,
+		HandledBy:       &job.OperatorID,
+		WasteQuantity:   &job.RejectedSheets,
+	}
+	if total := job.GoodSheets + job.RejectedSheets; total > 0 {
+		percentage := float64(job.RejectedSheets) / float64(total) * 100
+		stage.WastePercentage = &percentage
+	}
+	b.stages = append(b.stages, stage)
+
+	var logs []models.PrintJobLog
+	if err := s.db.Where("print_job_id = ?", job.ID).Order("created_at ASC").Find(&logs).Error; err != nil {
+		return fmt.Errorf("gagal mengambil print job logs: %w", err)
+	}
+	for i := range logs {
+		log := logs[i]
+		b.addEvent(&log.CreatedAt, TimelineSourcePrintJob, models.StageCetak, "PRINT_JOB_"+string(log.Type),
+			log.Notes, &log.LoggedBy, map[string]interface{}{
+				"good_sheets":     log.GoodSheets,
+				"rejected_sheets": log.RejectedSheets,
+			})
+	}
+	return nil
+}
+
+// loadCounting menambahkan stage penghitungan beserta variance dari target dan jumlah rusak
+func (s *POTimelineService) loadCounting(poID uint64, b *timelineBuilder) error {
+	var row struct {
+		Status             string
+		QuantityGood       int
+		QuantityDefect     int
+		VarianceFromTarget *int
+		VarianceReason     string
+		PercentageDefect   *float64
+		StartedAt          *time.Time
+		CompletedAt        *time.Time
+		DurationMinutes    *int
+		CountedBy          *uint64
+	}
+	result := s.db.Table("khazwal_counting_results").
+		Where("production_order_id = ? AND deleted_at IS NULL", poID).
+		Limit(1).Scan(&row)
+	if result.Error != nil {
+		return fmt.Errorf("gagal mengambil counting result: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	b.stages = append(b.stages, TimelineStage{
+		Stage:           models.StageKhazwalCounting,
+		Status:          row.Status,
+		StartedAt:       row.StartedAt,
+		CompletedAt:     row.CompletedAt,
+		DurationMinutes: row.DurationMinutes,
+		HandledBy:       row.CountedBy,
+		Variance:        row.VarianceFromTarget,
+		VarianceReason:  row.VarianceReason,
+		WasteQuantity:   &row.QuantityDefect,
+		WastePercentage: row.PercentageDefect,
+	})
+
+	b.addEvent(row.StartedAt, TimelineSourceCounting, models.StageKhazwalCounting, "COUNTING_STARTED",
+		"Penghitungan dimulai", row.CountedBy, nil)
+	b.addEvent(row.CompletedAt, TimelineSourceCounting, models.StageKhazwalCounting, "COUNTING_COMPLETED",
+		"Penghitungan selesai", row.CountedBy, map[string]interface{}{
+			"quantity_good":        row.QuantityGood,
+			"quantity_defect":      row.QuantityDefect,
+			"variance_from_target": row.VarianceFromTarget,
+		})
+	return nil
+}
+
+// loadCutting menambahkan stage pemotongan beserta waste pemotongan
+func (s *POTimelineService) loadCutting(poID uint64, b *timelineBuilder) error {
+	var row struct {
+		Status           string
+		InputLembarBesar int
+		ExpectedOutput   int
+		TotalOutput      int
+		WasteQuantity    int
+		WastePercentage  *float64
+		WasteReason      string
+		CuttingMachine   string
+		StartedAt        *time.Time
+		CompletedAt      *time.Time
+		DurationMinutes  *int
+		CutBy            *uint64
+	}
+	result := s.db.Table("khazwal_cutting_results").
+		Where("production_order_id = ? AND deleted_at IS NULL", poID).
+		Limit(1).Scan(&row)
+	if result.Error != nil {
+		return fmt.Errorf("gagal mengambil cutting result: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	variance := row.TotalOutput - row.ExpectedOutput
+	stage := TimelineStage{
+		Stage:           models.StageKhazwalCutting,
+		Status:          row.Status,
+		StartedAt:       row.StartedAt,
+		CompletedAt:     row.CompletedAt,
+		DurationMinutes: row.DurationMinutes,
+		HandledBy:       row.CutBy,
+		VarianceReason:  row.WasteReason,
+		WasteQuantity:   &row.WasteQuantity,
+		WastePercentage: row.WastePercentage,
+	}
+	if row.CompletedAt != nil {
+		stage.Variance = &variance
+	}
+	b.stages = append(b.stages, stage)
+
+	b.addEvent(row.StartedAt, TimelineSourceCutting, models.StageKhazwalCutting, "CUTTING_STARTED",
+		fmt.Sprintf("Pemotongan dimulai di mesin %s", row.CuttingMachine), row.CutBy, nil)
+	b.addEvent(row.CompletedAt, TimelineSourceCutting, models.StageKhazwalCutting, "CUTTING_COMPLETED",
+		"Pemotongan selesai", row.CutBy, map[string]interface{}{
+			"input_lembar_besar": row.InputLembarBesar,
+			"total_output":       row.TotalOutput,
+			"waste_quantity":     row.WasteQuantity,
+		})
+	return nil
+}
+
+// loadVerifikasi menambahkan stage verifikasi dengan HCTS sebagai waste
+func (s *POTimelineService) loadVerifikasi(poID uint64, b *timelineBuilder) error {
+	var row struct {
+		Status          string
+		TotalInput      int
+		TotalHCS        int `gorm:"column:total_hcs"`
+		TotalHCTS       int `gorm:"column:total_hcts"`
+		StartedAt       *time.Time
+		CompletedAt     *time.Time
+		DurationMinutes *int
+		VerifiedBy      *uint64
+	}
+	result := s.db.Table("verifikasi_results").
+		Where("production_order_id = ? AND deleted_at IS NULL", poID).
+		Limit(1).Scan(&row)
+	if result.Error != nil {
+		return fmt.Errorf("gagal mengambil verifikasi result: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	stage := TimelineStage{
+		Stage:           models.StageVerifikasi,
+		Status:          row.Status,
+		StartedAt:       row.StartedAt,
+		CompletedAt:     row.CompletedAt,
+		DurationMinutes: row.DurationMinutes,
+		HandledBy:       row.VerifiedBy,
+		WasteQuantity:   &row.TotalHCTS,
+	}
+	if inspected := row.TotalHCS + row.TotalHCTS; inspected > 0 {
+		percentage := float64(row.TotalHCTS) / float64(inspected) * 100
+		stage.WastePercentage = &percentage
+	}
+	b.stages = append(b.stages, stage)
+
+	b.addEvent(row.StartedAt, TimelineSourceVerifikasi, models.StageVerifikasi, "VERIFIKASI_STARTED",
+		"Verifikasi dimulai", row.VerifiedBy, nil)
+	b.addEvent(row.CompletedAt, TimelineSourceVerifikasi, models.StageVerifikasi, "VERIFIKASI_COMPLETED",
+		"Verifikasi selesai", row.VerifiedBy, map[string]interface{}{
+			"total_input": row.TotalInput,
+			"total_hcs":   row.TotalHCS,
+			"total_hcts":  row.TotalHCTS,
+		})
+	return nil
+}
+
+// loadKhazkhir menambahkan stage Khazanah Akhir beserta variance penghitungan akhir
+func (s *POTimelineService) loadKhazkhir(poID uint64, b *timelineBuilder) error {
+	var row struct {
+		Status          string
+		InputHCS        int `gorm:"column:input_hcs"`
+		FinalCount      *int
+		CountVariance   int
+		VarianceReason  string
+		TotalRims       int
+		TotalBoxes      int
+		StartedAt       *time.Time
+		CountedAt       *time.Time
+		PackedAt        *time.Time
+		DispatchedAt    *time.Time
+		DurationMinutes *int
+		StaffID         *uint64
+	}
+	result := s.db.Table("khazkhir_results").
+		Where("production_order_id = ? AND deleted_at IS NULL", poID).
+		Limit(1).Scan(&row)
+	if result.Error != nil {
+		return fmt.Errorf("gagal mengambil khazkhir result: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	stage := TimelineStage{
+		Stage:           models.StageKhazkhir,
+		Status:          row.Status,
+		StartedAt:       row.StartedAt,
+		CompletedAt:     row.DispatchedAt,
+		DurationMinutes: row.DurationMinutes,
+		HandledBy:       row.StaffID,
+		VarianceReason:  row.VarianceReason,
+	}
+	if row.FinalCount != nil {
+		stage.Variance = &row.CountVariance
+	}
+	b.stages = append(b.stages, stage)
+
+	b.addEvent(row.StartedAt, TimelineSourceKhazkhir, models.StageKhazkhir, "KHAZKHIR_STARTED",
+		"Khazanah Akhir dimulai", row.StaffID, map[string]interface{}{"input_hcs": row.InputHCS})
+	b.addEvent(row.CountedAt, TimelineSourceKhazkhir, models.StageKhazkhir, "FINAL_COUNT_SUBMITTED",
+		"Penghitungan akhir selesai", row.StaffID, map[string]interface{}{
+			"final_count":    row.FinalCount,
+			"count_variance": row.CountVariance,
+		})
+	b.addEvent(row.PackedAt, TimelineSourceKhazkhir, models.StageKhazkhir, "PACKED",
+		fmt.Sprintf("Dikemas menjadi %d rim dalam %d box", row.TotalRims, row.TotalBoxes), row.StaffID, nil)
+	b.addEvent(row.DispatchedAt, TimelineSourceKhazkhir, models.StageKhazkhir, "DISPATCHED",
+		"PO dikirim", row.StaffID, nil)
+	return nil
+}
+
+// loadStageTrackings menambahkan setiap perpindahan stage/status PO sebagai event
+func (s *POTimelineService) loadStageTrackings(poID uint64, b *timelineBuilder) error {
+	var trackings []models.POStageTracking
+	if err := s.db.Where("production_order_id = ?", poID).
+		Order("created_at ASC").
+		Find(&trackings).Error; err != nil {
+		return fmt.Errorf("gagal mengambil stage tracking: %w", err)
+	}
+
+	for i := range trackings {
+		tracking := trackings[i]
+		var details map[string]interface{}
+		if tracking.DurationMinutes != nil {
+			details = map[string]interface{}{"duration_minutes": *tracking.DurationMinutes}
+		}
+		b.addEvent(&tracking.CreatedAt, TimelineSourceStage, tracking.Stage, string(tracking.Status),
+			tracking.Notes, tracking.HandledBy, details)
+	}
+	return nil
+}
+
+// loadActivityLogs menambahkan activity log yang terkait PO sebagai event,
+// activity log workflow produksi diberi stage sesuai entity_type-nya
+func (s *POTimelineService) loadActivityLogs(poID uint64, b *timelineBuilder) error {
+	var logs []models.ActivityLog
+	if err := s.db.Where("production_order_id = ?", poID).
+		Or("entity_type = ? AND entity_id = ?", "production_orders", poID).
+		Order("created_at ASC").
+		Find(&logs).Error; err != nil {
+		return fmt.Errorf("gagal mengambil activity logs: %w", err)
+	}
+
+	for i := range logs {
+		log := logs[i]
+		userID := log.UserID
+		b.addEvent(&log.CreatedAt, TimelineSourceActivity, activityLogStages[log.EntityType], string(log.Action),
+			log.Description, &userID, map[string]interface{}{"entity_type": log.EntityType})
+	}
+	return nil
+}
+
+// resolveHandlerNames mengisi nama handler untuk stage dan event dengan satu query users
+func (s *POTimelineService) resolveHandlerNames(b *timelineBuilder) error {
+	idSet := make(map[uint64]struct{})
+	for _, stage := range b.stages {
+		if stage.HandledBy != nil {
+			idSet[*stage.HandledBy] = struct{}{}
+		}
+	}
+	for _, event := range b.events {
+		if event.HandledBy != nil {
+			idSet[*event.HandledBy] = struct{}{}
+		}
+	}
+	if len(idSet) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(idSet))
+	for id := range idSet {
+		ids = append(ids, id)
+	}
+
+	var users []models.User
+	if err := s.db.Select("id", "full_name").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return fmt.Errorf("gagal mengambil data handler: %w", err)
+	}
+	names := make(map[uint64]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.FullName
+	}
+
+	for i := range b.stages {
+		if b.stages[i].HandledBy != nil {
+			b.stages[i].HandlerName = names[*b.stages[i].HandledBy]
+		}
+	}
+	for i := range b.events {
+		if b.events[i].HandledBy != nil {
+			b.events[i].HandlerName = names[*b.events[i].HandledBy]
+		}
+	}
+	return nil
+}
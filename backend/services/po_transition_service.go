@@ -0,0 +1,146 @@
+package services
+
+import (
+	"sirine-go/backend/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// POTransitionService merupakan central engine untuk perpindahan stage/status Production Order
+// yang memvalidasi graph di models.ValidateTransition dan mencatat POStageTracking di setiap perpindahan
+type POTransitionService struct {
+	db *gorm.DB
+}
+
+// NewPOTransitionService membuat instance baru dari POTransitionService
+func NewPOTransitionService(db *gorm.DB) *POTransitionService {
+	return &POTransitionService{db: db}
+}
+
+// POTransitionRequest merupakan parameter untuk memindahkan PO ke status tujuan
+type POTransitionRequest struct {
+	POID      uint64
+	To        models.POStatus
+	HandledBy *uint64
+	Notes     string
+	// StartedAt opsional, digunakan untuk menghitung durasi stage yang diselesaikan
+	StartedAt *time.Time
+}
+
+// Transition memindahkan PO ke status tujuan dalam transaction sendiri
+func (s *POTransitionService) Transition(req POTransitionRequest) (*models.POStageTracking, error) {
+	var tracking *models.POStageTracking
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		var err error
+		tracking, err = s.TransitionInTx(tx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tracking, nil
+}
+
+// TransitionInTx memindahkan PO ke status tujuan di dalam transaction milik caller,
+// dengan row lock pada PO, validasi graph, update current_stage/current_status,
+// dan pembuatan POStageTracking record untuk audit trail
+func (s *POTransitionService) TransitionInTx(tx *gorm.DB, req POTransitionRequest) (*models.POStageTracking, error) {
+	// Lock PO untuk prevent concurrent transitions
+	var po models.ProductionOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "po_number", "obc_number", "current_stage", "current_status").
+		First(&po, req.POID).Error; err != nil {
+		return nil, err
+	}
+
+	// Validasi graph perpindahan status
+	if err := models.ValidateTransition(po.CurrentStatus, req.To); err != nil {
+		return nil, err
+	}
+
+	fromStage, err := models.StageForStatus(po.CurrentStatus)
+	if err != nil {
+		return nil, err
+	}
+	toStage, err := models.StageAfterTransition(po.CurrentStatus, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update PO stage & status
+	now := time.Now()
+	if err := tx.Model(&models.ProductionOrder{}).
+		Where("id = ?", req.POID).
+		Updates(map[string]interface{}{
+			"current_stage":  toStage,
+			"current_status": req.To,
+			"updated_at":     now,
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	// Build tracking record: perpindahan antar stage dicatat sebagai penyelesaian stage asal,
+	// sedangkan perpindahan ke status IN_PROGRESS dicatat sebagai dimulainya stage
+	tracking := models.POStageTracking{
+		ProductionOrderID: req.POID,
+		Stage:             toStage,
+		Status:            req.To,
+		HandledBy:         req.HandledBy,
+		Notes:             req.Notes,
+	}
+	if fromStage != toStage {
+		tracking.Stage = fromStage
+		tracking.StartedAt = req.StartedAt
+		tracking.CompletedAt = &now
+		if tracking.StartedAt != nil {
+			tracking.UpdateDuration()
+		}
+	} else if models.IsInProgressStatus(req.To) {
+		tracking.StartedAt = &now
+	} else if req.To == models.StatusPOCancelled {
+		tracking.CompletedAt = &now
+	}
+
+	if err := tx.Create(&tracking).Error; err != nil {
+		return nil, err
+	}
+
+	// Event perpindahan status dipublish ke queue stage asal dan stage tujuan setelah commit
+	RecordDomainEventInTx(tx, NewPOStatusChangedEvent(po, req.To, req.HandledBy))
+
+	return &tracking, nil
+}
+
+// NewPOStatusChangedEvent membuat event perpindahan status PO dari status PO sebelum diubah
+func NewPOStatusChangedEvent(po models.ProductionOrder, to models.POStatus, handledBy *uint64) DomainEvent {
+	// Status tanpa stage (CANCELLED) tetap berada di stage terakhir PO
+	toStage, err := models.StageAfterTransition(po.CurrentStatus, to)
+	if err != nil {
+		toStage = po.CurrentStage
+	}
+
+	var topics []string
+	if topic := TopicForStage(po.CurrentStage); topic != "" {
+		topics = append(topics, topic)
+	}
+	if topic := TopicForStage(toStage); topic != "" && toStage != po.CurrentStage {
+		topics = append(topics, topic)
+	}
+
+	return DomainEvent{
+		Type:   EventPOStatusChanged,
+		Topics: topics,
+		Data: POStatusChangedData{
+			POID:       po.ID,
+			PONumber:   po.PONumber,
+			OBCNumber:  po.OBCNumber,
+			FromStage:  po.CurrentStage,
+			FromStatus: po.CurrentStatus,
+			ToStage:    toStage,
+			ToStatus:   to,
+			HandledBy:  handledBy,
+		},
+	}
+}
@@ -0,0 +1,471 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sirine-go/backend/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk print job operations
+var (
+	ErrPrintJobNotFound       = errors.New("print job tidak ditemukan")
+	ErrPrintJobAlreadyStarted = errors.New("print job untuk PO ini sudah dimulai")
+	ErrPrintJobNotInProgress  = errors.New("print job tidak dalam status IN_PROGRESS")
+	ErrPrintJobNotPaused      = errors.New("print job tidak dalam status PAUSED")
+	ErrPrintJobCompleted      = errors.New("print job sudah selesai")
+	ErrInvalidPrintOperator   = errors.New("operator harus user OPERATOR_CETAK yang aktif")
+	ErrInvalidSheetCount      = errors.New("jumlah lembar tidak boleh negatif")
+	ErrNoGoodSheets           = errors.New("jumlah lembar baik harus lebih dari 0 untuk finalize")
+)
+
+// PrintJobService merupakan service untuk workflow cetak di Unit Cetak
+// yang mencakup start, pause, resume, progress logging, dan finalize print job
+type PrintJobService struct {
+	db          *gorm.DB
+	transitions *POTransitionService
+}
+
+// NewPrintJobService membuat instance baru dari PrintJobService
+func NewPrintJobService(db *gorm.DB) *PrintJobService {
+	return &PrintJobService{
+		db:          db,
+		transitions: NewPOTransitionService(db),
+	}
+}
+
+// StartPrintJobRequest merupakan parameter untuk memulai print job
+type StartPrintJobRequest struct {
+	MachineID  uint64  `json:"machine_id" binding:"required"`
+	OperatorID *uint64 `json:"operator_id"`
+	Notes      string  `json:"notes"`
+}
+
+// PrintProgressRequest merupakan parameter untuk mencatat progress lembar baik dan rusak
+type PrintProgressRequest struct {
+	GoodSheets     int    `json:"good_sheets"`
+	RejectedSheets int    `json:"rejected_sheets"`
+	Notes          string `json:"notes"`
+}
+
+// StartPrintJob memulai print job untuk PO dengan status READY_FOR_CETAK
+// dengan assignment mesin dan operator, lalu memindahkan PO ke CETAK_IN_PROGRESS
+func (s *PrintJobService) StartPrintJob(poID uint64, req StartPrintJobRequest, userID uint64) (*models.PrintJob, error) {
+	operatorID := userID
+	if req.OperatorID != nil {
+		operatorID = *req.OperatorID
+	}
+
+	eventDB, events := WithDomainEvents(s.db)
+	tx := eventDB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Pindahkan PO ke CETAK_IN_PROGRESS via state machine (lock + validasi status)
+	if _, err := s.transitions.TransitionInTx(tx, POTransitionRequest{
+		POID:      poID,
+		To:        models.StatusCetakInProgress,
+		HandledBy: &operatorID,
+		Notes:     "Proses cetak dimulai",
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 2. Validasi belum ada print job untuk PO ini
+	var existing int64
+	if err := tx.Model(&models.PrintJob{}).
+		Where("production_order_id = ?", poID).
+		Count(&existing).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if existing > 0 {
+		tx.Rollback()
+		return nil, ErrPrintJobAlreadyStarted
+	}
+
+	// 3. Validasi mesin dan operator
+	if err := s.validateMachine(tx, req.MachineID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.validateOperator(tx, operatorID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 4. Create print job
+	now := time.Now()
+	job := models.PrintJob{
+		ProductionOrderID: poID,
+		MachineID:         req.MachineID,
+		OperatorID:        operatorID,
+		Status:            models.PrintJobInProgress,
+		StartedAt:         &now,
+		Notes:             req.Notes,
+	}
+	if err := tx.Create(&job).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 5. Catat log START dan tandai mesin RUNNING
+	if err := s.createLog(tx, job.ID, models.PrintJobLogStart, 0, 0, req.Notes, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := setMachineStatus(tx, req.MachineID, models.MachineRunning); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	events.Publish()
+
+	return s.GetPrintJob(job.ID)
+}
+
+// PausePrintJob menghentikan sementara print job yang sedang berjalan
+func (s *PrintJobService) PausePrintJob(jobID uint64, reason string, userID uint64) (*models.PrintJob, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		job, err := s.lockPrintJob(tx, jobID)
+		if err != nil {
+			return err
+		}
+		if !job.IsInProgress() {
+			return ErrPrintJobNotInProgress
+		}
+
+		now := time.Now()
+		if err := tx.Model(job).Updates(map[string]interface{}{
+			"status":    models.PrintJobPaused,
+			"paused_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		return s.createLog(tx, job.ID, models.PrintJobLogPause, 0, 0, reason, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPrintJob(jobID)
+}
+
+// ResumePrintJob melanjutkan print job yang sedang di-pause
+// dan mengakumulasi durasi pause ke total_paused_minutes
+func (s *PrintJobService) ResumePrintJob(jobID uint64, notes string, userID uint64) (*models.PrintJob, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		job, err := s.lockPrintJob(tx, jobID)
+		if err != nil {
+			return err
+		}
+		if !job.IsPaused() {
+			return ErrPrintJobNotPaused
+		}
+
+		if err := tx.Model(job).Updates(map[string]interface{}{
+			"status":               models.PrintJobInProgress,
+			"paused_at":            nil,
+			"total_paused_minutes": job.TotalPausedMinutes + pausedMinutes(job, time.Now()),
+		}).Error; err != nil {
+			return err
+		}
+
+		return s.createLog(tx, job.ID, models.PrintJobLogResume, 0, 0, notes, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPrintJob(jobID)
+}
+
+// LogProgress mencatat progress cetak berupa tambahan lembar baik dan rusak
+func (s *PrintJobService) LogProgress(jobID uint64, req PrintProgressRequest, userID uint64) (*models.PrintJob, error) {
+	if req.GoodSheets < 0 || req.RejectedSheets < 0 {
+		return nil, ErrInvalidSheetCount
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		job, err := s.lockPrintJob(tx, jobID)
+		if err != nil {
+			return err
+		}
+		if !job.IsInProgress() {
+			return ErrPrintJobNotInProgress
+		}
+
+		if err := tx.Model(job).Updates(map[string]interface{}{
+			"good_sheets":     job.GoodSheets + req.GoodSheets,
+			"rejected_sheets": job.RejectedSheets + req.RejectedSheets,
+		}).Error; err != nil {
+			return err
+		}
+
+		return s.createLog(tx, job.ID, models.PrintJobLogProgress, req.GoodSheets, req.RejectedSheets, req.Notes, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPrintJob(jobID)
+}
+
+// FinalizePrintJob menyelesaikan print job, membuat PrintJobSummary,
+// memindahkan PO ke WAITING_COUNTING, dan mengirim notifikasi ke Staff Khazwal
+func (s *PrintJobService) FinalizePrintJob(jobID uint64, req PrintProgressRequest, userID uint64) (*models.PrintJobSummary, error) {
+	if req.GoodSheets < 0 || req.RejectedSheets < 0 {
+		return nil, ErrInvalidSheetCount
+	}
+
+	eventDB, events := WithDomainEvents(s.db)
+	tx := eventDB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Lock print job dan validasi status
+	job, err := s.lockPrintJob(tx, jobID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if job.IsCompleted() {
+		tx.Rollback()
+		return nil, ErrPrintJobCompleted
+	}
+
+	// 2. Akumulasi counts terakhir dan tutup pause yang masih terbuka
+	now := time.Now()
+	job.GoodSheets += req.GoodSheets
+	job.RejectedSheets += req.RejectedSheets
+	if job.GoodSheets <= 0 {
+		tx.Rollback()
+		return nil, ErrNoGoodSheets
+	}
+	if job.IsPaused() {
+		job.TotalPausedMinutes += pausedMinutes(job, now)
+	}
+	durationMinutes := job.CalculateDurationMinutes(now)
+
+	// 3. Update print job ke COMPLETED
+	if err := tx.Model(job).Updates(map[string]interface{}{
+		"status":               models.PrintJobCompleted,
+		"good_sheets":          job.GoodSheets,
+		"rejected_sheets":      job.RejectedSheets,
+		"paused_at":            nil,
+		"total_paused_minutes": job.TotalPausedMinutes,
+		"completed_at":         now,
+		"duration_minutes":     durationMinutes,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.createLog(tx, job.ID, models.PrintJobLogFinalize, req.GoodSheets, req.RejectedSheets, req.Notes, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Mesin kembali IDLE setelah print job selesai
+	if err := setMachineStatus(tx, job.MachineID, models.MachineIdle); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 4. Create print job summary untuk queue penghitungan
+	summary := models.PrintJobSummary{
+		ProductionOrderID:   job.ProductionOrderID,
+		PrintJobID:          job.ID,
+		MachineID:           job.MachineID,
+		OperatorID:          job.OperatorID,
+		TotalGoodSheets:     job.GoodSheets,
+		TotalRejectedSheets: job.RejectedSheets,
+		RejectPercentage:    job.RejectPercentage(),
+		DurationMinutes:     durationMinutes,
+		TotalPausedMinutes:  job.TotalPausedMinutes,
+		FinalizedAt:         now,
+		Notes:               req.Notes,
+	}
+	if err := tx.Create(&summary).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Akumulasi impression ke plat yang dipakai PO (lembar baik + rusak)
+	if err := recordPlatImpressions(tx, job.ProductionOrderID, int64(job.GoodSheets+job.RejectedSheets)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 5. Pindahkan PO ke WAITING_COUNTING via state machine
+	if _, err := s.transitions.TransitionInTx(tx, POTransitionRequest{
+		POID:      job.ProductionOrderID,
+		To:        models.StatusWaitingCounting,
+		HandledBy: &job.OperatorID,
+		Notes:     fmt.Sprintf("Cetak selesai. Lembar baik: %d, rusak: %d", job.GoodSheets, job.RejectedSheets),
+		StartedAt: job.StartedAt,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	RecordDomainEventInTx(tx, NewFinalizedEvent(EventPrintJobFinalized, models.StageCetak, job.ProductionOrderID, job.ID, &job.OperatorID))
+
+	// 6. Notifikasi serah terima ke staff Khazwal untuk penghitungan
+	var po models.ProductionOrder
+	if err := tx.Select("id", "po_number", "obc_number").First(&po, job.ProductionOrderID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := NotifyHandoverInTx(tx, po.ID, models.StageKhazwalCounting,
+		"Siap Dihitung - PO #"+po.OBCNumber,
+		fmt.Sprintf("Cetak PO #%d (OBC %s) telah selesai. Silakan lakukan penghitungan.", po.PONumber, po.OBCNumber),
+	); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Eskalasi ke manager Unit Cetak dan PPIC jika lembar rusak melebihi threshold
+	if job.RejectPercentage() > models.RejectThresholdPercentage {
+		if err := NotifyEscalationInTx(tx, po.ID, models.StageCetak,
+			"Lembar Rusak Tinggi - PO #"+po.OBCNumber,
+			fmt.Sprintf("Cetak PO #%d (OBC %s) selesai dengan %.2f%% lembar rusak (%d dari %d lembar).", po.PONumber, po.OBCNumber, job.RejectPercentage(), job.RejectedSheets, job.TotalSheets()),
+		); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// 7. Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	events.Publish()
+
+	return &summary, nil
+}
+
+// GetPrintJob mengambil print job dengan relasi mesin, operator, dan progress logs
+func (s *PrintJobService) GetPrintJob(jobID uint64) (*models.PrintJob, error) {
+	var job models.PrintJob
+	if err := s.db.
+		Preload("Machine").
+		Preload("Operator").
+		Preload("Logs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&job, jobID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPrintJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetPrintJobByPO mengambil print job berdasarkan Production Order ID
+func (s *PrintJobService) GetPrintJobByPO(poID uint64) (*models.PrintJob, error) {
+	var job models.PrintJob
+	if err := s.db.Select("id").Where("production_order_id = ?", poID).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPrintJobNotFound
+		}
+		return nil, err
+	}
+	return s.GetPrintJob(job.ID)
+}
+
+// GetActivePrintJobs mengambil list print job yang sedang berjalan atau di-pause
+// untuk monitoring mesin di Unit Cetak
+func (s *PrintJobService) GetActivePrintJobs() ([]models.PrintJob, error) {
+	var jobs []models.PrintJob
+	err := s.db.
+		Preload("ProductionOrder").
+		Preload("Machine").
+		Preload("Operator").
+		Where("status IN ?", []models.PrintJobStatus{models.PrintJobInProgress, models.PrintJobPaused}).
+		Order("started_at ASC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// lockPrintJob mengambil print job dengan row lock untuk prevent concurrent updates
+func (s *PrintJobService) lockPrintJob(tx *gorm.DB, jobID uint64) (*models.PrintJob, error) {
+	var job models.PrintJob
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, jobID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPrintJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// validateMachine memastikan mesin terdaftar di registry sebagai mesin CETAK yang operasional
+// dan tidak sedang dipakai oleh print job lain
+func (s *PrintJobService) validateMachine(tx *gorm.DB, machineID uint64) error {
+	if _, err := findOperationalMachine(tx, machineID, models.MachineTypeCetak); err != nil {
+		return err
+	}
+
+	var busy int64
+	if err := tx.Model(&models.PrintJob{}).
+		Where("machine_id = ?", machineID).
+		Where("status IN ?", []models.PrintJobStatus{models.PrintJobInProgress, models.PrintJobPaused}).
+		Count(&busy).Error; err != nil {
+		return err
+	}
+	if busy > 0 {
+		return ErrMachineBusy
+	}
+	return nil
+}
+
+// validateOperator memastikan operator merupakan user OPERATOR_CETAK yang aktif
+func (s *PrintJobService) validateOperator(tx *gorm.DB, operatorID uint64) error {
+	var operator models.User
+	if err := tx.Select("id", "role", "status").First(&operator, operatorID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrInvalidPrintOperator
+		}
+		return err
+	}
+	if operator.Role != models.RoleOperatorCetak || operator.Status != models.StatusActive {
+		return ErrInvalidPrintOperator
+	}
+	return nil
+}
+
+// createLog mencatat event ke progress log print job
+func (s *PrintJobService) createLog(tx *gorm.DB, jobID uint64, logType models.PrintJobLogType, good, rejected int, notes string, userID uint64) error {
+	return tx.Create(&models.PrintJobLog{
+		PrintJobID:     jobID,
+		Type:           logType,
+		GoodSheets:     good,
+		RejectedSheets: rejected,
+		Notes:          notes,
+		LoggedBy:       userID,
+	}).Error
+}
+
+// pausedMinutes menghitung durasi pause yang sedang berjalan sampai waktu tertentu
+func pausedMinutes(job *models.PrintJob, until time.Time) int {
+	if job.PausedAt == nil {
+		return 0
+	}
+	return int(until.Sub(*job.PausedAt).Minutes())
+}
@@ -0,0 +1,600 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sirine-go/backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk Production Order management
+var (
+	ErrPONotFound             = errors.New("production order tidak ditemukan")
+	ErrPONotEditable          = errors.New("PO sudah selesai atau dibatalkan dan tidak bisa diubah")
+	ErrPONotCancellable       = errors.New("PO sedang dikerjakan atau sudah selesai sehingga tidak bisa dibatalkan")
+	ErrPONotSplittable        = errors.New("PO hanya bisa di-split atau di-merge saat status WAITING_MATERIAL_PREP")
+	ErrInvalidPriority        = errors.New("priority harus URGENT, NORMAL, atau LOW")
+	ErrInvalidDueDate         = errors.New("due_date tidak valid, gunakan format YYYY-MM-DD")
+	ErrInvalidSplitQuantities = errors.New("quantity split harus > 0 dan totalnya sama dengan quantity PO")
+	ErrMergeRequiresMultiple  = errors.New("merge membutuhkan minimal 2 PO yang berbeda")
+	ErrMergeDifferentOBC      = errors.New("PO yang di-merge harus berasal dari OBC yang sama")
+	ErrMergeExceedsMaximum    = errors.New("total quantity hasil merge melebihi batas maksimal per PO")
+	ErrCancelReasonRequired   = errors.New("alasan pembatalan wajib diisi")
+)
+
+// ProductionOrderService merupakan service untuk manajemen Production Order oleh PPIC
+// yang mencakup list dengan filter, edit priority/due date, split, merge, dan cancel PO
+type ProductionOrderService struct {
+	db          *gorm.DB
+	poNumbers   *PONumberGenerator
+	transitions *POTransitionService
+}
+
+// NewProductionOrderService membuat instance baru dari ProductionOrderService
+func NewProductionOrderService(db *gorm.DB, poNumbers *PONumberGenerator) *ProductionOrderService {
+	return &ProductionOrderService{
+		db:          db,
+		poNumbers:   poNumbers,
+		transitions: NewPOTransitionService(db),
+	}
+}
+
+// ProductionOrderFilters merupakan struct untuk filter dan pagination list PO
+type ProductionOrderFilters struct {
+	Stage     string `form:"stage"`
+	Status    string `form:"status"`
+	OBCNumber string `form:"obc_number"`
+	Priority  string `form:"priority"`
+	DueFrom   string `form:"due_from"`
+	DueTo     string `form:"due_to"`
+	Search    string `form:"search"`
+	Page      int    `form:"page"`
+	PerPage   int    `form:"per_page"`
+}
+
+// ProductionOrderListResponse merupakan struct untuk paginated list PO
+type ProductionOrderListResponse struct {
+	Items      []models.ProductionOrder `json:"items"`
+	Total      int                      `json:"total"`
+	Page       int                      `json:"page"`
+	PerPage    int                      `json:"per_page"`
+	TotalPages int                      `json:"total_pages"`
+}
+
+// UpdateProductionOrderRequest merupakan request untuk edit priority, due date, dan catatan PO
+type UpdateProductionOrderRequest struct {
+	Priority *models.POPriority `json:"priority"`
+	DueDate  *string            `json:"due_date"`
+	Notes    *string            `json:"notes"`
+}
+
+// SplitProductionOrderRequest merupakan request untuk memecah satu PO menjadi beberapa PO
+type SplitProductionOrderRequest struct {
+	Quantities []int  `json:"quantities" binding:"required,min=2"`
+	Reason     string `json:"reason"`
+}
+
+// MergeProductionOrdersRequest merupakan request untuk menggabungkan PO sisa yang kecil
+type MergeProductionOrdersRequest struct {
+	POIDs  []uint64 `json:"po_ids" binding:"required,min=2"`
+	Reason string   `json:"reason"`
+}
+
+// CancelProductionOrderRequest merupakan request untuk membatalkan PO
+type CancelProductionOrderRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ListProductionOrders mengambil list PO dengan filter stage, status, OBC, priority, dan due window
+func (s *ProductionOrderService) ListProductionOrders(filters ProductionOrderFilters) (*ProductionOrderListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.ProductionOrder{})
+	if filters.Stage != "" {
+		query = query.Where("current_stage = ?", filters.Stage)
+	}
+	if filters.Status != "" {
+		query = query.Where("current_status = ?", filters.Status)
+	}
+	if filters.OBCNumber != "" {
+		query = query.Where("obc_number LIKE ?", "%"+filters.OBCNumber+"%")
+	}
+	if filters.Priority != "" {
+		query = query.Where("priority = ?", filters.Priority)
+	}
+	if filters.DueFrom != "" {
+		dueFrom, err := time.Parse("2006-01-02", filters.DueFrom)
+		if err != nil {
+			return nil, ErrInvalidDueDate
+		}
+		query = query.Where("due_date >= ?", dueFrom)
+	}
+	if filters.DueTo != "" {
+		dueTo, err := time.Parse("2006-01-02", filters.DueTo)
+		if err != nil {
+			return nil, ErrInvalidDueDate
+		}
+		query = query.Where("due_date <= ?", dueTo)
+	}
+	if filters.Search != "" {
+		searchPattern := "%" + filters.Search + "%"
+		query = query.Where("CAST(po_number AS CHAR) LIKE ? OR product_name LIKE ?", searchPattern, searchPattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var orders []models.ProductionOrder
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Order("priority_score DESC, due_date ASC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &ProductionOrderListResponse{
+		Items:      orders,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetProductionOrder mengambil detail PO beserta stage tracking
+func (s *ProductionOrderService) GetProductionOrder(id uint64) (*models.ProductionOrder, error) {
+	var po models.ProductionOrder
+	if err := s.db.Preload("StageTracking", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).First(&po, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPONotFound
+		}
+		return nil, err
+	}
+	return &po, nil
+}
+
+// UpdateProductionOrder mengubah priority, due date, dan catatan PO
+// dengan recompute PriorityScore dan audit log perubahan
+func (s *ProductionOrderService) UpdateProductionOrder(id uint64, req UpdateProductionOrderRequest, userID uint64) (*models.ProductionOrder, error) {
+	var result models.ProductionOrder
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		po, err := s.lockPO(tx, id)
+		if err != nil {
+			return err
+		}
+		if !po.IsEditable() {
+			return ErrPONotEditable
+		}
+
+		before := map[string]interface{}{
+			"priority":       po.Priority,
+			"due_date":       po.DueDate.Format("2006-01-02"),
+			"priority_score": po.PriorityScore,
+			"notes":          po.Notes,
+		}
+
+		if req.Priority != nil {
+			if !isValidPriority(*req.Priority) {
+				return ErrInvalidPriority
+			}
+			po.Priority = *req.Priority
+		}
+		if req.DueDate != nil {
+			dueDate, err := time.Parse("2006-01-02", *req.DueDate)
+			if err != nil {
+				return ErrInvalidDueDate
+			}
+			po.DueDate = dueDate
+		}
+		if req.Notes != nil {
+			po.Notes = *req.Notes
+		}
+		po.UpdatePriorityScore()
+
+		if err := tx.Model(&models.ProductionOrder{}).
+			Where("id = ?", po.ID).
+			Updates(map[string]interface{}{
+				"priority":       po.Priority,
+				"due_date":       po.DueDate,
+				"priority_score": po.PriorityScore,
+				"notes":          po.Notes,
+			}).Error; err != nil {
+			return err
+		}
+
+		after := map[string]interface{}{
+			"priority":       po.Priority,
+			"due_date":       po.DueDate.Format("2006-01-02"),
+			"priority_score": po.PriorityScore,
+			"notes":          po.Notes,
+		}
+		if err := s.logPOAudit(tx, userID, models.ActionPOUpdate, po.ID,
+			fmt.Sprintf("Update priority/due date PO %d", po.PONumber), before, after); err != nil {
+			return err
+		}
+
+		result = *po
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SplitProductionOrder memecah satu PO menjadi beberapa PO sesuai list quantity,
+// PO asal mempertahankan quantity pertama dan PO baru mencatat parent_po_id
+func (s *ProductionOrderService) SplitProductionOrder(id uint64, req SplitProductionOrderRequest, userID uint64) ([]models.ProductionOrder, error) {
+	var result []models.ProductionOrder
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		po, err := s.lockPO(tx, id)
+		if err != nil {
+			return err
+		}
+		if po.CurrentStatus != models.StatusWaitingMaterialPrep {
+			return ErrPONotSplittable
+		}
+		if err := validateSplitQuantities(req.Quantities, po.QuantityOrdered); err != nil {
+			return err
+		}
+
+		original := *po
+		poNumbers, err := s.nextPONumbers(tx, po, len(req.Quantities)-1)
+		if err != nil {
+			return err
+		}
+		rule, err := loadProductionRule(tx, po.ProductionRuleID)
+		if err != nil {
+			return err
+		}
+
+		// Update PO asal dengan quantity pertama
+		po.ApplyQuantity(req.Quantities[0], rule)
+		if err := tx.Model(&models.ProductionOrder{}).
+			Where("id = ?", po.ID).
+			Updates(map[string]interface{}{
+				"quantity_ordered":             po.QuantityOrdered,
+				"quantity_target_lembar_besar": po.QuantityTargetLembarBesar,
+				"estimated_rims":               po.EstimatedRims,
+			}).Error; err != nil {
+			return err
+		}
+		result = append(result, *po)
+
+		// Create PO baru untuk sisa quantity
+		for i, qty := range req.Quantities[1:] {
+			child := newDerivedPO(&original, poNumbers[i])
+			child.ParentPOID = &original.ID
+			child.ApplyQuantity(qty, rule)
+			if err := tx.Create(&child).Error; err != nil {
+				return fmt.Errorf("gagal membuat PO hasil split: %w", err)
+			}
+			result = append(result, child)
+		}
+
+		childNumbers := make([]int64, 0, len(result)-1)
+		for _, child := range result[1:] {
+			childNumbers = append(childNumbers, child.PONumber)
+		}
+		description := fmt.Sprintf("Split PO %d menjadi %d PO", original.PONumber, len(result))
+		if req.Reason != "" {
+			description += ": " + req.Reason
+		}
+		return s.logPOAudit(tx, userID, models.ActionPOSplit, original.ID, description,
+			map[string]interface{}{"quantity_ordered": original.QuantityOrdered},
+			map[string]interface{}{"quantities": req.Quantities, "new_po_numbers": childNumbers})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MergeProductionOrders menggabungkan beberapa PO sisa dari OBC yang sama ke PO dengan quantity terbesar,
+// PO sumber dibatalkan dengan merged_into_po_id menunjuk PO target
+func (s *ProductionOrderService) MergeProductionOrders(req MergeProductionOrdersRequest, userID uint64) (*models.ProductionOrder, error) {
+	ids := uniquePOIDs(req.POIDs)
+	if len(ids) < 2 {
+		return nil, ErrMergeRequiresMultiple
+	}
+
+	var result models.ProductionOrder
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		var orders []models.ProductionOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Order("quantity_ordered DESC, id ASC").
+			Find(&orders).Error; err != nil {
+			return err
+		}
+		if len(orders) != len(ids) {
+			return ErrPONotFound
+		}
+
+		totalQty := 0
+		for _, po := range orders {
+			if po.CurrentStatus != models.StatusWaitingMaterialPrep {
+				return ErrPONotSplittable
+			}
+			if po.OBCMasterID != orders[0].OBCMasterID {
+				return ErrMergeDifferentOBC
+			}
+			totalQty += po.QuantityOrdered
+		}
+		// Batas maksimal mengikuti production rule yang dipakai PO target
+		rule, err := loadProductionRule(tx, orders[0].ProductionRuleID)
+		if err != nil {
+			return err
+		}
+		if totalQty > rule.MaxQuantityPerPO {
+			return ErrMergeExceedsMaximum
+		}
+
+		// PO target = quantity terbesar, mengambil priority tertinggi dan due date paling awal
+		target := orders[0]
+		before := map[string]interface{}{"quantity_ordered": target.QuantityOrdered}
+		sourceNumbers := make([]int64, 0, len(orders)-1)
+		for _, source := range orders[1:] {
+			if priorityRank(source.Priority) > priorityRank(target.Priority) {
+				target.Priority = source.Priority
+			}
+			if source.DueDate.Before(target.DueDate) {
+				target.DueDate = source.DueDate
+			}
+			sourceNumbers = append(sourceNumbers, source.PONumber)
+		}
+		target.ApplyQuantity(totalQty, rule)
+		target.UpdatePriorityScore()
+
+		if err := tx.Model(&models.ProductionOrder{}).
+			Where("id = ?", target.ID).
+			Updates(map[string]interface{}{
+				"quantity_ordered":             target.QuantityOrdered,
+				"quantity_target_lembar_besar": target.QuantityTargetLembarBesar,
+				"estimated_rims":               target.EstimatedRims,
+				"priority":                     target.Priority,
+				"due_date":                     target.DueDate,
+				"priority_score":               target.PriorityScore,
+			}).Error; err != nil {
+			return err
+		}
+
+		reason := fmt.Sprintf("Digabung ke PO %d", target.PONumber)
+		if req.Reason != "" {
+			reason += ": " + req.Reason
+		}
+		for _, source := range orders[1:] {
+			if err := s.cancelInTx(tx, &source, reason, userID, &target.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.logPOAudit(tx, userID, models.ActionPOMerge, target.ID,
+			fmt.Sprintf("Merge %d PO ke PO %d", len(sourceNumbers), target.PONumber),
+			before,
+			map[string]interface{}{"quantity_ordered": target.QuantityOrdered, "merged_po_numbers": sourceNumbers}); err != nil {
+			return err
+		}
+
+		result = target
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CancelProductionOrder membatalkan PO dengan alasan dan mencatat stage tracking serta audit log
+func (s *ProductionOrderService) CancelProductionOrder(id uint64, req CancelProductionOrderRequest, userID uint64) (*models.ProductionOrder, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrCancelReasonRequired
+	}
+
+	var result models.ProductionOrder
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		po, err := s.lockPO(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.cancelInTx(tx, po, reason, userID, nil); err != nil {
+			return err
+		}
+		result = *po
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// cancelInTx membatalkan PO di dalam transaction melalui state machine, digunakan oleh cancel dan merge,
+// plat yang masih dipakai PO dikembalikan agar bisa diambil PO lain
+func (s *ProductionOrderService) cancelInTx(tx *gorm.DB, po *models.ProductionOrder, reason string, userID uint64, mergedInto *uint64) error {
+	if !models.CanCancelPO(po.CurrentStatus) {
+		return ErrPONotCancellable
+	}
+
+	previousStatus := po.CurrentStatus
+	if _, err := s.transitions.TransitionInTx(tx, POTransitionRequest{
+		POID:      po.ID,
+		To:        models.StatusPOCancelled,
+		HandledBy: &userID,
+		Notes:     reason,
+	}); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	po.CurrentStatus = models.StatusPOCancelled
+	po.CancelledAt = &now
+	po.CancelledBy = &userID
+	po.CancellationReason = reason
+	po.MergedIntoPOID = mergedInto
+
+	if err := tx.Model(&models.ProductionOrder{}).
+		Where("id = ?", po.ID).
+		Updates(map[string]interface{}{
+			"cancelled_at":        po.CancelledAt,
+			"cancelled_by":        po.CancelledBy,
+			"cancellation_reason": po.CancellationReason,
+			"merged_into_po_id":   po.MergedIntoPOID,
+		}).Error; err != nil {
+		return err
+	}
+
+	releasedPlats, err := releasePlatsInTx(tx, po.ID, userID, fmt.Sprintf("Dikembalikan karena PO %d dibatalkan", po.PONumber))
+	if err != nil {
+		return err
+	}
+
+	return s.logPOAudit(tx, userID, models.ActionPOCancel, po.ID,
+		fmt.Sprintf("Cancel PO %d: %s", po.PONumber, reason),
+		map[string]interface{}{"current_status": previousStatus},
+		map[string]interface{}{"current_status": po.CurrentStatus, "merged_into_po_id": mergedInto, "released_plats": releasedPlats})
+}
+
+// lockPO mengambil PO dengan row lock di dalam transaction
+func (s *ProductionOrderService) lockPO(tx *gorm.DB, id uint64) (*models.ProductionOrder, error) {
+	var po models.ProductionOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&po, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPONotFound
+		}
+		return nil, err
+	}
+	return &po, nil
+}
+
+// logPOAudit mencatat perubahan PO ke activity_logs dengan before/after values
+func (s *ProductionOrderService) logPOAudit(tx *gorm.DB, userID uint64, action models.ActivityAction, poID uint64, description string, before, after interface{}) error {
+	changes, err := json.Marshal(models.ChangeData{Before: before, After: after})
+	if err != nil {
+		return err
+	}
+
+	entityID := poID
+	log := models.ActivityLog{
+		UserID:            userID,
+		Action:            action,
+		EntityType:        "production_orders",
+		EntityID:          &entityID,
+		ProductionOrderID: &entityID,
+		Description:       description,
+		Changes:           changes,
+	}
+	return tx.Create(&log).Error
+}
+
+// newDerivedPO membuat PO baru yang mewarisi data OBC dan jadwal dari PO asal
+func newDerivedPO(source *models.ProductionOrder, poNumber int64) models.ProductionOrder {
+	return models.ProductionOrder{
+		PONumber:                  poNumber,
+		OBCMasterID:               source.OBCMasterID,
+		OBCNumber:                 source.OBCNumber,
+		ProductName:               source.ProductName,
+		SAPCustomerCode:           source.SAPCustomerCode,
+		SAPProductCode:            source.SAPProductCode,
+		ProductSpecifications:     source.ProductSpecifications,
+		QuantityOrdered:           source.QuantityOrdered,
+		QuantityTargetLembarBesar: source.QuantityTargetLembarBesar,
+		OrderDate:                 source.OrderDate,
+		DueDate:                   source.DueDate,
+		Priority:                  source.Priority,
+		PriorityScore:             source.PriorityScore,
+		CurrentStage:              models.StageKhazwalMaterialPrep,
+		CurrentStatus:             models.StatusWaitingMaterialPrep,
+		ProductionRuleID:          source.ProductionRuleID,
+		ProductionRuleVersion:     source.ProductionRuleVersion,
+	}
+}
+
+// nextPONumbers mengalokasikan nomor PO baru dari sequence sesuai plant OBC asal PO
+func (s *ProductionOrderService) nextPONumbers(tx *gorm.DB, po *models.ProductionOrder, count int) ([]int64, error) {
+	var plantCodes []string
+	if err := tx.Model(&models.OBCMaster{}).
+		Where("id = ?", po.OBCMasterID).
+		Pluck("plant_code", &plantCodes).Error; err != nil {
+		return nil, err
+	}
+
+	plantCode := ""
+	if len(plantCodes) > 0 {
+		plantCode = plantCodes[0]
+	}
+	return s.poNumbers.Next(tx, plantCode, count)
+}
+
+// validateSplitQuantities memvalidasi list quantity split terhadap quantity PO asal
+func validateSplitQuantities(quantities []int, total int) error {
+	if len(quantities) < 2 {
+		return ErrInvalidSplitQuantities
+	}
+	sum := 0
+	for _, qty := range quantities {
+		if qty <= 0 {
+			return ErrInvalidSplitQuantities
+		}
+		sum += qty
+	}
+	if sum != total {
+		return ErrInvalidSplitQuantities
+	}
+	return nil
+}
+
+// uniquePOIDs menghapus duplikasi ID dengan mempertahankan urutan
+func uniquePOIDs(ids []uint64) []uint64 {
+	seen := make(map[uint64]bool, len(ids))
+	result := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// isValidPriority memeriksa apakah priority termasuk enum yang valid
+func isValidPriority(priority models.POPriority) bool {
+	switch priority {
+	case models.PriorityUrgent, models.PriorityNormal, models.PriorityLow:
+		return true
+	}
+	return false
+}
+
+// priorityRank mengembalikan peringkat priority untuk perbandingan (lebih tinggi = lebih urgent)
+func priorityRank(priority models.POPriority) int {
+	switch priority {
+	case models.PriorityUrgent:
+		return 2
+	case models.PriorityNormal:
+		return 1
+	}
+	return 0
+}
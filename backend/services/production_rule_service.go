@@ -0,0 +1,340 @@
+package services
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk production rule operations
+var (
+	ErrProductionRuleNotFound   = errors.New("production rule tidak ditemukan")
+	ErrProductionRuleCodeExists = errors.New("kode production rule sudah terdaftar")
+	ErrProductionRuleInactive   = errors.New("production rule sudah tidak aktif")
+	ErrDefaultRuleRequired      = errors.New("DEFAULT production rule tidak dapat dinonaktifkan, ubah nilainya melalui update rule")
+	ErrNoActiveDefaultRule      = errors.New("tidak ada versi aktif DEFAULT production rule")
+)
+
+// ProductionRuleService merupakan service untuk aturan pembagian OBC → PO yang versioned
+// yang mencakup CRUD rule, versioning setiap perubahan, dan pemilihan rule per OBC
+type ProductionRuleService struct {
+	db *gorm.DB
+}
+
+// NewProductionRuleService membuat instance baru dari ProductionRuleService
+func NewProductionRuleService(db *gorm.DB) *ProductionRuleService {
+	return &ProductionRuleService{
+		db: db,
+	}
+}
+
+// ProductionRuleFilters merupakan struct untuk filter dan pagination list production rule
+type ProductionRuleFilters struct {
+	Code            string `form:"code"`
+	ProductType     string `form:"product_type"`
+	Personalization string `form:"personalization"`
+	ActiveOnly      bool   `form:"active_only"`
+	Page            int    `form:"page"`
+	PerPage         int    `form:"per_page"`
+}
+
+// ProductionRuleListResponse merupakan struct untuk paginated list production rule
+type ProductionRuleListResponse struct {
+	Items      []models.ProductionRule `json:"items"`
+	Total      int                     `json:"total"`
+	Page       int                     `json:"page"`
+	PerPage    int                     `json:"per_page"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+// CreateProductionRuleRequest merupakan request untuk membuat production rule baru
+type CreateProductionRuleRequest struct {
+	Code                  string  `json:"code" binding:"required,max=50"`
+	Name                  string  `json:"name" binding:"required,max=100"`
+	ProductType           string  `json:"product_type" binding:"max=50"`
+	Personalization       string  `json:"personalization" binding:"max=20"`
+	BufferPercentage      float64 `json:"buffer_percentage"`
+	MaxQuantityPerPO      int     `json:"max_quantity_per_po" binding:"required"`
+	SheetsPerRim          int     `json:"sheets_per_rim" binding:"required"`
+	LembarBesarMultiplier int     `json:"lembar_besar_multiplier" binding:"required"`
+	Notes                 string  `json:"notes"`
+}
+
+// UpdateProductionRuleRequest merupakan request untuk mengubah production rule,
+// setiap perubahan menghasilkan versi baru dan versi lama dinonaktifkan
+type UpdateProductionRuleRequest struct {
+	Name                  *string  `json:"name" binding:"omitempty,max=100"`
+	ProductType           *string  `json:"product_type" binding:"omitempty,max=50"`
+	Personalization       *string  `json:"personalization" binding:"omitempty,max=20"`
+	BufferPercentage      *float64 `json:"buffer_percentage"`
+	MaxQuantityPerPO      *int     `json:"max_quantity_per_po"`
+	SheetsPerRim          *int     `json:"sheets_per_rim"`
+	LembarBesarMultiplier *int     `json:"lembar_besar_multiplier"`
+	Notes                 *string  `json:"notes"`
+}
+
+// ListRules mengambil list production rule dengan filter kode, tipe produk, personalisasi, dan pagination
+func (s *ProductionRuleService) ListRules(filters ProductionRuleFilters) (*ProductionRuleListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.ProductionRule{})
+	if filters.Code != "" {
+		query = query.Where("code = ?", strings.ToUpper(filters.Code))
+	}
+	if filters.ProductType != "" {
+		query = query.Where("product_type = ?", filters.ProductType)
+	}
+	if filters.Personalization != "" {
+		query = query.Where("personalization = ?", filters.Personalization)
+	}
+	if filters.ActiveOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var rules []models.ProductionRule
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Order("code ASC, version DESC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &ProductionRuleListResponse{
+		Items:      rules,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetRule mengambil detail production rule berdasarkan ID
+func (s *ProductionRuleService) GetRule(id uint64) (*models.ProductionRule, error) {
+	var rule models.ProductionRule
+	if err := s.db.First(&rule, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrProductionRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// CreateRule membuat production rule baru dengan versi 1 dan validasi kode unik
+func (s *ProductionRuleService) CreateRule(req CreateProductionRuleRequest, userID uint64) (*models.ProductionRule, error) {
+	rule := models.ProductionRule{
+		Code:                  strings.ToUpper(strings.TrimSpace(req.Code)),
+		Version:               1,
+		Name:                  req.Name,
+		ProductType:           strings.TrimSpace(req.ProductType),
+		Personalization:       strings.TrimSpace(req.Personalization),
+		BufferPercentage:      req.BufferPercentage,
+		MaxQuantityPerPO:      req.MaxQuantityPerPO,
+		SheetsPerRim:          req.SheetsPerRim,
+		LembarBesarMultiplier: req.LembarBesarMultiplier,
+		IsActive:              true,
+		Notes:                 req.Notes,
+		CreatedBy:             &userID,
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	var existing int64
+	if err := s.db.Model(&models.ProductionRule{}).Where("code = ?", rule.Code).Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrProductionRuleCodeExists
+	}
+
+	if err := s.db.Create(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateRule membuat versi baru dari production rule dengan perubahan yang diminta
+// dan menonaktifkan versi sebelumnya, sehingga PO lama tetap mereferensikan versi yang dipakai
+func (s *ProductionRuleService) UpdateRule(id uint64, req UpdateProductionRuleRequest, userID uint64) (*models.ProductionRule, error) {
+	var result models.ProductionRule
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var current models.ProductionRule
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrProductionRuleNotFound
+			}
+			return err
+		}
+		if !current.IsActive {
+			return ErrProductionRuleInactive
+		}
+
+		next := current
+		next.ID = 0
+		next.Version = current.Version + 1
+		next.CreatedBy = &userID
+		if req.Name != nil {
+			next.Name = *req.Name
+		}
+		if req.ProductType != nil {
+			next.ProductType = strings.TrimSpace(*req.ProductType)
+		}
+		if req.Personalization != nil {
+			next.Personalization = strings.TrimSpace(*req.Personalization)
+		}
+		if req.BufferPercentage != nil {
+			next.BufferPercentage = *req.BufferPercentage
+		}
+		if req.MaxQuantityPerPO != nil {
+			next.MaxQuantityPerPO = *req.MaxQuantityPerPO
+		}
+		if req.SheetsPerRim != nil {
+			next.SheetsPerRim = *req.SheetsPerRim
+		}
+		if req.LembarBesarMultiplier != nil {
+			next.LembarBesarMultiplier = *req.LembarBesarMultiplier
+		}
+		if req.Notes != nil {
+			next.Notes = *req.Notes
+		}
+		if err := next.Validate(); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.ProductionRule{}).
+			Where("id = ?", current.ID).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&next).Error; err != nil {
+			return err
+		}
+
+		result = next
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeactivateRule menonaktifkan production rule sehingga tidak dipilih lagi saat generate PO,
+// DEFAULT rule tidak dapat dinonaktifkan karena menjadi fallback untuk OBC yang tidak cocok dengan rule lain
+func (s *ProductionRuleService) DeactivateRule(id uint64) (*models.ProductionRule, error) {
+	rule, err := s.GetRule(id)
+	if err != nil {
+		return nil, err
+	}
+	if !rule.IsActive {
+		return nil, ErrProductionRuleInactive
+	}
+	if rule.Code == models.DefaultProductionRuleCode {
+		return nil, ErrDefaultRuleRequired
+	}
+
+	if err := s.db.Model(rule).Update("is_active", false).Error; err != nil {
+		return nil, err
+	}
+	rule.IsActive = false
+	return rule, nil
+}
+
+// ResolveRule memilih production rule yang berlaku untuk tipe produk dan personalisasi tertentu
+func (s *ProductionRuleService) ResolveRule(productType, personalization string) (*models.ProductionRule, error) {
+	return resolveProductionRule(s.db, productType, personalization)
+}
+
+// resolveProductionRule memilih rule aktif yang cocok dengan specificity tertinggi,
+// dan membuat DEFAULT rule sesuai Flow.md jika belum ada rule yang terdaftar
+func resolveProductionRule(tx *gorm.DB, productType, personalization string) (*models.ProductionRule, error) {
+	var rules []models.ProductionRule
+	if err := tx.Where("is_active = ?", true).
+		Order("version DESC, id DESC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	var selected *models.ProductionRule
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Matches(productType, personalization) {
+			continue
+		}
+		if selected == nil || rule.Specificity() > selected.Specificity() {
+			selected = rule
+		}
+	}
+	if selected != nil {
+		return selected, nil
+	}
+
+	return ensureDefaultProductionRule(tx)
+}
+
+// loadProductionRule mengambil rule yang dipakai PO, fallback ke DEFAULT rule untuk PO lama
+func loadProductionRule(tx *gorm.DB, ruleID *uint64) (*models.ProductionRule, error) {
+	if ruleID != nil {
+		var rule models.ProductionRule
+		err := tx.First(&rule, *ruleID).Error
+		if err == nil {
+			return &rule, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+	return ensureDefaultProductionRule(tx)
+}
+
+// ensureDefaultProductionRule mengambil versi aktif terbaru DEFAULT rule atau membuatnya jika belum pernah ada,
+// DEFAULT rule yang hanya tersisa versi nonaktif tidak dipakai agar nilai lama tidak diam-diam berlaku lagi
+func ensureDefaultProductionRule(tx *gorm.DB) (*models.ProductionRule, error) {
+	var rule models.ProductionRule
+	err := tx.Where("code = ? AND is_active = ?", models.DefaultProductionRuleCode, true).
+		Order("version DESC").
+		First(&rule).Error
+	if err == nil {
+		return &rule, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	var versions int64
+	if err := tx.Model(&models.ProductionRule{}).
+		Where("code = ?", models.DefaultProductionRuleCode).
+		Count(&versions).Error; err != nil {
+		return nil, err
+	}
+	if versions > 0 {
+		return nil, ErrNoActiveDefaultRule
+	}
+
+	rule = models.DefaultProductionRule()
+	if err := tx.Create(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
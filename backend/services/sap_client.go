@@ -0,0 +1,257 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sirine-go/backend/models"
+	"strings"
+	"time"
+)
+
+// SAP movement type yang dipakai untuk posting dokumen material
+const (
+	SAPMovementGoodsIssue   = "261" // pemakaian material ke production order
+	SAPMovementGoodsReceipt = "101" // hasil produksi yang diterima dari production order
+	SAPMovementScrap        = "551" // material rusak/waste yang di-scrap
+)
+
+// SAPPostingItem merupakan satu baris dokumen material yang diposting ke SAP
+type SAPPostingItem struct {
+	Material     string  `json:"material"`
+	Description  string  `json:"description,omitempty"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	MovementType string  `json:"movement_type"`
+}
+
+// SAPPosting merupakan payload dokumen material yang disimpan di outbox dan dikirim ke SAP
+type SAPPosting struct {
+	EventType         models.SAPOutboxEventType `json:"event_type"`
+	AggregateType     string                    `json:"aggregate_type"`
+	AggregateID       uint64                    `json:"aggregate_id"`
+	ProductionOrderID uint64                    `json:"production_order_id"`
+	PONumber          int64                     `json:"po_number"`
+	OBCNumber         string                    `json:"obc_number"`
+	PostingDate       string                    `json:"posting_date"`
+	Items             []SAPPostingItem          `json:"items"`
+}
+
+// SAPClient merupakan kontrak adapter pengiriman posting ke SAP,
+// reference yang dikembalikan disimpan sebagai nomor dokumen eksternal di outbox
+type SAPClient interface {
+	Name() string
+	Deliver(ctx context.Context, idempotencyKey string, posting *SAPPosting) (reference string, err error)
+}
+
+// ErrSAPRejected merupakan error untuk posting yang ditolak SAP
+var ErrSAPRejected = errors.New("posting ditolak SAP")
+
+// SAPFileDropClient merupakan adapter yang menulis posting sebagai file IDoc XML (MBGMCR03)
+// ke folder outbound yang diambil oleh middleware SAP
+type SAPFileDropClient struct {
+	dir    string
+	sender string
+}
+
+// NewSAPFileDropClient membuat instance baru dari SAPFileDropClient
+func NewSAPFileDropClient(dir, sender string) *SAPFileDropClient {
+	if sender == "" {
+		sender = "SIRINE"
+	}
+	return &SAPFileDropClient{
+		dir:    dir,
+		sender: sender,
+	}
+}
+
+// Name mengembalikan nama adapter
+func (c *SAPFileDropClient) Name() string {
+	return "file"
+}
+
+// idocFileNamePattern membatasi karakter nama file IDoc agar aman untuk semua filesystem
+var idocFileNamePattern = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// Deliver menulis file IDoc secara atomic (tulis file sementara lalu rename),
+// nama file berasal dari idempotency key sehingga pengiriman ulang menimpa file yang sama
+func (c *SAPFileDropClient) Deliver(ctx context.Context, idempotencyKey string, posting *SAPPosting) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return "", fmt.Errorf("gagal membuat folder outbound SAP: %w", err)
+	}
+
+	docNum := idocFileNamePattern.ReplaceAllString(idempotencyKey, "_")
+	content, err := buildMaterialIDoc(docNum, c.sender, posting)
+	if err != nil {
+		return "", err
+	}
+
+	fileName := fmt.Sprintf("MBGMCR_%s.xml", docNum)
+	tmpFile, err := os.CreateTemp(c.dir, ".tmp-"+fileName+"-*")
+	if err != nil {
+		return "", fmt.Errorf("gagal menulis file IDoc: %w", err)
+	}
+	tmpName := tmpFile.Name()
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpName)
+		return "", fmt.Errorf("gagal menulis file IDoc: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpName)
+		return "", fmt.Errorf("gagal menulis file IDoc: %w", err)
+	}
+	if err := os.Rename(tmpName, filepath.Join(c.dir, fileName)); err != nil {
+		os.Remove(tmpName)
+		return "", fmt.Errorf("gagal menulis file IDoc: %w", err)
+	}
+	return docNum, nil
+}
+
+// idocMBGMCR merupakan struktur IDoc XML goods movement (MBGMCR03) yang dihasilkan file-drop adapter
+type idocMBGMCR struct {
+	XMLName xml.Name `xml:"MBGMCR03"`
+	IDoc    struct {
+		Begin   string `xml:"BEGIN,attr"`
+		Control struct {
+			Segment string `xml:"SEGMENT,attr"`
+			DocNum  string `xml:"DOCNUM"`
+			IDocTyp string `xml:"IDOCTYP"`
+			MesTyp  string `xml:"MESTYP"`
+			SndPrn  string `xml:"SNDPRN"`
+			CreDat  string `xml:"CREDAT"`
+			CreTim  string `xml:"CRETIM"`
+		} `xml:"EDI_DC40"`
+		Header struct {
+			Segment   string `xml:"SEGMENT,attr"`
+			PstngDate string `xml:"PSTNG_DATE"`
+			RefDocNo  string `xml:"REF_DOC_NO"`
+			HeaderTxt string `xml:"HEADER_TXT"`
+		} `xml:"E1BP2017_GM_HEAD_01"`
+		Items []idocItem `xml:"E1BP2017_GM_ITEM_CREATE"`
+	} `xml:"IDOC"`
+}
+
+// idocItem merupakan segment item goods movement di IDoc MBGMCR03
+type idocItem struct {
+	Segment  string `xml:"SEGMENT,attr"`
+	Material string `xml:"MATERIAL"`
+	MoveType string `xml:"MOVE_TYPE"`
+	EntryQnt string `xml:"ENTRY_QNT"`
+	EntryUom string `xml:"ENTRY_UOM"`
+	OrderID  string `xml:"ORDERID"`
+	ItemText string `xml:"ITEM_TEXT,omitempty"`
+}
+
+// buildMaterialIDoc menyusun file IDoc XML dari payload posting
+func buildMaterialIDoc(docNum, sender string, posting *SAPPosting) ([]byte, error) {
+	now := time.Now()
+	var doc idocMBGMCR
+	doc.IDoc.Begin = "1"
+	doc.IDoc.Control.Segment = "1"
+	doc.IDoc.Control.DocNum = docNum
+	doc.IDoc.Control.IDocTyp = "MBGMCR03"
+	doc.IDoc.Control.MesTyp = "MBGMCR"
+	doc.IDoc.Control.SndPrn = sender
+	doc.IDoc.Control.CreDat = now.Format("20060102")
+	doc.IDoc.Control.CreTim = now.Format("150405")
+
+	doc.IDoc.Header.Segment = "1"
+	doc.IDoc.Header.PstngDate = strings.ReplaceAll(posting.PostingDate, "-", "")
+	doc.IDoc.Header.RefDocNo = fmt.Sprintf("%d", posting.PONumber)
+	doc.IDoc.Header.HeaderTxt = fmt.Sprintf("%s OBC %s", posting.EventType, posting.OBCNumber)
+
+	for _, item := range posting.Items {
+		doc.IDoc.Items = append(doc.IDoc.Items, idocItem{
+			Segment:  "1",
+			Material: item.Material,
+			MoveType: item.MovementType,
+			EntryQnt: fmt.Sprintf("%.3f", item.Quantity),
+			EntryUom: item.Unit,
+			OrderID:  fmt.Sprintf("%d", posting.PONumber),
+			ItemText: item.Description,
+		})
+	}
+
+	content, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyusun IDoc: %w", err)
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+// SAPHTTPClient merupakan adapter yang mengirim posting sebagai JSON ke endpoint HTTP
+// (gateway SAP atau mock server untuk testing)
+type SAPHTTPClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewSAPHTTPClient membuat instance baru dari SAPHTTPClient
+func NewSAPHTTPClient(baseURL, token string, timeout time.Duration) *SAPHTTPClient {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &SAPHTTPClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name mengembalikan nama adapter
+func (c *SAPHTTPClient) Name() string {
+	return "http"
+}
+
+// Deliver mengirim posting ke endpoint /material-documents dengan header Idempotency-Key,
+// response 2xx dianggap berhasil dan field reference disimpan sebagai nomor dokumen SAP
+func (c *SAPHTTPClient) Deliver(ctx context.Context, idempotencyKey string, posting *SAPPosting) (string, error) {
+	body, err := json.Marshal(posting)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/material-documents", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gagal menghubungi SAP: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("%w: HTTP %d %s", ErrSAPRejected, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result struct {
+		Reference string `json:"reference"`
+	}
+	if len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return "", fmt.Errorf("response SAP tidak valid: %w", err)
+		}
+	}
+	return result.Reference, nil
+}
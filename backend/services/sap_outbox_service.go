@@ -0,0 +1,442 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sirine-go/backend/config"
+	"sirine-go/backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk SAP integration outbox
+var (
+	ErrSAPOutboxNotFound     = errors.New("entry SAP outbox tidak ditemukan")
+	ErrSAPOutboxNotRetryable = errors.New("entry SAP outbox sudah terkirim atau sedang diproses")
+)
+
+const (
+	sapOutboxBatchSize         = 20
+	sapOutboxProcessingTimeout = 5 * time.Minute
+)
+
+// SAPOutboxService merupakan service untuk integration outbox posting material ke SAP
+// yang mencakup worker pengiriman dengan retry backoff serta monitoring untuk admin
+type SAPOutboxService struct {
+	db           *gorm.DB
+	client       SAPClient
+	pollInterval time.Duration
+}
+
+// NewSAPOutboxService membuat instance baru dari SAPOutboxService,
+// client nil berarti worker tidak dijalankan dan entry tetap PENDING
+func NewSAPOutboxService(db *gorm.DB, client SAPClient, pollInterval time.Duration) *SAPOutboxService {
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+	return &SAPOutboxService{
+		db:           db,
+		client:       client,
+		pollInterval: pollInterval,
+	}
+}
+
+// NewSAPClientFromConfig membuat adapter SAP sesuai SAP_ADAPTER (file, http, atau none)
+func NewSAPClientFromConfig(cfg *config.Config) (SAPClient, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.SAPAdapter)) {
+	case "", "none":
+		return nil, nil
+	case "file":
+		return NewSAPFileDropClient(cfg.SAPFileDropDir, cfg.SAPSenderPartner), nil
+	case "http":
+		if cfg.SAPHTTPBaseURL == "" {
+			return nil, errors.New("SAP_HTTP_BASE_URL wajib diisi untuk adapter http")
+		}
+		return NewSAPHTTPClient(cfg.SAPHTTPBaseURL, cfg.SAPHTTPToken, cfg.SAPHTTPTimeout), nil
+	default:
+		return nil, fmt.Errorf("SAP_ADAPTER tidak dikenal: %s", cfg.SAPAdapter)
+	}
+}
+
+// SAPOutboxFilters merupakan struct untuk filter dan pagination list entry outbox
+type SAPOutboxFilters struct {
+	Status            string `form:"status"`
+	EventType         string `form:"event_type"`
+	ProductionOrderID uint64 `form:"production_order_id"`
+	Page              int    `form:"page"`
+	PerPage           int    `form:"per_page"`
+}
+
+// SAPOutboxListResponse merupakan struct untuk paginated list entry outbox
+type SAPOutboxListResponse struct {
+	Items      []models.SAPOutboxEntry `json:"items"`
+	Total      int                     `json:"total"`
+	Page       int                     `json:"page"`
+	PerPage    int                     `json:"per_page"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+// SAPOutboxSummary merupakan ringkasan jumlah entry outbox per status untuk dashboard admin
+type SAPOutboxSummary struct {
+	Adapter         string     `json:"adapter"`
+	Pending         int64      `json:"pending"`
+	Processing      int64      `json:"processing"`
+	Delivered       int64      `json:"delivered"`
+	Failed          int64      `json:"failed"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at"`
+}
+
+// EnqueueSAPPostingInTx menulis posting ke outbox di dalam transaction milik caller,
+// PO number dan OBC diisi dari PO, item tanpa material memakai kode produk SAP milik PO,
+// dan posting yang sama untuk aggregate yang sama hanya ditulis sekali
+func EnqueueSAPPostingInTx(tx *gorm.DB, posting SAPPosting) error {
+	var po models.ProductionOrder
+	if err := tx.Select("id", "po_number", "obc_number", "sap_product_code").
+		First(&po, posting.ProductionOrderID).Error; err != nil {
+		return err
+	}
+
+	posting.PONumber = po.PONumber
+	posting.OBCNumber = po.OBCNumber
+	if posting.PostingDate == "" {
+		posting.PostingDate = time.Now().Format("2006-01-02")
+	}
+	for i := range posting.Items {
+		if posting.Items[i].Material == "" {
+			posting.Items[i].Material = po.SAPProductCode
+		}
+	}
+
+	payload, err := json.Marshal(posting)
+	if err != nil {
+		return err
+	}
+
+	entry := models.SAPOutboxEntry{
+		EventType:         posting.EventType,
+		AggregateType:     posting.AggregateType,
+		AggregateID:       posting.AggregateID,
+		ProductionOrderID: posting.ProductionOrderID,
+		IdempotencyKey:    fmt.Sprintf("%s-%d", posting.EventType, posting.AggregateID),
+		Payload:           datatypes.JSON(payload),
+		Status:            models.SAPOutboxPending,
+		MaxAttempts:       models.SAPOutboxDefaultMaxAttempts,
+		NextAttemptAt:     time.Now(),
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry).Error
+}
+
+// enqueueMaterialPrepPostingInTx menyusun posting goods issue kertas blanko dan tinta actual
+// dari material prep yang difinalisasi (TintaItems harus sudah di-preload),
+// item tanpa kode material (jenis kertas belum tercatat) dilewati
+func enqueueMaterialPrepPostingInTx(tx *gorm.DB, prep *models.KhazwalMaterialPreparation) error {
+	var items []SAPPostingItem
+
+	if prep.KertasBlankoActual != nil && *prep.KertasBlankoActual > 0 {
+		paperType, err := paperTypeForPO(tx, prep.ProductionOrderID)
+		if err != nil {
+			return err
+		}
+		if paperType != "" {
+			items = append(items, SAPPostingItem{
+				Material:     paperType,
+				Description:  "Kertas blanko",
+				Quantity:     float64(*prep.KertasBlankoActual),
+				Unit:         "lembar",
+				MovementType: SAPMovementGoodsIssue,
+			})
+		}
+	}
+
+	for _, u := range tintaUsageFromItems(prep.TintaItems) {
+		description := "Tinta " + u.Color
+		if u.BatchNumber != "" {
+			description += " batch " + u.BatchNumber
+		}
+		items = append(items, SAPPostingItem{
+			Material:     strings.ToUpper(u.Color),
+			Description:  description,
+			Quantity:     u.QuantityKg,
+			Unit:         "KG",
+			MovementType: SAPMovementGoodsIssue,
+		})
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+	return EnqueueSAPPostingInTx(tx, SAPPosting{
+		EventType:         models.SAPEventMaterialPrepFinalized,
+		AggregateType:     "khazwal_material_preparation",
+		AggregateID:       prep.ID,
+		ProductionOrderID: prep.ProductionOrderID,
+		Items:             items,
+	})
+}
+
+// StartWorker menjalankan worker pengiriman outbox di background sampai context dibatalkan
+func (s *SAPOutboxService) StartWorker(ctx context.Context) {
+	if s.client == nil {
+		log.Println("SAP outbox: adapter tidak dikonfigurasi, worker tidak dijalankan")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.ProcessDue(ctx); err != nil {
+				log.Printf("SAP outbox: gagal memproses entry: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("SAP outbox worker berjalan (adapter %s, interval %s)", s.client.Name(), s.pollInterval)
+}
+
+// ProcessDue mengirim entry PENDING yang sudah jatuh tempo melalui adapter SAP
+// dan mengembalikan jumlah entry yang berhasil terkirim
+func (s *SAPOutboxService) ProcessDue(ctx context.Context) (int, error) {
+	if s.client == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+
+	// Entry PROCESSING yang tertahan (mis. server restart saat mengirim) dikembalikan ke antrian
+	if err := s.db.Model(&models.SAPOutboxEntry{}).
+		Where("status = ? AND locked_at < ?", models.SAPOutboxProcessing, now.Add(-sapOutboxProcessingTimeout)).
+		Updates(map[string]interface{}{
+			"status":    models.SAPOutboxPending,
+			"locked_at": nil,
+		}).Error; err != nil {
+		return 0, err
+	}
+
+	var candidates []models.SAPOutboxEntry
+	if err := s.db.Where("status = ? AND next_attempt_at <= ?", models.SAPOutboxPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(sapOutboxBatchSize).
+		Find(&candidates).Error; err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		entry := &candidates[i]
+		claimed, err := s.claim(entry)
+		if err != nil {
+			return delivered, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.deliver(ctx, entry); err != nil {
+			log.Printf("SAP outbox: entry %d gagal dikirim (percobaan %d): %v", entry.ID, entry.Attempts, err)
+			continue
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// claim menandai entry sebagai PROCESSING secara atomic agar tidak dikirim dua kali oleh worker lain
+func (s *SAPOutboxService) claim(entry *models.SAPOutboxEntry) (bool, error) {
+	now := time.Now()
+	result := s.db.Model(&models.SAPOutboxEntry{}).
+		Where("id = ? AND status = ?", entry.ID, models.SAPOutboxPending).
+		Updates(map[string]interface{}{
+			"status":    models.SAPOutboxProcessing,
+			"locked_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// deliver mengirim satu entry dan mencatat hasilnya, kegagalan dijadwalkan ulang dengan
+// exponential backoff sampai batas percobaan lalu ditandai FAILED
+func (s *SAPOutboxService) deliver(ctx context.Context, entry *models.SAPOutboxEntry) error {
+	var posting SAPPosting
+	deliverErr := json.Unmarshal(entry.Payload, &posting)
+
+	reference := ""
+	if deliverErr == nil {
+		reference, deliverErr = s.client.Deliver(ctx, entry.IdempotencyKey, &posting)
+	}
+
+	now := time.Now()
+	entry.Attempts++
+	updates := map[string]interface{}{
+		"attempts":        entry.Attempts,
+		"last_attempt_at": now,
+		"locked_at":       nil,
+	}
+
+	if deliverErr == nil {
+		updates["status"] = models.SAPOutboxDelivered
+		updates["delivered_at"] = now
+		updates["external_reference"] = reference
+		updates["last_error"] = ""
+	} else {
+		updates["last_error"] = deliverErr.Error()
+		if entry.HasExhaustedAttempts() {
+			updates["status"] = models.SAPOutboxFailed
+		} else {
+			updates["status"] = models.SAPOutboxPending
+			updates["next_attempt_at"] = now.Add(models.SAPOutboxRetryDelay(entry.Attempts))
+		}
+	}
+
+	if err := s.db.Model(&models.SAPOutboxEntry{}).Where("id = ?", entry.ID).Updates(updates).Error; err != nil {
+		return err
+	}
+	return deliverErr
+}
+
+// ListEntries mengambil list entry outbox dengan filter status, event type, PO, dan pagination
+func (s *SAPOutboxService) ListEntries(filters SAPOutboxFilters) (*SAPOutboxListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.SAPOutboxEntry{})
+	if filters.Status != "" {
+		query = query.Where("status = ?", strings.ToUpper(filters.Status))
+	}
+	if filters.EventType != "" {
+		query = query.Where("event_type = ?", strings.ToUpper(filters.EventType))
+	}
+	if filters.ProductionOrderID > 0 {
+		query = query.Where("production_order_id = ?", filters.ProductionOrderID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var entries []models.SAPOutboxEntry
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Order("id DESC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &SAPOutboxListResponse{
+		Items:      entries,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetEntry mengambil detail entry outbox berdasarkan ID
+func (s *SAPOutboxService) GetEntry(id uint64) (*models.SAPOutboxEntry, error) {
+	var entry models.SAPOutboxEntry
+	if err := s.db.First(&entry, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrSAPOutboxNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetSummary menghitung jumlah entry per status beserta entry PENDING tertua
+func (s *SAPOutboxService) GetSummary() (*SAPOutboxSummary, error) {
+	summary := &SAPOutboxSummary{Adapter: "none"}
+	if s.client != nil {
+		summary.Adapter = s.client.Name()
+	}
+
+	var counts []struct {
+		Status models.SAPOutboxStatus
+		Total  int64
+	}
+	if err := s.db.Model(&models.SAPOutboxEntry{}).
+		Select("status, COUNT(*) AS total").
+		Group("status").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		switch count.Status {
+		case models.SAPOutboxPending:
+			summary.Pending = count.Total
+		case models.SAPOutboxProcessing:
+			summary.Processing = count.Total
+		case models.SAPOutboxDelivered:
+			summary.Delivered = count.Total
+		case models.SAPOutboxFailed:
+			summary.Failed = count.Total
+		}
+	}
+
+	var oldest models.SAPOutboxEntry
+	err := s.db.Where("status = ?", models.SAPOutboxPending).Order("created_at ASC").First(&oldest).Error
+	if err == nil {
+		summary.OldestPendingAt = &oldest.CreatedAt
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// RetryEntry menjadwalkan ulang entry FAILED atau PENDING untuk segera dikirim
+// dengan jumlah percobaan di-reset
+func (s *SAPOutboxService) RetryEntry(id uint64) (*models.SAPOutboxEntry, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var entry models.SAPOutboxEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entry, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrSAPOutboxNotFound
+			}
+			return err
+		}
+		if !entry.CanRetry() {
+			return ErrSAPOutboxNotRetryable
+		}
+
+		return tx.Model(&entry).Updates(map[string]interface{}{
+			"status":          models.SAPOutboxPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetEntry(id)
+}
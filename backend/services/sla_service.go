@@ -0,0 +1,624 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sirine-go/backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk SLA policy dan laporan breach
+var (
+	ErrSLAPolicyNotFound   = errors.New("SLA policy tidak ditemukan")
+	ErrSLAStageInvalid     = errors.New("stage tidak memiliki SLA antrian")
+	ErrSLAThresholdInvalid = errors.New("warning_minutes harus lebih dari 0 dan lebih kecil dari breach_minutes")
+	ErrSLADateInvalid      = errors.New("date_from dan date_to tidak valid, gunakan format YYYY-MM-DD")
+)
+
+// slaStageLabels merupakan nama antrian setiap stage untuk judul notifikasi eskalasi SLA
+var slaStageLabels = map[models.POStage]string{
+	models.StageKhazwalMaterialPrep: "Persiapan Material",
+	models.StageCetak:               "Cetak",
+	models.StageKhazwalCounting:     "Penghitungan",
+	models.StageKhazwalCutting:      "Pemotongan",
+	models.StageVerifikasi:          "Verifikasi",
+	models.StageKhazkhir:            "Khazanah Akhir",
+}
+
+type slaPolicyKey struct {
+	stage    models.POStage
+	priority models.POPriority
+}
+
+// SLAThresholds merupakan snapshot SLA yang berlaku, dimuat sekali per query antrian atau per scan
+type SLAThresholds struct {
+	policies map[slaPolicyKey]models.SLAPolicy
+}
+
+// LoadSLAThresholds memuat SLA policy dari database, stage dan priority tanpa policy memakai SLA bawaan
+func LoadSLAThresholds(db *gorm.DB) (*SLAThresholds, error) {
+	var policies []models.SLAPolicy
+	if err := db.Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	thresholds := &SLAThresholds{policies: make(map[slaPolicyKey]models.SLAPolicy, len(policies))}
+	for _, policy := range policies {
+		thresholds.policies[slaPolicyKey{policy.Stage, policy.Priority}] = policy
+	}
+	return thresholds, nil
+}
+
+// lookup mencari SLA dengan urutan: policy stage+priority, policy stage untuk semua priority, lalu SLA bawaan
+func (t *SLAThresholds) lookup(stage models.POStage, priority models.POPriority) (models.SLAThreshold, *models.SLAPolicy, bool) {
+	if policy, ok := t.policies[slaPolicyKey{stage, priority}]; ok && priority != "" {
+		return policy.Threshold(), &policy, true
+	}
+	if policy, ok := t.policies[slaPolicyKey{stage, ""}]; ok {
+		return policy.Threshold(), &policy, true
+	}
+	threshold, ok := models.DefaultSLAThreshold(stage, priority)
+	return threshold, nil, ok
+}
+
+// For mengembalikan SLA yang berlaku untuk stage dan priority
+func (t *SLAThresholds) For(stage models.POStage, priority models.POPriority) (models.SLAThreshold, bool) {
+	threshold, _, ok := t.lookup(stage, priority)
+	return threshold, ok
+}
+
+// Evaluate menentukan status SLA dari lama waktu tunggu, stage tanpa SLA selalu ON_TRACK
+func (t *SLAThresholds) Evaluate(stage models.POStage, priority models.POPriority, waitingMinutes int) models.SLALevel {
+	threshold, ok := t.For(stage, priority)
+	if !ok {
+		return models.SLAOnTrack
+	}
+	return threshold.LevelFor(waitingMinutes)
+}
+
+// QueueSLAStatus merupakan status SLA satu PO di antrian stage
+type QueueSLAStatus struct {
+	WaitingSince   time.Time       `json:"waiting_since"`
+	WaitingMinutes int             `json:"waiting_minutes"`
+	Level          models.SLALevel `json:"level"`
+	IsOverdue      bool            `json:"is_overdue"`
+}
+
+// Status menghitung status SLA PO yang menunggu sejak waitingSince
+func (t *SLAThresholds) Status(stage models.POStage, priority models.POPriority, waitingSince, now time.Time) QueueSLAStatus {
+	waitingMinutes := int(now.Sub(waitingSince).Minutes())
+	level := t.Evaluate(stage, priority, waitingMinutes)
+	return QueueSLAStatus{
+		WaitingSince:   waitingSince,
+		WaitingMinutes: waitingMinutes,
+		Level:          level,
+		IsOverdue:      level == models.SLABreached,
+	}
+}
+
+// QueueWaitingSince menghitung sejak kapan setiap PO menunggu di status saat ini, yaitu
+// tracking terakhir yang mencatat perpindahan ke status tersebut, dengan fallback created_at PO
+// untuk PO baru yang belum memiliki tracking
+func QueueWaitingSince(db *gorm.DB, pos []models.ProductionOrder) (map[uint64]time.Time, error) {
+	waitingSince := make(map[uint64]time.Time, len(pos))
+	if len(pos) == 0 {
+		return waitingSince, nil
+	}
+
+	poIDs := make([]uint64, 0, len(pos))
+	statuses := make(map[uint64]models.POStatus, len(pos))
+	for _, po := range pos {
+		poIDs = append(poIDs, po.ID)
+		statuses[po.ID] = po.CurrentStatus
+		waitingSince[po.ID] = po.CreatedAt
+	}
+
+	var trackings []models.POStageTracking
+	if err := db.Select("id", "production_order_id", "status", "created_at").
+		Where("production_order_id IN ?", poIDs).
+		Find(&trackings).Error; err != nil {
+		return nil, err
+	}
+
+	entered := make(map[uint64]time.Time)
+	for _, tracking := range trackings {
+		if tracking.Status != statuses[tracking.ProductionOrderID] {
+			continue
+		}
+		if last, ok := entered[tracking.ProductionOrderID]; !ok || tracking.CreatedAt.After(last) {
+			entered[tracking.ProductionOrderID] = tracking.CreatedAt
+		}
+	}
+	for poID, since := range entered {
+		waitingSince[poID] = since
+	}
+	return waitingSince, nil
+}
+
+// SLAService merupakan service untuk konfigurasi SLA antrian stage, scheduler eskalasi
+// PO yang hampir atau sudah melewati SLA, dan laporan history breach
+type SLAService struct {
+	db *gorm.DB
+}
+
+// NewSLAService membuat instance baru dari SLAService
+func NewSLAService(db *gorm.DB) *SLAService {
+	return &SLAService{
+		db: db,
+	}
+}
+
+// UpsertSLAPolicyRequest merupakan request untuk menyimpan SLA stage, Priority kosong berarti semua priority
+type UpsertSLAPolicyRequest struct {
+	Stage          models.POStage    `json:"stage" binding:"required"`
+	Priority       models.POPriority `json:"priority"`
+	WarningMinutes int               `json:"warning_minutes" binding:"required"`
+	BreachMinutes  int               `json:"breach_minutes" binding:"required"`
+}
+
+// SLAPolicyItem merupakan SLA efektif untuk satu kombinasi stage dan priority,
+// Source DEFAULT berarti belum ada policy dan SLA bawaan yang dipakai
+type SLAPolicyItem struct {
+	Stage          models.POStage    `json:"stage"`
+	Priority       models.POPriority `json:"priority"`
+	QueueStatus    models.POStatus   `json:"queue_status"`
+	WarningMinutes int               `json:"warning_minutes"`
+	BreachMinutes  int               `json:"breach_minutes"`
+	Source         string            `json:"source"`
+	PolicyID       *uint64           `json:"policy_id"`
+}
+
+// SLAScanResult merupakan ringkasan satu kali scan antrian oleh scheduler
+type SLAScanResult struct {
+	Scanned   int `json:"scanned"`
+	AtRisk    int `json:"at_risk"`
+	Breached  int `json:"breached"`
+	Escalated int `json:"escalated"`
+	Resolved  int `json:"resolved"`
+}
+
+// SLABreachFilters merupakan struct untuk filter dan pagination history breach
+type SLABreachFilters struct {
+	Stage    string `form:"stage"`
+	Level    string `form:"level"`
+	Priority string `form:"priority"`
+	Status   string `form:"status"` // open, resolved
+	DateFrom string `form:"date_from"`
+	DateTo   string `form:"date_to"`
+	Page     int    `form:"page"`
+	PerPage  int    `form:"per_page"`
+}
+
+// SLABreachItem merupakan history breach beserta nomor PO dan OBC
+type SLABreachItem struct {
+	models.SLABreach
+	PONumber  int64  `json:"po_number"`
+	OBCNumber string `json:"obc_number"`
+}
+
+// SLABreachListResponse merupakan struct untuk paginated history breach
+type SLABreachListResponse struct {
+	Items      []SLABreachItem `json:"items"`
+	Total      int             `json:"total"`
+	Page       int             `json:"page"`
+	PerPage    int             `json:"per_page"`
+	TotalPages int             `json:"total_pages"`
+}
+
+// SLABreachSummary merupakan rekap breach per stage untuk laporan
+type SLABreachSummary struct {
+	Stage    models.POStage `json:"stage"`
+	AtRisk   int            `json:"at_risk"`
+	Breached int            `json:"breached"`
+	Open     int            `json:"open"`
+}
+
+// ListPolicies mengambil SLA efektif untuk setiap stage dan priority
+func (s *SLAService) ListPolicies() ([]SLAPolicyItem, error) {
+	thresholds, err := LoadSLAThresholds(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities := []models.POPriority{models.PriorityUrgent, models.PriorityNormal, models.PriorityLow}
+	items := make([]SLAPolicyItem, 0, len(models.SLAStages())*len(priorities))
+	for _, stage := range models.SLAStages() {
+		queueStatus, _ := models.SLAQueueStatus(stage)
+		for _, priority := range priorities {
+			threshold, policy, _ := thresholds.lookup(stage, priority)
+			item := SLAPolicyItem{
+				Stage:          stage,
+				Priority:       priority,
+				QueueStatus:    queueStatus,
+				WarningMinutes: threshold.WarningMinutes,
+				BreachMinutes:  threshold.BreachMinutes,
+				Source:         "DEFAULT",
+			}
+			if policy != nil {
+				policyID := policy.ID
+				item.Source = "POLICY"
+				item.PolicyID = &policyID
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// UpsertPolicy menyimpan SLA untuk stage dan priority, policy yang sudah ada di-update
+func (s *SLAService) UpsertPolicy(req UpsertSLAPolicyRequest, userID uint64) (*models.SLAPolicy, error) {
+	req.Stage = models.POStage(strings.ToUpper(string(req.Stage)))
+	req.Priority = models.POPriority(strings.ToUpper(string(req.Priority)))
+
+	if _, ok := models.SLAQueueStatus(req.Stage); !ok {
+		return nil, ErrSLAStageInvalid
+	}
+	if req.Priority != "" && !isValidPriority(req.Priority) {
+		return nil, ErrInvalidPriority
+	}
+	threshold := models.SLAThreshold{WarningMinutes: req.WarningMinutes, BreachMinutes: req.BreachMinutes}
+	if !threshold.IsValid() {
+		return nil, ErrSLAThresholdInvalid
+	}
+
+	var policy models.SLAPolicy
+	err := s.db.Where("stage = ? AND priority = ?", req.Stage, req.Priority).First(&policy).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	policy.Stage = req.Stage
+	policy.Priority = req.Priority
+	policy.WarningMinutes = req.WarningMinutes
+	policy.BreachMinutes = req.BreachMinutes
+	policy.UpdatedBy = &userID
+	if err := s.db.Save(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// DeletePolicy menghapus SLA policy sehingga stage dan priority kembali memakai SLA bawaan
+func (s *SLAService) DeletePolicy(id uint64) error {
+	result := s.db.Delete(&models.SLAPolicy{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSLAPolicyNotFound
+	}
+	return nil
+}
+
+// StartScheduler menjalankan scan antrian secara periodik sampai context dibatalkan
+func (s *SLAService) StartScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		log.Println("SLA scheduler: scan antrian tidak dijalankan")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			result, err := s.ScanQueues(time.Now())
+			if err != nil {
+				log.Printf("SLA scheduler: gagal scan antrian: %v", err)
+			} else if result.Escalated > 0 || result.Resolved > 0 {
+				log.Printf("SLA scheduler: %d eskalasi baru, %d breach selesai", result.Escalated, result.Resolved)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("SLA scheduler berjalan (interval %s)", interval)
+}
+
+// slaQueueEntry merupakan posisi PO di antrian saat scan, dipakai untuk menutup breach lama
+type slaQueueEntry struct {
+	stage        models.POStage
+	waitingSince time.Time
+}
+
+// ScanQueues memeriksa semua PO di antrian stage terhadap SLA, mencatat breach AT_RISK dan BREACHED
+// sekali per PO per kali masuk antrian beserta eskalasinya, lalu menutup breach PO yang sudah diambil
+func (s *SLAService) ScanQueues(now time.Time) (*SLAScanResult, error) {
+	thresholds, err := LoadSLAThresholds(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	queueStatuses := make([]models.POStatus, 0, len(models.SLAStages()))
+	for _, stage := range models.SLAStages() {
+		status, _ := models.SLAQueueStatus(stage)
+		queueStatuses = append(queueStatuses, status)
+	}
+
+	var pos []models.ProductionOrder
+	if err := s.db.Select("id", "po_number", "obc_number", "priority", "current_status", "created_at").
+		Where("current_status IN ?", queueStatuses).
+		Find(&pos).Error; err != nil {
+		return nil, err
+	}
+
+	waitingSince, err := QueueWaitingSince(s.db, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SLAScanResult{Scanned: len(pos)}
+	queued := make(map[uint64]slaQueueEntry, len(pos))
+	for i := range pos {
+		po := &pos[i]
+		stage, err := models.StageForStatus(po.CurrentStatus)
+		if err != nil {
+			continue
+		}
+		since := waitingSince[po.ID]
+		queued[po.ID] = slaQueueEntry{stage: stage, waitingSince: since}
+
+		threshold, ok := thresholds.For(stage, po.Priority)
+		if !ok {
+			continue
+		}
+		waitingMinutes := int(now.Sub(since).Minutes())
+		level := threshold.LevelFor(waitingMinutes)
+		switch level {
+		case models.SLAAtRisk:
+			result.AtRisk++
+		case models.SLABreached:
+			result.Breached++
+		default:
+			continue
+		}
+
+		breach := models.SLABreach{
+			ProductionOrderID: po.ID,
+			Stage:             stage,
+			Level:             level,
+			WaitingSince:      since,
+			QueueStatus:       po.CurrentStatus,
+			Priority:          po.Priority,
+			ThresholdMinutes:  threshold.WarningMinutes,
+			WaitingMinutes:    waitingMinutes,
+			DetectedAt:        now,
+		}
+		if level == models.SLABreached {
+			breach.ThresholdMinutes = threshold.BreachMinutes
+		}
+
+		escalated, err := s.recordBreach(po, &breach)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mencatat breach SLA PO %d: %w", po.PONumber, err)
+		}
+		if escalated {
+			result.Escalated++
+		}
+	}
+
+	resolved, err := s.resolveBreaches(queued, now)
+	if err != nil {
+		return nil, err
+	}
+	result.Resolved = resolved
+	return result, nil
+}
+
+// recordBreach menulis breach dan notifikasi eskalasi dalam satu transaction,
+// breach yang sudah tercatat untuk level dan waktu masuk antrian yang sama dilewati
+func (s *SLAService) recordBreach(po *models.ProductionOrder, breach *models.SLABreach) (bool, error) {
+	escalated := false
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(breach)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		escalated = true
+		return notifySLABreachInTx(tx, po, breach)
+	})
+	return escalated, err
+}
+
+// notifySLABreachInTx mengirim eskalasi ke manager departemen stage, breach BREACHED juga dikirim ke PPIC
+func notifySLABreachInTx(tx *gorm.DB, po *models.ProductionOrder, breach *models.SLABreach) error {
+	label := slaStageLabels[breach.Stage]
+	content := NotificationContent{
+		Category: models.NotificationCategoryAlert,
+		POID:     po.ID,
+		Stage:    breach.Stage,
+	}
+	var audiences []NotificationAudience
+	if department, ok := stageDepartments[breach.Stage]; ok {
+		audiences = append(audiences, NotificationAudience{
+			Roles:      []models.UserRole{models.RoleManager},
+			Department: department,
+		})
+	}
+
+	if breach.Level == models.SLABreached {
+		content.Type = models.NotificationError
+		content.Title = fmt.Sprintf("SLA %s Terlewati - PO #%s", label, po.OBCNumber)
+		content.Message = fmt.Sprintf("PO #%d (OBC %s, %s) sudah menunggu %d menit di antrian %s, melewati SLA %d menit.",
+			po.PONumber, po.OBCNumber, po.Priority, breach.WaitingMinutes, label, breach.ThresholdMinutes)
+		audiences = append(audiences, AudiencePPIC)
+	} else {
+		content.Type = models.NotificationWarning
+		content.Title = fmt.Sprintf("SLA %s Hampir Terlewati - PO #%s", label, po.OBCNumber)
+		content.Message = fmt.Sprintf("PO #%d (OBC %s, %s) sudah menunggu %d menit di antrian %s, mendekati batas SLA.",
+			po.PONumber, po.OBCNumber, po.Priority, breach.WaitingMinutes, label)
+	}
+
+	if len(audiences) == 0 {
+		return nil
+	}
+	_, err := NotifyAudiencesInTx(tx, content, audiences...)
+	return err
+}
+
+// resolveBreaches menutup breach yang PO-nya sudah keluar dari antrian atau masuk antrian baru
+func (s *SLAService) resolveBreaches(queued map[uint64]slaQueueEntry, now time.Time) (int, error) {
+	var open []models.SLABreach
+	if err := s.db.Select("id", "production_order_id", "stage", "waiting_since").
+		Where("resolved_at IS NULL").
+		Find(&open).Error; err != nil {
+		return 0, err
+	}
+
+	var resolvedIDs []uint64
+	for _, breach := range open {
+		entry, ok := queued[breach.ProductionOrderID]
+		if ok && entry.stage == breach.Stage && entry.waitingSince.Equal(breach.WaitingSince) {
+			continue
+		}
+		resolvedIDs = append(resolvedIDs, breach.ID)
+	}
+
+	if len(resolvedIDs) == 0 {
+		return 0, nil
+	}
+	if err := s.db.Model(&models.SLABreach{}).
+		Where("id IN ?", resolvedIDs).
+		Update("resolved_at", now).Error; err != nil {
+		return 0, err
+	}
+	return len(resolvedIDs), nil
+}
+
+// breachQuery menyusun query history breach dengan filter stage, level, priority, status, dan tanggal deteksi
+func (s *SLAService) breachQuery(filters SLABreachFilters) (*gorm.DB, error) {
+	query := s.db.Table("sla_breaches sb")
+	if filters.Stage != "" {
+		query = query.Where("sb.stage = ?", strings.ToUpper(filters.Stage))
+	}
+	if filters.Level != "" {
+		query = query.Where("sb.level = ?", strings.ToUpper(filters.Level))
+	}
+	if filters.Priority != "" {
+		query = query.Where("sb.priority = ?", strings.ToUpper(filters.Priority))
+	}
+	switch strings.ToLower(filters.Status) {
+	case "open":
+		query = query.Where("sb.resolved_at IS NULL")
+	case "resolved":
+		query = query.Where("sb.resolved_at IS NOT NULL")
+	}
+	if filters.DateFrom != "" {
+		dateFrom, err := time.ParseInLocation("2006-01-02", filters.DateFrom, time.Local)
+		if err != nil {
+			return nil, ErrSLADateInvalid
+		}
+		query = query.Where("sb.detected_at >= ?", dateFrom)
+	}
+	if filters.DateTo != "" {
+		dateTo, err := time.ParseInLocation("2006-01-02", filters.DateTo, time.Local)
+		if err != nil {
+			return nil, ErrSLADateInvalid
+		}
+		query = query.Where("sb.detected_at < ?", dateTo.AddDate(0, 0, 1))
+	}
+	return query, nil
+}
+
+// ListBreaches mengambil history breach SLA untuk laporan, terbaru terlebih dahulu
+func (s *SLAService) ListBreaches(filters SLABreachFilters) (*SLABreachListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query, err := s.breachQuery(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	items := []SLABreachItem{}
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Select("sb.*, po.po_number, po.obc_number").
+		Joins("LEFT JOIN production_orders po ON po.id = sb.production_order_id").
+		Order("sb.detected_at DESC, sb.id DESC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Scan(&items).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &SLABreachListResponse{
+		Items:      items,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetBreachSummary merekap jumlah breach AT_RISK, BREACHED, dan yang masih terbuka per stage
+func (s *SLAService) GetBreachSummary(filters SLABreachFilters) ([]SLABreachSummary, error) {
+	query, err := s.breachQuery(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Stage     models.POStage
+		Level     models.SLALevel
+		Total     int
+		OpenCount int
+	}
+	if err := query.
+		Select("sb.stage, sb.level, COUNT(*) as total, SUM(CASE WHEN sb.resolved_at IS NULL THEN 1 ELSE 0 END) as open_count").
+		Group("sb.stage, sb.level").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	byStage := make(map[models.POStage]*SLABreachSummary)
+	for _, row := range rows {
+		summary, ok := byStage[row.Stage]
+		if !ok {
+			summary = &SLABreachSummary{Stage: row.Stage}
+			byStage[row.Stage] = summary
+		}
+		if row.Level == models.SLABreached {
+			summary.Breached += row.Total
+		} else {
+			summary.AtRisk += row.Total
+		}
+		summary.Open += row.OpenCount
+	}
+
+	summaries := make([]SLABreachSummary, 0, len(models.SLAStages()))
+	for _, stage := range models.SLAStages() {
+		if summary, ok := byStage[stage]; ok {
+			summaries = append(summaries, *summary)
+		} else {
+			summaries = append(summaries, SLABreachSummary{Stage: stage})
+		}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sirine-go/backend/models"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// maxTintaItemKg merupakan batas jumlah tinta per warna untuk satu material prep
+// yang dipakai untuk menolak salah input (mis. gram diisi sebagai kg)
+const maxTintaItemKg = 1000
+
+// tintaColorPattern membatasi kode warna tinta ke huruf kecil, angka, spasi, underscore, dan dash
+var tintaColorPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9 _-]{0,29}$`)
+
+// TintaActualInput merupakan input jumlah actual satu warna tinta dari checklist Staff Khazwal,
+// satuan yang diterima adalah kg (default) atau g
+type TintaActualInput struct {
+	Color       string  `json:"color" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"min=0"`
+	Unit        string  `json:"unit"`
+	BatchNumber string  `json:"batch_number" binding:"max=50"`
+	Checked     bool    `json:"checked"`
+}
+
+// UpdateTintaResult merupakan hasil update tinta actual berupa line item terbaru
+// dan warna yang saldonya di bawah minimum
+type UpdateTintaResult struct {
+	TintaItems []models.KhazwalTintaItem `json:"tinta_items"`
+	LowStock   []TintaLowStock           `json:"low_stock"`
+}
+
+// TintaVariance merupakan perbandingan kebutuhan dan actual satu warna tinta untuk tampilan detail
+type TintaVariance struct {
+	Color              string   `json:"color"`
+	RequiredKg         float64  `json:"required_kg"`
+	ActualKg           *float64 `json:"actual_kg"`
+	VarianceKg         *float64 `json:"variance_kg"`
+	VariancePercentage *float64 `json:"variance_percentage"`
+	BatchNumber        string   `json:"batch_number"`
+	LowStock           bool     `json:"low_stock"`
+}
+
+// ValidateTintaActual memvalidasi input tinta actual dan mengkonversinya ke pemakaian per warna dalam kg,
+// warna duplikat, satuan yang tidak dikenal, dan jumlah di luar batas ditolak
+func ValidateTintaActual(inputs []TintaActualInput) ([]TintaUsage, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("%w: minimal satu warna tinta harus diisi", ErrInvalidTintaUsage)
+	}
+
+	seen := make(map[string]bool, len(inputs))
+	usage := make([]TintaUsage, 0, len(inputs))
+	for _, input := range inputs {
+		color := models.NormalizeTintaColor(input.Color)
+		if !tintaColorPattern.MatchString(color) {
+			return nil, fmt.Errorf("%w: kode warna tinta '%s' tidak valid", ErrInvalidTintaUsage, input.Color)
+		}
+		if seen[color] {
+			return nil, fmt.Errorf("%w: warna tinta %s diisi lebih dari sekali", ErrInvalidTintaUsage, color)
+		}
+		seen[color] = true
+
+		var factor float64
+		switch strings.ToLower(strings.TrimSpace(input.Unit)) {
+		case "", "kg":
+			factor = 1
+		case "g", "gram":
+			factor = 0.001
+		default:
+			return nil, fmt.Errorf("%w: satuan tinta '%s' tidak dikenal, gunakan kg atau g", ErrInvalidTintaUsage, input.Unit)
+		}
+
+		quantityKg := roundKg(input.Quantity * factor)
+		if quantityKg < 0 {
+			return nil, fmt.Errorf("%w: jumlah tinta %s tidak boleh negatif", ErrInvalidTintaUsage, color)
+		}
+		if quantityKg > maxTintaItemKg {
+			return nil, fmt.Errorf("%w: jumlah tinta %s melebihi %d kg", ErrInvalidTintaUsage, color, maxTintaItemKg)
+		}
+		if input.Checked && quantityKg == 0 {
+			return nil, fmt.Errorf("%w: jumlah tinta %s wajib diisi untuk warna yang dicentang", ErrInvalidTintaUsage, color)
+		}
+
+		batch := strings.TrimSpace(input.BatchNumber)
+		if len(batch) > 50 {
+			return nil, fmt.Errorf("%w: nomor batch tinta %s maksimal 50 karakter", ErrInvalidTintaUsage, color)
+		}
+
+		usage = append(usage, TintaUsage{
+			Color:       color,
+			QuantityKg:  quantityKg,
+			BatchNumber: batch,
+			Checked:     input.Checked || quantityKg > 0,
+		})
+	}
+
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Color < usage[j].Color })
+	return usage, nil
+}
+
+// syncTintaItemsInTx menyimpan actual per warna ke line item material prep di dalam transaction milik caller,
+// warna dengan kebutuhan yang tidak ada di update dikosongkan actualnya dan warna tambahan tanpa kebutuhan dihapus
+func syncTintaItemsInTx(tx *gorm.DB, prepID uint64, usage []TintaUsage, lowStock []TintaLowStock) ([]models.KhazwalTintaItem, error) {
+	var existing []models.KhazwalTintaItem
+	if err := tx.Where("material_prep_id = ?", prepID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	byColor := make(map[string]*models.KhazwalTintaItem, len(existing))
+	for i := range existing {
+		byColor[existing[i].ColorCode] = &existing[i]
+	}
+
+	lowColors := make(map[string]bool, len(lowStock))
+	for _, item := range lowStock {
+		lowColors[item.Color] = true
+	}
+
+	submitted := make(map[string]bool, len(usage))
+	for _, item := range usage {
+		submitted[item.Color] = true
+		actual := item.QuantityKg
+
+		if current, ok := byColor[item.Color]; ok {
+			if err := tx.Model(current).Updates(map[string]interface{}{
+				"actual_kg":    actual,
+				"batch_number": item.BatchNumber,
+				"checked":      item.Checked,
+				"low_stock":    lowColors[item.Color],
+			}).Error; err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		created := models.KhazwalTintaItem{
+			MaterialPrepID: prepID,
+			ColorCode:      item.Color,
+			ActualKg:       &actual,
+			BatchNumber:    item.BatchNumber,
+			Checked:        item.Checked,
+			LowStock:       lowColors[item.Color],
+		}
+		if err := tx.Create(&created).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	for _, item := range existing {
+		if submitted[item.ColorCode] {
+			continue
+		}
+		if item.RequiredKg <= 0 {
+			if err := tx.Delete(&models.KhazwalTintaItem{}, item.ID).Error; err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := tx.Model(&models.KhazwalTintaItem{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+			"actual_kg":    nil,
+			"batch_number": "",
+			"checked":      false,
+			"low_stock":    false,
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var items []models.KhazwalTintaItem
+	if err := tx.Where("material_prep_id = ?", prepID).Order("color_code ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// tintaUsageFromItems mengambil pemakaian actual per warna dari line item material prep
+func tintaUsageFromItems(items []models.KhazwalTintaItem) []TintaUsage {
+	usage := make([]TintaUsage, 0, len(items))
+	for _, item := range items {
+		if item.ActualKg == nil || *item.ActualKg <= 0 {
+			continue
+		}
+		usage = append(usage, TintaUsage{
+			Color:       item.ColorCode,
+			QuantityKg:  *item.ActualKg,
+			BatchNumber: item.BatchNumber,
+			Checked:     item.Checked,
+		})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Color < usage[j].Color })
+	return usage
+}
+
+// BuildTintaVariances menyusun variance kebutuhan terhadap actual per warna, diurutkan berdasarkan warna
+func BuildTintaVariances(items []models.KhazwalTintaItem) []TintaVariance {
+	variances := make([]TintaVariance, 0, len(items))
+	for i := range items {
+		item := &items[i]
+		variances = append(variances, TintaVariance{
+			Color:              item.ColorCode,
+			RequiredKg:         item.RequiredKg,
+			ActualKg:           item.ActualKg,
+			VarianceKg:         item.VarianceKg(),
+			VariancePercentage: item.VariancePercentage(),
+			BatchNumber:        item.BatchNumber,
+			LowStock:           item.LowStock,
+		})
+	}
+	sort.Slice(variances, func(i, j int) bool { return variances[i].Color < variances[j].Color })
+	return variances
+}
+
+// ParseLegacyTintaRequirements membaca kolom tinta_requirements format JSON lama,
+// yaitu object {"cyan": 2}, array [{color, requirement}], atau object {"colors": [...]}
+func ParseLegacyTintaRequirements(data []byte) ([]TintaUsage, error) {
+	if isEmptyJSON(data) {
+		return nil, nil
+	}
+
+	var byColor map[string]float64
+	if err := json.Unmarshal(data, &byColor); err == nil {
+		items := make([]legacyTintaItem, 0, len(byColor))
+		for color, quantity := range byColor {
+			items = append(items, legacyTintaItem{Color: color, Quantity: quantity})
+		}
+		return aggregateLegacyTinta(items)
+	}
+
+	var items []legacyTintaItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		var wrapper struct {
+			Colors []legacyTintaItem `json:"colors"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, ErrInvalidTintaUsage
+		}
+		items = wrapper.Colors
+	}
+	return aggregateLegacyTinta(items)
+}
+
+// legacyTintaItem merupakan satu warna pada format JSON tinta lama
+type legacyTintaItem struct {
+	Color       string  `json:"color"`
+	Requirement float64 `json:"requirement"`
+	Quantity    float64 `json:"quantity"`
+}
+
+// aggregateLegacyTinta menormalisasi warna dan menjumlahkan warna duplikat dari format JSON lama
+func aggregateLegacyTinta(items []legacyTintaItem) ([]TintaUsage, error) {
+	totals := make(map[string]float64)
+	for _, item := range items {
+		color := models.NormalizeTintaColor(item.Color)
+		if color == "" {
+			return nil, fmt.Errorf("%w: warna tinta tidak boleh kosong", ErrInvalidTintaUsage)
+		}
+		quantity := item.Requirement
+		if quantity == 0 {
+			quantity = item.Quantity
+		}
+		if quantity < 0 {
+			return nil, fmt.Errorf("%w: jumlah tinta %s tidak boleh negatif", ErrInvalidTintaUsage, color)
+		}
+		totals[color] += quantity
+	}
+
+	usage := make([]TintaUsage, 0, len(totals))
+	for color, quantity := range totals {
+		usage = append(usage, TintaUsage{Color: color, QuantityKg: roundKg(quantity)})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Color < usage[j].Color })
+	return usage, nil
+}
+
+// MigrateLegacyTintaInTx mengkonversi kolom JSON tinta lama satu material prep menjadi line item,
+// prep yang sudah memiliki line item dilewati sehingga migrasi aman dijalankan berulang
+func MigrateLegacyTintaInTx(tx *gorm.DB, prepID uint64, requirements, actual, lowStockFlags []byte) (int, error) {
+	var count int64
+	if err := tx.Model(&models.KhazwalTintaItem{}).Where("material_prep_id = ?", prepID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		return 0, nil
+	}
+
+	required, err := ParseLegacyTintaRequirements(requirements)
+	if err != nil {
+		return 0, fmt.Errorf("tinta_requirements: %w", err)
+	}
+
+	var used []TintaUsage
+	if !isEmptyJSON(actual) {
+		used, err = ParseTintaUsage(actual)
+		if err != nil {
+			return 0, fmt.Errorf("tinta_actual: %w", err)
+		}
+	}
+
+	var flags struct {
+		LowStockColors []string `json:"low_stock_colors"`
+	}
+	if !isEmptyJSON(lowStockFlags) {
+		_ = json.Unmarshal(lowStockFlags, &flags)
+	}
+	lowColors := make(map[string]bool, len(flags.LowStockColors))
+	for _, color := range flags.LowStockColors {
+		lowColors[models.NormalizeTintaColor(color)] = true
+	}
+
+	items := make(map[string]*models.KhazwalTintaItem)
+	itemFor := func(color string) *models.KhazwalTintaItem {
+		if item, ok := items[color]; ok {
+			return item
+		}
+		item := &models.KhazwalTintaItem{MaterialPrepID: prepID, ColorCode: color, LowStock: lowColors[color]}
+		items[color] = item
+		return item
+	}
+	for _, item := range required {
+		itemFor(item.Color).RequiredKg = item.QuantityKg
+	}
+	for _, item := range used {
+		quantity := item.QuantityKg
+		target := itemFor(item.Color)
+		target.ActualKg = &quantity
+		target.Checked = true
+	}
+
+	colors := make([]string, 0, len(items))
+	for color := range items {
+		colors = append(colors, color)
+	}
+	sort.Strings(colors)
+	for _, color := range colors {
+		if err := tx.Create(items[color]).Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(colors), nil
+}
+
+// isEmptyJSON memeriksa apakah nilai kolom JSON kosong atau null
+func isEmptyJSON(data []byte) bool {
+	trimmed := strings.TrimSpace(string(data))
+	return trimmed == "" || trimmed == "null"
+}
@@ -0,0 +1,546 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sirine-go/backend/models"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Error untuk inventory tinta
+var (
+	ErrTintaStockNotFound     = errors.New("stok tinta tidak ditemukan")
+	ErrTintaColorExists       = errors.New("warna tinta sudah terdaftar")
+	ErrTintaStockInsufficient = errors.New("stok tinta tidak mencukupi")
+	ErrInvalidTintaQuantity   = errors.New("jumlah tinta harus lebih dari 0 kg")
+	ErrInvalidTintaMinimum    = errors.New("batas minimum tinta tidak boleh negatif")
+	ErrInvalidTintaUsage      = errors.New("format data tinta tidak valid")
+)
+
+// TintaStockService merupakan service untuk inventory tinta per warna
+// yang mencakup saldo stok dalam kg, batas minimum, penerimaan, dan riwayat mutasi
+type TintaStockService struct {
+	db *gorm.DB
+}
+
+// NewTintaStockService membuat instance baru dari TintaStockService
+func NewTintaStockService(db *gorm.DB) *TintaStockService {
+	return &TintaStockService{
+		db: db,
+	}
+}
+
+// TintaStockFilters merupakan struct untuk filter dan pagination list stok tinta
+type TintaStockFilters struct {
+	Search   string `form:"search"`
+	LowStock bool   `form:"low_stock"`
+	Page     int    `form:"page"`
+	PerPage  int    `form:"per_page"`
+}
+
+// TintaStockLevel merupakan saldo stok tinta beserta flag low stock
+type TintaStockLevel struct {
+	models.TintaStock
+	IsLowStock bool `json:"is_low_stock"`
+}
+
+// TintaStockListResponse merupakan struct untuk paginated list stok tinta
+type TintaStockListResponse struct {
+	Items      []TintaStockLevel `json:"items"`
+	Total      int               `json:"total"`
+	Page       int               `json:"page"`
+	PerPage    int               `json:"per_page"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// TintaMovementFilters merupakan struct untuk filter dan pagination riwayat mutasi tinta
+type TintaMovementFilters struct {
+	Type              string `form:"type"`
+	ProductionOrderID uint64 `form:"production_order_id"`
+	Page              int    `form:"page"`
+	PerPage           int    `form:"per_page"`
+}
+
+// TintaMovementListResponse merupakan struct untuk paginated riwayat mutasi tinta
+type TintaMovementListResponse struct {
+	Items      []models.TintaStockMovement `json:"items"`
+	Total      int                         `json:"total"`
+	Page       int                         `json:"page"`
+	PerPage    int                         `json:"per_page"`
+	TotalPages int                         `json:"total_pages"`
+}
+
+// CreateTintaStockRequest merupakan request untuk mendaftarkan warna tinta baru
+type CreateTintaStockRequest struct {
+	ColorCode string  `json:"color_code" binding:"required,max=30"`
+	Name      string  `json:"name" binding:"max=100"`
+	MinimumKg float64 `json:"minimum_kg"`
+}
+
+// UpdateTintaStockRequest merupakan request untuk update nama dan batas minimum warna tinta
+type UpdateTintaStockRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,max=100"`
+	MinimumKg *float64 `json:"minimum_kg"`
+}
+
+// ReceiveTintaRequest merupakan request untuk mencatat penerimaan tinta ke gudang
+type ReceiveTintaRequest struct {
+	QuantityKg float64 `json:"quantity_kg" binding:"required"`
+	Reference  string  `json:"reference" binding:"max=100"`
+	Notes      string  `json:"notes"`
+}
+
+// ListStocks mengambil list saldo stok tinta dengan filter search, low stock, dan pagination
+func (s *TintaStockService) ListStocks(filters TintaStockFilters) (*TintaStockListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.TintaStock{})
+	if filters.Search != "" {
+		searchPattern := "%" + filters.Search + "%"
+		query = query.Where("color_code LIKE ? OR name LIKE ?", searchPattern, searchPattern)
+	}
+	if filters.LowStock {
+		query = query.Where("minimum_kg > 0 AND on_hand_kg < minimum_kg")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var stocks []models.TintaStock
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Order("color_code ASC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]TintaStockLevel, 0, len(stocks))
+	for i := range stocks {
+		items = append(items, TintaStockLevel{TintaStock: stocks[i], IsLowStock: stocks[i].IsLowStock()})
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &TintaStockListResponse{
+		Items:      items,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetStock mengambil saldo stok satu warna tinta berdasarkan ID
+func (s *TintaStockService) GetStock(id uint64) (*TintaStockLevel, error) {
+	var stock models.TintaStock
+	if err := s.db.First(&stock, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTintaStockNotFound
+		}
+		return nil, err
+	}
+	return &TintaStockLevel{TintaStock: stock, IsLowStock: stock.IsLowStock()}, nil
+}
+
+// CreateStock mendaftarkan warna tinta baru dengan saldo awal 0 kg,
+// saldo awal dicatat lewat penerimaan agar tercatat di ledger
+func (s *TintaStockService) CreateStock(req CreateTintaStockRequest) (*TintaStockLevel, error) {
+	if req.MinimumKg < 0 {
+		return nil, ErrInvalidTintaMinimum
+	}
+
+	colorCode := models.NormalizeTintaColor(req.ColorCode)
+	var existing int64
+	if err := s.db.Unscoped().Model(&models.TintaStock{}).Where("color_code = ?", colorCode).Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrTintaColorExists
+	}
+
+	stock := models.TintaStock{
+		ColorCode: colorCode,
+		Name:      req.Name,
+		MinimumKg: roundKg(req.MinimumKg),
+	}
+	if err := s.db.Create(&stock).Error; err != nil {
+		return nil, err
+	}
+	return s.GetStock(stock.ID)
+}
+
+// UpdateStock mengupdate nama dan batas minimum warna tinta,
+// perubahan batas minimum langsung dievaluasi untuk notifikasi low stock
+func (s *TintaStockService) UpdateStock(id uint64, req UpdateTintaStockRequest) (*TintaStockLevel, error) {
+	if req.MinimumKg != nil && *req.MinimumKg < 0 {
+		return nil, ErrInvalidTintaMinimum
+	}
+
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		stock, err := lockTintaStock(tx, "id = ?", id)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if req.Name != nil {
+			updates["name"] = *req.Name
+		}
+		if req.MinimumKg != nil {
+			stock.MinimumKg = roundKg(*req.MinimumKg)
+			updates["minimum_kg"] = stock.MinimumKg
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := tx.Model(stock).Updates(updates).Error; err != nil {
+			return err
+		}
+		return checkTintaLowStockInTx(tx, stock)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStock(id)
+}
+
+// ReceiveStock mencatat penerimaan tinta ke gudang dan menambah saldo on hand
+func (s *TintaStockService) ReceiveStock(id uint64, req ReceiveTintaRequest, userID uint64) (*TintaStockLevel, error) {
+	quantity := roundKg(req.QuantityKg)
+	if quantity <= 0 {
+		return nil, ErrInvalidTintaQuantity
+	}
+
+	err := TransactionWithEvents(s.db, func(tx *gorm.DB) error {
+		stock, err := lockTintaStock(tx, "id = ?", id)
+		if err != nil {
+			return err
+		}
+		return applyTintaMovementInTx(tx, stock, tintaMovement{
+			Type:        models.TintaMovementReceipt,
+			QuantityKg:  quantity,
+			OnHandDelta: quantity,
+			Reference:   strings.TrimSpace(req.Reference),
+			Notes:       req.Notes,
+			CreatedBy:   userID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStock(id)
+}
+
+// ListMovements mengambil riwayat mutasi stok satu warna tinta, terbaru di atas
+func (s *TintaStockService) ListMovements(stockID uint64, filters TintaMovementFilters) (*TintaMovementListResponse, error) {
+	if _, err := s.GetStock(stockID); err != nil {
+		return nil, err
+	}
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	query := s.db.Model(&models.TintaStockMovement{}).Where("tinta_stock_id = ?", stockID)
+	if filters.Type != "" {
+		query = query.Where("type = ?", strings.ToUpper(filters.Type))
+	}
+	if filters.ProductionOrderID > 0 {
+		query = query.Where("production_order_id = ?", filters.ProductionOrderID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var movements []models.TintaStockMovement
+	offset := (filters.Page - 1) * filters.PerPage
+	if err := query.
+		Preload("CreatedByUser", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "nip", "full_name")
+		}).
+		Order("created_at DESC, id DESC").
+		Limit(filters.PerPage).
+		Offset(offset).
+		Find(&movements).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filters.PerPage
+	if int(total)%filters.PerPage > 0 {
+		totalPages++
+	}
+
+	return &TintaMovementListResponse{
+		Items:      movements,
+		Total:      int(total),
+		Page:       filters.Page,
+		PerPage:    filters.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// TintaUsage merupakan pemakaian satu warna tinta dalam kg untuk satu material prep
+type TintaUsage struct {
+	Color       string
+	QuantityKg  float64
+	BatchNumber string
+	Checked     bool
+}
+
+// ParseTintaUsage membaca kolom tinta_actual format JSON lama menjadi pemakaian per warna,
+// format yang didukung adalah array [{color, quantity}] dan object {"colors": [...]},
+// warna yang sama dijumlahkan dan hasilnya diurutkan berdasarkan warna
+func ParseTintaUsage(data []byte) ([]TintaUsage, error) {
+	type tintaItem struct {
+		Color    string  `json:"color"`
+		Quantity float64 `json:"quantity"`
+	}
+
+	var items []tintaItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		var wrapper struct {
+			Colors []tintaItem `json:"colors"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, ErrInvalidTintaUsage
+		}
+		items = wrapper.Colors
+	}
+
+	totals := make(map[string]float64)
+	for _, item := range items {
+		color := models.NormalizeTintaColor(item.Color)
+		if color == "" {
+			return nil, fmt.Errorf("%w: warna tinta tidak boleh kosong", ErrInvalidTintaUsage)
+		}
+		if item.Quantity < 0 {
+			return nil, fmt.Errorf("%w: jumlah tinta %s tidak boleh negatif", ErrInvalidTintaUsage, color)
+		}
+		totals[color] += item.Quantity
+	}
+
+	usage := make([]TintaUsage, 0, len(totals))
+	for color, quantity := range totals {
+		usage = append(usage, TintaUsage{Color: color, QuantityKg: roundKg(quantity)})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Color < usage[j].Color })
+	return usage, nil
+}
+
+// TintaLowStock merupakan warna tinta yang saldonya di bawah minimum setelah pemakaian diposting
+type TintaLowStock struct {
+	Color     string  `json:"color"`
+	OnHandKg  float64 `json:"on_hand_kg"`
+	MinimumKg float64 `json:"minimum_kg"`
+}
+
+// postTintaUsageInTx memposting pemakaian tinta material prep ke ledger di dalam transaction milik caller
+// dan mengembalikan warna low stock yang dihitung dari saldo server. Update berulang hanya memposting selisih
+// terhadap jumlah yang sudah dikeluarkan sebelumnya, warna yang belum terdaftar di inventory dilewati
+func postTintaUsageInTx(tx *gorm.DB, prep *models.KhazwalMaterialPreparation, usage []TintaUsage, userID uint64) ([]TintaLowStock, error) {
+	targets := make(map[string]float64, len(usage))
+	for _, item := range usage {
+		targets[item.Color] = item.QuantityKg
+	}
+
+	// Warna yang pernah dikeluarkan untuk prep ini tetapi tidak ada di update terbaru dikembalikan ke stok
+	var issuedColors []string
+	if err := tx.Model(&models.TintaStockMovement{}).
+		Joins("JOIN tinta_stocks ON tinta_stocks.id = tinta_stock_movements.tinta_stock_id").
+		Where("tinta_stock_movements.material_prep_id = ?", prep.ID).
+		Distinct().
+		Pluck("tinta_stocks.color_code", &issuedColors).Error; err != nil {
+		return nil, err
+	}
+	for _, color := range issuedColors {
+		if _, ok := targets[color]; !ok {
+			targets[color] = 0
+		}
+	}
+
+	// Lock diambil berurutan berdasarkan warna untuk menghindari deadlock antar prep
+	colors := make([]string, 0, len(targets))
+	for color := range targets {
+		colors = append(colors, color)
+	}
+	sort.Strings(colors)
+
+	poID := prep.ProductionOrderID
+	prepID := prep.ID
+	lowStock := make([]TintaLowStock, 0)
+
+	for _, color := range colors {
+		stock, err := lockTintaStock(tx, "color_code = ?", color)
+		if err != nil {
+			if errors.Is(err, ErrTintaStockNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		issued, err := issuedTintaForPrep(tx, stock.ID, prep.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		delta := roundKg(targets[color] - issued)
+		switch {
+		case delta > 0:
+			if stock.OnHandKg < delta {
+				return nil, fmt.Errorf("%w: %s tersedia %.3f kg, dibutuhkan %.3f kg", ErrTintaStockInsufficient, color, stock.OnHandKg, delta)
+			}
+			if err := applyTintaMovementInTx(tx, stock, tintaMovement{
+				Type:              models.TintaMovementIssue,
+				QuantityKg:        delta,
+				OnHandDelta:       -delta,
+				ProductionOrderID: &poID,
+				MaterialPrepID:    &prepID,
+				CreatedBy:         userID,
+			}); err != nil {
+				return nil, err
+			}
+		case delta < 0:
+			if err := applyTintaMovementInTx(tx, stock, tintaMovement{
+				Type:              models.TintaMovementReturn,
+				QuantityKg:        -delta,
+				OnHandDelta:       -delta,
+				ProductionOrderID: &poID,
+				MaterialPrepID:    &prepID,
+				Notes:             "Koreksi jumlah tinta material prep",
+				CreatedBy:         userID,
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		if stock.IsLowStock() {
+			lowStock = append(lowStock, TintaLowStock{
+				Color:     color,
+				OnHandKg:  stock.OnHandKg,
+				MinimumKg: stock.MinimumKg,
+			})
+		}
+	}
+	return lowStock, nil
+}
+
+// issuedTintaForPrep menghitung total tinta bersih (keluar dikurangi kembali) untuk satu prep dan warna
+func issuedTintaForPrep(tx *gorm.DB, stockID, prepID uint64) (float64, error) {
+	var issued float64
+	if err := tx.Model(&models.TintaStockMovement{}).
+		Where("tinta_stock_id = ? AND material_prep_id = ?", stockID, prepID).
+		Select("COALESCE(SUM(CASE WHEN type = ? THEN quantity_kg WHEN type = ? THEN -quantity_kg ELSE 0 END), 0)",
+			models.TintaMovementIssue, models.TintaMovementReturn).
+		Scan(&issued).Error; err != nil {
+		return 0, err
+	}
+	return roundKg(issued), nil
+}
+
+// tintaMovement merupakan parameter mutasi stok tinta yang diposting ke ledger
+type tintaMovement struct {
+	Type              models.TintaMovementType
+	QuantityKg        float64
+	OnHandDelta       float64
+	ProductionOrderID *uint64
+	MaterialPrepID    *uint64
+	Reference         string
+	Notes             string
+	CreatedBy         uint64
+}
+
+// applyTintaMovementInTx mengupdate saldo stok tinta yang sudah di-lock, mencatat mutasi ke ledger,
+// dan mengevaluasi notifikasi low stock setelah saldo berubah
+func applyTintaMovementInTx(tx *gorm.DB, stock *models.TintaStock, movement tintaMovement) error {
+	stock.OnHandKg = roundKg(stock.OnHandKg + movement.OnHandDelta)
+
+	if err := tx.Model(stock).Update("on_hand_kg", stock.OnHandKg).Error; err != nil {
+		return err
+	}
+
+	record := models.TintaStockMovement{
+		TintaStockID:      stock.ID,
+		Type:              movement.Type,
+		QuantityKg:        movement.QuantityKg,
+		OnHandAfterKg:     stock.OnHandKg,
+		ProductionOrderID: movement.ProductionOrderID,
+		MaterialPrepID:    movement.MaterialPrepID,
+		Reference:         movement.Reference,
+		Notes:             movement.Notes,
+		CreatedBy:         movement.CreatedBy,
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return err
+	}
+
+	return checkTintaLowStockInTx(tx, stock)
+}
+
+// checkTintaLowStockInTx mengirim notifikasi low stock sekali saat stok tinta turun di bawah batas minimum,
+// flag notifikasi di-reset ketika stok kembali di atas batas minimum
+func checkTintaLowStockInTx(tx *gorm.DB, stock *models.TintaStock) error {
+	if !stock.IsLowStock() {
+		if stock.LowStockNotifiedAt == nil {
+			return nil
+		}
+		stock.LowStockNotifiedAt = nil
+		return tx.Model(stock).Update("low_stock_notified_at", nil).Error
+	}
+	if stock.LowStockNotifiedAt != nil {
+		return nil
+	}
+
+	title := "Stok Tinta Rendah - " + stock.ColorCode
+	message := fmt.Sprintf("Stok tinta %s tinggal %.3f kg (minimum %.3f kg). Segera lakukan pengadaan.", stock.ColorCode, stock.OnHandKg, stock.MinimumKg)
+	if err := notifyMaterialLowStockInTx(tx, title, message); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	stock.LowStockNotifiedAt = &now
+	return tx.Model(stock).Update("low_stock_notified_at", now).Error
+}
+
+// lockTintaStock mengambil stok tinta dengan row lock di dalam transaction milik caller
+func lockTintaStock(tx *gorm.DB, condition string, value interface{}) (*models.TintaStock, error) {
+	var stock models.TintaStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(condition, value).
+		First(&stock).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTintaStockNotFound
+		}
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// roundKg membulatkan berat tinta ke 3 desimal sesuai presisi kolom decimal(12,3)
+func roundKg(kg float64) float64 {
+	return math.Round(kg*1000) / 1000
+}
@@ -477,6 +477,9 @@ func (s *UserService) BulkImportUsersFromCSV(csvData []byte) (*BulkImportResult,
 		return nil, errors.New("CSV file kosong atau tidak memiliki data")
 	}
 
+	// Kolom error dari failed-rows report diabaikan agar report bisa langsung di-upload ulang
+	records = stripImportReportColumn(records)
+
 	// Validate header (expected: NIP, Full Name, Email, Phone, Role, Department)
 	header := records[0]
 	expectedHeaders := []string{"NIP", "Full Name", "Email", "Phone", "Role", "Department"}
@@ -584,6 +587,20 @@ func (s *UserService) BulkImportUsersFromCSV(csvData []byte) (*BulkImportResult,
 	return result, nil
 }
 
+// BuildImportReport menyusun file CSV import asli dengan kolom error untuk setiap row yang gagal
+func (s *UserService) BuildImportReport(csvData []byte, fileName string, result *BulkImportResult, opts ImportReportOptions) (*ImportReport, error) {
+	records, err := csv.NewReader(bytes.NewReader(csvData)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca CSV file: %w", err)
+	}
+
+	rowErrors := make(map[int]string, len(result.Errors))
+	for _, importErr := range result.Errors {
+		rowErrors[importErr.Row] = importErr.Reason
+	}
+	return buildImportReport(records, rowErrors, fileName, opts)
+}
+
 // ExportUsersToCSV mengekspor semua users ke CSV format
 // untuk backup atau migration purposes
 func (s *UserService) ExportUsersToCSV(filters UserFilters) ([]byte, error) {
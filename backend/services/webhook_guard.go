@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Error untuk pembatasan tujuan webhook notifikasi
+var (
+	ErrWebhookHostNotAllowed  = errors.New("host webhook tidak terdaftar di allowlist server")
+	ErrWebhookAddressBlocked  = errors.New("webhook tidak boleh mengarah ke alamat loopback, private, atau link-local")
+	ErrWebhookRedirectBlocked = errors.New("webhook tidak boleh redirect ke host lain")
+)
+
+const webhookResolveTimeout = 5 * time.Second
+
+// carrierGradeNAT merupakan range shared address space (100.64.0.0/10) yang tidak dicakup net.IP.IsPrivate
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// WebhookGuard membatasi tujuan webhook ke host yang terdaftar di allowlist server
+// dan menolak alamat jaringan internal setelah DNS di-resolve untuk mencegah SSRF
+type WebhookGuard struct {
+	allowedHosts         []string
+	allowPrivateNetworks bool
+}
+
+// NewWebhookGuard membuat instance baru dari WebhookGuard, entry allowlist berupa host persis
+// (chat.example.com) atau wildcard subdomain (*.example.com), allowlist kosong menolak semua webhook.
+// allowPrivateNetworks hanya untuk development dengan HTTP sink lokal
+func NewWebhookGuard(allowedHosts []string, allowPrivateNetworks bool) *WebhookGuard {
+	hosts := make([]string, 0, len(allowedHosts))
+	for _, host := range allowedHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return &WebhookGuard{
+		allowedHosts:         hosts,
+		allowPrivateNetworks: allowPrivateNetworks,
+	}
+}
+
+// ValidateURL memeriksa scheme dan allowlist host, lalu me-resolve host dan menolak
+// jika salah satu alamatnya merupakan alamat jaringan internal
+func (g *WebhookGuard) ValidateURL(ctx context.Context, raw string) error {
+	parsed, err := g.checkURL(raw)
+	if err != nil {
+		return err
+	}
+
+	host := parsed.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		return g.checkIP(ip)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookResolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("%w: host tidak dapat di-resolve", ErrWebhookURLInvalid)
+	}
+	for _, addr := range addrs {
+		if err := g.checkIP(addr.IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkURL memeriksa webhook URL absolut dengan scheme http atau https dan host yang ada di allowlist
+func (g *WebhookGuard) checkURL(raw string) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Hostname() == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, ErrWebhookURLInvalid
+	}
+	if !g.isHostAllowed(parsed.Hostname()) {
+		return nil, ErrWebhookHostNotAllowed
+	}
+	return parsed, nil
+}
+
+// isHostAllowed mencocokkan host dengan allowlist, wildcard *.example.com hanya berlaku untuk subdomain
+func (g *WebhookGuard) isHostAllowed(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range g.allowedHosts {
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIP menolak alamat loopback, private, link-local (termasuk metadata cloud), multicast, dan unspecified
+func (g *WebhookGuard) checkIP(ip net.IP) error {
+	if g.allowPrivateNetworks {
+		return nil
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || carrierGradeNAT.Contains(ip) {
+		return ErrWebhookAddressBlocked
+	}
+	return nil
+}
+
+// dialControl memeriksa alamat hasil resolve tepat sebelum koneksi dibuka,
+// sehingga host yang di-resolve ulang ke alamat internal (DNS rebinding) tetap ditolak
+func (g *WebhookGuard) dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ErrWebhookAddressBlocked
+	}
+	return g.checkIP(ip)
+}
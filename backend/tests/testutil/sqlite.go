@@ -0,0 +1,56 @@
+// Package testutil berisi helper database untuk unit test service
+package testutil
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// enumSQLiteDialector merupakan dialector sqlite untuk test yang memetakan kolom enum MySQL ke text,
+// sehingga model produksi dengan kolom enum bisa langsung di-AutoMigrate tanpa DDL manual
+type enumSQLiteDialector struct {
+	sqlite.Dialector
+}
+
+// DataTypeOf mengganti tipe enum dengan text dan meneruskan tipe lain ke dialector sqlite
+func (d enumSQLiteDialector) DataTypeOf(field *schema.Field) string {
+	if strings.HasPrefix(strings.ToLower(string(field.DataType)), "enum") {
+		return "text"
+	}
+	return d.Dialector.DataTypeOf(field)
+}
+
+// Migrator memakai migrator sqlite dengan DataTypeOf dari enumSQLiteDialector
+func (d enumSQLiteDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return sqlite.Migrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+// SetupModelDB membuat database sqlite in-memory dan melakukan AutoMigrate model yang diberikan
+func SetupModelDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(enumSQLiteDialector{sqlite.Dialector{DSN: ":memory:"}}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Gagal membuat test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Satu koneksi agar semua query memakai database in-memory yang sama
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("Gagal migrate test database: %v", err)
+	}
+	return db
+}
@@ -0,0 +1,148 @@
+package cutting_test
+
+import (
+	"errors"
+	"sirine-go/backend/internal/counting"
+	"sirine-go/backend/internal/cutting"
+	"sirine-go/backend/internal/verifikasi"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"sirine-go/backend/tests/testutil"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// setupCuttingDB menyiapkan tabel PO, machine registry, hasil penghitungan dan pemotongan,
+// serta tabel yang ditulis saat finalisasi (label verifikasi, SAP outbox, notifikasi)
+func setupCuttingDB(t *testing.T) *gorm.DB {
+	return testutil.SetupModelDB(t,
+		&models.ProductionOrder{}, &models.POStageTracking{}, &models.Machine{}, &models.PrintJob{},
+		&counting.KhazwalCountingResult{}, &cutting.KhazwalCuttingResult{}, &verifikasi.VerificationLabel{},
+		&models.SAPOutboxEntry{}, &models.User{}, &models.Notification{}, &models.NotificationPreference{})
+}
+
+// seedReadyForCutting menambahkan PO READY_FOR_CUTTING beserta hasil penghitungan yang sudah selesai
+func seedReadyForCutting(t *testing.T, db *gorm.DB, poNumber int64) uint64 {
+	po := models.ProductionOrder{
+		PONumber:        poNumber,
+		OBCNumber:       "OBC-CUT",
+		QuantityOrdered: 1000,
+		OrderDate:       time.Now(),
+		DueDate:         time.Now().AddDate(0, 0, 7),
+		Priority:        models.PriorityNormal,
+		CurrentStage:    models.StageKhazwalCutting,
+		CurrentStatus:   models.StatusReadyForCutting,
+	}
+	if err := db.Create(&po).Error; err != nil {
+		t.Fatal(err)
+	}
+	completedAt := time.Now()
+	if err := db.Create(&counting.KhazwalCountingResult{
+		ProductionOrderID: po.ID,
+		QuantityGood:      500,
+		Status:            counting.CountingCompleted,
+		CompletedAt:       &completedAt,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return po.ID
+}
+
+// seedMachine menambahkan mesin ke machine registry
+func seedMachine(t *testing.T, db *gorm.DB, code string, machineType models.MachineType, status models.MachineStatus) models.Machine {
+	machine := models.Machine{Code: code, Name: code, Type: machineType, Status: status}
+	if err := db.Create(&machine).Error; err != nil {
+		t.Fatal(err)
+	}
+	return machine
+}
+
+// machineStatus membaca status mesin terkini dari database
+func machineStatus(t *testing.T, db *gorm.DB, id uint64) models.MachineStatus {
+	var machine models.Machine
+	if err := db.First(&machine, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return machine.Status
+}
+
+// TestStartCuttingMachineUsage memverifikasi start cutting menandai mesin RUNNING,
+// menolak mesin yang sedang dipakai cutting lain atau tidak operasional, dan finalisasi mengembalikan mesin ke IDLE
+func TestStartCuttingMachineUsage(t *testing.T) {
+	db := setupCuttingDB(t)
+	service := cutting.NewService(cutting.NewRepository(db))
+	first := seedReadyForCutting(t, db, 2026100100000001)
+	second := seedReadyForCutting(t, db, 2026100100000002)
+	machine := seedMachine(t, db, "CUT-01", models.MachineTypeCutting, models.MachineIdle)
+	seedMachine(t, db, "CUT-02", models.MachineTypeCutting, models.MachineDown)
+	seedMachine(t, db, "CTK-01", models.MachineTypeCetak, models.MachineIdle)
+
+	started, err := service.StartCutting(first, cutting.StartCuttingRequest{CuttingMachine: " cut-01 "}, 1)
+	if err != nil {
+		t.Fatalf("StartCutting() error = %v", err)
+	}
+	if started.CuttingMachine != "CUT-01" {
+		t.Errorf("cutting machine = %s, expected CUT-01", started.CuttingMachine)
+	}
+	if status := machineStatus(t, db, machine.ID); status != models.MachineRunning {
+		t.Errorf("status mesin setelah start = %s, expected RUNNING", status)
+	}
+
+	if _, err := service.StartCutting(second, cutting.StartCuttingRequest{CuttingMachine: "CUT-01"}, 1); !errors.Is(err, cutting.ErrMachineBusy) {
+		t.Errorf("start di mesin yang sedang dipakai = %v, expected ErrMachineBusy", err)
+	}
+	if _, err := service.StartCutting(second, cutting.StartCuttingRequest{CuttingMachine: "CUT-02"}, 1); !errors.Is(err, cutting.ErrMachineNotAvailable) {
+		t.Errorf("start di mesin DOWN = %v, expected ErrMachineNotAvailable", err)
+	}
+	if _, err := service.StartCutting(second, cutting.StartCuttingRequest{CuttingMachine: "CTK-01"}, 1); !errors.Is(err, cutting.ErrMachineNotAvailable) {
+		t.Errorf("start di mesin cetak = %v, expected ErrMachineNotAvailable", err)
+	}
+	var secondPO models.ProductionOrder
+	db.First(&secondPO, second)
+	if secondPO.CurrentStatus != models.StatusReadyForCutting {
+		t.Errorf("status PO yang ditolak = %s, expected tetap READY_FOR_CUTTING", secondPO.CurrentStatus)
+	}
+
+	if _, err := service.UpdateCuttingResult(started.ID, cutting.UpdateResultRequest{OutputSisiranKiri: 500, OutputSisiranKanan: 500}); err != nil {
+		t.Fatalf("UpdateCuttingResult() error = %v", err)
+	}
+	if _, err := service.FinalizeCutting(started.ID); err != nil {
+		t.Fatalf("FinalizeCutting() error = %v", err)
+	}
+	if status := machineStatus(t, db, machine.ID); status != models.MachineIdle {
+		t.Errorf("status mesin setelah finalisasi = %s, expected IDLE", status)
+	}
+
+	if _, err := service.StartCutting(second, cutting.StartCuttingRequest{CuttingMachine: "CUT-01"}, 1); err != nil {
+		t.Errorf("start setelah mesin kembali IDLE error = %v", err)
+	}
+}
+
+// TestDeleteMachineWithActiveCutting memverifikasi mesin potong yang sedang dipakai cutting tidak bisa dihapus
+func TestDeleteMachineWithActiveCutting(t *testing.T) {
+	db := setupCuttingDB(t)
+	service := cutting.NewService(cutting.NewRepository(db))
+	machines := services.NewMachineService(db)
+	poID := seedReadyForCutting(t, db, 2026100100000001)
+	machine := seedMachine(t, db, "CUT-01", models.MachineTypeCutting, models.MachineIdle)
+
+	started, err := service.StartCutting(poID, cutting.StartCuttingRequest{CuttingMachine: "CUT-01"}, 1)
+	if err != nil {
+		t.Fatalf("StartCutting() error = %v", err)
+	}
+	if err := machines.DeleteMachine(machine.ID); !errors.Is(err, services.ErrMachineBusy) {
+		t.Fatalf("hapus mesin dengan cutting aktif = %v, expected ErrMachineBusy", err)
+	}
+
+	if _, err := service.UpdateCuttingResult(started.ID, cutting.UpdateResultRequest{OutputSisiranKiri: 500, OutputSisiranKanan: 500}); err != nil {
+		t.Fatalf("UpdateCuttingResult() error = %v", err)
+	}
+	if _, err := service.FinalizeCutting(started.ID); err != nil {
+		t.Fatalf("FinalizeCutting() error = %v", err)
+	}
+	if err := machines.DeleteMachine(machine.ID); err != nil {
+		t.Errorf("hapus mesin setelah cutting selesai error = %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package khazkhir_test
+
+import (
+	"errors"
+	"sirine-go/backend/internal/khazkhir"
+	"sirine-go/backend/tests/testutil"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// seedKhazkhirResult menambahkan rekap Khazanah Akhir dengan status tertentu dan input 1000 lembar HCS
+func seedKhazkhirResult(t *testing.T, db *gorm.DB, poID uint64, status khazkhir.KhazkhirStatus) khazkhir.KhazkhirResult {
+	startedAt := time.Now()
+	result := khazkhir.KhazkhirResult{ProductionOrderID: poID, InputHCS: 1000, Status: status, StartedAt: &startedAt}
+	if err := db.Create(&result).Error; err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// TestSubmitFinalCount memverifikasi final count bisa dikoreksi selama belum dikemas,
+// dan ditolak setelah HCS dikemas atau sebelum Khazanah Akhir dimulai
+func TestSubmitFinalCount(t *testing.T) {
+	db := testutil.SetupModelDB(t, &khazkhir.KhazkhirResult{})
+	service := khazkhir.NewKhazkhirService(db, khazkhir.NewKhazkhirRepository(db))
+	seedKhazkhirResult(t, db, 1, khazkhir.KhazkhirCounting)
+	packed := seedKhazkhirResult(t, db, 2, khazkhir.KhazkhirPacked)
+
+	if _, err := service.SubmitFinalCount(1, khazkhir.FinalCountRequest{FinalCount: 1000}, 3); err != nil {
+		t.Fatalf("SubmitFinalCount() error = %v", err)
+	}
+	result, err := service.SubmitFinalCount(1, khazkhir.FinalCountRequest{FinalCount: 990, VarianceReason: "Rusak saat dihitung"}, 3)
+	if err != nil {
+		t.Fatalf("koreksi final count error = %v", err)
+	}
+	if result.Status != khazkhir.KhazkhirCounted || *result.FinalCount != 990 || result.CountVariance != -10 {
+		t.Errorf("SubmitFinalCount() = %+v, expected COUNTED dengan selisih -10", result)
+	}
+
+	if _, err := service.SubmitFinalCount(2, khazkhir.FinalCountRequest{FinalCount: 1000}, 3); !errors.Is(err, khazkhir.ErrAlreadyCounted) {
+		t.Errorf("final count setelah dikemas = %v, expected ErrAlreadyCounted", err)
+	}
+	var stored khazkhir.KhazkhirResult
+	db.First(&stored, packed.ID)
+	if stored.Status != khazkhir.KhazkhirPacked || stored.FinalCount != nil {
+		t.Errorf("rekap yang sudah dikemas = %+v, expected tidak berubah", stored)
+	}
+
+	if _, err := service.SubmitFinalCount(3, khazkhir.FinalCountRequest{FinalCount: 1000}, 3); !errors.Is(err, khazkhir.ErrKhazkhirNotStarted) {
+		t.Errorf("final count sebelum dimulai = %v, expected ErrKhazkhirNotStarted", err)
+	}
+}
@@ -0,0 +1,89 @@
+package khazkhir_test
+
+import (
+	"errors"
+	"sirine-go/backend/internal/khazkhir"
+	"testing"
+)
+
+// TestBuildPackages memverifikasi pengemasan HCS per rim 500 lembar dan pengelompokan ke box
+func TestBuildPackages(t *testing.T) {
+	tests := []struct {
+		name          string
+		quantity      int
+		rimsPerBox    int
+		expectedRims  int
+		expectedBoxes int
+		lastRimQty    int
+		lastBoxRims   int
+	}{
+		{"Flow example 29.500 lembar", 29500, 10, 59, 6, 500, 9},
+		{"With partial rim", 1200, 10, 3, 1, 200, 3},
+		{"Exact boxes", 2000, 2, 4, 2, 500, 2},
+		{"Zero quantity", 0, 10, 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boxes := khazkhir.BuildPackages(1, 2025001, tt.quantity, tt.rimsPerBox)
+			if len(boxes) != tt.expectedBoxes {
+				t.Fatalf("BuildPackages() boxes = %d, expected %d", len(boxes), tt.expectedBoxes)
+			}
+			if tt.expectedBoxes == 0 {
+				return
+			}
+
+			totalRims := 0
+			totalQty := 0
+			for _, box := range boxes {
+				totalRims += len(box.Rims)
+				totalQty += box.Quantity
+			}
+			if totalRims != tt.expectedRims {
+				t.Errorf("total rims = %d, expected %d", totalRims, tt.expectedRims)
+			}
+			if totalQty != tt.quantity {
+				t.Errorf("total quantity = %d, expected %d", totalQty, tt.quantity)
+			}
+
+			lastBox := boxes[len(boxes)-1]
+			if lastBox.RimCount != tt.lastBoxRims {
+				t.Errorf("last box rims = %d, expected %d", lastBox.RimCount, tt.lastBoxRims)
+			}
+			lastRim := lastBox.Rims[len(lastBox.Rims)-1]
+			if lastRim.Quantity != tt.lastRimQty {
+				t.Errorf("last rim quantity = %d, expected %d", lastRim.Quantity, tt.lastRimQty)
+			}
+			if lastRim.TotalRims != tt.expectedRims {
+				t.Errorf("rim total = %d, expected %d", lastRim.TotalRims, tt.expectedRims)
+			}
+			if boxes[0].Rims[0].RimNumber != "RIM-2025001-001" {
+				t.Errorf("first rim number = %s, expected RIM-2025001-001", boxes[0].Rims[0].RimNumber)
+			}
+		})
+	}
+}
+
+// TestValidateFinalCount memverifikasi business rules penghitungan akhir
+func TestValidateFinalCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       khazkhir.FinalCountRequest
+		inputHCS  int
+		expectErr error
+	}{
+		{"Valid - matches HCS", khazkhir.FinalCountRequest{FinalCount: 29500}, 29500, nil},
+		{"Valid - variance with reason", khazkhir.FinalCountRequest{FinalCount: 29490, VarianceReason: "Selisih hitung ulang"}, 29500, nil},
+		{"Invalid - zero count", khazkhir.FinalCountRequest{FinalCount: 0}, 29500, khazkhir.ErrInvalidFinalCount},
+		{"Invalid - variance without reason", khazkhir.FinalCountRequest{FinalCount: 29490}, 29500, khazkhir.ErrVarianceReasonRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := khazkhir.ValidateFinalCount(tt.req, tt.inputHCS)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("ValidateFinalCount() error = %v, expected %v", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package models_test
+
+import (
+	"sirine-go/backend/models"
+	"testing"
+)
+
+// TestImportJobProgress memverifikasi status selesai dan persentase progress import job
+func TestImportJobProgress(t *testing.T) {
+	tests := []struct {
+		name             string
+		job              models.ImportJob
+		expectedFinished bool
+		expectedProgress float64
+	}{
+		{"Queued tanpa total rows", models.ImportJob{Status: models.ImportJobQueued}, false, 0},
+		{"Running setengah", models.ImportJob{Status: models.ImportJobRunning, TotalRows: 400, ProcessedRows: 200}, false, 50},
+		{"Completed file kosong", models.ImportJob{Status: models.ImportJobCompleted}, true, 100},
+		{"Failed di tengah", models.ImportJob{Status: models.ImportJobFailed, TotalRows: 1000, ProcessedRows: 600}, true, 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.job.IsFinished(); got != tt.expectedFinished {
+				t.Errorf("IsFinished() = %v, expected %v", got, tt.expectedFinished)
+			}
+			if got := tt.job.ProgressPercentage(); got != tt.expectedProgress {
+				t.Errorf("ProgressPercentage() = %v, expected %v", got, tt.expectedProgress)
+			}
+		})
+	}
+}
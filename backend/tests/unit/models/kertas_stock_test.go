@@ -0,0 +1,53 @@
+package models_test
+
+import (
+	"sirine-go/backend/models"
+	"testing"
+)
+
+// TestKertasStockLevel memverifikasi perhitungan stok tersedia dan flag low stock
+func TestKertasStockLevel(t *testing.T) {
+	tests := []struct {
+		name              string
+		stock             models.KertasStock
+		expectedAvailable int64
+		expectedLowStock  bool
+	}{
+		{"Tanpa threshold", models.KertasStock{OnHand: 100, Reserved: 90}, 10, false},
+		{"Di atas threshold", models.KertasStock{OnHand: 5000, Reserved: 1000, LowStockThreshold: 2000}, 4000, false},
+		{"Tepat di threshold", models.KertasStock{OnHand: 3000, Reserved: 1000, LowStockThreshold: 2000}, 2000, false},
+		{"Di bawah threshold karena reservasi", models.KertasStock{OnHand: 3000, Reserved: 1500, LowStockThreshold: 2000}, 1500, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stock.Available(); got != tt.expectedAvailable {
+				t.Errorf("Available() = %d, expected %d", got, tt.expectedAvailable)
+			}
+			if got := tt.stock.IsLowStock(); got != tt.expectedLowStock {
+				t.Errorf("IsLowStock() = %v, expected %v", got, tt.expectedLowStock)
+			}
+		})
+	}
+}
+
+// TestKertasReservationReturnableQuantity memverifikasi batas retur kertas per PO
+func TestKertasReservationReturnableQuantity(t *testing.T) {
+	tests := []struct {
+		name        string
+		reservation models.KertasReservation
+		expected    int64
+	}{
+		{"Masih reserved", models.KertasReservation{Status: models.KertasReservationReserved, Quantity: 1000}, 0},
+		{"Consumed belum retur", models.KertasReservation{Status: models.KertasReservationConsumed, ConsumedQuantity: 1050}, 1050},
+		{"Consumed sebagian diretur", models.KertasReservation{Status: models.KertasReservationConsumed, ConsumedQuantity: 1050, ReturnedQuantity: 50}, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reservation.ReturnableQuantity(); got != tt.expected {
+				t.Errorf("ReturnableQuantity() = %d, expected %d", got, tt.expected)
+			}
+		})
+	}
+}
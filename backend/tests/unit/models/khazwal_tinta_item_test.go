@@ -0,0 +1,57 @@
+package models_test
+
+import (
+	"sirine-go/backend/models"
+	"testing"
+)
+
+// TestKhazwalTintaItemVariance memverifikasi perhitungan variance tinta actual terhadap kebutuhan
+func TestKhazwalTintaItemVariance(t *testing.T) {
+	kg := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name               string
+		item               models.KhazwalTintaItem
+		expectedVariance   *float64
+		expectedPercentage *float64
+	}{
+		{"Actual belum diisi", models.KhazwalTintaItem{RequiredKg: 2}, nil, nil},
+		{"Lebih dari kebutuhan", models.KhazwalTintaItem{RequiredKg: 2, ActualKg: kg(2.5)}, kg(0.5), kg(25)},
+		{"Kurang dari kebutuhan", models.KhazwalTintaItem{RequiredKg: 3, ActualKg: kg(2)}, kg(-1), kg(-33.33)},
+		{"Warna tanpa kebutuhan", models.KhazwalTintaItem{ActualKg: kg(1.2)}, kg(1.2), nil},
+	}
+
+	equal := func(a, b *float64) bool {
+		if a == nil || b == nil {
+			return a == nil && b == nil
+		}
+		return *a == *b
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.item.VarianceKg(); !equal(got, tt.expectedVariance) {
+				t.Errorf("VarianceKg() = %v, expected %v", got, tt.expectedVariance)
+			}
+			if got := tt.item.VariancePercentage(); !equal(got, tt.expectedPercentage) {
+				t.Errorf("VariancePercentage() = %v, expected %v", got, tt.expectedPercentage)
+			}
+		})
+	}
+}
+
+// TestHasTintaActual memverifikasi pengecekan tinta actual dari line item material prep
+func TestHasTintaActual(t *testing.T) {
+	actual := 1.5
+	prep := models.KhazwalMaterialPreparation{
+		TintaItems: []models.KhazwalTintaItem{{ColorCode: "cyan", RequiredKg: 2}},
+	}
+	if prep.HasTintaActual() {
+		t.Error("HasTintaActual() = true untuk prep tanpa actual")
+	}
+
+	prep.TintaItems = append(prep.TintaItems, models.KhazwalTintaItem{ColorCode: "black", ActualKg: &actual})
+	if !prep.HasTintaActual() {
+		t.Error("HasTintaActual() = false untuk prep dengan actual")
+	}
+}
@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	"reflect"
+	"sirine-go/backend/models"
+	"testing"
+
+	"gorm.io/datatypes"
+)
+
+// TestOBCImportBatchItemPOIDs memverifikasi pembacaan PO hasil import dan row yang perlu di-revert
+func TestOBCImportBatchItemPOIDs(t *testing.T) {
+	tests := []struct {
+		name            string
+		item            models.OBCImportBatchItem
+		expectedIDs     []uint64
+		expectedApplied bool
+		expectError     bool
+	}{
+		{"Insert dengan PO", models.OBCImportBatchItem{Outcome: models.ImportItemInserted, GeneratedPOIDs: datatypes.JSON("[11,12]")}, []uint64{11, 12}, true, false},
+		{"Update tanpa PO", models.OBCImportBatchItem{Outcome: models.ImportItemUpdated}, []uint64{}, true, false},
+		{"Unchanged dengan PO", models.OBCImportBatchItem{Outcome: models.ImportItemUnchanged, GeneratedPOIDs: datatypes.JSON("[7]")}, []uint64{7}, false, false},
+		{"Row gagal", models.OBCImportBatchItem{Outcome: models.ImportItemFailed}, []uint64{}, false, false},
+		{"JSON rusak", models.OBCImportBatchItem{Outcome: models.ImportItemInserted, GeneratedPOIDs: datatypes.JSON("{")}, nil, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.item.IsApplied(); got != tt.expectedApplied {
+				t.Errorf("IsApplied() = %v, expected %v", got, tt.expectedApplied)
+			}
+
+			ids, err := tt.item.POIDs()
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(ids, tt.expectedIDs) {
+				t.Errorf("POIDs() = %v, expected %v", ids, tt.expectedIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package models_test
+
+import (
+	"sirine-go/backend/models"
+	"testing"
+	"time"
+)
+
+// TestOBCMasterDiffFields memverifikasi diff per field untuk preview import OBC
+func TestOBCMasterDiffFields(t *testing.T) {
+	dueDate := time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC)
+	sameDueDateOtherZone := time.Date(2025, time.June, 30, 7, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	newDueDate := time.Date(2025, time.July, 15, 0, 0, 0, 0, time.UTC)
+
+	existing := models.OBCMaster{
+		ID:              10,
+		OBCNumber:       "OBC-001",
+		Material:        "PCA-A",
+		QuantityOrdered: 500000,
+		HJE:             1250.5,
+		DueDate:         &dueDate,
+		CreatedAt:       time.Now().Add(-time.Hour),
+	}
+
+	tests := []struct {
+		name           string
+		modify         func(o *models.OBCMaster)
+		expectedFields []string
+	}{
+		{"Tanpa perubahan", func(o *models.OBCMaster) {}, nil},
+		{"Field sistem diabaikan", func(o *models.OBCMaster) { o.ID = 0; o.CreatedAt = time.Time{} }, nil},
+		{"Tanggal sama beda zona", func(o *models.OBCMaster) { o.DueDate = &sameDueDateOtherZone }, nil},
+		{"Presisi desimal", func(o *models.OBCMaster) { o.HJE = 1250.5000001 }, nil},
+		{"Quantity berubah", func(o *models.OBCMaster) { o.QuantityOrdered = 600000 }, []string{"quantity_ordered"}},
+		{"Beberapa field berubah", func(o *models.OBCMaster) {
+			o.Material = "PCA-B"
+			o.DueDate = &newDueDate
+		}, []string{"material", "due_date"}},
+		{"Due date dihapus", func(o *models.OBCMaster) { o.DueDate = nil }, []string{"due_date"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := existing
+			tt.modify(&updated)
+
+			changes := existing.DiffFields(&updated)
+			if len(changes) != len(tt.expectedFields) {
+				t.Fatalf("DiffFields() = %+v, expected fields %v", changes, tt.expectedFields)
+			}
+			for i, field := range tt.expectedFields {
+				if changes[i].Field != field {
+					t.Errorf("changes[%d].Field = %s, expected %s", i, changes[i].Field, field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package models_test
+
+import (
+	"sirine-go/backend/models"
+	"testing"
+)
+
+// TestPlatCheckedOutBy memverifikasi pengecekan PO yang sedang memakai plat
+func TestPlatCheckedOutBy(t *testing.T) {
+	poID := uint64(10)
+
+	tests := []struct {
+		name     string
+		plat     models.Plat
+		poID     uint64
+		expected bool
+	}{
+		{"Dipakai PO yang sama", models.Plat{Status: models.PlatInUse, CurrentPOID: &poID}, 10, true},
+		{"Dipakai PO lain", models.Plat{Status: models.PlatInUse, CurrentPOID: &poID}, 11, false},
+		{"Available tanpa PO", models.Plat{Status: models.PlatAvailable}, 10, false},
+		{"Maintenance dengan sisa PO", models.Plat{Status: models.PlatMaintenance, CurrentPOID: &poID}, 10, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.plat.IsCheckedOutBy(tt.poID); got != tt.expected {
+				t.Errorf("IsCheckedOutBy(%d) = %v, expected %v", tt.poID, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPlatNearEndOfLife memverifikasi batas impression plat
+func TestPlatNearEndOfLife(t *testing.T) {
+	tests := []struct {
+		name     string
+		plat     models.Plat
+		expected bool
+	}{
+		{"Tanpa batas impression", models.Plat{MaxImpressions: 0, TotalImpressions: 1000000}, false},
+		{"Di bawah batas", models.Plat{MaxImpressions: 500000, TotalImpressions: 499999}, false},
+		{"Tepat di batas", models.Plat{MaxImpressions: 500000, TotalImpressions: 500000}, true},
+		{"Melewati batas", models.Plat{MaxImpressions: 500000, TotalImpressions: 520000}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.plat.IsNearEndOfLife(); got != tt.expected {
+				t.Errorf("IsNearEndOfLife() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestIsValidPlatStatus memverifikasi status plat yang dikenal
+func TestIsValidPlatStatus(t *testing.T) {
+	tests := []struct {
+		status   models.PlatStatus
+		expected bool
+	}{
+		{models.PlatAvailable, true},
+		{models.PlatInUse, true},
+		{models.PlatMaintenance, true},
+		{models.PlatRetired, true},
+		{models.PlatStatus("BROKEN"), false},
+		{models.PlatStatus(""), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := models.IsValidPlatStatus(tt.status); got != tt.expected {
+				t.Errorf("IsValidPlatStatus(%q) = %v, expected %v", tt.status, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package models_test
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"testing"
+	"time"
+)
+
+// TestParsePONumberFormat memverifikasi validasi format nomor PO
+func TestParsePONumberFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		seqDigits int
+		expectErr bool
+	}{
+		{"Default format", models.DefaultPONumberFormat, 6, false},
+		{"Tahun 2 digit dan bulan", "{YY}{MM}{SEQ:5}", 5, false},
+		{"Dengan literal angka", "9{YYYY}{SEQ:4}", 4, false},
+		{"Hanya running number", "{SEQ:8}", 8, false},
+		{"SEQ tidak di akhir", "{SEQ:6}{YYYY}", 0, true},
+		{"Tanpa SEQ", "{YYYY}{PLANT:4}", 0, true},
+		{"PLANT tanpa digit", "{YYYY}{PLANT}{SEQ:6}", 0, true},
+		{"Literal huruf", "PO-{YYYY}{SEQ:6}", 0, true},
+		{"Token tidak dikenal", "{YYYY}{DAY}{SEQ:6}", 0, true},
+		{"Melebihi BIGINT", "{YYYY}{PLANT:8}{SEQ:8}", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := models.ParsePONumberFormat(tt.pattern)
+			if tt.expectErr {
+				if !errors.Is(err, models.ErrInvalidPONumberFormat) {
+					t.Errorf("ParsePONumberFormat() error = %v, expected ErrInvalidPONumberFormat", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePONumberFormat() unexpected error = %v", err)
+			}
+			if format.SeqDigits != tt.seqDigits {
+				t.Errorf("SeqDigits = %d, expected %d", format.SeqDigits, tt.seqDigits)
+			}
+		})
+	}
+}
+
+// TestPONumberFormatBuild memverifikasi penyusunan nomor PO dari tanggal, plant, dan running number
+func TestPONumberFormatBuild(t *testing.T) {
+	at := time.Date(2025, time.March, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		pattern   string
+		plantCode string
+		sequence  int64
+		expected  int64
+		expectErr error
+	}{
+		{"Default format", models.DefaultPONumberFormat, "1000", 1, 20251000000001, nil},
+		{"Plant code dipad", models.DefaultPONumberFormat, "12", 42, 20250012000042, nil},
+		{"Plant code dengan huruf", models.DefaultPONumberFormat, "P-1001", 7, 20251001000007, nil},
+		{"Tahun 2 digit dan bulan", "{YY}{MM}{SEQ:5}", "", 123, 250300123, nil},
+		{"Plant code terlalu panjang", models.DefaultPONumberFormat, "123456", 1, 0, models.ErrInvalidPlantCode},
+		{"Running number habis", "{YYYY}{SEQ:2}", "", 100, 0, models.ErrPONumberSequenceExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := models.ParsePONumberFormat(tt.pattern)
+			if err != nil {
+				t.Fatalf("ParsePONumberFormat() unexpected error = %v", err)
+			}
+
+			prefix, err := format.Prefix(at, tt.plantCode)
+			if err == nil {
+				var number int64
+				number, err = format.Build(prefix, tt.sequence)
+				if err == nil && number != tt.expected {
+					t.Errorf("Build() = %d, expected %d", number, tt.expected)
+				}
+			}
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("error = %v, expected %v", err, tt.expectErr)
+			}
+		})
+	}
+}
+
+// TestPONumberFormatSequenceRange memverifikasi rentang nomor PO untuk sinkronisasi sequence
+func TestPONumberFormatSequenceRange(t *testing.T) {
+	format, err := models.ParsePONumberFormat(models.DefaultPONumberFormat)
+	if err != nil {
+		t.Fatalf("ParsePONumberFormat() unexpected error = %v", err)
+	}
+
+	lower, upper, err := format.SequenceRange("20251000")
+	if err != nil {
+		t.Fatalf("SequenceRange() unexpected error = %v", err)
+	}
+	if lower != 20251000000001 || upper != 20251000999999 {
+		t.Errorf("SequenceRange() = [%d, %d], expected [20251000000001, 20251000999999]", lower, upper)
+	}
+}
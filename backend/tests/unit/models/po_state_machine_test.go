@@ -0,0 +1,186 @@
+package models_test
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"testing"
+)
+
+// TestValidateTransition memverifikasi graph perpindahan status PO
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		name        string
+		from        models.POStatus
+		to          models.POStatus
+		expectValid bool
+		expectErr   error
+	}{
+		{
+			name:        "Valid - start material prep",
+			from:        models.StatusWaitingMaterialPrep,
+			to:          models.StatusMaterialPrepInProgress,
+			expectValid: true,
+		},
+		{
+			name:        "Valid - cetak finished to counting",
+			from:        models.StatusCetakInProgress,
+			to:          models.StatusWaitingCounting,
+			expectValid: true,
+		},
+		{
+			name:        "Valid - counting finished to cutting",
+			from:        models.StatusCountingInProgress,
+			to:          models.StatusReadyForCutting,
+			expectValid: true,
+		},
+		{
+			name:        "Valid - khazkhir finished to completed",
+			from:        models.StatusKhazkhirInProgress,
+			to:          models.StatusPOCompleted,
+			expectValid: true,
+		},
+		{
+			name:        "Valid - cancel before cetak",
+			from:        models.StatusReadyForCetak,
+			to:          models.StatusPOCancelled,
+			expectValid: true,
+		},
+		{
+			name:        "Invalid - cancel after production started",
+			from:        models.StatusWaitingCounting,
+			to:          models.StatusPOCancelled,
+			expectValid: false,
+		},
+		{
+			name:        "Invalid - skip cetak",
+			from:        models.StatusMaterialPrepInProgress,
+			to:          models.StatusWaitingCounting,
+			expectValid: false,
+		},
+		{
+			name:        "Invalid - backward",
+			from:        models.StatusReadyForCetak,
+			to:          models.StatusMaterialPrepInProgress,
+			expectValid: false,
+		},
+		{
+			name:        "Invalid - completed is terminal",
+			from:        models.StatusPOCompleted,
+			to:          models.StatusWaitingMaterialPrep,
+			expectValid: false,
+		},
+		{
+			name:        "Unknown - legacy status",
+			from:        models.POStatus("SIAP_POTONG"),
+			to:          models.StatusCuttingInProgress,
+			expectValid: false,
+			expectErr:   models.ErrUnknownPOStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := models.ValidateTransition(tt.from, tt.to)
+			if tt.expectValid {
+				if err != nil {
+					t.Errorf("ValidateTransition() error = %v, expected nil", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("ValidateTransition() expected error, got nil")
+			}
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Errorf("ValidateTransition() error = %v, expected %v", err, tt.expectErr)
+				}
+				return
+			}
+
+			var transitionErr *models.InvalidPOTransitionError
+			if !errors.As(err, &transitionErr) {
+				t.Fatalf("ValidateTransition() error type = %T, expected *InvalidPOTransitionError", err)
+			}
+			if transitionErr.From != tt.from || transitionErr.To != tt.to {
+				t.Errorf("InvalidPOTransitionError = %s → %s, expected %s → %s", transitionErr.From, transitionErr.To, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+// TestStageForStatus memverifikasi mapping status ke stage pemiliknya
+func TestStageForStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   models.POStatus
+		expected models.POStage
+	}{
+		{"Material prep", models.StatusMaterialPrepInProgress, models.StageKhazwalMaterialPrep},
+		{"Cetak", models.StatusReadyForCetak, models.StageCetak},
+		{"Counting", models.StatusWaitingCounting, models.StageKhazwalCounting},
+		{"Cutting", models.StatusCuttingInProgress, models.StageKhazwalCutting},
+		{"Verifikasi", models.StatusReadyForVerifikasi, models.StageVerifikasi},
+		{"Khazkhir", models.StatusKhazkhirInProgress, models.StageKhazkhir},
+		{"Completed", models.StatusPOCompleted, models.StageCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage, err := models.StageForStatus(tt.status)
+			if err != nil {
+				t.Fatalf("StageForStatus() error = %v", err)
+			}
+			if stage != tt.expected {
+				t.Errorf("StageForStatus() = %v, expected %v", stage, tt.expected)
+			}
+		})
+	}
+}
+
+// TestStageAfterTransition memverifikasi PO yang dibatalkan tetap berada di stage terakhirnya
+func TestStageAfterTransition(t *testing.T) {
+	stage, err := models.StageAfterTransition(models.StatusReadyForCetak, models.StatusPOCancelled)
+	if err != nil || stage != models.StageCetak {
+		t.Errorf("StageAfterTransition(READY_FOR_CETAK, CANCELLED) = %s, %v, expected CETAK", stage, err)
+	}
+	stage, err = models.StageAfterTransition(models.StatusMaterialPrepInProgress, models.StatusReadyForCetak)
+	if err != nil || stage != models.StageCetak {
+		t.Errorf("StageAfterTransition(MATERIAL_PREP_IN_PROGRESS, READY_FOR_CETAK) = %s, %v, expected CETAK", stage, err)
+	}
+}
+
+// TestCancelledPOIsTerminal memverifikasi PO yang dibatalkan tidak bisa dipindahkan ke status lain
+func TestCancelledPOIsTerminal(t *testing.T) {
+	err := models.ValidateTransition(models.StatusPOCancelled, models.StatusMaterialPrepInProgress)
+	var transitionErr *models.InvalidPOTransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("ValidateTransition() error = %v, expected *InvalidPOTransitionError", err)
+	}
+}
+
+// TestCanCancelPO memverifikasi PO hanya bisa dibatalkan sebelum produksi dimulai
+func TestCanCancelPO(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   models.POStatus
+		expected bool
+	}{
+		{"Waiting material prep", models.StatusWaitingMaterialPrep, true},
+		{"Ready for cetak", models.StatusReadyForCetak, true},
+		{"Material prep in progress", models.StatusMaterialPrepInProgress, false},
+		{"Cetak in progress", models.StatusCetakInProgress, false},
+		{"Waiting counting", models.StatusWaitingCounting, false},
+		{"Ready for verifikasi", models.StatusReadyForVerifikasi, false},
+		{"Completed", models.StatusPOCompleted, false},
+		{"Already cancelled", models.StatusPOCancelled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := models.CanCancelPO(tt.status); got != tt.expected {
+				t.Errorf("CanCancelPO(%s) = %v, expected %v", tt.status, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package models_test
+
+import (
+	"sirine-go/backend/models"
+	"testing"
+	"time"
+)
+
+// TestPrintJobCalculateDurationMinutes memverifikasi durasi efektif dikurangi waktu pause
+func TestPrintJobCalculateDurationMinutes(t *testing.T) {
+	startedAt := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	completedAt := startedAt.Add(3 * time.Hour)
+
+	tests := []struct {
+		name          string
+		startedAt     *time.Time
+		pausedMinutes int
+		expected      int
+	}{
+		{"Without pause", &startedAt, 0, 180},
+		{"With pause", &startedAt, 45, 135},
+		{"Pause longer than duration", &startedAt, 240, 0},
+		{"Not started", nil, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &models.PrintJob{
+				StartedAt:          tt.startedAt,
+				TotalPausedMinutes: tt.pausedMinutes,
+			}
+			result := job.CalculateDurationMinutes(completedAt)
+			if result != tt.expected {
+				t.Errorf("CalculateDurationMinutes() = %d, expected %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPrintJobRejectPercentage memverifikasi persentase lembar rusak
+func TestPrintJobRejectPercentage(t *testing.T) {
+	tests := []struct {
+		name     string
+		good     int
+		rejected int
+		expected float64
+	}{
+		{"No sheets", 0, 0, 0},
+		{"No reject", 1000, 0, 0},
+		{"Five percent reject", 950, 50, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &models.PrintJob{
+				GoodSheets:     tt.good,
+				RejectedSheets: tt.rejected,
+			}
+			result := job.RejectPercentage()
+			if result != tt.expected {
+				t.Errorf("RejectPercentage() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
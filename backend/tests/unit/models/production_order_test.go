@@ -0,0 +1,67 @@
+package models_test
+
+import (
+	"sirine-go/backend/models"
+	"testing"
+)
+
+// TestApplyQuantity memverifikasi penyesuaian target lembar besar dan estimasi rim saat split/merge
+func TestApplyQuantity(t *testing.T) {
+	defaultRule := models.DefaultProductionRule()
+	customRule := models.ProductionRule{SheetsPerRim: 1000, LembarBesarMultiplier: 4}
+
+	tests := []struct {
+		name           string
+		rule           models.ProductionRule
+		newQuantity    int
+		expectedTarget int
+		expectedRims   int
+	}{
+		{"Default rule", defaultRule, 15000, 7500, 30},
+		{"Default rule dengan sisa rim", defaultRule, 2500, 1250, 5},
+		{"Default rule quantity ganjil", defaultRule, 1201, 601, 3},
+		{"Custom rule", customRule, 10000, 2500, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			po := &models.ProductionOrder{QuantityOrdered: 40000}
+			po.ApplyQuantity(tt.newQuantity, &tt.rule)
+
+			if po.QuantityOrdered != tt.newQuantity {
+				t.Errorf("QuantityOrdered = %d, expected %d", po.QuantityOrdered, tt.newQuantity)
+			}
+			if po.QuantityTargetLembarBesar != tt.expectedTarget {
+				t.Errorf("QuantityTargetLembarBesar = %d, expected %d", po.QuantityTargetLembarBesar, tt.expectedTarget)
+			}
+			if po.EstimatedRims != tt.expectedRims {
+				t.Errorf("EstimatedRims = %d, expected %d", po.EstimatedRims, tt.expectedRims)
+			}
+		})
+	}
+}
+
+// TestIsMaterialPrepStarted memverifikasi deteksi PO yang sudah mulai material prep
+func TestIsMaterialPrepStarted(t *testing.T) {
+	tests := []struct {
+		name     string
+		stage    models.POStage
+		status   models.POStatus
+		expected bool
+	}{
+		{"Menunggu material prep", models.StageKhazwalMaterialPrep, models.StatusWaitingMaterialPrep, false},
+		{"Dibatalkan sebelum material prep", models.StageKhazwalMaterialPrep, models.StatusPOCancelled, false},
+		{"Material prep berjalan", models.StageKhazwalMaterialPrep, models.StatusMaterialPrepInProgress, true},
+		{"Siap cetak", models.StageCetak, models.StatusReadyForCetak, true},
+		{"Dibatalkan setelah material prep", models.StageCetak, models.StatusPOCancelled, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			po := &models.ProductionOrder{CurrentStage: tt.stage, CurrentStatus: tt.status}
+			if got := po.IsMaterialPrepStarted(); got != tt.expected {
+				t.Errorf("IsMaterialPrepStarted() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package models_test
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"testing"
+)
+
+// TestProductionRuleCalculateTotalWithBuffer memverifikasi perhitungan total produksi dengan buffer
+func TestProductionRuleCalculateTotalWithBuffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		buffer   float64
+		quantity int
+		expected int
+	}{
+		{"Default buffer 6%", models.DefaultBufferPercentage, 500000, 530000},
+		{"Buffer 10%", 10, 1000, 1100},
+		{"Tanpa buffer", 0, 1000, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := models.ProductionRule{BufferPercentage: tt.buffer}
+			if got := rule.CalculateTotalWithBuffer(tt.quantity); got != tt.expected {
+				t.Errorf("CalculateTotalWithBuffer() = %d, expected %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestProductionRuleSplitQuantities memverifikasi pembagian total produksi ke beberapa PO
+func TestProductionRuleSplitQuantities(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxPerPO      int
+		total         int
+		expectedCount int
+		expectedLast  int
+	}{
+		{"Flow example 530.000 lembar", 40000, 530000, 14, 10000},
+		{"Tepat kelipatan", 40000, 80000, 2, 40000},
+		{"Di bawah maksimal", 40000, 1060, 1, 1060},
+		{"Custom maksimal", 25000, 60000, 3, 10000},
+		{"Total nol", 40000, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := models.ProductionRule{MaxQuantityPerPO: tt.maxPerPO}
+			quantities := rule.SplitQuantities(tt.total)
+			if len(quantities) != tt.expectedCount {
+				t.Fatalf("SplitQuantities() count = %d, expected %d", len(quantities), tt.expectedCount)
+			}
+			if tt.expectedCount == 0 {
+				return
+			}
+
+			sum := 0
+			for _, qty := range quantities {
+				if qty > tt.maxPerPO {
+					t.Errorf("quantity %d melebihi maksimal %d", qty, tt.maxPerPO)
+				}
+				sum += qty
+			}
+			if sum != tt.total {
+				t.Errorf("total quantity = %d, expected %d", sum, tt.total)
+			}
+			if last := quantities[len(quantities)-1]; last != tt.expectedLast {
+				t.Errorf("last quantity = %d, expected %d", last, tt.expectedLast)
+			}
+		})
+	}
+}
+
+// TestProductionRuleMatches memverifikasi pemilihan rule berdasarkan tipe produk dan personalisasi
+func TestProductionRuleMatches(t *testing.T) {
+	tests := []struct {
+		name                string
+		rule                models.ProductionRule
+		productType         string
+		personalization     string
+		expectedMatch       bool
+		expectedSpecificity int
+	}{
+		{"Wildcard rule", models.ProductionRule{}, "PCA", "Perso", true, 0},
+		{"Tipe produk cocok", models.ProductionRule{ProductType: "PCA"}, "pca", "Non Perso", true, 2},
+		{"Tipe produk berbeda", models.ProductionRule{ProductType: "MMEA"}, "PCA", "Perso", false, 2},
+		{"Personalisasi cocok", models.ProductionRule{Personalization: "Perso"}, "PCA", "PERSO", true, 1},
+		{"Keduanya cocok", models.ProductionRule{ProductType: "PCA", Personalization: "Perso"}, "PCA", "Perso", true, 3},
+		{"Personalisasi berbeda", models.ProductionRule{ProductType: "PCA", Personalization: "Perso"}, "PCA", "Non Perso", false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(tt.productType, tt.personalization); got != tt.expectedMatch {
+				t.Errorf("Matches() = %v, expected %v", got, tt.expectedMatch)
+			}
+			if got := tt.rule.Specificity(); got != tt.expectedSpecificity {
+				t.Errorf("Specificity() = %d, expected %d", got, tt.expectedSpecificity)
+			}
+		})
+	}
+}
+
+// TestProductionRuleValidate memverifikasi validasi parameter production rule
+func TestProductionRuleValidate(t *testing.T) {
+	valid := models.DefaultProductionRule()
+
+	tests := []struct {
+		name      string
+		modify    func(r *models.ProductionRule)
+		expectErr error
+	}{
+		{"Default rule valid", func(r *models.ProductionRule) {}, nil},
+		{"Buffer negatif", func(r *models.ProductionRule) { r.BufferPercentage = -1 }, models.ErrInvalidBufferPercentage},
+		{"Maks per PO nol", func(r *models.ProductionRule) { r.MaxQuantityPerPO = 0 }, models.ErrInvalidMaxQuantityPerPO},
+		{"Lembar per rim nol", func(r *models.ProductionRule) { r.SheetsPerRim = 0 }, models.ErrInvalidSheetsPerRim},
+		{"Multiplier nol", func(r *models.ProductionRule) { r.LembarBesarMultiplier = 0 }, models.ErrInvalidLembarBesarMultiplier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := valid
+			tt.modify(&rule)
+			if err := rule.Validate(); !errors.Is(err, tt.expectErr) {
+				t.Errorf("Validate() error = %v, expected %v", err, tt.expectErr)
+			}
+		})
+	}
+}
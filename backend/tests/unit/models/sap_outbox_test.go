@@ -0,0 +1,56 @@
+package models_test
+
+import (
+	"sirine-go/backend/models"
+	"testing"
+	"time"
+)
+
+// TestSAPOutboxRetryDelay memverifikasi exponential backoff dengan batas maksimal 1 jam
+func TestSAPOutboxRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{20, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := models.SAPOutboxRetryDelay(tt.attempts); got != tt.expected {
+			t.Errorf("SAPOutboxRetryDelay(%d) = %v, expected %v", tt.attempts, got, tt.expected)
+		}
+	}
+}
+
+// TestSAPOutboxEntryState memverifikasi status retry dan batas percobaan entry outbox
+func TestSAPOutboxEntryState(t *testing.T) {
+	tests := []struct {
+		name              string
+		entry             models.SAPOutboxEntry
+		expectedRetry     bool
+		expectedExhausted bool
+	}{
+		{"Pending baru", models.SAPOutboxEntry{Status: models.SAPOutboxPending, MaxAttempts: 8}, true, false},
+		{"Sedang diproses", models.SAPOutboxEntry{Status: models.SAPOutboxProcessing, Attempts: 2, MaxAttempts: 8}, false, false},
+		{"Gagal permanen", models.SAPOutboxEntry{Status: models.SAPOutboxFailed, Attempts: 8, MaxAttempts: 8}, true, true},
+		{"Sudah terkirim", models.SAPOutboxEntry{Status: models.SAPOutboxDelivered, Attempts: 1, MaxAttempts: 8}, false, false},
+		{"Tanpa batas percobaan", models.SAPOutboxEntry{Status: models.SAPOutboxPending, Attempts: 50}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.CanRetry(); got != tt.expectedRetry {
+				t.Errorf("CanRetry() = %v, expected %v", got, tt.expectedRetry)
+			}
+			if got := tt.entry.HasExhaustedAttempts(); got != tt.expectedExhausted {
+				t.Errorf("HasExhaustedAttempts() = %v, expected %v", got, tt.expectedExhausted)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	"sirine-go/backend/models"
+	"testing"
+)
+
+// TestSLAThresholdLevelFor memverifikasi batas AT_RISK dan BREACHED dari waktu tunggu
+func TestSLAThresholdLevelFor(t *testing.T) {
+	threshold := models.SLAThreshold{WarningMinutes: 90, BreachMinutes: 120}
+	tests := []struct {
+		waitingMinutes int
+		expected       models.SLALevel
+	}{
+		{0, models.SLAOnTrack},
+		{90, models.SLAOnTrack},
+		{91, models.SLAAtRisk},
+		{120, models.SLAAtRisk},
+		{121, models.SLABreached},
+	}
+
+	for _, tt := range tests {
+		if got := threshold.LevelFor(tt.waitingMinutes); got != tt.expected {
+			t.Errorf("LevelFor(%d) = %s, expected %s", tt.waitingMinutes, got, tt.expected)
+		}
+	}
+}
+
+// TestDefaultSLAThreshold memverifikasi SLA bawaan per stage, SLA URGENT setengah dari normal
+func TestDefaultSLAThreshold(t *testing.T) {
+	for _, stage := range models.SLAStages() {
+		normal, ok := models.DefaultSLAThreshold(stage, models.PriorityNormal)
+		if !ok || !normal.IsValid() {
+			t.Errorf("DefaultSLAThreshold(%s) = %+v, %v", stage, normal, ok)
+		}
+		urgent, _ := models.DefaultSLAThreshold(stage, models.PriorityUrgent)
+		if urgent.BreachMinutes*2 != normal.BreachMinutes || !urgent.IsValid() {
+			t.Errorf("DefaultSLAThreshold(%s, URGENT) = %+v, normal %+v", stage, urgent, normal)
+		}
+		if _, ok := models.SLAQueueStatus(stage); !ok {
+			t.Errorf("SLAQueueStatus(%s) tidak terdaftar", stage)
+		}
+	}
+
+	if _, ok := models.DefaultSLAThreshold(models.StageCompleted, models.PriorityNormal); ok {
+		t.Error("DefaultSLAThreshold(COMPLETED) expected tidak memiliki SLA")
+	}
+}
@@ -0,0 +1,215 @@
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupAttachmentService membuat AttachmentService dengan sqlite in-memory dan folder sementara
+func setupAttachmentService(t *testing.T) (*services.AttachmentService, *gorm.DB, string) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Gagal membuat test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Attachment{}); err != nil {
+		t.Fatalf("Gagal migrate database: %v", err)
+	}
+
+	dir := t.TempDir()
+	return services.NewAttachmentService(db, dir, "/uploads/evidence", 1024*1024), db, dir
+}
+
+// multipartFile membuat multipart.FileHeader dari isi file untuk dipakai Upload
+func multipartFile(t *testing.T, filename string, data []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(data)
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/api/attachments", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if err := req.ParseMultipartForm(2 * 1024 * 1024); err != nil {
+		t.Fatal(err)
+	}
+	return req.MultipartForm.File["file"][0]
+}
+
+// samplePNG membuat gambar PNG berukuran tertentu
+func samplePNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, x%height, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// TestAttachmentUpload memverifikasi content sniffing, thumbnail, dan penolakan file yang tidak valid
+func TestAttachmentUpload(t *testing.T) {
+	svc, _, dir := setupAttachmentService(t)
+
+	// Extension .jpg tetapi isi PNG: format ditentukan dari isi file
+	attachment, err := svc.Upload(models.AttachmentOwnerMaterialPrep, multipartFile(t, "foto.jpg", samplePNG(t, 800, 400)), 7)
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if attachment.ContentType != "image/png" || filepath.Ext(attachment.StoragePath) != ".png" {
+		t.Errorf("content type = %s, path = %s", attachment.ContentType, attachment.StoragePath)
+	}
+	if attachment.Width != 800 || attachment.Height != 400 || attachment.IsLinked() {
+		t.Errorf("attachment = %+v", attachment)
+	}
+	if attachment.URL != "/uploads/evidence/"+attachment.StoragePath {
+		t.Errorf("URL = %s", attachment.URL)
+	}
+
+	thumbFile, err := os.Open(filepath.Join(dir, attachment.ThumbnailPath))
+	if err != nil {
+		t.Fatalf("thumbnail tidak dibuat: %v", err)
+	}
+	defer thumbFile.Close()
+	thumb, format, err := image.DecodeConfig(thumbFile)
+	if err != nil || format != "jpeg" || thumb.Width != 320 || thumb.Height != 160 {
+		t.Errorf("thumbnail = %+v format %s err %v", thumb, format, err)
+	}
+
+	tests := []struct {
+		name     string
+		owner    models.AttachmentOwnerType
+		filename string
+		data     []byte
+		expected error
+	}{
+		{"Bukan gambar", models.AttachmentOwnerMaterialPrep, "foto.jpg", []byte("bukan gambar sama sekali"), services.ErrAttachmentUnsupportedType},
+		{"PNG rusak", models.AttachmentOwnerMaterialPrep, "foto.png", samplePNG(t, 10, 10)[:40], services.ErrAttachmentInvalidImage},
+		{"Melebihi batas ukuran", models.AttachmentOwnerMaterialPrep, "foto.png", make([]byte, 1024*1024+1), services.ErrAttachmentTooLarge},
+		{"Jenis pemilik tidak dikenal", models.AttachmentOwnerType("PO"), "foto.png", samplePNG(t, 10, 10), services.ErrAttachmentInvalidOwner},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Upload(tt.owner, multipartFile(t, tt.filename, tt.data), 7); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestLinkAttachmentsInTx memverifikasi linking ke entity pemilik dan penggantian lampiran lama
+func TestLinkAttachmentsInTx(t *testing.T) {
+	svc, db, _ := setupAttachmentService(t)
+
+	upload := func(owner models.AttachmentOwnerType) uint64 {
+		attachment, err := svc.Upload(owner, multipartFile(t, "foto.png", samplePNG(t, 20, 20)), 7)
+		if err != nil {
+			t.Fatalf("Upload() error = %v", err)
+		}
+		return attachment.ID
+	}
+	first, second, waste := upload(models.AttachmentOwnerCuttingWaste), upload(models.AttachmentOwnerCuttingWaste), upload(models.AttachmentOwnerMaterialPrep)
+
+	linked, err := services.LinkAttachmentsInTx(db, models.AttachmentOwnerCuttingWaste, 5, []uint64{first})
+	if err != nil || len(linked) != 1 || !linked[0].IsLinkedTo(models.AttachmentOwnerCuttingWaste, 5) {
+		t.Fatalf("link pertama: %+v, err = %v", linked, err)
+	}
+
+	// Lampiran yang sudah terhubung tidak bisa dipakai entity lain
+	if _, err := services.LinkAttachmentsInTx(db, models.AttachmentOwnerCuttingWaste, 6, []uint64{first}); !errors.Is(err, services.ErrAttachmentAlreadyLinked) {
+		t.Errorf("expected ErrAttachmentAlreadyLinked, got %v", err)
+	}
+	if _, err := services.LinkAttachmentsInTx(db, models.AttachmentOwnerCuttingWaste, 5, []uint64{waste}); !errors.Is(err, services.ErrAttachmentInvalidOwner) {
+		t.Errorf("expected ErrAttachmentInvalidOwner, got %v", err)
+	}
+	if _, err := services.LinkAttachmentsInTx(db, models.AttachmentOwnerCuttingWaste, 5, []uint64{999}); !errors.Is(err, services.ErrAttachmentNotFound) {
+		t.Errorf("expected ErrAttachmentNotFound, got %v", err)
+	}
+	if _, err := services.LinkAttachmentsInTx(db, models.AttachmentOwnerCuttingWaste, 5, []uint64{first, second}); !errors.Is(err, services.ErrAttachmentTooMany) {
+		t.Errorf("expected ErrAttachmentTooMany, got %v", err)
+	}
+
+	// Foto waste baru menggantikan foto lama yang kemudian dilepas
+	if _, err := services.LinkAttachmentsInTx(db, models.AttachmentOwnerCuttingWaste, 5, []uint64{second}); err != nil {
+		t.Fatalf("link pengganti: %v", err)
+	}
+	var released models.Attachment
+	db.First(&released, first)
+	if released.IsLinked() || released.LinkedAt != nil {
+		t.Errorf("lampiran lama masih terhubung: %+v", released)
+	}
+	if err := svc.DeleteAttachment(second, 7, false); !errors.Is(err, services.ErrAttachmentLocked) {
+		t.Errorf("expected ErrAttachmentLocked, got %v", err)
+	}
+	if err := svc.DeleteAttachment(first, 8, false); !errors.Is(err, services.ErrAttachmentForbidden) {
+		t.Errorf("expected ErrAttachmentForbidden, got %v", err)
+	}
+}
+
+// TestCleanupOrphans memverifikasi retention job menghapus lampiran tanpa pemilik dan file liar
+func TestCleanupOrphans(t *testing.T) {
+	svc, db, dir := setupAttachmentService(t)
+
+	upload := func() *models.Attachment {
+		attachment, err := svc.Upload(models.AttachmentOwnerMaterialPrep, multipartFile(t, "foto.png", samplePNG(t, 20, 20)), 7)
+		if err != nil {
+			t.Fatalf("Upload() error = %v", err)
+		}
+		return attachment
+	}
+	orphan, linked, fresh := upload(), upload(), upload()
+	if _, err := services.LinkAttachmentsInTx(db, models.AttachmentOwnerMaterialPrep, 1, []uint64{linked.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	db.Model(&models.Attachment{}).Where("id IN ?", []uint64{orphan.ID, linked.ID}).Update("updated_at", old)
+
+	stray := filepath.Join(dir, "material_prep", "stray.png")
+	os.WriteFile(stray, []byte("upload terputus"), 0644)
+	os.Chtimes(stray, old, old)
+	for _, attachment := range []*models.Attachment{orphan, linked, fresh} {
+		os.Chtimes(filepath.Join(dir, attachment.StoragePath), old, old)
+	}
+
+	result, err := svc.CleanupOrphans(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("CleanupOrphans() error = %v", err)
+	}
+	if result.DeletedAttachments != 1 || result.DeletedFiles != 3 {
+		t.Errorf("result = %+v, expected 1 lampiran dan 3 file", result)
+	}
+
+	var remaining int64
+	db.Model(&models.Attachment{}).Count(&remaining)
+	if remaining != 2 {
+		t.Errorf("sisa lampiran = %d, expected 2", remaining)
+	}
+	if _, err := os.Stat(filepath.Join(dir, orphan.StoragePath)); !os.IsNotExist(err) {
+		t.Error("file lampiran orphan masih ada")
+	}
+	if _, err := os.Stat(stray); !os.IsNotExist(err) {
+		t.Error("file liar masih ada")
+	}
+	if _, err := os.Stat(filepath.Join(dir, linked.StoragePath)); err != nil {
+		t.Errorf("file lampiran yang terhubung ikut terhapus: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// receiveEvents mengambil semua event yang sudah ada di channel subscriber tanpa menunggu
+func receiveEvents(sub *services.EventSubscription) []services.DomainEvent {
+	var events []services.DomainEvent
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+// TestUserEventFilter memverifikasi filter topic berdasarkan role dan notifikasi per user
+func TestUserEventFilter(t *testing.T) {
+	cetakEvent := services.DomainEvent{Type: services.EventPrintJobFinalized, Topics: []string{services.TopicCetak}}
+	userID := uint64(7)
+	notification := services.DomainEvent{Type: services.EventNotificationCreated, Topics: []string{services.TopicNotifications}, UserID: &userID}
+
+	tests := []struct {
+		name     string
+		userID   uint64
+		role     models.UserRole
+		topics   []string
+		event    services.DomainEvent
+		expected bool
+	}{
+		{"Operator cetak menerima queue cetak", 1, models.RoleOperatorCetak, nil, cetakEvent, true},
+		{"Staff khazwal tidak menerima queue cetak", 1, models.RoleStaffKhazwal, nil, cetakEvent, false},
+		{"Topic yang tidak diminta diabaikan", 1, models.RoleAdmin, []string{services.TopicNotifications}, cetakEvent, false},
+		{"Topic di luar role diabaikan", 1, models.RoleStaffKhazwal, []string{services.TopicCetak}, cetakEvent, false},
+		{"Notifikasi milik user sendiri", 7, models.RoleStaffKhazwal, nil, notification, true},
+		{"Notifikasi milik user lain", 8, models.RoleAdmin, nil, notification, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := services.NewUserEventFilter(tt.userID, tt.role, tt.topics)
+			if got := filter(tt.event); got != tt.expected {
+				t.Errorf("filter() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestEventBusSlowSubscriber memverifikasi subscriber yang buffer-nya penuh diputus tanpa memblokir publisher
+func TestEventBusSlowSubscriber(t *testing.T) {
+	bus := services.NewEventBus(2)
+	sub := bus.Subscribe(nil)
+
+	for i := 0; i < 3; i++ {
+		bus.Publish(services.DomainEvent{Type: services.EventPOStatusChanged, Topics: []string{services.TopicCetak}})
+	}
+
+	if events := receiveEvents(sub); len(events) != 2 {
+		t.Errorf("received %d event, expected 2", len(events))
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Error("channel subscriber lambat masih terbuka")
+	}
+	if bus.SubscriberCount() != 0 {
+		t.Errorf("SubscriberCount() = %d, expected 0", bus.SubscriberCount())
+	}
+}
+
+// TestEventBusCloseAll memverifikasi semua subscriber ditutup saat server shutdown
+func TestEventBusCloseAll(t *testing.T) {
+	bus := services.NewEventBus(2)
+	subs := []*services.EventSubscription{bus.Subscribe(nil), bus.Subscribe(nil)}
+
+	bus.CloseAll()
+
+	for i, sub := range subs {
+		if _, ok := <-sub.Events(); ok {
+			t.Errorf("channel subscriber %d masih terbuka", i)
+		}
+		bus.Unsubscribe(sub)
+	}
+	if bus.SubscriberCount() != 0 {
+		t.Errorf("SubscriberCount() = %d, expected 0", bus.SubscriberCount())
+	}
+}
+
+// TestTransactionWithEvents memverifikasi event hanya dipublish setelah transaction berhasil commit
+func TestTransactionWithEvents(t *testing.T) {
+	db := setupNotificationDB(t)
+	if err := services.RegisterDomainEventCallbacks(db); err != nil {
+		t.Fatalf("RegisterDomainEventCallbacks() error = %v", err)
+	}
+
+	bus := services.DefaultEventBus()
+	sub := bus.Subscribe(services.NewUserEventFilter(7, models.RoleOperatorCetak, nil))
+	defer bus.Unsubscribe(sub)
+
+	finalized := services.NewFinalizedEvent(services.EventPrintJobFinalized, models.StageCetak, 10, 3, nil)
+	rollback := errors.New("rollback")
+	err := services.TransactionWithEvents(db, func(tx *gorm.DB) error {
+		services.RecordDomainEventInTx(tx, finalized)
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+	if events := receiveEvents(sub); len(events) != 0 {
+		t.Fatalf("event dari transaction yang di-rollback terkirim: %+v", events)
+	}
+
+	err = services.TransactionWithEvents(db, func(tx *gorm.DB) error {
+		services.RecordDomainEventInTx(tx, finalized)
+		if err := tx.Create(&models.Notification{UserID: 7, Title: "Material Siap", Message: "Silakan cetak"}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.Notification{UserID: 8, Title: "Material Siap", Message: "Silakan cetak"}).Error; err != nil {
+			return err
+		}
+		if events := receiveEvents(sub); len(events) != 0 {
+			t.Errorf("event terkirim sebelum commit: %+v", events)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("TransactionWithEvents() error = %v", err)
+	}
+
+	events := receiveEvents(sub)
+	if len(events) != 2 || events[0].Type != services.EventPrintJobFinalized || events[1].Type != services.EventNotificationCreated {
+		t.Fatalf("events = %+v, expected finalize dan satu notifikasi milik user", events)
+	}
+	if data, ok := events[1].Data.(services.NotificationEventData); !ok || data.ID == 0 || data.Title != "Material Siap" {
+		t.Errorf("notification data = %+v", events[1].Data)
+	}
+}
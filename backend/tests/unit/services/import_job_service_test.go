@@ -0,0 +1,212 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"sirine-go/backend/tests/testutil"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// setupImportJobDB menyiapkan tabel import job, import batch, OBC, dan tabel untuk generate PO
+func setupImportJobDB(t *testing.T) *gorm.DB {
+	return testutil.SetupModelDB(t,
+		&models.ImportJob{}, &models.OBCImportBatch{}, &models.OBCImportBatchItem{},
+		&models.OBCMaster{}, &models.ProductionOrder{}, &models.ProductionRule{}, &models.PONumberSequence{})
+}
+
+// newImportJobService membuat service import job dengan format nomor PO default
+func newImportJobService(db *gorm.DB) *services.ImportJobService {
+	obcService := services.NewOBCImportService(db, services.NewPONumberGenerator(models.DefaultPONumberFormat, ""), nil)
+	return services.NewImportJobService(db, obcService)
+}
+
+// buildImportCSV membuat file CSV import OBC dengan jumlah row tertentu
+func buildImportCSV(rows int) []byte {
+	var b strings.Builder
+	b.WriteString("No OBC,Material,QTY PESAN,Plnt\n")
+	for i := 1; i <= rows; i++ {
+		fmt.Fprintf(&b, "OBC-JOB-%04d,PITA,1000,1001\n", i)
+	}
+	return []byte(b.String())
+}
+
+// failBatchSummaryUpdate memasang callback yang memanggil hook pada setiap update ringkasan import batch,
+// hook mengembalikan error untuk menggagalkan transaction chunk tersebut
+func failBatchSummaryUpdate(t *testing.T, db *gorm.DB, hook func(call int) error) {
+	calls := 0
+	err := db.Callback().Update().Before("gorm:update").Register("test:import_batch_summary", func(tx *gorm.DB) {
+		if tx.Statement.Table != "obc_import_batches" {
+			return
+		}
+		calls++
+		if err := hook(calls); err != nil {
+			tx.AddError(err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// loadImportJob membaca import job terkini dari database
+func loadImportJob(t *testing.T, db *gorm.DB, id uint64) models.ImportJob {
+	var job models.ImportJob
+	if err := db.First(&job, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+// TestImportJobChunking memverifikasi job memproses row per chunk sampai selesai
+// dan ringkasan job maupun import batch yang terhubung sesuai hasil import
+func TestImportJobChunking(t *testing.T) {
+	db := setupImportJobDB(t)
+	service := newImportJobService(db)
+
+	started, err := service.StartImportJob(buildImportCSV(450), "obc.csv", true, 1)
+	if err != nil {
+		t.Fatalf("StartImportJob() error = %v", err)
+	}
+	service.Wait()
+
+	job := loadImportJob(t, db, started.ID)
+	if job.Status != models.ImportJobCompleted || job.ProcessedRows != 450 || job.SuccessCount != 450 || job.PartiallyApplied {
+		t.Fatalf("import job = %+v, expected COMPLETED dengan 450 row sukses", job)
+	}
+	if job.POsGenerated != 450 {
+		t.Errorf("pos_generated = %d, expected 450", job.POsGenerated)
+	}
+
+	var batch models.OBCImportBatch
+	if job.BatchID == nil || db.First(&batch, *job.BatchID).Error != nil {
+		t.Fatalf("import job tidak terhubung ke import batch: %+v", job)
+	}
+	if batch.ImportJobID == nil || *batch.ImportJobID != job.ID || batch.SuccessCount != 450 || batch.POsGenerated != 450 {
+		t.Errorf("import batch = %+v, expected ringkasan dari 3 chunk", batch)
+	}
+
+	result, err := service.GetJobResult(job.ID)
+	if err != nil || result.SuccessCount != 450 || result.BatchID != batch.ID {
+		t.Errorf("GetJobResult() = %+v, %v", result, err)
+	}
+}
+
+// TestImportJobChunkFailureMarksPartiallyApplied memverifikasi chunk yang gagal menghentikan job,
+// chunk sebelumnya tetap tersimpan dan job ditandai partially_applied dengan batch untuk rollback
+func TestImportJobChunkFailureMarksPartiallyApplied(t *testing.T) {
+	db := setupImportJobDB(t)
+	service := newImportJobService(db)
+	failBatchSummaryUpdate(t, db, func(call int) error {
+		if call == 2 {
+			return errors.New("koneksi database terputus")
+		}
+		return nil
+	})
+
+	started, err := service.StartImportJob(buildImportCSV(450), "obc.csv", false, 1)
+	if err != nil {
+		t.Fatalf("StartImportJob() error = %v", err)
+	}
+	service.Wait()
+
+	job := loadImportJob(t, db, started.ID)
+	if job.Status != models.ImportJobFailed || !job.PartiallyApplied || job.ProcessedRows != 200 || job.SuccessCount != 200 {
+		t.Fatalf("import job = %+v, expected FAILED partially applied setelah chunk pertama", job)
+	}
+	if job.BatchID == nil || !strings.Contains(job.ErrorMessage, fmt.Sprintf("import batch #%d", *job.BatchID)) {
+		t.Errorf("error message = %q, expected menyebutkan import batch", job.ErrorMessage)
+	}
+
+	var obcCount int64
+	db.Model(&models.OBCMaster{}).Count(&obcCount)
+	if obcCount != 200 {
+		t.Errorf("OBC tersimpan = %d, expected hanya 200 row dari chunk pertama", obcCount)
+	}
+	if _, err := service.GetJobResult(job.ID); !errors.Is(err, services.ErrImportJobFailed) {
+		t.Errorf("GetJobResult() job gagal = %v, expected ErrImportJobFailed", err)
+	}
+}
+
+// TestImportJobShutdown memverifikasi job berhenti di batas chunk saat context worker dibatalkan
+// dan job baru ditolak setelah shutdown
+func TestImportJobShutdown(t *testing.T) {
+	db := setupImportJobDB(t)
+	service := newImportJobService(db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Shutdown terjadi saat chunk pertama sedang diproses
+	failBatchSummaryUpdate(t, db, func(call int) error {
+		if call == 1 {
+			cancel()
+		}
+		return nil
+	})
+	service.StartWorker(ctx)
+
+	started, err := service.StartImportJob(buildImportCSV(450), "obc.csv", false, 1)
+	if err != nil {
+		t.Fatalf("StartImportJob() error = %v", err)
+	}
+	service.Wait()
+
+	job := loadImportJob(t, db, started.ID)
+	if job.Status != models.ImportJobFailed || !job.PartiallyApplied || job.ProcessedRows != 200 ||
+		!strings.Contains(job.ErrorMessage, "shutdown") {
+		t.Errorf("import job = %+v, expected FAILED karena shutdown setelah chunk pertama", job)
+	}
+
+	if _, err := service.StartImportJob(buildImportCSV(1), "obc.csv", false, 1); !errors.Is(err, services.ErrImportJobUnavailable) {
+		t.Errorf("StartImportJob() setelah shutdown = %v, expected ErrImportJobUnavailable", err)
+	}
+}
+
+// TestImportJobFailStaleJobs memverifikasi hanya job yang heartbeat-nya berhenti yang ditandai FAILED,
+// job yang masih diproses instance lain tetap berjalan
+func TestImportJobFailStaleJobs(t *testing.T) {
+	db := setupImportJobDB(t)
+	service := newImportJobService(db)
+
+	stale := time.Now().Add(-10 * time.Minute)
+	fresh := time.Now()
+	jobs := []models.ImportJob{
+		{Status: models.ImportJobRunning, SuccessCount: 200, HeartbeatAt: &stale, CreatedBy: 1},
+		{Status: models.ImportJobRunning, SuccessCount: 200, HeartbeatAt: &fresh, CreatedBy: 1},
+		{Status: models.ImportJobQueued, CreatedBy: 1, CreatedAt: stale, UpdatedAt: stale},
+		{Status: models.ImportJobCompleted, HeartbeatAt: &stale, CreatedBy: 1},
+	}
+	if err := db.Create(&jobs).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := service.FailStaleJobs()
+	if err != nil {
+		t.Fatalf("FailStaleJobs() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("FailStaleJobs() = %d, expected 2 job terputus", count)
+	}
+
+	expected := []struct {
+		status  models.ImportJobStatus
+		partial bool
+	}{
+		{models.ImportJobFailed, true},
+		{models.ImportJobRunning, false},
+		{models.ImportJobFailed, false},
+		{models.ImportJobCompleted, false},
+	}
+	for i, want := range expected {
+		job := loadImportJob(t, db, jobs[i].ID)
+		if job.Status != want.status || job.PartiallyApplied != want.partial {
+			t.Errorf("job %d = status %s partially_applied %v, expected %s %v", i, job.Status, job.PartiallyApplied, want.status, want.partial)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"sirine-go/backend/services"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// TestParseImportReportOptions memverifikasi parsing format dan scope report import
+func TestParseImportReportOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		scope     string
+		expected  services.ImportReportOptions
+		expectErr bool
+	}{
+		{"Default failed only", "xlsx", "", services.ImportReportOptions{Format: services.ImportReportXLSX, FailedOnly: true}, false},
+		{"CSV semua row", "CSV", "all", services.ImportReportOptions{Format: services.ImportReportCSV, FailedOnly: false}, false},
+		{"Format tidak didukung", "pdf", "", services.ImportReportOptions{}, true},
+		{"Scope tidak valid", "csv", "success", services.ImportReportOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := services.ParseImportReportOptions(tt.format, tt.scope)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if opts != tt.expected {
+				t.Errorf("options = %+v, expected %+v", opts, tt.expected)
+			}
+		})
+	}
+}
+
+// TestBuildFailedRowsReport memverifikasi file report berisi row asli beserta kolom error
+func TestBuildFailedRowsReport(t *testing.T) {
+	service := services.NewOBCImportService(nil, nil, nil)
+	content := []byte("No OBC;QTY PESAN\nOBC-1;100\nOBC-2;abc\n;50\n")
+	failedRows := []services.FailedRow{
+		{RowNumber: 3, OBCNumber: "OBC-2", Error: "invalid QTY PESAN"},
+		{RowNumber: 4, OBCNumber: "", Error: "No OBC tidak boleh kosong"},
+	}
+
+	t.Run("CSV hanya row gagal", func(t *testing.T) {
+		opts := services.ImportReportOptions{Format: services.ImportReportCSV, FailedOnly: true}
+		report, err := service.BuildFailedRowsReport(content, "obc_januari.txt", failedRows, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.FileName != "obc_januari_failed_rows.csv" {
+			t.Errorf("FileName = %s", report.FileName)
+		}
+
+		records, err := csv.NewReader(bytes.NewReader(report.Content)).ReadAll()
+		if err != nil {
+			t.Fatalf("report csv tidak valid: %v", err)
+		}
+		expected := [][]string{
+			{"No OBC", "QTY PESAN", services.ImportReportErrorColumn},
+			{"OBC-2", "abc", "invalid QTY PESAN"},
+			{"", "50", "No OBC tidak boleh kosong"},
+		}
+		if !reflect.DeepEqual(records, expected) {
+			t.Errorf("records = %v, expected %v", records, expected)
+		}
+	})
+
+	t.Run("Upload ulang report mengganti kolom error lama", func(t *testing.T) {
+		reupload := []byte("No OBC,QTY PESAN,Import Error\nOBC-2,abc,invalid QTY PESAN\n")
+		opts := services.ImportReportOptions{Format: services.ImportReportCSV, FailedOnly: false}
+		report, err := service.BuildFailedRowsReport(reupload, "obc_januari_failed_rows.csv", nil, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records, err := csv.NewReader(bytes.NewReader(report.Content)).ReadAll()
+		if err != nil {
+			t.Fatalf("report csv tidak valid: %v", err)
+		}
+		expected := [][]string{
+			{"No OBC", "QTY PESAN", services.ImportReportErrorColumn},
+			{"OBC-2", "abc", ""},
+		}
+		if !reflect.DeepEqual(records, expected) {
+			t.Errorf("records = %v, expected %v", records, expected)
+		}
+	})
+
+	t.Run("XLSX semua row", func(t *testing.T) {
+		opts := services.ImportReportOptions{Format: services.ImportReportXLSX, FailedOnly: false}
+		report, err := service.BuildFailedRowsReport(content, "obc_januari.txt", failedRows, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		xlsxFile, err := excelize.OpenReader(bytes.NewReader(report.Content))
+		if err != nil {
+			t.Fatalf("report xlsx tidak valid: %v", err)
+		}
+		defer xlsxFile.Close()
+
+		rows, err := xlsxFile.GetRows(xlsxFile.GetSheetName(0))
+		if err != nil {
+			t.Fatalf("gagal membaca report: %v", err)
+		}
+		if len(rows) != 4 {
+			t.Fatalf("jumlah rows = %d, expected 4", len(rows))
+		}
+		if len(rows[1]) > 2 && rows[1][2] != "" {
+			t.Errorf("row sukses seharusnya tanpa error, got %q", rows[1][2])
+		}
+		if rows[2][2] != "invalid QTY PESAN" {
+			t.Errorf("error row 3 = %q", rows[2][2])
+		}
+	})
+}
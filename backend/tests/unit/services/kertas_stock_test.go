@@ -0,0 +1,157 @@
+package services_test
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"sirine-go/backend/tests/testutil"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// setupMaterialPrepKertasDB menyiapkan tabel material prep beserta stock ledger kertas
+// dan tabel yang ditulis saat finalize (SAP outbox, notifikasi, lampiran)
+func setupMaterialPrepKertasDB(t *testing.T) *gorm.DB {
+	return testutil.SetupModelDB(t,
+		&models.User{}, &models.OBCMaster{}, &models.ProductionOrder{}, &models.POStageTracking{}, &models.ActivityLog{},
+		&models.KhazwalMaterialPreparation{}, &models.KhazwalTintaItem{}, &models.Attachment{},
+		&models.KertasStock{}, &models.KertasStockMovement{}, &models.KertasReservation{},
+		&models.TintaStock{}, &models.TintaStockMovement{}, &models.SAPOutboxEntry{},
+		&models.Notification{}, &models.NotificationDelivery{})
+}
+
+// seedKertasMaterialPrep menambahkan PO dengan jenis kertas tertentu yang menunggu material prep,
+// kebutuhan kertas blanko 1000 lembar
+func seedKertasMaterialPrep(t *testing.T, db *gorm.DB, paperType string) models.KhazwalMaterialPreparation {
+	obc := models.OBCMaster{OBCNumber: "OBC-KERTAS", QuantityOrdered: 40000, Material: paperType}
+	if err := db.Create(&obc).Error; err != nil {
+		t.Fatal(err)
+	}
+	po := seedProductionOrder(t, db, obc.ID, 2001, 40000, models.StatusWaitingMaterialPrep, models.PriorityNormal)
+	prep := models.KhazwalMaterialPreparation{
+		ProductionOrderID: po.ID, SAPPlatCode: "PLAT-001", KertasBlankoQuantity: 1000, Status: models.MaterialPrepPending,
+	}
+	if err := db.Create(&prep).Error; err != nil {
+		t.Fatal(err)
+	}
+	return prep
+}
+
+// seedKertasStock mendaftarkan jenis kertas di stock ledger dengan saldo on hand tertentu
+func seedKertasStock(t *testing.T, db *gorm.DB, paperType string, onHand int64) models.KertasStock {
+	stock := models.KertasStock{PaperType: paperType, OnHand: onHand}
+	if err := db.Create(&stock).Error; err != nil {
+		t.Fatal(err)
+	}
+	return stock
+}
+
+// completeMaterialPrepSteps mencatat plat, kertas blanko actual, dan tinta actual
+// seperti yang dilakukan operator sebelum finalize
+func completeMaterialPrepSteps(t *testing.T, db *gorm.DB, prepID uint64, kertasActual int) {
+	now := time.Now()
+	if err := db.Model(&models.KhazwalMaterialPreparation{}).Where("id = ?", prepID).Updates(map[string]interface{}{
+		"status":               models.MaterialPrepInProgress,
+		"started_at":           now,
+		"plat_retrieved_at":    now,
+		"kertas_blanko_actual": kertasActual,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	actualKg := 2.0
+	if err := db.Create(&models.KhazwalTintaItem{MaterialPrepID: prepID, ColorCode: "cyan", RequiredKg: 2, ActualKg: &actualKg}).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestStartMaterialPrepUnregisteredPaperType memverifikasi material prep tidak bisa dimulai
+// jika jenis kertas PO belum terdaftar di stock ledger
+func TestStartMaterialPrepUnregisteredPaperType(t *testing.T) {
+	db := setupMaterialPrepKertasDB(t)
+	prep := seedKertasMaterialPrep(t, db, "KERTAS-X")
+
+	_, err := services.NewKhazwalService(db, false).StartMaterialPrep(prep.ProductionOrderID, 3)
+	if !errors.Is(err, services.ErrKertasPaperTypeNotFound) {
+		t.Fatalf("StartMaterialPrep() = %v, expected ErrKertasPaperTypeNotFound", err)
+	}
+
+	var po models.ProductionOrder
+	db.First(&po, prep.ProductionOrderID)
+	if po.CurrentStatus != models.StatusWaitingMaterialPrep {
+		t.Errorf("status PO = %s, expected tetap %s", po.CurrentStatus, models.StatusWaitingMaterialPrep)
+	}
+}
+
+// TestFinalizeMaterialPrepConsumesReservation memverifikasi reservasi dari start material prep
+// dilepas dan on hand dikurangi sejumlah actual saat finalize
+func TestFinalizeMaterialPrepConsumesReservation(t *testing.T) {
+	db := setupMaterialPrepKertasDB(t)
+	prep := seedKertasMaterialPrep(t, db, "KERTAS-A")
+	stock := seedKertasStock(t, db, "KERTAS-A", 5000)
+	service := services.NewKhazwalService(db, false)
+
+	if _, err := service.StartMaterialPrep(prep.ProductionOrderID, 3); err != nil {
+		t.Fatalf("StartMaterialPrep() error = %v", err)
+	}
+	db.First(&stock, stock.ID)
+	if stock.Reserved != 1000 {
+		t.Fatalf("reserved = %d, expected 1000", stock.Reserved)
+	}
+
+	completeMaterialPrepSteps(t, db, prep.ID, 1020)
+	if _, err := service.FinalizeMaterialPrep(prep.ID, nil, "", 3); err != nil {
+		t.Fatalf("FinalizeMaterialPrep() error = %v", err)
+	}
+
+	db.First(&stock, stock.ID)
+	if stock.OnHand != 3980 || stock.Reserved != 0 {
+		t.Errorf("stok = on hand %d reserved %d, expected 3980 dan 0", stock.OnHand, stock.Reserved)
+	}
+	var reservation models.KertasReservation
+	db.Where("production_order_id = ?", prep.ProductionOrderID).First(&reservation)
+	if reservation.Status != models.KertasReservationConsumed || reservation.ConsumedQuantity != 1020 {
+		t.Errorf("reservasi = %+v, expected CONSUMED 1020", reservation)
+	}
+}
+
+// TestFinalizeMaterialPrepWithoutReservation memverifikasi material prep yang dimulai sebelum
+// jenis kertasnya terdaftar tetap memposting pemakaian ke stok jenis kertas PO saat finalize
+func TestFinalizeMaterialPrepWithoutReservation(t *testing.T) {
+	db := setupMaterialPrepKertasDB(t)
+	prep := seedKertasMaterialPrep(t, db, "KERTAS-B")
+	if err := db.Model(&models.ProductionOrder{}).Where("id = ?", prep.ProductionOrderID).
+		Updates(map[string]interface{}{
+			"current_stage":  models.StageKhazwalMaterialPrep,
+			"current_status": models.StatusMaterialPrepInProgress,
+		}).Error; err != nil {
+		t.Fatal(err)
+	}
+	completeMaterialPrepSteps(t, db, prep.ID, 1000)
+	service := services.NewKhazwalService(db, false)
+
+	if _, err := service.FinalizeMaterialPrep(prep.ID, nil, "", 3); !errors.Is(err, services.ErrKertasPaperTypeNotFound) {
+		t.Fatalf("FinalizeMaterialPrep() jenis kertas belum terdaftar = %v, expected ErrKertasPaperTypeNotFound", err)
+	}
+
+	stock := seedKertasStock(t, db, "KERTAS-B", 5000)
+	if _, err := service.FinalizeMaterialPrep(prep.ID, nil, "", 3); err != nil {
+		t.Fatalf("FinalizeMaterialPrep() error = %v", err)
+	}
+
+	db.First(&stock, stock.ID)
+	if stock.OnHand != 4000 || stock.Reserved != 0 {
+		t.Errorf("stok = on hand %d reserved %d, expected 4000 dan 0", stock.OnHand, stock.Reserved)
+	}
+	var movements []models.KertasStockMovement
+	db.Where("kertas_stock_id = ?", stock.ID).Find(&movements)
+	if len(movements) != 1 || movements[0].Type != models.KertasMovementConsume || movements[0].Quantity != 1000 {
+		t.Errorf("mutasi = %+v, expected satu CONSUME 1000", movements)
+	}
+	var reservation models.KertasReservation
+	db.Where("production_order_id = ?", prep.ProductionOrderID).First(&reservation)
+	if reservation.Status != models.KertasReservationConsumed || reservation.ReturnableQuantity() != 1000 {
+		t.Errorf("reservasi = %+v, expected CONSUMED dan bisa diretur 1000", reservation)
+	}
+}
@@ -0,0 +1,70 @@
+package services_test
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"sirine-go/backend/tests/testutil"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// seedPlatRetrievalPrep menambahkan PO yang sedang material prep dengan plat PLAT-001 di OBC Master
+func seedPlatRetrievalPrep(t *testing.T) (*gorm.DB, models.KhazwalMaterialPreparation) {
+	db := testutil.SetupModelDB(t,
+		&models.OBCMaster{}, &models.ProductionOrder{}, &models.POStageTracking{},
+		&models.KhazwalMaterialPreparation{}, &models.Plat{}, &models.PlatCheckout{})
+
+	obc := models.OBCMaster{OBCNumber: "OBC-PLAT", QuantityOrdered: 40000, PlatNumber: "PLAT-001"}
+	if err := db.Create(&obc).Error; err != nil {
+		t.Fatal(err)
+	}
+	po := seedProductionOrder(t, db, obc.ID, 1001, 40000, models.StatusMaterialPrepInProgress, models.PriorityNormal)
+	prep := models.KhazwalMaterialPreparation{ProductionOrderID: po.ID, Status: models.MaterialPrepInProgress}
+	if err := db.Create(&prep).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db, prep
+}
+
+// TestConfirmPlatRetrievalUnregistered memverifikasi kode plat yang belum terdaftar di registry ditolak,
+// kecuali fallback legacy diaktifkan
+func TestConfirmPlatRetrievalUnregistered(t *testing.T) {
+	db, prep := seedPlatRetrievalPrep(t)
+
+	err := services.NewKhazwalService(db, false).ConfirmPlatRetrieval(prep.ID, "PLAT-001", 3)
+	if !errors.Is(err, services.ErrPlatNotRegistered) {
+		t.Fatalf("ConfirmPlatRetrieval() = %v, expected ErrPlatNotRegistered", err)
+	}
+	var stored models.KhazwalMaterialPreparation
+	db.First(&stored, prep.ID)
+	if stored.PlatRetrievedAt != nil {
+		t.Errorf("plat_retrieved_at = %v, expected kosong setelah ditolak", stored.PlatRetrievedAt)
+	}
+
+	if err := services.NewKhazwalService(db, true).ConfirmPlatRetrieval(prep.ID, "PLAT-001", 3); err != nil {
+		t.Fatalf("ConfirmPlatRetrieval() dengan fallback legacy error = %v", err)
+	}
+	db.First(&stored, prep.ID)
+	if stored.PlatRetrievedAt == nil || !stored.PlatMatch {
+		t.Errorf("material prep = %+v, expected plat tercatat diambil", stored)
+	}
+}
+
+// TestConfirmPlatRetrievalRegistered memverifikasi plat yang terdaftar di-checkout ke PO
+func TestConfirmPlatRetrievalRegistered(t *testing.T) {
+	db, prep := seedPlatRetrievalPrep(t)
+	if err := db.Create(&models.Plat{Code: "PLAT-001", Status: models.PlatAvailable}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := services.NewKhazwalService(db, false).ConfirmPlatRetrieval(prep.ID, "PLAT-001", 3); err != nil {
+		t.Fatalf("ConfirmPlatRetrieval() error = %v", err)
+	}
+	var plat models.Plat
+	db.Where("code = ?", "PLAT-001").First(&plat)
+	if plat.Status != models.PlatInUse || plat.CurrentPOID == nil || *plat.CurrentPOID != prep.ProductionOrderID {
+		t.Errorf("plat = %+v, expected IN_USE untuk PO %d", plat, prep.ProductionOrderID)
+	}
+}
@@ -0,0 +1,371 @@
+package services_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// smtpCatcher merupakan SMTP server minimal untuk test, setara MailHog/Mailpit lokal
+type smtpCatcher struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []string
+}
+
+// newSMTPCatcher menjalankan SMTP catcher di port acak localhost
+func newSMTPCatcher(t *testing.T) *smtpCatcher {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	catcher := &smtpCatcher{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go catcher.serve(conn)
+		}
+	}()
+	return catcher
+}
+
+// serve melayani satu sesi SMTP dan menyimpan isi DATA
+func (c *smtpCatcher) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 catcher ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 catcher")
+		case command == "DATA":
+			reply("354 end with .")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			c.mu.Lock()
+			c.messages = append(c.messages, data.String())
+			c.mu.Unlock()
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// port mengembalikan port SMTP catcher
+func (c *smtpCatcher) port() int {
+	return c.listener.Addr().(*net.TCPAddr).Port
+}
+
+// received mengembalikan salinan email yang sudah diterima
+func (c *smtpCatcher) received() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.messages...)
+}
+
+// setupDeliveryDB menyiapkan database notifikasi dengan tabel delivery queue dan callback-nya
+func setupDeliveryDB(t *testing.T) *gorm.DB {
+	db := setupNotificationDB(t)
+	if err := db.AutoMigrate(&models.NotificationDelivery{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := services.RegisterNotificationDeliveryCallbacks(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seedDeliveryUser menambahkan manager cetak aktif dengan email dan preferensi channel
+func seedDeliveryUser(t *testing.T, db *gorm.DB, email string, preference models.NotificationPreference) uint64 {
+	userID := seedRoutingUser(t, db, models.RoleManager, models.DeptCetak, models.StatusActive)
+	if err := db.Table("users").Where("id = ?", userID).Updates(map[string]interface{}{"email": email, "full_name": "Manager Cetak"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	preference.UserID = userID
+	if err := db.Create(&preference).Error; err != nil {
+		t.Fatal(err)
+	}
+	return userID
+}
+
+// deliveries mengambil semua pengiriman urut ID
+func deliveries(t *testing.T, db *gorm.DB) []models.NotificationDelivery {
+	var items []models.NotificationDelivery
+	if err := db.Order("id").Find(&items).Error; err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+// TestNotificationDeliveryEnqueue memverifikasi hanya notifikasi kritis yang masuk delivery queue
+// dan webhook yang sama hanya menerima satu pengiriman per notifikasi
+func TestNotificationDeliveryEnqueue(t *testing.T) {
+	db := setupDeliveryDB(t)
+	hook := "http://chat.local/hooks/cetak"
+	first := seedDeliveryUser(t, db, "manager1@sirine.local", models.NotificationPreference{EmailEnabled: true, WebhookEnabled: true, WebhookURL: hook})
+	seedDeliveryUser(t, db, "manager2@sirine.local", models.NotificationPreference{WebhookEnabled: true, WebhookURL: hook})
+	seedDeliveryUser(t, db, "manager3@sirine.local", models.NotificationPreference{})
+
+	audience := services.NotificationAudience{Roles: []models.UserRole{models.RoleManager}, Department: models.DeptCetak}
+	if _, err := services.NotifyAudiencesInTx(db, services.NotificationContent{Title: "Info Cetak", Message: "PO mulai dicetak"}, audience); err != nil {
+		t.Fatal(err)
+	}
+	if items := deliveries(t, db); len(items) != 0 {
+		t.Fatalf("notifikasi INFO tidak boleh dikirim keluar, got %+v", items)
+	}
+
+	if _, err := services.NotifyAudiencesInTx(db, services.NotificationContent{Title: "Reject Tinggi", Message: "Reject 7%", Type: models.NotificationWarning}, audience); err != nil {
+		t.Fatal(err)
+	}
+	items := deliveries(t, db)
+	if len(items) != 2 {
+		t.Fatalf("expected 1 email dan 1 webhook, got %+v", items)
+	}
+	if items[0].Channel != models.ChannelEmail || items[0].UserID != first || items[0].Recipient != "manager1@sirine.local" {
+		t.Errorf("email delivery = %+v", items[0])
+	}
+	if items[1].Channel != models.ChannelWebhook || items[1].Recipient != hook || !strings.Contains(items[1].Body, "Reject Tinggi") {
+		t.Errorf("webhook delivery = %+v", items[1])
+	}
+}
+
+// TestNotificationDeliveryProcessDue memverifikasi pengiriman ke SMTP catcher dan HTTP sink,
+// termasuk retry dengan backoff sampai FAILED saat webhook terus menolak
+func TestNotificationDeliveryProcessDue(t *testing.T) {
+	db := setupDeliveryDB(t)
+	catcher := newSMTPCatcher(t)
+
+	var mu sync.Mutex
+	var sinkKeys []string
+	sinkStatus := http.StatusOK
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		sinkKeys = append(sinkKeys, r.Header.Get("X-Sirine-Delivery"))
+		if !strings.HasPrefix(r.Header.Get("X-Sirine-Signature"), "sha256=") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(sinkStatus)
+	}))
+	defer sink.Close()
+
+	seedDeliveryUser(t, db, "manager@sirine.local", models.NotificationPreference{EmailEnabled: true, WebhookEnabled: true, WebhookURL: sink.URL})
+
+	service := services.NewNotificationDeliveryService(db,
+		services.NewSMTPEmailSender("127.0.0.1", catcher.port(), "", "", "noreply@sirine.local", 5*time.Second),
+		services.NewHTTPWebhookSender("secret", 5*time.Second, services.NewWebhookGuard([]string{"127.0.0.1"}, true)),
+		services.NotificationDeliverySettings{DigestHour: 7})
+
+	audience := services.NotificationAudience{Roles: []models.UserRole{models.RoleManager}}
+	if _, err := services.NotifyAudiencesInTx(db, services.NotificationContent{Title: "Waste Berlebih", Message: "Waste 3%", Type: models.NotificationWarning}, audience); err != nil {
+		t.Fatal(err)
+	}
+
+	sent, err := service.ProcessDue(context.Background())
+	if err != nil || sent != 2 {
+		t.Fatalf("ProcessDue() = %d, %v, expected 2 terkirim", sent, err)
+	}
+	messages := catcher.received()
+	if len(messages) != 1 || !strings.Contains(messages[0], "To: manager@sirine.local") || !strings.Contains(messages[0], "Waste 3%") {
+		t.Fatalf("email diterima = %q", messages)
+	}
+	if len(sinkKeys) != 1 || !strings.HasPrefix(sinkKeys[0], "ALERT-WEBHOOK-") {
+		t.Fatalf("webhook diterima = %v", sinkKeys)
+	}
+	for _, item := range deliveries(t, db) {
+		if item.Status != models.DeliverySent || item.Attempts != 1 || item.SentAt == nil {
+			t.Errorf("delivery %d = %+v, expected SENT", item.ID, item)
+		}
+	}
+
+	mu.Lock()
+	sinkStatus = http.StatusInternalServerError
+	mu.Unlock()
+	if _, err := services.NotifyAudiencesInTx(db, services.NotificationContent{Title: "Tinta Menipis", Message: "Stok tinta merah di bawah minimum", Type: models.NotificationWarning}, audience); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&models.NotificationDelivery{}).Where("channel = ?", models.ChannelWebhook).
+		Update("max_attempts", 2).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.ProcessDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var webhook models.NotificationDelivery
+	db.Where("channel = ? AND status <> ?", models.ChannelWebhook, models.DeliverySent).First(&webhook)
+	if webhook.Status != models.DeliveryPending || webhook.Attempts != 1 || !webhook.NextAttemptAt.After(time.Now()) || !strings.Contains(webhook.LastError, "500") {
+		t.Fatalf("webhook setelah gagal pertama = %+v, expected PENDING dengan backoff", webhook)
+	}
+
+	db.Model(&webhook).Update("next_attempt_at", time.Now().Add(-time.Second))
+	if _, err := service.ProcessDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	db.First(&webhook, webhook.ID)
+	if webhook.Status != models.DeliveryFailed || webhook.Attempts != 2 {
+		t.Fatalf("webhook setelah batas percobaan = %+v, expected FAILED", webhook)
+	}
+
+	retried, err := service.RetryDelivery(webhook.ID)
+	if err != nil || retried.Status != models.DeliveryPending {
+		t.Fatalf("RetryDelivery() = %+v, %v", retried, err)
+	}
+}
+
+// TestWebhookGuard memverifikasi webhook hanya boleh ke host di allowlist, alamat internal ditolak
+// setelah DNS di-resolve, dan redirect ke host lain tidak diikuti
+func TestWebhookGuard(t *testing.T) {
+	ctx := context.Background()
+	guard := services.NewWebhookGuard([]string{"chat.example.com", "*.hooks.example.com", "127.0.0.1", "localhost", "169.254.169.254"}, false)
+
+	tests := []struct {
+		url      string
+		expected error
+	}{
+		{"ftp://chat.example.com/hook", services.ErrWebhookURLInvalid},
+		{"https://evil.example.net/hook", services.ErrWebhookHostNotAllowed},
+		{"https://hooks.example.com/hook", services.ErrWebhookHostNotAllowed},
+		{"https://chat.example.com.evil.net/hook", services.ErrWebhookHostNotAllowed},
+		{"http://127.0.0.1:9000/hook", services.ErrWebhookAddressBlocked},
+		{"http://localhost:9000/hook", services.ErrWebhookAddressBlocked},
+		{"http://169.254.169.254/latest/meta-data", services.ErrWebhookAddressBlocked},
+	}
+	for _, tt := range tests {
+		if err := guard.ValidateURL(ctx, tt.url); !errors.Is(err, tt.expected) {
+			t.Errorf("ValidateURL(%q) = %v, expected %v", tt.url, err, tt.expected)
+		}
+	}
+
+	var hits int
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, strings.Replace("http://"+r.Host+"/hook", "127.0.0.1", "localhost", 1), http.StatusTemporaryRedirect)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer sink.Close()
+
+	blocked := services.NewHTTPWebhookSender("", 5*time.Second, guard)
+	if err := blocked.Post(ctx, sink.URL+"/hook", "KEY-1", []byte(`{}`)); !errors.Is(err, services.ErrWebhookAddressBlocked) {
+		t.Errorf("Post ke loopback = %v, expected ErrWebhookAddressBlocked", err)
+	}
+	if err := blocked.Post(ctx, "https://evil.example.net/hook", "KEY-2", []byte(`{}`)); !errors.Is(err, services.ErrWebhookHostNotAllowed) {
+		t.Errorf("Post ke host di luar allowlist = %v, expected ErrWebhookHostNotAllowed", err)
+	}
+	if hits != 0 {
+		t.Fatalf("sink tidak boleh menerima request, got %d", hits)
+	}
+
+	local := services.NewHTTPWebhookSender("", 5*time.Second, services.NewWebhookGuard([]string{"127.0.0.1", "localhost"}, true))
+	if err := local.Post(ctx, sink.URL+"/redirect", "KEY-3", []byte(`{}`)); !errors.Is(err, services.ErrWebhookRedirectBlocked) {
+		t.Errorf("Post dengan redirect ke host lain = %v, expected ErrWebhookRedirectBlocked", err)
+	}
+	if hits != 1 {
+		t.Errorf("redirect tidak boleh diikuti, sink menerima %d request", hits)
+	}
+}
+
+// TestNotificationPreferenceWebhookAllowlist memverifikasi webhook_url di luar allowlist ditolak saat disimpan
+func TestNotificationPreferenceWebhookAllowlist(t *testing.T) {
+	db := setupDeliveryDB(t)
+	userID := seedDeliveryUser(t, db, "manager@sirine.local", models.NotificationPreference{})
+	service := services.NewNotificationDeliveryService(db, nil, nil, services.NotificationDeliverySettings{
+		WebhookGuard: services.NewWebhookGuard([]string{"10.0.0.5"}, false),
+	})
+
+	for _, tt := range []struct {
+		url      string
+		expected error
+	}{
+		{"https://chat.example.com/hook", services.ErrWebhookHostNotAllowed},
+		{"http://10.0.0.5/hook", services.ErrWebhookAddressBlocked},
+	} {
+		_, err := service.UpdatePreference(userID, services.UpdateNotificationPreferenceRequest{WebhookEnabled: true, WebhookURL: tt.url})
+		if !errors.Is(err, tt.expected) {
+			t.Errorf("UpdatePreference(%q) = %v, expected %v", tt.url, err, tt.expected)
+		}
+	}
+}
+
+// TestNotificationDailyDigest memverifikasi digest hanya dibuat sekali per hari setelah jam digest
+// dan hanya berisi notifikasi belum dibaca
+func TestNotificationDailyDigest(t *testing.T) {
+	db := setupDeliveryDB(t)
+	userID := seedDeliveryUser(t, db, "ppic@sirine.local", models.NotificationPreference{DigestEnabled: true})
+	service := services.NewNotificationDeliveryService(db, nil, nil, services.NotificationDeliverySettings{DigestHour: 7, AppURL: "http://sirine.local"})
+
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.Local)
+	for i, notification := range []models.Notification{
+		{UserID: userID, Title: "Counting Overdue", Message: "PO 1001 belum dihitung", Type: models.NotificationInfo, CreatedAt: now.Add(-2 * time.Hour)},
+		{UserID: userID, Title: "Sudah Dibaca", Message: "abaikan", Type: models.NotificationInfo, IsRead: true, CreatedAt: now.Add(-time.Hour)},
+	} {
+		if err := db.Create(&notification).Error; err != nil {
+			t.Fatalf("notifikasi %d: %v", i, err)
+		}
+	}
+
+	if count, err := service.EnqueueDailyDigests(now.Add(-2 * time.Hour)); err != nil || count != 0 {
+		t.Fatalf("digest sebelum jam digest = %d, %v", count, err)
+	}
+	if count, err := service.EnqueueDailyDigests(now); err != nil || count != 1 {
+		t.Fatalf("EnqueueDailyDigests() = %d, %v, expected 1", count, err)
+	}
+	if count, err := service.EnqueueDailyDigests(now.Add(time.Hour)); err != nil || count != 0 {
+		t.Fatalf("digest kedua di hari yang sama = %d, %v, expected 0", count, err)
+	}
+
+	items := deliveries(t, db)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 digest, got %d", len(items))
+	}
+	digest := items[0]
+	expectedKey := "DIGEST-" + strconv.FormatUint(userID, 10) + "-20260302"
+	if digest.Kind != models.DeliveryKindDigest || digest.IdempotencyKey != expectedKey || digest.Recipient != "ppic@sirine.local" {
+		t.Errorf("digest = %+v", digest)
+	}
+	if !strings.Contains(digest.Body, "Counting Overdue") || strings.Contains(digest.Body, "Sudah Dibaca") || !strings.Contains(digest.Body, "http://sirine.local/notifications") {
+		t.Errorf("isi digest = %q", digest.Body)
+	}
+}
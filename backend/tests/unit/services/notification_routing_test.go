@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupNotificationDB membuat sqlite in-memory dengan tabel users dan notifications,
+// tabel dibuat manual karena kolom enum kedua model tidak didukung sqlite
+func setupNotificationDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Gagal membuat test database: %v", err)
+	}
+	for _, ddl := range []string{
+		`CREATE TABLE users (
+			id integer PRIMARY KEY AUTOINCREMENT, full_name text DEFAULT '', email text DEFAULT '',
+			role text NOT NULL, department text NOT NULL, status text NOT NULL, deleted_at datetime)`,
+		`CREATE TABLE notifications (
+			id integer PRIMARY KEY AUTOINCREMENT, user_id integer NOT NULL, title text NOT NULL, message text NOT NULL,
+			type text DEFAULT 'INFO', category text NOT NULL DEFAULT 'SYSTEM', po_id integer, stage text,
+			is_read numeric DEFAULT false, read_at datetime, expires_at datetime, created_at datetime)`,
+	} {
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.AutoMigrate(&models.NotificationPreference{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seedRoutingUser menambahkan user dengan role, departemen, dan status tertentu
+func seedRoutingUser(t *testing.T, db *gorm.DB, role models.UserRole, department models.Department, status models.UserStatus) uint64 {
+	result := map[string]interface{}{"role": role, "department": department, "status": status}
+	if err := db.Table("users").Create(result).Error; err != nil {
+		t.Fatal(err)
+	}
+	var id uint64
+	db.Table("users").Select("MAX(id)").Scan(&id)
+	return id
+}
+
+// recipients mengambil user_id penerima notifikasi dengan judul tertentu
+func recipients(db *gorm.DB, title string) []uint64 {
+	var ids []uint64
+	db.Model(&models.Notification{}).Where("title = ?", title).Order("user_id").Pluck("user_id", &ids)
+	return ids
+}
+
+// TestNotifyAudiencesInTx memverifikasi routing notifikasi berdasarkan role, departemen, dan status user
+func TestNotifyAudiencesInTx(t *testing.T) {
+	db := setupNotificationDB(t)
+
+	verifikator := seedRoutingUser(t, db, models.RoleVerifikator, models.DeptVerifikasi, models.StatusActive)
+	qc := seedRoutingUser(t, db, models.RoleQCInspector, models.DeptVerifikasi, models.StatusActive)
+	seedRoutingUser(t, db, models.RoleVerifikator, models.DeptVerifikasi, models.StatusInactive)
+	seedRoutingUser(t, db, models.RoleQCInspector, models.DeptKhazwal, models.StatusActive)
+	khazwalManager := seedRoutingUser(t, db, models.RoleManager, models.DeptKhazwal, models.StatusActive)
+	seedRoutingUser(t, db, models.RoleManager, models.DeptCetak, models.StatusActive)
+	ppic := seedRoutingUser(t, db, models.RolePPIC, models.DeptPPIC, models.StatusActive)
+
+	if err := services.NotifyHandoverInTx(db, 42, models.StageVerifikasi, "Siap Verifikasi", "PO siap diverifikasi"); err != nil {
+		t.Fatalf("NotifyHandoverInTx() error = %v", err)
+	}
+	if got := recipients(db, "Siap Verifikasi"); len(got) != 2 || got[0] != verifikator || got[1] != qc {
+		t.Errorf("penerima serah terima = %v, expected [%d %d]", got, verifikator, qc)
+	}
+
+	if err := services.NotifyEscalationInTx(db, 42, models.StageKhazwalCutting, "Waste Tinggi", "Waste 4%"); err != nil {
+		t.Fatalf("NotifyEscalationInTx() error = %v", err)
+	}
+	if got := recipients(db, "Waste Tinggi"); len(got) != 2 || got[0] != khazwalManager || got[1] != ppic {
+		t.Errorf("penerima eskalasi = %v, expected [%d %d]", got, khazwalManager, ppic)
+	}
+
+	// User yang cocok dengan beberapa audience hanya menerima satu notifikasi
+	count, err := services.NotifyAudiencesInTx(db, services.NotificationContent{Title: "Broadcast", Message: "Info"},
+		services.NotificationAudience{Roles: []models.UserRole{models.RolePPIC}},
+		services.NotificationAudience{Roles: []models.UserRole{models.RolePPIC, models.RoleManager}, Department: models.DeptPPIC},
+	)
+	if err != nil || count != 1 {
+		t.Errorf("count = %d, err = %v, expected 1", count, err)
+	}
+
+	if _, err := services.NotifyAudiencesInTx(db, services.NotificationContent{Title: "Kosong", Message: "Info"}, services.NotificationAudience{}); !errors.Is(err, services.ErrNotificationAudienceEmpty) {
+		t.Errorf("expected ErrNotificationAudienceEmpty, got %v", err)
+	}
+}
+
+// TestNotifyAudiencesCategoryAndMute memverifikasi kategori, link PO, masa berlaku,
+// dan user yang me-mute kategori tidak menerima notifikasi kecuali kategori ALERT
+func TestNotifyAudiencesCategoryAndMute(t *testing.T) {
+	db := setupNotificationDB(t)
+
+	active := seedRoutingUser(t, db, models.RoleManager, models.DeptKhazwal, models.StatusActive)
+	muted := seedRoutingUser(t, db, models.RoleManager, models.DeptKhazwal, models.StatusActive)
+	if err := db.Create(&models.NotificationPreference{
+		UserID:          muted,
+		MutedCategories: []models.NotificationCategory{models.NotificationCategoryHandover, models.NotificationCategorySystem},
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	audience := services.NotificationAudience{Roles: []models.UserRole{models.RoleManager}}
+
+	count, err := services.NotifyAudiencesInTx(db, services.NotificationContent{
+		Title:    "Siap Dipotong",
+		Message:  "PO siap dipotong",
+		Category: models.NotificationCategoryHandover,
+		POID:     42,
+		Stage:    models.StageKhazwalCutting,
+	}, audience)
+	if err != nil || count != 1 {
+		t.Fatalf("count = %d, err = %v, expected 1", count, err)
+	}
+	if got := recipients(db, "Siap Dipotong"); len(got) != 1 || got[0] != active {
+		t.Errorf("penerima handover = %v, expected [%d]", got, active)
+	}
+
+	var handover models.Notification
+	db.Where("title = ?", "Siap Dipotong").First(&handover)
+	if handover.Category != models.NotificationCategoryHandover || handover.POID == nil || *handover.POID != 42 || handover.Stage != models.StageKhazwalCutting {
+		t.Errorf("notifikasi handover = %+v", handover)
+	}
+	if handover.ExpiresAt == nil || handover.ExpiresAt.Sub(handover.CreatedAt) < 6*24*time.Hour {
+		t.Errorf("expires_at = %v, expected sekitar 7 hari", handover.ExpiresAt)
+	}
+
+	if err := services.NotifyEscalationInTx(db, 42, models.StageKhazwalCutting, "Waste Tinggi", "Waste 4%"); err != nil {
+		t.Fatal(err)
+	}
+	if got := recipients(db, "Waste Tinggi"); len(got) != 2 {
+		t.Errorf("alert harus diterima semua manager, got %v", got)
+	}
+
+	if _, err := services.NotifyAudiencesInTx(db, services.NotificationContent{Title: "Pencapaian", Message: "Badge baru", Type: models.NotificationAchievement}, audience); err != nil {
+		t.Fatal(err)
+	}
+	var achievement models.Notification
+	db.Where("title = ?", "Pencapaian").First(&achievement)
+	if achievement.Category != models.NotificationCategoryAchievement || achievement.ExpiresAt != nil {
+		t.Errorf("notifikasi achievement = %+v, expected kategori ACHIEVEMENT tanpa masa berlaku", achievement)
+	}
+}
+
+// TestNotificationCleanup memverifikasi notifikasi kedaluwarsa disembunyikan dan dihapus,
+// notifikasi dibaca yang melewati retention dihapus, dan notifikasi belum dibaca tetap ada
+func TestNotificationCleanup(t *testing.T) {
+	db := setupNotificationDB(t)
+	service := services.NewNotificationService(db)
+
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	oldRead := now.Add(-40 * 24 * time.Hour)
+	recentRead := now.Add(-24 * time.Hour)
+	for _, notification := range []models.Notification{
+		{UserID: 1, Title: "Kedaluwarsa", Message: "-", Category: models.NotificationCategoryHandover, ExpiresAt: &past},
+		{UserID: 1, Title: "Dibaca Lama", Message: "-", Category: models.NotificationCategoryAchievement, IsRead: true, ReadAt: &oldRead},
+		{UserID: 1, Title: "Dibaca Baru", Message: "-", Category: models.NotificationCategorySystem, IsRead: true, ReadAt: &recentRead},
+		{UserID: 1, Title: "Belum Dibaca", Message: "-", Category: models.NotificationCategoryAlert},
+	} {
+		if err := db.Create(&notification).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	visible, err := service.GetUserNotifications(1, false, "")
+	if err != nil || len(visible) != 3 {
+		t.Fatalf("GetUserNotifications() = %d item, %v, expected 3 (tanpa yang kedaluwarsa)", len(visible), err)
+	}
+	if alerts, _ := service.GetUserNotifications(1, false, models.NotificationCategoryAlert); len(alerts) != 1 || alerts[0].Title != "Belum Dibaca" {
+		t.Errorf("filter kategori ALERT = %+v", alerts)
+	}
+
+	result, err := service.CleanupNotifications(now, 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DeletedExpired != 1 || result.DeletedRead != 1 {
+		t.Errorf("CleanupNotifications() = %+v, expected 1 kedaluwarsa dan 1 dibaca", result)
+	}
+
+	var titles []string
+	db.Model(&models.Notification{}).Order("id").Pluck("title", &titles)
+	if len(titles) != 2 || titles[0] != "Dibaca Baru" || titles[1] != "Belum Dibaca" {
+		t.Errorf("notifikasi tersisa = %v", titles)
+	}
+}
@@ -0,0 +1,67 @@
+package services_test
+
+import (
+	"errors"
+	"sirine-go/backend/services"
+	"testing"
+)
+
+// TestDetectImportFormat memverifikasi deteksi format file import OBC dari isi dan ekstensi file
+func TestDetectImportFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileName  string
+		content   string
+		expected  services.ImportFileFormat
+		expectErr error
+	}{
+		{"Excel dari magic bytes", "obc.xlsx", "PK\x03\x04rest-of-zip", services.ImportFormatXLSX, nil},
+		{"CSV koma", "obc_sample_basic.csv", "No OBC,Tgl OBC,Material\nOBC-1,15/01/2024,PITA", services.ImportFormatCSV, nil},
+		{"CSV dengan BOM", "obc.csv", "\xEF\xBB\xBFNo OBC,QTY PESAN\nOBC-1,100", services.ImportFormatCSV, nil},
+		{"Semicolon SAP export", "export.txt", "No OBC;Tgl OBC;QTY PESAN\r\nOBC-1;15.01.2024;1.000,00", services.ImportFormatSemicolon, nil},
+		{"Tab delimited", "export.txt", "No OBC\tQTY PESAN\nOBC-1\t100", services.ImportFormatTSV, nil},
+		{"Ekstensi TSV", "export.tsv", "No OBC\nOBC-1", services.ImportFormatTSV, nil},
+		{"CSV satu kolom", "obc.csv", "No OBC\nOBC-1", services.ImportFormatCSV, nil},
+		{"Excel rusak", "obc.xlsx", "bukan excel", "", services.ErrUnsupportedImportFormat},
+		{"File kosong", "obc.txt", "", "", services.ErrUnsupportedImportFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := services.DetectImportFormat(tt.fileName, []byte(tt.content))
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("DetectImportFormat() error = %v, expected %v", err, tt.expectErr)
+			}
+			if format != tt.expected {
+				t.Errorf("DetectImportFormat() = %s, expected %s", format, tt.expected)
+			}
+		})
+	}
+}
+
+// TestParseHeaderAliases memverifikasi parsing alias header dari konfigurasi
+func TestParseHeaderAliases(t *testing.T) {
+	aliases := services.ParseHeaderAliases("Nomor Order=No OBC; Jumlah = QTY PESAN;invalid;=Plnt;Plant=Plnt Kode")
+
+	tests := []struct {
+		alias    string
+		expected string
+	}{
+		{"Nomor Order", "No OBC"},
+		{"Jumlah", "QTY PESAN"},
+		{"OBC No", "No OBC"},
+		{"Plant", "Plnt Kode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			if got := aliases[tt.alias]; got != tt.expected {
+				t.Errorf("alias %q = %q, expected %q", tt.alias, got, tt.expected)
+			}
+		})
+	}
+
+	if _, exists := aliases["invalid"]; exists {
+		t.Error("pair tanpa '=' tidak boleh menjadi alias")
+	}
+}
@@ -0,0 +1,147 @@
+package services_test
+
+import (
+	"fmt"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"sirine-go/backend/tests/testutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// setupOBCImportDB menyiapkan tabel OBC, PO, production rule, sequence nomor PO, dan import batch
+func setupOBCImportDB(t *testing.T) *gorm.DB {
+	return testutil.SetupModelDB(t,
+		&models.OBCMaster{}, &models.ProductionOrder{}, &models.ProductionRule{}, &models.PONumberSequence{},
+		&models.OBCImportBatch{}, &models.OBCImportBatchItem{}, &models.OBCImportPreview{})
+}
+
+// TestOBCImportGeneratePOFailure memverifikasi row yang gagal generate PO dilaporkan sebagai failed row
+// dan OBC dari row tersebut ikut dibatalkan, sementara row lain tetap tersimpan
+func TestOBCImportGeneratePOFailure(t *testing.T) {
+	db := setupOBCImportDB(t)
+	service := services.NewOBCImportService(db, services.NewPONumberGenerator(models.DefaultPONumberFormat, ""), nil)
+
+	csv := "No OBC,Material,QTY PESAN,Plnt\nOBC-IMP-1,PITA,1000,1001\nOBC-IMP-2,PITA,1000,123456\n"
+	result, err := service.ImportFromFile(strings.NewReader(csv), "obc.csv", true, 1)
+	if err != nil {
+		t.Fatalf("ImportFromFile() error = %v", err)
+	}
+	if result.SuccessCount != 1 || result.FailedCount != 1 || result.POsGenerated == 0 {
+		t.Fatalf("ImportFromFile() = %+v, expected 1 sukses dengan PO dan 1 gagal", result)
+	}
+	if failed := result.FailedRows[0]; failed.OBCNumber != "OBC-IMP-2" || !strings.Contains(failed.Error, models.ErrInvalidPlantCode.Error()) {
+		t.Errorf("failed row = %+v, expected error kode plant", failed)
+	}
+
+	var obcCount int64
+	db.Model(&models.OBCMaster{}).Where("obc_number = ?", "OBC-IMP-2").Count(&obcCount)
+	if obcCount != 0 {
+		t.Error("OBC dari row yang gagal generate PO tidak boleh tersimpan")
+	}
+
+	var batch models.OBCImportBatch
+	db.First(&batch, result.BatchID)
+	if batch.SuccessCount != 1 || batch.FailedCount != 1 || batch.POsGenerated != result.POsGenerated {
+		t.Errorf("import batch = %+v, expected ringkasan sesuai hasil import", batch)
+	}
+}
+
+// TestOBCImportCommitPreviewGeneratesPOForUnchangedRows memverifikasi commit preview menjalankan
+// generate PO yang sama dengan import langsung, termasuk untuk row yang tidak berubah
+func TestOBCImportCommitPreviewGeneratesPOForUnchangedRows(t *testing.T) {
+	db := setupOBCImportDB(t)
+	service := services.NewOBCImportService(db, services.NewPONumberGenerator(models.DefaultPONumberFormat, ""), nil)
+
+	csv := "No OBC,Material,QTY PESAN,Plnt\nOBC-IMP-1,PITA,1000,1001\n"
+	direct, err := service.ImportFromFile(strings.NewReader(csv), "obc.csv", true, 1)
+	if err != nil || direct.POsGenerated == 0 {
+		t.Fatalf("ImportFromFile() = %+v, %v", direct, err)
+	}
+
+	preview, err := service.PreviewImport(strings.NewReader(csv), "obc.csv", true, 1)
+	if err != nil {
+		t.Fatalf("PreviewImport() error = %v", err)
+	}
+	if preview.UnchangedCount != 1 {
+		t.Fatalf("preview = %+v, expected 1 row UNCHANGED", preview)
+	}
+
+	committed, err := service.CommitPreview(preview.Token, 1)
+	if err != nil {
+		t.Fatalf("CommitPreview() error = %v", err)
+	}
+	if committed.SuccessCount != 1 || committed.POsGenerated != direct.POsGenerated {
+		t.Errorf("CommitPreview() = %+v, expected %d PO seperti import langsung", committed, direct.POsGenerated)
+	}
+
+	var items []models.OBCImportBatchItem
+	db.Where("batch_id = ?", committed.BatchID).Find(&items)
+	if len(items) != 1 || items[0].Outcome != models.ImportItemUnchanged || len(items[0].GeneratedPOIDs) == 0 {
+		t.Errorf("batch items = %+v, expected UNCHANGED dengan generated PO IDs", items)
+	}
+}
+
+// TestGeneratePOsFromOBCConcurrent memverifikasi generate PO manual yang berjalan bersamaan
+// untuk prefix yang sama tidak menghasilkan nomor PO yang bentrok
+func TestGeneratePOsFromOBCConcurrent(t *testing.T) {
+	db := setupOBCImportDB(t)
+	service := services.NewOBCImportService(db, services.NewPONumberGenerator(models.DefaultPONumberFormat, ""), nil)
+
+	const obcCount = 5
+	obcIDs := make([]uint64, obcCount)
+	for i := range obcIDs {
+		obc := models.OBCMaster{OBCNumber: fmt.Sprintf("OBC-GEN-%d", i), PlantCode: "1001", QuantityOrdered: 80000}
+		if err := db.Create(&obc).Error; err != nil {
+			t.Fatal(err)
+		}
+		obcIDs[i] = obc.ID
+	}
+
+	// Jeda setelah membaca sequence agar generate lain sempat berjalan di antara baca dan update sequence
+	err := db.Callback().Query().After("gorm:query").Register("test:po_number_sequence_yield", func(tx *gorm.DB) {
+		if tx.Statement.Table == "po_number_sequences" {
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, obcCount)
+	for _, obcID := range obcIDs {
+		wg.Add(1)
+		go func(obcID uint64) {
+			defer wg.Done()
+			if _, err := service.GeneratePOsFromOBC(obcID); err != nil {
+				errs <- err
+			}
+		}(obcID)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("GeneratePOsFromOBC() error = %v", err)
+	}
+
+	var pos []models.ProductionOrder
+	db.Find(&pos)
+	seen := make(map[int64]bool, len(pos))
+	for _, po := range pos {
+		if seen[po.PONumber] {
+			t.Errorf("nomor PO %d dipakai lebih dari satu PO", po.PONumber)
+		}
+		seen[po.PONumber] = true
+	}
+
+	var sequences []models.PONumberSequence
+	db.Find(&sequences)
+	if len(sequences) != 1 || sequences[0].LastValue != int64(len(pos)) {
+		t.Errorf("sequences = %+v, expected satu sequence dengan last_value %d", sequences, len(pos))
+	}
+}
@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"sirine-go/backend/internal/counting"
+	"sirine-go/backend/internal/cutting"
+	"sirine-go/backend/internal/khazkhir"
+	"sirine-go/backend/internal/verifikasi"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"sirine-go/backend/tests/testutil"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// setupPOTimelineDB menyiapkan tabel PO, record setiap stage, stage tracking, activity log, dan users
+func setupPOTimelineDB(t *testing.T) *gorm.DB {
+	return testutil.SetupModelDB(t,
+		&models.User{}, &models.ProductionOrder{}, &models.POStageTracking{}, &models.ActivityLog{},
+		&models.KhazwalMaterialPreparation{}, &models.PrintJob{}, &models.PrintJobLog{},
+		&counting.KhazwalCountingResult{}, &cutting.KhazwalCuttingResult{},
+		&verifikasi.VerifikasiResult{}, &khazkhir.KhazkhirResult{})
+}
+
+// timeAt mengembalikan pointer waktu untuk seed data
+func timeAt(t time.Time) *time.Time {
+	return &t
+}
+
+// TestPOTimelineDedupesStageEvents memverifikasi mulai/selesai stage yang tercatat di record stage,
+// stage tracking, dan activity log hanya muncul sekali, sementara event lain tetap ada dan terurut waktu
+func TestPOTimelineDedupesStageEvents(t *testing.T) {
+	db := setupPOTimelineDB(t)
+	service := services.NewPOTimelineService(db)
+
+	base := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	countStart := base.Add(2 * time.Hour)
+	countEnd := countStart.Add(30 * time.Minute)
+	cutStart := countEnd.Add(time.Hour)
+	cutEnd := cutStart.Add(45 * time.Minute)
+	userID := uint64(7)
+
+	po := models.ProductionOrder{
+		PONumber: 2026100100000001, OBCNumber: "OBC-TL", QuantityOrdered: 1000,
+		OrderDate: base, DueDate: base.AddDate(0, 0, 7), Priority: models.PriorityNormal,
+		CurrentStage: models.StageVerifikasi, CurrentStatus: models.StatusReadyForVerifikasi,
+		CreatedAt: base,
+	}
+	if err := db.Create(&po).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	countDuration, cutDuration := 30, 45
+	if err := db.Create(&counting.KhazwalCountingResult{
+		ProductionOrderID: po.ID, QuantityGood: 500, Status: counting.CountingCompleted,
+		StartedAt: &countStart, CompletedAt: &countEnd, DurationMinutes: &countDuration, CountedBy: &userID,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&cutting.KhazwalCuttingResult{
+		ProductionOrderID: po.ID, InputLembarBesar: 500, ExpectedOutput: 1000, TotalOutput: 1000,
+		CuttingMachine: "CUT-01", Status: cutting.CuttingCompleted,
+		StartedAt: &cutStart, CompletedAt: &cutEnd, DurationMinutes: &cutDuration, CutBy: &userID,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Stage tracking ditulis beberapa milidetik setelah record stage dalam transaction yang sama,
+	// kecuali perpindahan dari cetak yang tidak memiliki print job
+	handoverFromCetak := base.Add(time.Hour)
+	trackings := []models.POStageTracking{
+		{Stage: models.StageCetak, Status: models.StatusWaitingCounting, CompletedAt: &handoverFromCetak, CreatedAt: handoverFromCetak},
+		{Stage: models.StageKhazwalCounting, Status: models.StatusCountingInProgress, StartedAt: timeAt(countStart.Add(200 * time.Millisecond)), CreatedAt: countStart.Add(200 * time.Millisecond)},
+		{Stage: models.StageKhazwalCounting, Status: models.StatusReadyForCutting, StartedAt: &countStart, CompletedAt: timeAt(countEnd.Add(time.Second)), DurationMinutes: &countDuration, CreatedAt: countEnd.Add(time.Second)},
+		{Stage: models.StageKhazwalCutting, Status: models.StatusCuttingInProgress, StartedAt: &cutStart, CreatedAt: cutStart.Add(300 * time.Millisecond)},
+		{Stage: models.StageKhazwalCutting, Status: models.StatusReadyForVerifikasi, StartedAt: &cutStart, CompletedAt: &cutEnd, DurationMinutes: &cutDuration, CreatedAt: cutEnd.Add(500 * time.Millisecond)},
+	}
+	for i := range trackings {
+		trackings[i].ProductionOrderID = po.ID
+		trackings[i].HandledBy = &userID
+	}
+	if err := db.Create(&trackings).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	activities := []models.ActivityLog{
+		{UserID: userID, Action: "START_COUNTING", EntityType: "COUNTING", ProductionOrderID: &po.ID, CreatedAt: countStart.Add(100 * time.Millisecond)},
+		{UserID: userID, Action: "FINALIZE_COUNTING", EntityType: "COUNTING", ProductionOrderID: &po.ID, CreatedAt: countEnd.Add(time.Second)},
+		{UserID: 1, Action: models.ActionPOUpdate, EntityType: "production_orders", EntityID: &po.ID, CreatedAt: countStart.Add(10 * time.Minute)},
+	}
+	if err := db.Create(&activities).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	timeline, err := service.GetTimeline(po.ID)
+	if err != nil {
+		t.Fatalf("GetTimeline() error = %v", err)
+	}
+
+	expectedEvents := []struct {
+		source string
+		event  string
+	}{
+		{services.TimelineSourcePO, "PO_CREATED"},
+		{services.TimelineSourceStage, string(models.StatusWaitingCounting)},
+		{services.TimelineSourceCounting, "COUNTING_STARTED"},
+		{services.TimelineSourceActivity, string(models.ActionPOUpdate)},
+		{services.TimelineSourceCounting, "COUNTING_COMPLETED"},
+		{services.TimelineSourceCutting, "CUTTING_STARTED"},
+		{services.TimelineSourceCutting, "CUTTING_COMPLETED"},
+	}
+	if len(timeline.Events) != len(expectedEvents) {
+		t.Fatalf("jumlah event = %d, expected %d: %+v", len(timeline.Events), len(expectedEvents), timeline.Events)
+	}
+	for i, want := range expectedEvents {
+		got := timeline.Events[i]
+		if got.Source != want.source || got.Event != want.event {
+			t.Errorf("event %d = %s/%s, expected %s/%s", i, got.Source, got.Event, want.source, want.event)
+		}
+		if i > 0 && got.Timestamp.Before(timeline.Events[i-1].Timestamp) {
+			t.Errorf("event %d tidak terurut waktu", i)
+		}
+	}
+
+	durations := map[models.POStage]int{}
+	for _, stage := range timeline.Stages {
+		if stage.DurationMinutes != nil {
+			durations[stage.Stage] = *stage.DurationMinutes
+		}
+	}
+	if len(timeline.Stages) != 2 || durations[models.StageKhazwalCounting] != 30 || durations[models.StageKhazwalCutting] != 45 {
+		t.Errorf("stages = %+v, expected counting 30 menit dan cutting 45 menit", timeline.Stages)
+	}
+}
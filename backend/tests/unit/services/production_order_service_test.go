@@ -0,0 +1,192 @@
+package services_test
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"sirine-go/backend/tests/testutil"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// setupProductionOrderDB menyiapkan tabel PO beserta tracking, sequence nomor PO, audit log, dan registry plat
+func setupProductionOrderDB(t *testing.T) *gorm.DB {
+	return testutil.SetupModelDB(t,
+		&models.OBCMaster{}, &models.ProductionOrder{}, &models.POStageTracking{}, &models.ProductionRule{},
+		&models.PONumberSequence{}, &models.ActivityLog{}, &models.Plat{}, &models.PlatCheckout{})
+}
+
+// seedOBC menambahkan OBC Master dengan plant tertentu
+func seedOBC(t *testing.T, db *gorm.DB, obcNumber, plantCode string) uint64 {
+	obc := models.OBCMaster{OBCNumber: obcNumber, PlantCode: plantCode, QuantityOrdered: 40000}
+	if err := db.Create(&obc).Error; err != nil {
+		t.Fatal(err)
+	}
+	return obc.ID
+}
+
+// seedProductionOrder menambahkan PO untuk OBC dengan quantity, status, dan priority tertentu
+func seedProductionOrder(t *testing.T, db *gorm.DB, obcID uint64, poNumber int64, qty int, status models.POStatus, priority models.POPriority) models.ProductionOrder {
+	stage, err := models.StageForStatus(status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	po := models.ProductionOrder{
+		PONumber:        poNumber,
+		OBCMasterID:     obcID,
+		OBCNumber:       "OBC-PO",
+		QuantityOrdered: qty,
+		OrderDate:       time.Now(),
+		DueDate:         time.Now().AddDate(0, 0, 14),
+		Priority:        priority,
+		CurrentStage:    stage,
+		CurrentStatus:   status,
+	}
+	if err := db.Create(&po).Error; err != nil {
+		t.Fatal(err)
+	}
+	return po
+}
+
+// newProductionOrderService membuat service PO dengan format nomor PO default
+func newProductionOrderService(db *gorm.DB) *services.ProductionOrderService {
+	return services.NewProductionOrderService(db, services.NewPONumberGenerator(models.DefaultPONumberFormat, ""))
+}
+
+// TestSplitProductionOrder memverifikasi split mempertahankan quantity pertama di PO asal
+// dan membuat PO baru dengan parent_po_id serta nomor PO dari sequence
+func TestSplitProductionOrder(t *testing.T) {
+	db := setupProductionOrderDB(t)
+	service := newProductionOrderService(db)
+	obcID := seedOBC(t, db, "OBC-PO", "1001")
+	po := seedProductionOrder(t, db, obcID, 2026100100000001, 40000, models.StatusWaitingMaterialPrep, models.PriorityNormal)
+
+	if _, err := service.SplitProductionOrder(po.ID, services.SplitProductionOrderRequest{Quantities: []int{30000, 5000}}, 1); !errors.Is(err, services.ErrInvalidSplitQuantities) {
+		t.Fatalf("split dengan total berbeda = %v, expected ErrInvalidSplitQuantities", err)
+	}
+
+	result, err := service.SplitProductionOrder(po.ID, services.SplitProductionOrderRequest{Quantities: []int{25000, 15000}, Reason: "Kapasitas mesin"}, 1)
+	if err != nil {
+		t.Fatalf("SplitProductionOrder() error = %v", err)
+	}
+	if len(result) != 2 || result[0].ID != po.ID || result[0].QuantityOrdered != 25000 {
+		t.Fatalf("SplitProductionOrder() = %+v, expected PO asal dengan quantity 25000", result)
+	}
+	child := result[1]
+	if child.ParentPOID == nil || *child.ParentPOID != po.ID || child.QuantityOrdered != 15000 ||
+		child.PONumber == po.PONumber || child.CurrentStatus != models.StatusWaitingMaterialPrep {
+		t.Errorf("PO hasil split = %+v", child)
+	}
+
+	var stored models.ProductionOrder
+	db.First(&stored, po.ID)
+	if stored.QuantityOrdered != 25000 {
+		t.Errorf("quantity PO asal = %d, expected 25000", stored.QuantityOrdered)
+	}
+	var audits int64
+	db.Model(&models.ActivityLog{}).Where("action = ? AND entity_id = ?", models.ActionPOSplit, po.ID).Count(&audits)
+	if audits != 1 {
+		t.Errorf("audit log split = %d, expected 1", audits)
+	}
+
+	db.Model(&models.ProductionOrder{}).Where("id = ?", child.ID).Update("current_status", models.StatusReadyForCetak)
+	if _, err := service.SplitProductionOrder(child.ID, services.SplitProductionOrderRequest{Quantities: []int{10000, 5000}}, 1); !errors.Is(err, services.ErrPONotSplittable) {
+		t.Errorf("split PO READY_FOR_CETAK = %v, expected ErrPONotSplittable", err)
+	}
+}
+
+// TestMergeProductionOrders memverifikasi merge ke PO dengan quantity terbesar yang mengambil priority tertinggi,
+// dan PO sumber dibatalkan melalui state machine dengan merged_into_po_id
+func TestMergeProductionOrders(t *testing.T) {
+	db := setupProductionOrderDB(t)
+	service := newProductionOrderService(db)
+	obcID := seedOBC(t, db, "OBC-PO", "1001")
+	otherOBC := seedOBC(t, db, "OBC-LAIN", "1001")
+	target := seedProductionOrder(t, db, obcID, 2026100100000001, 10000, models.StatusWaitingMaterialPrep, models.PriorityNormal)
+	source := seedProductionOrder(t, db, obcID, 2026100100000002, 5000, models.StatusWaitingMaterialPrep, models.PriorityUrgent)
+	other := seedProductionOrder(t, db, otherOBC, 2026100100000003, 5000, models.StatusWaitingMaterialPrep, models.PriorityNormal)
+
+	if _, err := service.MergeProductionOrders(services.MergeProductionOrdersRequest{POIDs: []uint64{target.ID, other.ID}}, 1); !errors.Is(err, services.ErrMergeDifferentOBC) {
+		t.Fatalf("merge beda OBC = %v, expected ErrMergeDifferentOBC", err)
+	}
+
+	merged, err := service.MergeProductionOrders(services.MergeProductionOrdersRequest{POIDs: []uint64{source.ID, target.ID}}, 1)
+	if err != nil {
+		t.Fatalf("MergeProductionOrders() error = %v", err)
+	}
+	if merged.ID != target.ID || merged.QuantityOrdered != 15000 || merged.Priority != models.PriorityUrgent {
+		t.Errorf("hasil merge = %+v, expected PO %d dengan quantity 15000 dan priority URGENT", merged, target.ID)
+	}
+
+	var cancelled models.ProductionOrder
+	db.First(&cancelled, source.ID)
+	if cancelled.CurrentStatus != models.StatusPOCancelled || cancelled.MergedIntoPOID == nil || *cancelled.MergedIntoPOID != target.ID {
+		t.Errorf("PO sumber = %+v, expected CANCELLED dengan merged_into_po_id %d", cancelled, target.ID)
+	}
+	var tracking models.POStageTracking
+	if err := db.Where("production_order_id = ? AND status = ?", source.ID, models.StatusPOCancelled).First(&tracking).Error; err != nil {
+		t.Errorf("tracking pembatalan PO sumber tidak ditemukan: %v", err)
+	}
+}
+
+// TestCancelProductionOrder memverifikasi cancel hanya sebelum produksi dimulai,
+// dicatat melalui state machine, dan plat yang masih dipakai PO dikembalikan
+func TestCancelProductionOrder(t *testing.T) {
+	db := setupProductionOrderDB(t)
+	service := newProductionOrderService(db)
+	obcID := seedOBC(t, db, "OBC-PO", "1001")
+	po := seedProductionOrder(t, db, obcID, 2026100100000001, 40000, models.StatusReadyForCetak, models.PriorityNormal)
+	started := seedProductionOrder(t, db, obcID, 2026100100000002, 40000, models.StatusWaitingCounting, models.PriorityNormal)
+
+	checkedOutAt := time.Now().Add(-time.Hour)
+	plat := models.Plat{Code: "PLT-001", Status: models.PlatInUse, CurrentPOID: &po.ID, CheckedOutAt: &checkedOutAt}
+	if err := db.Create(&plat).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.PlatCheckout{PlatID: plat.ID, ProductionOrderID: po.ID, CheckedOutBy: 2, CheckedOutAt: checkedOutAt}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.CancelProductionOrder(po.ID, services.CancelProductionOrderRequest{Reason: " "}, 1); !errors.Is(err, services.ErrCancelReasonRequired) {
+		t.Fatalf("cancel tanpa alasan = %v, expected ErrCancelReasonRequired", err)
+	}
+	if _, err := service.CancelProductionOrder(started.ID, services.CancelProductionOrderRequest{Reason: "Order batal"}, 1); !errors.Is(err, services.ErrPONotCancellable) {
+		t.Fatalf("cancel PO yang sudah dicetak = %v, expected ErrPONotCancellable", err)
+	}
+
+	cancelled, err := service.CancelProductionOrder(po.ID, services.CancelProductionOrderRequest{Reason: "Order batal"}, 1)
+	if err != nil {
+		t.Fatalf("CancelProductionOrder() error = %v", err)
+	}
+	if cancelled.CurrentStatus != models.StatusPOCancelled || cancelled.CancelledBy == nil || cancelled.CancellationReason != "Order batal" {
+		t.Errorf("CancelProductionOrder() = %+v", cancelled)
+	}
+
+	var stored models.ProductionOrder
+	db.First(&stored, po.ID)
+	if stored.CurrentStatus != models.StatusPOCancelled || stored.CurrentStage != models.StageCetak || stored.CancelledAt == nil {
+		t.Errorf("PO tersimpan = %+v, expected CANCELLED di stage CETAK", stored)
+	}
+	var tracking models.POStageTracking
+	if err := db.Where("production_order_id = ? AND status = ?", po.ID, models.StatusPOCancelled).First(&tracking).Error; err != nil ||
+		tracking.Stage != models.StageCetak || tracking.CompletedAt == nil || tracking.Notes != "Order batal" {
+		t.Errorf("tracking pembatalan = %+v, %v", tracking, err)
+	}
+
+	var released models.Plat
+	db.First(&released, plat.ID)
+	if released.Status != models.PlatAvailable || released.CurrentPOID != nil {
+		t.Errorf("plat setelah PO dibatalkan = %+v, expected AVAILABLE", released)
+	}
+	var checkout models.PlatCheckout
+	db.Where("plat_id = ?", plat.ID).First(&checkout)
+	if checkout.ReturnedAt == nil || checkout.ReturnedBy == nil || *checkout.ReturnedBy != 1 {
+		t.Errorf("riwayat pemakaian plat = %+v, expected sudah dikembalikan", checkout)
+	}
+
+	if _, err := service.CancelProductionOrder(po.ID, services.CancelProductionOrderRequest{Reason: "Ulang"}, 1); !errors.Is(err, services.ErrPONotCancellable) {
+		t.Errorf("cancel ulang = %v, expected ErrPONotCancellable", err)
+	}
+}
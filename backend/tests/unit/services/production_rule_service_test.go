@@ -0,0 +1,98 @@
+package services_test
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"sirine-go/backend/tests/testutil"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// setupProductionRuleDB menyiapkan tabel production rule
+func setupProductionRuleDB(t *testing.T) *gorm.DB {
+	return testutil.SetupModelDB(t, &models.ProductionRule{})
+}
+
+// TestProductionRuleDefaultCannotBeDeactivated memverifikasi DEFAULT rule tetap aktif sebagai fallback,
+// sementara rule lain tetap bisa dinonaktifkan
+func TestProductionRuleDefaultCannotBeDeactivated(t *testing.T) {
+	db := setupProductionRuleDB(t)
+	service := services.NewProductionRuleService(db)
+
+	defaultRule, err := service.ResolveRule("", "")
+	if err != nil {
+		t.Fatalf("ResolveRule() error = %v", err)
+	}
+	if defaultRule.Code != models.DefaultProductionRuleCode {
+		t.Fatalf("ResolveRule() tanpa rule = %s, expected DEFAULT dibuat otomatis", defaultRule.Code)
+	}
+	if _, err := service.DeactivateRule(defaultRule.ID); !errors.Is(err, services.ErrDefaultRuleRequired) {
+		t.Errorf("nonaktifkan DEFAULT = %v, expected ErrDefaultRuleRequired", err)
+	}
+
+	perso, err := service.CreateRule(services.CreateProductionRuleRequest{
+		Code: "PERSO", Name: "Perso", Personalization: "Perso", BufferPercentage: 10,
+		MaxQuantityPerPO: 20000, SheetsPerRim: 500, LembarBesarMultiplier: 2,
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+	if _, err := service.DeactivateRule(perso.ID); err != nil {
+		t.Errorf("nonaktifkan rule PERSO error = %v", err)
+	}
+}
+
+// TestProductionRuleInactiveDefaultNotUsed memverifikasi versi DEFAULT yang sudah nonaktif tidak dipakai sebagai fallback
+func TestProductionRuleInactiveDefaultNotUsed(t *testing.T) {
+	db := setupProductionRuleDB(t)
+	service := services.NewProductionRuleService(db)
+
+	rule := models.DefaultProductionRule()
+	rule.BufferPercentage = 20
+	if err := db.Create(&rule).Error; err != nil {
+		t.Fatal(err)
+	}
+	db.Model(&rule).Update("is_active", false)
+
+	if _, err := service.ResolveRule("", ""); !errors.Is(err, services.ErrNoActiveDefaultRule) {
+		t.Errorf("ResolveRule() dengan DEFAULT nonaktif = %v, expected ErrNoActiveDefaultRule", err)
+	}
+}
+
+// TestOBCTotalWithBufferUsesResolvedRule memverifikasi total dengan buffer di detail OBC
+// memakai buffer dari rule yang cocok, bukan buffer bawaan
+func TestOBCTotalWithBufferUsesResolvedRule(t *testing.T) {
+	db := testutil.SetupModelDB(t, &models.OBCMaster{}, &models.ProductionOrder{}, &models.ProductionRule{})
+	rules := services.NewProductionRuleService(db)
+	obcService := services.NewOBCImportService(db, services.NewPONumberGenerator(models.DefaultPONumberFormat, ""), nil)
+
+	if _, err := rules.CreateRule(services.CreateProductionRuleRequest{
+		Code: "PERSO", Name: "Perso", Personalization: "Perso", BufferPercentage: 10,
+		MaxQuantityPerPO: 20000, SheetsPerRim: 500, LembarBesarMultiplier: 2,
+	}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		personalization string
+		expected        int
+	}{
+		{"Rule PERSO", "Perso", 11000},
+		{"Fallback DEFAULT", "Non Perso", 10600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obc := models.OBCMaster{QuantityOrdered: 10000, Personalization: tt.personalization}
+			rule, err := obcService.ResolveProductionRule(&obc)
+			if err != nil {
+				t.Fatalf("ResolveProductionRule() error = %v", err)
+			}
+			if got := obc.CalculateTotalWithBuffer(rule); got != tt.expected {
+				t.Errorf("CalculateTotalWithBuffer() = %d, expected %d", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testSAPPosting membuat payload posting untuk testing adapter
+func testSAPPosting() *services.SAPPosting {
+	return &services.SAPPosting{
+		EventType:         models.SAPEventCountingFinalized,
+		AggregateType:     "khazwal_counting_result",
+		AggregateID:       7,
+		ProductionOrderID: 3,
+		PONumber:          2025000100000042,
+		OBCNumber:         "OBC-001",
+		PostingDate:       "2025-01-15",
+		Items: []services.SAPPostingItem{
+			{Material: "PRD-1000", Quantity: 480, Unit: "lembar", MovementType: services.SAPMovementGoodsReceipt},
+			{Material: "PRD-1000", Quantity: 20, Unit: "lembar", MovementType: services.SAPMovementScrap},
+		},
+	}
+}
+
+// TestSAPHTTPClientDeliver memverifikasi adapter HTTP mengirim idempotency key dan membaca reference
+func TestSAPHTTPClientDeliver(t *testing.T) {
+	var received services.SAPPosting
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/material-documents" {
+			t.Errorf("path = %s, expected /material-documents", r.URL.Path)
+		}
+		if got := r.Header.Get("Idempotency-Key"); got != "COUNTING_FINALIZED-7" {
+			t.Errorf("Idempotency-Key = %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("body tidak valid: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"reference":"4900001234"}`))
+	}))
+	defer server.Close()
+
+	client := services.NewSAPHTTPClient(server.URL+"/", "secret", time.Second)
+	reference, err := client.Deliver(context.Background(), "COUNTING_FINALIZED-7", testSAPPosting())
+	if err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if reference != "4900001234" {
+		t.Errorf("reference = %q, expected 4900001234", reference)
+	}
+	if len(received.Items) != 2 || received.Items[1].MovementType != services.SAPMovementScrap {
+		t.Errorf("items yang diterima tidak sesuai: %+v", received.Items)
+	}
+}
+
+// TestSAPHTTPClientRejected memverifikasi response non-2xx dikembalikan sebagai ErrSAPRejected
+func TestSAPHTTPClientRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "material tidak dikenal", http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	client := services.NewSAPHTTPClient(server.URL, "", time.Second)
+	_, err := client.Deliver(context.Background(), "COUNTING_FINALIZED-7", testSAPPosting())
+	if !errors.Is(err, services.ErrSAPRejected) {
+		t.Fatalf("Deliver() error = %v, expected ErrSAPRejected", err)
+	}
+	if !strings.Contains(err.Error(), "material tidak dikenal") {
+		t.Errorf("error tidak menyertakan pesan SAP: %v", err)
+	}
+}
+
+// TestSAPFileDropClientDeliver memverifikasi adapter file menulis IDoc MBGMCR03 dengan nama dari idempotency key
+func TestSAPFileDropClientDeliver(t *testing.T) {
+	dir := t.TempDir()
+	client := services.NewSAPFileDropClient(dir, "")
+
+	reference, err := client.Deliver(context.Background(), "COUNTING_FINALIZED-7", testSAPPosting())
+	if err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if reference != "COUNTING_FINALIZED-7" {
+		t.Errorf("reference = %q", reference)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "MBGMCR_COUNTING_FINALIZED-7.xml"))
+	if err != nil {
+		t.Fatalf("file IDoc tidak ditulis: %v", err)
+	}
+	for _, expected := range []string{"<MBGMCR03>", "<SNDPRN>SIRINE</SNDPRN>", "<PSTNG_DATE>20250115</PSTNG_DATE>", "<MOVE_TYPE>551</MOVE_TYPE>", "<ENTRY_QNT>480.000</ENTRY_QNT>"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("IDoc tidak mengandung %s", expected)
+		}
+	}
+
+	// Pengiriman ulang menimpa file yang sama tanpa meninggalkan file sementara
+	if _, err := client.Deliver(context.Background(), "COUNTING_FINALIZED-7", testSAPPosting()); err != nil {
+		t.Fatalf("Deliver() ulang error = %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("jumlah file = %d, expected 1", len(entries))
+	}
+}
+
+// setupSAPOutboxDB membuat in-memory database dengan tabel outbox untuk testing worker
+func setupSAPOutboxDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Gagal membuat test database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&models.SAPOutboxEntry{}); err != nil {
+		t.Fatalf("Gagal migrate database: %v", err)
+	}
+	return db
+}
+
+// createSAPOutboxEntry menyimpan entry outbox PENDING yang sudah jatuh tempo
+func createSAPOutboxEntry(t *testing.T, db *gorm.DB, aggregateID uint64, maxAttempts int) *models.SAPOutboxEntry {
+	posting := testSAPPosting()
+	posting.AggregateID = aggregateID
+	payload, _ := json.Marshal(posting)
+
+	entry := &models.SAPOutboxEntry{
+		EventType:         posting.EventType,
+		AggregateType:     posting.AggregateType,
+		AggregateID:       aggregateID,
+		ProductionOrderID: posting.ProductionOrderID,
+		IdempotencyKey:    fmt.Sprintf("COUNTING_FINALIZED-%d", aggregateID),
+		Payload:           datatypes.JSON(payload),
+		Status:            models.SAPOutboxPending,
+		MaxAttempts:       maxAttempts,
+		NextAttemptAt:     time.Now().Add(-time.Second),
+	}
+	if err := db.Create(entry).Error; err != nil {
+		t.Fatalf("Gagal membuat entry outbox: %v", err)
+	}
+	return entry
+}
+
+// TestSAPOutboxProcessDue memverifikasi retry dengan backoff sampai terkirim dan penandaan FAILED
+func TestSAPOutboxProcessDue(t *testing.T) {
+	var calls int32
+	failing := int32(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			http.Error(w, "SAP sedang maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"reference":"4900000001"}`))
+	}))
+	defer server.Close()
+
+	db := setupSAPOutboxDB(t)
+	service := services.NewSAPOutboxService(db, services.NewSAPHTTPClient(server.URL, "", time.Second), time.Minute)
+
+	retried := createSAPOutboxEntry(t, db, 1, models.SAPOutboxDefaultMaxAttempts)
+	exhausted := createSAPOutboxEntry(t, db, 2, 1)
+
+	delivered, err := service.ProcessDue(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessDue() error = %v", err)
+	}
+	if delivered != 0 || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("delivered = %d, calls = %d, expected 0 dan 2", delivered, calls)
+	}
+
+	reload := func(id uint64) models.SAPOutboxEntry {
+		var entry models.SAPOutboxEntry
+		if err := db.First(&entry, id).Error; err != nil {
+			t.Fatalf("Gagal mengambil entry outbox: %v", err)
+		}
+		return entry
+	}
+
+	entry := reload(retried.ID)
+	if entry.Status != models.SAPOutboxPending || entry.Attempts != 1 || entry.LastError == "" {
+		t.Errorf("entry gagal pertama: status %s, attempts %d, last_error %q", entry.Status, entry.Attempts, entry.LastError)
+	}
+	if !entry.NextAttemptAt.After(time.Now()) {
+		t.Errorf("next_attempt_at tidak dijadwalkan ulang: %v", entry.NextAttemptAt)
+	}
+
+	entry = reload(exhausted.ID)
+	if entry.Status != models.SAPOutboxFailed {
+		t.Errorf("entry yang mencapai batas percobaan: status %s, expected FAILED", entry.Status)
+	}
+
+	// Belum jatuh tempo sehingga tidak dikirim ulang
+	if _, err := service.ProcessDue(context.Background()); err != nil {
+		t.Fatalf("ProcessDue() error = %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("entry yang belum jatuh tempo ikut dikirim, calls = %d", calls)
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	db.Model(&models.SAPOutboxEntry{}).Where("id = ?", retried.ID).Update("next_attempt_at", time.Now().Add(-time.Second))
+
+	delivered, err = service.ProcessDue(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessDue() error = %v", err)
+	}
+	if delivered != 1 {
+		t.Fatalf("delivered = %d, expected 1", delivered)
+	}
+
+	entry = reload(retried.ID)
+	if !entry.IsDelivered() || entry.ExternalReference != "4900000001" || entry.Attempts != 2 || entry.DeliveredAt == nil {
+		t.Errorf("entry terkirim: status %s, reference %q, attempts %d", entry.Status, entry.ExternalReference, entry.Attempts)
+	}
+
+	summary, err := service.GetSummary()
+	if err != nil {
+		t.Fatalf("GetSummary() error = %v", err)
+	}
+	if summary.Adapter != "http" || summary.Delivered != 1 || summary.Failed != 1 || summary.Pending != 0 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
@@ -0,0 +1,250 @@
+package services_test
+
+import (
+	"errors"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// setupSLADB menambahkan tabel production_orders, po_stage_trackings, dan tabel SLA ke database notifikasi,
+// production_orders dan po_stage_trackings dibuat manual karena kolom enum tidak didukung sqlite
+func setupSLADB(t *testing.T) *gorm.DB {
+	db := setupNotificationDB(t)
+	for _, ddl := range []string{
+		`CREATE TABLE production_orders (
+			id integer PRIMARY KEY AUTOINCREMENT, po_number integer NOT NULL, obc_number text NOT NULL,
+			priority text DEFAULT 'NORMAL', current_stage text, current_status text NOT NULL,
+			created_at datetime, updated_at datetime, deleted_at datetime)`,
+		`CREATE TABLE po_stage_trackings (
+			id integer PRIMARY KEY AUTOINCREMENT, production_order_id integer NOT NULL, stage text NOT NULL,
+			status text NOT NULL, created_at datetime, updated_at datetime, deleted_at datetime)`,
+	} {
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.AutoMigrate(&models.SLAPolicy{}, &models.SLABreach{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seedQueuedPO menambahkan PO di status antrian yang masuk antrian sejak enteredAt,
+// enteredAt nil berarti PO belum memiliki tracking sehingga waktu tunggu dihitung dari created_at
+func seedQueuedPO(t *testing.T, db *gorm.DB, poNumber int64, priority models.POPriority, status models.POStatus, createdAt time.Time, enteredAt *time.Time) uint64 {
+	stage, err := models.StageForStatus(status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Table("production_orders").Create(map[string]interface{}{
+		"po_number": poNumber, "obc_number": "OBC-SLA", "priority": priority,
+		"current_stage": stage, "current_status": status, "created_at": createdAt,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	var id uint64
+	db.Table("production_orders").Select("MAX(id)").Scan(&id)
+
+	if enteredAt != nil {
+		if err := db.Table("po_stage_trackings").Create(map[string]interface{}{
+			"production_order_id": id, "stage": stage, "status": status, "created_at": *enteredAt,
+		}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return id
+}
+
+// TestSLAThresholds memverifikasi urutan SLA: policy stage+priority, policy stage, lalu SLA bawaan
+func TestSLAThresholds(t *testing.T) {
+	db := setupSLADB(t)
+	service := services.NewSLAService(db)
+
+	thresholds, err := services.LoadSLAThresholds(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := thresholds.For(models.StageKhazwalCounting, models.PriorityNormal); got.BreachMinutes != 120 {
+		t.Errorf("SLA bawaan penghitungan = %+v, expected breach 120 menit", got)
+	}
+	if got, _ := thresholds.For(models.StageKhazwalCounting, models.PriorityUrgent); got.BreachMinutes != 60 {
+		t.Errorf("SLA bawaan penghitungan URGENT = %+v, expected breach 60 menit", got)
+	}
+	if level := thresholds.Evaluate(models.StageCompleted, models.PriorityNormal, 10000); level != models.SLAOnTrack {
+		t.Errorf("stage tanpa SLA = %s, expected ON_TRACK", level)
+	}
+
+	if _, err := service.UpsertPolicy(services.UpsertSLAPolicyRequest{
+		Stage: models.StageKhazwalCounting, WarningMinutes: 200, BreachMinutes: 300,
+	}, 1); err != nil {
+		t.Fatalf("UpsertPolicy() error = %v", err)
+	}
+	if _, err := service.UpsertPolicy(services.UpsertSLAPolicyRequest{
+		Stage: "khazwal_counting", Priority: "urgent", WarningMinutes: 20, BreachMinutes: 30,
+	}, 1); err != nil {
+		t.Fatalf("UpsertPolicy() error = %v", err)
+	}
+	// Upsert kedua untuk stage dan priority yang sama meng-update policy
+	if _, err := service.UpsertPolicy(services.UpsertSLAPolicyRequest{
+		Stage: models.StageKhazwalCounting, Priority: models.PriorityUrgent, WarningMinutes: 15, BreachMinutes: 25,
+	}, 1); err != nil {
+		t.Fatalf("UpsertPolicy() error = %v", err)
+	}
+
+	thresholds, _ = services.LoadSLAThresholds(db)
+	if got, _ := thresholds.For(models.StageKhazwalCounting, models.PriorityUrgent); got.BreachMinutes != 25 {
+		t.Errorf("SLA penghitungan URGENT = %+v, expected policy breach 25 menit", got)
+	}
+	if got, _ := thresholds.For(models.StageKhazwalCounting, models.PriorityLow); got.BreachMinutes != 300 {
+		t.Errorf("SLA penghitungan LOW = %+v, expected policy stage breach 300 menit", got)
+	}
+
+	items, err := service.ListPolicies()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := map[models.POPriority]string{}
+	for _, item := range items {
+		if item.Stage == models.StageKhazwalCounting {
+			sources[item.Priority] = item.Source
+		}
+	}
+	if len(items) != 18 || sources[models.PriorityUrgent] != "POLICY" || sources[models.PriorityLow] != "POLICY" {
+		t.Errorf("ListPolicies() = %d item dengan source penghitungan %v", len(items), sources)
+	}
+
+	for _, req := range []services.UpsertSLAPolicyRequest{
+		{Stage: models.StageCompleted, WarningMinutes: 10, BreachMinutes: 20},
+		{Stage: models.StageCetak, WarningMinutes: 30, BreachMinutes: 30},
+		{Stage: models.StageCetak, Priority: "HIGH", WarningMinutes: 10, BreachMinutes: 20},
+	} {
+		if _, err := service.UpsertPolicy(req, 1); err == nil {
+			t.Errorf("UpsertPolicy(%+v) expected error", req)
+		}
+	}
+
+	var policy models.SLAPolicy
+	db.Where("stage = ? AND priority = ?", models.StageKhazwalCounting, "").First(&policy)
+	if err := service.DeletePolicy(policy.ID); err != nil {
+		t.Fatalf("DeletePolicy() error = %v", err)
+	}
+	if err := service.DeletePolicy(policy.ID); !errors.Is(err, services.ErrSLAPolicyNotFound) {
+		t.Errorf("DeletePolicy() kedua error = %v, expected ErrSLAPolicyNotFound", err)
+	}
+}
+
+// TestSLAScanQueues memverifikasi deteksi AT_RISK dan BREACHED, eskalasi tanpa duplikasi,
+// dan penutupan breach saat PO diambil dari antrian
+func TestSLAScanQueues(t *testing.T) {
+	db := setupSLADB(t)
+	service := services.NewSLAService(db)
+
+	khazwalManager := seedRoutingUser(t, db, models.RoleManager, models.DeptKhazwal, models.StatusActive)
+	cetakManager := seedRoutingUser(t, db, models.RoleManager, models.DeptCetak, models.StatusActive)
+	ppic := seedRoutingUser(t, db, models.RolePPIC, models.DeptPPIC, models.StatusActive)
+
+	now := time.Now()
+	minutesAgo := func(minutes int) *time.Time {
+		at := now.Add(-time.Duration(minutes) * time.Minute)
+		return &at
+	}
+
+	// Penghitungan NORMAL 100 menit: lewat warning 90 menit, belum breach 120 menit
+	atRiskPO := seedQueuedPO(t, db, 1001, models.PriorityNormal, models.StatusWaitingCounting, *minutesAgo(600), minutesAgo(100))
+	// Cetak URGENT 250 menit: melewati SLA URGENT 240 menit
+	breachedPO := seedQueuedPO(t, db, 1002, models.PriorityUrgent, models.StatusReadyForCetak, *minutesAgo(900), minutesAgo(250))
+	// Cetak NORMAL 250 menit masih aman (SLA 480 menit)
+	seedQueuedPO(t, db, 1003, models.PriorityNormal, models.StatusReadyForCetak, *minutesAgo(900), minutesAgo(250))
+	// PO baru tanpa tracking dihitung dari created_at
+	seedQueuedPO(t, db, 1004, models.PriorityNormal, models.StatusWaitingMaterialPrep, *minutesAgo(10), nil)
+	// PO yang sedang dikerjakan tidak termasuk antrian
+	seedQueuedPO(t, db, 1005, models.PriorityNormal, models.StatusCountingInProgress, *minutesAgo(900), minutesAgo(500))
+
+	result, err := service.ScanQueues(now)
+	if err != nil {
+		t.Fatalf("ScanQueues() error = %v", err)
+	}
+	if result.Scanned != 4 || result.AtRisk != 1 || result.Breached != 1 || result.Escalated != 2 {
+		t.Errorf("ScanQueues() = %+v, expected scanned 4, at_risk 1, breached 1, escalated 2", result)
+	}
+
+	var notifications []models.Notification
+	db.Order("user_id").Find(&notifications)
+	byUser := map[uint64][]models.Notification{}
+	for _, notification := range notifications {
+		byUser[notification.UserID] = append(byUser[notification.UserID], notification)
+	}
+	if got := byUser[khazwalManager]; len(got) != 1 || got[0].Type != models.NotificationWarning || got[0].POID == nil || *got[0].POID != atRiskPO {
+		t.Errorf("eskalasi AT_RISK ke manager khazwal = %+v", got)
+	}
+	if got := byUser[cetakManager]; len(got) != 1 || got[0].Type != models.NotificationError || got[0].Category != models.NotificationCategoryAlert {
+		t.Errorf("eskalasi BREACHED ke manager cetak = %+v", got)
+	}
+	if got := byUser[ppic]; len(got) != 1 || got[0].Stage != models.StageCetak || *got[0].POID != breachedPO {
+		t.Errorf("eskalasi BREACHED ke PPIC = %+v", got)
+	}
+
+	// Scan ulang tidak mengirim eskalasi yang sama dua kali
+	result, err = service.ScanQueues(now.Add(5 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notificationCount, breachCount int64
+	db.Model(&models.Notification{}).Count(&notificationCount)
+	db.Model(&models.SLABreach{}).Count(&breachCount)
+	if result.Escalated != 0 || notificationCount != 3 || breachCount != 2 {
+		t.Errorf("scan ulang: escalated %d, %d notifikasi, %d breach; expected 0, 3, 2", result.Escalated, notificationCount, breachCount)
+	}
+
+	// Penghitungan mencapai 125 menit: breach BREACHED baru dicatat di samping AT_RISK
+	result, err = service.ScanQueues(now.Add(25 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Escalated != 1 || len(recipients(db, "SLA Penghitungan Terlewati - PO #OBC-SLA")) != 2 {
+		t.Errorf("breach penghitungan: escalated %d, expected 1 ke manager khazwal dan PPIC", result.Escalated)
+	}
+
+	// PO penghitungan diambil dari antrian: breach-nya ditutup
+	db.Table("production_orders").Where("id = ?", atRiskPO).Update("current_status", models.StatusCountingInProgress)
+	result, err = service.ScanQueues(now.Add(30 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Resolved != 2 {
+		t.Errorf("Resolved = %d, expected 2 breach penghitungan ditutup", result.Resolved)
+	}
+
+	open, err := service.ListBreaches(services.SLABreachFilters{Status: "open"})
+	if err != nil {
+		t.Fatalf("ListBreaches() error = %v", err)
+	}
+	if open.Total != 1 || open.Items[0].ProductionOrderID != breachedPO || open.Items[0].PONumber != 1002 {
+		t.Errorf("breach terbuka = %+v, expected hanya PO 1002", open.Items)
+	}
+
+	summary, err := service.GetBreachSummary(services.SLABreachFilters{})
+	if err != nil {
+		t.Fatalf("GetBreachSummary() error = %v", err)
+	}
+	for _, item := range summary {
+		switch item.Stage {
+		case models.StageKhazwalCounting:
+			if item.AtRisk != 1 || item.Breached != 1 || item.Open != 0 {
+				t.Errorf("rekap penghitungan = %+v", item)
+			}
+		case models.StageCetak:
+			if item.Breached != 1 || item.Open != 1 {
+				t.Errorf("rekap cetak = %+v", item)
+			}
+		}
+	}
+
+	if _, err := service.ListBreaches(services.SLABreachFilters{DateFrom: "18-10-2026"}); !errors.Is(err, services.ErrSLADateInvalid) {
+		t.Errorf("ListBreaches() tanggal tidak valid error = %v", err)
+	}
+}
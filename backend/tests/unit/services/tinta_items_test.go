@@ -0,0 +1,157 @@
+package services_test
+
+import (
+	"errors"
+	"reflect"
+	"sirine-go/backend/models"
+	"sirine-go/backend/services"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestValidateTintaActual memverifikasi validasi warna, satuan, dan jumlah tinta actual
+func TestValidateTintaActual(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputs    []services.TintaActualInput
+		expected  []services.TintaUsage
+		expectErr bool
+	}{
+		{
+			name: "Satuan kg dan gram dikonversi ke kg",
+			inputs: []services.TintaActualInput{
+				{Color: "Magenta", Quantity: 2.5, BatchNumber: " LOT-01 ", Checked: true},
+				{Color: "cyan", Quantity: 1500, Unit: "g", Checked: true},
+			},
+			expected: []services.TintaUsage{
+				{Color: "cyan", QuantityKg: 1.5, Checked: true},
+				{Color: "magenta", QuantityKg: 2.5, BatchNumber: "LOT-01", Checked: true},
+			},
+		},
+		{
+			name:     "Warna tidak dicentang tanpa jumlah",
+			inputs:   []services.TintaActualInput{{Color: "black", Quantity: 0}},
+			expected: []services.TintaUsage{{Color: "black", QuantityKg: 0}},
+		},
+		{name: "List kosong", inputs: []services.TintaActualInput{}, expectErr: true},
+		{name: "Warna duplikat", inputs: []services.TintaActualInput{{Color: "cyan", Quantity: 1}, {Color: "CYAN", Quantity: 2}}, expectErr: true},
+		{name: "Kode warna tidak valid", inputs: []services.TintaActualInput{{Color: "cyan;drop", Quantity: 1}}, expectErr: true},
+		{name: "Satuan tidak dikenal", inputs: []services.TintaActualInput{{Color: "cyan", Quantity: 1, Unit: "liter"}}, expectErr: true},
+		{name: "Jumlah melebihi batas", inputs: []services.TintaActualInput{{Color: "cyan", Quantity: 1500}}, expectErr: true},
+		{name: "Dicentang tanpa jumlah", inputs: []services.TintaActualInput{{Color: "cyan", Checked: true}}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := services.ValidateTintaActual(tt.inputs)
+			if tt.expectErr {
+				if !errors.Is(err, services.ErrInvalidTintaUsage) {
+					t.Errorf("expected ErrInvalidTintaUsage, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(usage, tt.expected) {
+				t.Errorf("usage = %+v, expected %+v", usage, tt.expected)
+			}
+		})
+	}
+}
+
+// TestParseLegacyTintaRequirements memverifikasi parsing format JSON tinta_requirements lama
+func TestParseLegacyTintaRequirements(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expected  []services.TintaUsage
+		expectErr bool
+	}{
+		{
+			name: "Object warna ke kg dari seeder",
+			data: `{"cyan": 2, "Black": 3}`,
+			expected: []services.TintaUsage{
+				{Color: "black", QuantityKg: 3},
+				{Color: "cyan", QuantityKg: 2},
+			},
+		},
+		{
+			name:     "Array requirement",
+			data:     `[{"color":"yellow","requirement":1.25}]`,
+			expected: []services.TintaUsage{{Color: "yellow", QuantityKg: 1.25}},
+		},
+		{name: "Null", data: `null`, expected: nil},
+		{name: "Format tidak dikenal", data: `"cyan"`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := services.ParseLegacyTintaRequirements([]byte(tt.data))
+			if tt.expectErr {
+				if !errors.Is(err, services.ErrInvalidTintaUsage) {
+					t.Errorf("expected ErrInvalidTintaUsage, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(usage, tt.expected) {
+				t.Errorf("usage = %+v, expected %+v", usage, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMigrateLegacyTintaInTx memverifikasi konversi kolom JSON lama ke line item dan migrasi berulang
+func TestMigrateLegacyTintaInTx(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Gagal membuat test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.KhazwalTintaItem{}); err != nil {
+		t.Fatalf("Gagal migrate database: %v", err)
+	}
+
+	requirements := []byte(`{"cyan": 2, "magenta": 2}`)
+	actual := []byte(`[{"color":"Cyan","quantity":2.5,"checked":true},{"color":"black","quantity":1,"checked":true}]`)
+	flags := []byte(`{"low_stock_colors":["cyan"],"warning":"Beberapa warna tinta memiliki stock rendah"}`)
+
+	created, err := services.MigrateLegacyTintaInTx(db, 10, requirements, actual, flags)
+	if err != nil {
+		t.Fatalf("MigrateLegacyTintaInTx() error = %v", err)
+	}
+	if created != 3 {
+		t.Fatalf("created = %d, expected 3", created)
+	}
+
+	var items []models.KhazwalTintaItem
+	db.Where("material_prep_id = ?", 10).Order("color_code").Find(&items)
+	if len(items) != 3 {
+		t.Fatalf("jumlah line item = %d, expected 3", len(items))
+	}
+
+	black, cyan, magenta := items[0], items[1], items[2]
+	if black.RequiredKg != 0 || black.ActualKg == nil || *black.ActualKg != 1 {
+		t.Errorf("black = %+v", black)
+	}
+	if cyan.RequiredKg != 2 || cyan.ActualKg == nil || *cyan.ActualKg != 2.5 || !cyan.LowStock || !cyan.Checked {
+		t.Errorf("cyan = %+v", cyan)
+	}
+	if magenta.RequiredKg != 2 || magenta.ActualKg != nil || magenta.Checked {
+		t.Errorf("magenta = %+v", magenta)
+	}
+
+	// Prep yang sudah memiliki line item dilewati
+	created, err = services.MigrateLegacyTintaInTx(db, 10, requirements, actual, flags)
+	if err != nil || created != 0 {
+		t.Errorf("migrasi ulang: created = %d, err = %v", created, err)
+	}
+
+	if _, err := services.MigrateLegacyTintaInTx(db, 11, []byte(`"cyan"`), nil, nil); !errors.Is(err, services.ErrInvalidTintaUsage) {
+		t.Errorf("expected ErrInvalidTintaUsage, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"errors"
+	"reflect"
+	"sirine-go/backend/services"
+	"testing"
+)
+
+// TestParseTintaUsage memverifikasi parsing tinta actual menjadi pemakaian per warna
+func TestParseTintaUsage(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expected  []services.TintaUsage
+		expectErr bool
+	}{
+		{
+			name: "Array dari checklist tinta",
+			data: `[{"color":"Magenta","quantity":5,"checked":true},{"color":" cyan ","quantity":5.5,"checked":true}]`,
+			expected: []services.TintaUsage{
+				{Color: "cyan", QuantityKg: 5.5},
+				{Color: "magenta", QuantityKg: 5},
+			},
+		},
+		{
+			name: "Object colors dengan warna duplikat dijumlahkan",
+			data: `{"colors":[{"color":"black","quantity":1.2},{"color":"BLACK","quantity":0.3004}]}`,
+			expected: []services.TintaUsage{
+				{Color: "black", QuantityKg: 1.5},
+			},
+		},
+		{name: "Array kosong", data: `[]`, expected: []services.TintaUsage{}},
+		{name: "Warna kosong", data: `[{"color":"","quantity":1}]`, expectErr: true},
+		{name: "Quantity negatif", data: `[{"color":"cyan","quantity":-1}]`, expectErr: true},
+		{name: "Bukan JSON tinta", data: `"cyan"`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := services.ParseTintaUsage([]byte(tt.data))
+			if tt.expectErr {
+				if !errors.Is(err, services.ErrInvalidTintaUsage) {
+					t.Errorf("expected ErrInvalidTintaUsage, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(usage, tt.expected) {
+				t.Errorf("usage = %+v, expected %+v", usage, tt.expected)
+			}
+		})
+	}
+}
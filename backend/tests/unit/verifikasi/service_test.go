@@ -0,0 +1,76 @@
+package verifikasi_test
+
+import (
+	"errors"
+	"sirine-go/backend/internal/verifikasi"
+	"sirine-go/backend/models"
+	"sirine-go/backend/tests/testutil"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// setupVerifikasiDB menyiapkan tabel PO, label verifikasi, dan rekap verifikasi
+func setupVerifikasiDB(t *testing.T) *gorm.DB {
+	return testutil.SetupModelDB(t,
+		&models.ProductionOrder{}, &verifikasi.VerificationLabel{}, &verifikasi.VerifikasiResult{})
+}
+
+// seedVerifikasiLabel menambahkan rekap verifikasi dengan status tertentu beserta satu label 500 lembar
+func seedVerifikasiLabel(t *testing.T, db *gorm.DB, status verifikasi.VerifikasiStatus) verifikasi.VerificationLabel {
+	startedAt := time.Now()
+	result := verifikasi.VerifikasiResult{ProductionOrderID: 1, Status: status, StartedAt: &startedAt}
+	if err := db.Create(&result).Error; err != nil {
+		t.Fatal(err)
+	}
+	label := verifikasi.VerificationLabel{
+		ProductionOrderID: 1, LabelNumber: "LBL-0001", Sequence: 1, TotalLabels: 1,
+		TargetQuantity: 500, Status: verifikasi.LabelPending,
+	}
+	if err := db.Create(&label).Error; err != nil {
+		t.Fatal(err)
+	}
+	return label
+}
+
+// TestUpdateLabelResult memverifikasi hasil label tersimpan selama verifikasi masih berjalan
+func TestUpdateLabelResult(t *testing.T) {
+	db := setupVerifikasiDB(t)
+	service := verifikasi.NewVerifikasiService(db, verifikasi.NewVerifikasiRepository(db))
+	label := seedVerifikasiLabel(t, db, verifikasi.VerifikasiInProgress)
+
+	req := verifikasi.LabelResultRequest{
+		QuantityHCS: 490, QuantityHCTS: 10,
+		HCTSBreakdown: []verifikasi.DefectBreakdownItem{{Type: "BLOBOR", Quantity: 10}},
+	}
+	response, err := service.UpdateLabelResult(label.ID, req, 3)
+	if err != nil {
+		t.Fatalf("UpdateLabelResult() error = %v", err)
+	}
+	if response.QuantityHCS != 490 || response.Status != verifikasi.LabelVerified {
+		t.Errorf("UpdateLabelResult() = %+v, expected 490 HCS dan VERIFIED", response)
+	}
+
+	if _, err := service.UpdateLabelResult(label.ID+1, req, 3); !errors.Is(err, verifikasi.ErrLabelNotFound) {
+		t.Errorf("label tidak ada = %v, expected ErrLabelNotFound", err)
+	}
+}
+
+// TestUpdateLabelResultAfterFinalize memverifikasi label tidak bisa diubah setelah rekap verifikasi difinalisasi
+func TestUpdateLabelResultAfterFinalize(t *testing.T) {
+	db := setupVerifikasiDB(t)
+	service := verifikasi.NewVerifikasiService(db, verifikasi.NewVerifikasiRepository(db))
+	label := seedVerifikasiLabel(t, db, verifikasi.VerifikasiCompleted)
+
+	req := verifikasi.LabelResultRequest{QuantityHCS: 500}
+	if _, err := service.UpdateLabelResult(label.ID, req, 3); !errors.Is(err, verifikasi.ErrVerifikasiNotInProgress) {
+		t.Fatalf("update label setelah finalize = %v, expected ErrVerifikasiNotInProgress", err)
+	}
+
+	var stored verifikasi.VerificationLabel
+	db.First(&stored, label.ID)
+	if stored.Status != verifikasi.LabelPending || stored.QuantityHCS != 0 {
+		t.Errorf("label tersimpan = %+v, expected tidak berubah", stored)
+	}
+}
@@ -0,0 +1,115 @@
+package verifikasi_test
+
+import (
+	"errors"
+	"sirine-go/backend/internal/verifikasi"
+	"testing"
+)
+
+// TestBuildLabels memverifikasi pembagian label per 500 lembar kirim
+func TestBuildLabels(t *testing.T) {
+	tests := []struct {
+		name          string
+		totalOutput   int
+		expectedCount int
+		lastTarget    int
+	}{
+		{"Exact multiple", 1000, 2, 500},
+		{"With remainder", 29900, 60, 400},
+		{"Less than one bundle", 120, 1, 120},
+		{"Zero output", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels := verifikasi.BuildLabels(1, 2025001, tt.totalOutput)
+			if len(labels) != tt.expectedCount {
+				t.Fatalf("BuildLabels() count = %d, expected %d", len(labels), tt.expectedCount)
+			}
+			if tt.expectedCount == 0 {
+				return
+			}
+
+			total := 0
+			for _, label := range labels {
+				total += label.TargetQuantity
+			}
+			if total != tt.totalOutput {
+				t.Errorf("BuildLabels() total target = %d, expected %d", total, tt.totalOutput)
+			}
+
+			last := labels[len(labels)-1]
+			if last.TargetQuantity != tt.lastTarget {
+				t.Errorf("last label target = %d, expected %d", last.TargetQuantity, tt.lastTarget)
+			}
+			if labels[0].LabelNumber != "VRF-2025001-001" {
+				t.Errorf("first label number = %s, expected VRF-2025001-001", labels[0].LabelNumber)
+			}
+		})
+	}
+}
+
+// TestValidateLabelResult memverifikasi business rules input HCS/HCTS per label
+func TestValidateLabelResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       verifikasi.LabelResultRequest
+		target    int
+		expectErr error
+	}{
+		{
+			name:   "Valid - all HCS",
+			req:    verifikasi.LabelResultRequest{QuantityHCS: 500},
+			target: 500,
+		},
+		{
+			name: "Valid - with HCTS breakdown",
+			req: verifikasi.LabelResultRequest{
+				QuantityHCS:  490,
+				QuantityHCTS: 10,
+				HCTSBreakdown: []verifikasi.DefectBreakdownItem{
+					{Type: "Warna Pudar", Quantity: 6},
+					{Type: "Noda Tinta", Quantity: 4},
+				},
+			},
+			target: 500,
+		},
+		{
+			name:      "Invalid - total mismatch",
+			req:       verifikasi.LabelResultRequest{QuantityHCS: 480},
+			target:    500,
+			expectErr: verifikasi.ErrLabelQuantityMismatch,
+		},
+		{
+			name:      "Invalid - HCTS without breakdown",
+			req:       verifikasi.LabelResultRequest{QuantityHCS: 490, QuantityHCTS: 10},
+			target:    500,
+			expectErr: verifikasi.ErrHCTSBreakdownRequired,
+		},
+		{
+			name: "Invalid - breakdown sum mismatch",
+			req: verifikasi.LabelResultRequest{
+				QuantityHCS:   490,
+				QuantityHCTS:  10,
+				HCTSBreakdown: []verifikasi.DefectBreakdownItem{{Type: "Sobek", Quantity: 7}},
+			},
+			target:    500,
+			expectErr: verifikasi.ErrHCTSBreakdownSumMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifikasi.ValidateLabelResult(tt.req, tt.target)
+			if tt.expectErr == nil {
+				if err != nil {
+					t.Errorf("ValidateLabelResult() error = %v, expected nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("ValidateLabelResult() error = %v, expected %v", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -49,7 +49,7 @@ export function useCuttingApi() {
   /**
    * Mengupdate hasil cutting (sisiran kiri & kanan)
    * @param {Number} id - Cutting record ID
-   * @param {Object} payload - Request payload dengan output_sisiran_kiri, output_sisiran_kanan, waste_reason, waste_photo_url
+   * @param {Object} payload - Request payload dengan output_sisiran_kiri, output_sisiran_kanan, waste_reason, waste_attachment_id
    * @returns {Promise<Object>} Update result response
    */
   const updateCuttingResult = async (id, payload) => {
@@ -0,0 +1,162 @@
+import { onBeforeUnmount } from 'vue'
+import apiClient from './useApi'
+import { useAuthStore } from '../stores/auth'
+
+const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || 'http://localhost:8080/api'
+
+const RECONNECT_MIN_MS = 2000
+const RECONNECT_MAX_MS = 30000
+
+/**
+ * parseFrame mengubah satu frame SSE (baris event/data/id) menjadi object
+ */
+const parseFrame = (frame) => {
+  let event = 'message'
+  const dataLines = []
+  for (const line of frame.split('\n')) {
+    if (line.startsWith('event:')) {
+      event = line.slice(6).trim()
+    } else if (line.startsWith('data:')) {
+      dataLines.push(line.slice(5).trim())
+    }
+  }
+  if (dataLines.length === 0) return null
+
+  try {
+    return { event, data: JSON.parse(dataLines.join('\n')) }
+  } catch {
+    return null
+  }
+}
+
+/**
+ * openEventStream membuka koneksi SSE ke /api/events dengan Bearer token,
+ * menggunakan fetch streaming karena EventSource tidak bisa mengirim header Authorization.
+ * Koneksi otomatis reconnect dengan backoff, dan onResync dipanggil setiap kali stream
+ * tersambung ulang agar caller memuat ulang data yang mungkin terlewat.
+ * @param {Object} options - topics (array), onEvent(type, event), onResync()
+ * @returns {Function} close untuk menutup koneksi
+ */
+export const openEventStream = ({ topics = [], onEvent, onResync } = {}) => {
+  const authStore = useAuthStore()
+  let controller = null
+  let closed = false
+  let retryDelay = RECONNECT_MIN_MS
+  let retryTimeout = null
+  let connectedOnce = false
+
+  const scheduleReconnect = () => {
+    if (closed) return
+    retryTimeout = setTimeout(connect, retryDelay)
+    retryDelay = Math.min(retryDelay * 2, RECONNECT_MAX_MS)
+  }
+
+  // Request ringan melalui axios agar interceptor melakukan refresh token
+  const refreshAccessToken = async () => {
+    try {
+      await apiClient.get('/auth/me')
+      return true
+    } catch {
+      return false
+    }
+  }
+
+  const handleFrame = (frame) => {
+    const parsed = parseFrame(frame)
+    if (!parsed) return
+
+    switch (parsed.event) {
+      case 'ready':
+        retryDelay = RECONNECT_MIN_MS
+        if (connectedOnce && onResync) onResync()
+        connectedOnce = true
+        break
+      case 'resync':
+        if (onResync) onResync()
+        break
+      case 'token_expired':
+        break
+      default:
+        if (onEvent) onEvent(parsed.event, parsed.data)
+    }
+  }
+
+  const connect = async () => {
+    if (closed || !authStore.isAuthenticated) return
+
+    controller = new AbortController()
+    const query = topics.length ? `?topics=${encodeURIComponent(topics.join(','))}` : ''
+
+    try {
+      const response = await fetch(`${API_BASE_URL}/events${query}`, {
+        headers: {
+          Accept: 'text/event-stream',
+          Authorization: `Bearer ${authStore.token}`,
+        },
+        signal: controller.signal,
+      })
+
+      if (response.status === 401) {
+        if (await refreshAccessToken()) {
+          connect()
+        }
+        return
+      }
+      if (!response.ok || !response.body) {
+        scheduleReconnect()
+        return
+      }
+
+      const reader = response.body.getReader()
+      const decoder = new TextDecoder()
+      let buffer = ''
+
+      for (;;) {
+        const { value, done } = await reader.read()
+        if (done) break
+
+        buffer += decoder.decode(value, { stream: true })
+        let boundary = buffer.indexOf('\n\n')
+        while (boundary !== -1) {
+          handleFrame(buffer.slice(0, boundary))
+          buffer = buffer.slice(boundary + 2)
+          boundary = buffer.indexOf('\n\n')
+        }
+      }
+    } catch (error) {
+      if (closed || error.name === 'AbortError') return
+    }
+
+    // Stream berakhir (token expired, server restart, atau resync): refresh token lalu reconnect
+    if (!closed) {
+      await refreshAccessToken()
+      scheduleReconnect()
+    }
+  }
+
+  connect()
+
+  return () => {
+    closed = true
+    if (retryTimeout) clearTimeout(retryTimeout)
+    if (controller) controller.abort()
+  }
+}
+
+/**
+ * useEventStream membuka event stream selama component aktif
+ * dan menutupnya otomatis saat component unmount
+ * @param {Array} topics - Topic yang di-subscribe, misalnya ['queue.cetak']
+ * @param {Function} onChange - Dipanggil untuk setiap event dan saat perlu memuat ulang data
+ */
+export const useEventStream = (topics, onChange) => {
+  const close = openEventStream({
+    topics,
+    onEvent: (type, event) => onChange(type, event),
+    onResync: () => onChange('resync', null),
+  })
+
+  onBeforeUnmount(close)
+
+  return { close }
+}
@@ -91,17 +91,36 @@ export const useKhazwalApi = () => {
   }
 
   /**
-   * Finalize material preparation dengan upload photos dan notes
+   * Upload foto bukti material ke server sebelum finalize
+   * @param {Blob} file - File foto (JPG atau PNG)
+   * @param {string} fileName - Nama file asli
+   * @returns {Promise<Object>} Attachment dengan id, url, dan thumbnail_url
+   */
+  const uploadAttachment = async (file, fileName = 'foto-material.jpg') => {
+    const formData = new FormData()
+    formData.append('owner_type', 'MATERIAL_PREP')
+    formData.append('file', file, fileName)
+
+    const response = await post('/attachments', formData, {
+      headers: {
+        'Content-Type': 'multipart/form-data'
+      }
+    })
+    return response.data
+  }
+
+  /**
+   * Finalize material preparation dengan foto bukti dan notes
    * yang mengirim ke Unit Cetak untuk proses selanjutnya
    * @param {number} id - Material Prep ID (bukan PO ID)
-   * @param {Object} data - Data finalize (photos, notes)
-   * @param {Array} data.photos - Array foto material dalam base64 format
+   * @param {Object} data - Data finalize (attachmentIds, notes)
+   * @param {Array<number>} data.attachmentIds - ID foto bukti hasil uploadAttachment
    * @param {string} data.notes - Catatan tambahan (opsional)
    * @returns {Promise<Object>} Completion summary dengan duration dan status
    */
-  const finalize = async (id, { photos = [], notes = '' } = {}) => {
+  const finalize = async (id, { attachmentIds = [], notes = '' } = {}) => {
     return await post(`/khazwal/material-prep/${id}/finalize`, {
-      photos,
+      attachment_ids: attachmentIds,
       notes
     })
   }
@@ -147,6 +166,7 @@ export const useKhazwalApi = () => {
     confirmPlat,
     updateKertas,
     updateTinta,
+    uploadAttachment,
     finalize,
 
     // History & Monitoring (Sprint 5)
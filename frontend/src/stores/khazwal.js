@@ -72,7 +72,7 @@ export const useKhazwalStore = defineStore('khazwal', {
       return (
         prep.plat_retrieved_at !== null &&
         prep.kertas_blanko_actual !== null &&
-        prep.tinta_actual !== null
+        (prep.tinta_items || []).some(item => item.actual_kg !== null)
       )
     },
 
@@ -265,11 +265,9 @@ export const useKhazwalStore = defineStore('khazwal', {
         if (response.data.success) {
           // Update current prep jika ada
           if (this.currentPrep && this.currentPrep.khazwal_material_prep?.id === prepId) {
-            this.currentPrep.khazwal_material_prep.tinta_actual = tintaActual
-            // Update low stock flags jika ada dari server
-            if (response.data.data?.tinta_low_stock_flags) {
-              this.currentPrep.khazwal_material_prep.tinta_low_stock_flags = 
-                response.data.data.tinta_low_stock_flags
+            // Line item tinta dan low stock dihitung ulang oleh server
+            if (response.data.data?.tinta_items) {
+              this.currentPrep.khazwal_material_prep.tinta_items = response.data.data.tinta_items
             }
           }
           
@@ -285,14 +283,14 @@ export const useKhazwalStore = defineStore('khazwal', {
     /**
      * Finalize Material Prep dan kirim ke Unit Cetak
      * @param {Number} prepId - Material Prep ID
-     * @param {Array<String>} photos - Array of photo URLs (optional)
+     * @param {Array<Number>} attachmentIds - ID foto bukti dari POST /attachments (optional)
      * @param {String} notes - Additional notes (optional)
      * @returns {Promise<Object>} Finalize result dengan duration
      */
-    async finalizeMaterialPrep(prepId, photos = [], notes = '') {
+    async finalizeMaterialPrep(prepId, attachmentIds = [], notes = '') {
       try {
         const response = await apiClient.post(`/khazwal/material-prep/${prepId}/finalize`, {
-          photos: photos,
+          attachment_ids: attachmentIds,
           notes: notes
         })
 
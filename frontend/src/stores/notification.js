@@ -1,8 +1,41 @@
 import { defineStore } from 'pinia'
 import { ref, computed } from 'vue'
 import { useApi } from '@/composables/useApi'
+import { openEventStream } from '@/composables/useEventStream'
 import { useAuthStore } from './auth'
 
+/**
+ * Halaman antrian untuk setiap stage PO yang menjadi link notifikasi
+ */
+const STAGE_ROUTES = {
+  KHAZWAL_MATERIAL_PREP: '/khazwal/material-prep',
+  CETAK: '/cetak/queue',
+  KHAZWAL_COUNTING: '/khazwal/counting',
+  KHAZWAL_CUTTING: '/khazwal/cutting',
+  VERIFIKASI: '/verifikasi',
+}
+
+/**
+ * Label kategori notifikasi untuk filter di notification center
+ */
+export const NOTIFICATION_CATEGORIES = [
+  { label: 'Serah Terima', value: 'HANDOVER' },
+  { label: 'Peringatan', value: 'ALERT' },
+  { label: 'Pencapaian', value: 'ACHIEVEMENT' },
+  { label: 'Sistem', value: 'SYSTEM' },
+]
+
+/**
+ * getNotificationLink mengembalikan halaman antrian stage yang terkait dengan notifikasi,
+ * null jika notifikasi tidak terkait PO atau stage tidak memiliki halaman
+ */
+export const getNotificationLink = (notification) => {
+  if (!notification?.po_id || !notification.stage) {
+    return null
+  }
+  return STAGE_ROUTES[notification.stage] || null
+}
+
 /**
  * Notification Store merupakan Pinia store untuk state management notifikasi
  * yang bertujuan untuk handling in-app notifications dengan real-time updates
@@ -16,6 +49,7 @@ export const useNotificationStore = defineStore('notification', () => {
   const unreadCount = ref(0)
   const isLoading = ref(false)
   const pollingInterval = ref(null)
+  let closeEventStream = null
 
   // Computed
   const unreadNotifications = computed(() => 
@@ -26,9 +60,9 @@ export const useNotificationStore = defineStore('notification', () => {
 
   /**
    * fetchNotifications mengambil semua notifikasi user
-   * dengan optional filter unread_only untuk performance
+   * dengan optional filter unread_only dan kategori (HANDOVER, ALERT, ACHIEVEMENT, SYSTEM)
    */
-  const fetchNotifications = async (unreadOnly = false) => {
+  const fetchNotifications = async (unreadOnly = false, category = '') => {
     // Skip jika user belum authenticated
     if (!authStore.isAuthenticated) {
       return []
@@ -36,8 +70,11 @@ export const useNotificationStore = defineStore('notification', () => {
 
     try {
       isLoading.value = true
-      const params = unreadOnly ? '?unread_only=true' : ''
-      const response = await api.get(`/notifications${params}`)
+      const params = new URLSearchParams()
+      if (unreadOnly) params.set('unread_only', 'true')
+      if (category) params.set('category', category)
+      const query = params.toString()
+      const response = await api.get(`/notifications${query ? `?${query}` : ''}`)
       
       if (response.success) {
         notifications.value = response.data || []
@@ -205,8 +242,8 @@ export const useNotificationStore = defineStore('notification', () => {
   }
 
   /**
-   * startPolling memulai polling untuk real-time updates
-   * dengan interval 30 detik (configurable)
+   * startPolling memulai event stream untuk real-time updates
+   * dengan polling interval 30 detik (configurable) sebagai fallback
    */
   const startPolling = (intervalMs = 30000) => {
     // Jangan mulai polling jika user belum authenticated
@@ -222,6 +259,15 @@ export const useNotificationStore = defineStore('notification', () => {
     // Initial fetch
     fetchUnreadCount()
 
+    // Notifikasi baru langsung memperbarui badge tanpa menunggu polling
+    closeEventStream = openEventStream({
+      topics: ['notifications'],
+      onEvent: (type) => {
+        if (type === 'NOTIFICATION_CREATED') fetchUnreadCount()
+      },
+      onResync: fetchUnreadCount,
+    })
+
     // Setup polling interval
     pollingInterval.value = setInterval(() => {
       // Cek auth sebelum fetch untuk safety
@@ -234,7 +280,7 @@ export const useNotificationStore = defineStore('notification', () => {
   }
 
   /**
-   * stopPolling menghentikan polling
+   * stopPolling menghentikan polling dan event stream
    * dipanggil saat user logout atau component unmount
    */
   const stopPolling = () => {
@@ -242,6 +288,10 @@ export const useNotificationStore = defineStore('notification', () => {
       clearInterval(pollingInterval.value)
       pollingInterval.value = null
     }
+    if (closeEventStream) {
+      closeEventStream()
+      closeEventStream = null
+    }
   }
 
   /**